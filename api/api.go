@@ -4,3 +4,6 @@ import _ "embed"
 
 //go:embed openapi.yml
 var OpenAPISpec []byte
+
+//go:embed openapi-v2.yml
+var OpenAPIV2Spec []byte
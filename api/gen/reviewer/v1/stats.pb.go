@@ -0,0 +1,305 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: reviewer/v1/stats.proto
+
+package reviewerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{0}
+}
+
+type UserStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	TeamName      string                 `protobuf:"bytes,3,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	ReviewCount   int64                  `protobuf:"varint,4,opt,name=review_count,json=reviewCount,proto3" json:"review_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserStats) Reset() {
+	*x = UserStats{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserStats) ProtoMessage() {}
+
+func (x *UserStats) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserStats.ProtoReflect.Descriptor instead.
+func (*UserStats) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UserStats) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserStats) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserStats) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *UserStats) GetReviewCount() int64 {
+	if x != nil {
+		return x.ReviewCount
+	}
+	return 0
+}
+
+type PullRequestStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	ReviewerCount int64                  `protobuf:"varint,2,opt,name=reviewer_count,json=reviewerCount,proto3" json:"reviewer_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullRequestStats) Reset() {
+	*x = PullRequestStats{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequestStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequestStats) ProtoMessage() {}
+
+func (x *PullRequestStats) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequestStats.ProtoReflect.Descriptor instead.
+func (*PullRequestStats) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PullRequestStats) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *PullRequestStats) GetReviewerCount() int64 {
+	if x != nil {
+		return x.ReviewerCount
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ByUser        []*UserStats           `protobuf:"bytes,1,rep,name=by_user,json=byUser,proto3" json:"by_user,omitempty"`
+	ByPr          []*PullRequestStats    `protobuf:"bytes,2,rep,name=by_pr,json=byPr,proto3" json:"by_pr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStatsResponse) GetByUser() []*UserStats {
+	if x != nil {
+		return x.ByUser
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetByPr() []*PullRequestStats {
+	if x != nil {
+		return x.ByPr
+	}
+	return nil
+}
+
+var File_reviewer_v1_stats_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_stats_proto_rawDesc = "" +
+	"\n" +
+	"\x17reviewer/v1/stats.proto\x12\vreviewer.v1\"\x11\n" +
+	"\x0fGetStatsRequest\"\x80\x01\n" +
+	"\tUserStats\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tteam_name\x18\x03 \x01(\tR\bteamName\x12!\n" +
+	"\freview_count\x18\x04 \x01(\x03R\vreviewCount\"a\n" +
+	"\x10PullRequestStats\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12%\n" +
+	"\x0ereviewer_count\x18\x02 \x01(\x03R\rreviewerCount\"w\n" +
+	"\x10GetStatsResponse\x12/\n" +
+	"\aby_user\x18\x01 \x03(\v2\x16.reviewer.v1.UserStatsR\x06byUser\x122\n" +
+	"\x05by_pr\x18\x02 \x03(\v2\x1d.reviewer.v1.PullRequestStatsR\x04byPr2W\n" +
+	"\fStatsService\x12G\n" +
+	"\bGetStats\x12\x1c.reviewer.v1.GetStatsRequest\x1a\x1d.reviewer.v1.GetStatsResponseB,Z*reviewer_pr/api/gen/reviewer/v1;reviewerv1b\x06proto3"
+
+var (
+	file_reviewer_v1_stats_proto_rawDescOnce sync.Once
+	file_reviewer_v1_stats_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_stats_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_stats_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_stats_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_stats_proto_rawDesc), len(file_reviewer_v1_stats_proto_rawDesc)))
+	})
+	return file_reviewer_v1_stats_proto_rawDescData
+}
+
+var file_reviewer_v1_stats_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_reviewer_v1_stats_proto_goTypes = []any{
+	(*GetStatsRequest)(nil),  // 0: reviewer.v1.GetStatsRequest
+	(*UserStats)(nil),        // 1: reviewer.v1.UserStats
+	(*PullRequestStats)(nil), // 2: reviewer.v1.PullRequestStats
+	(*GetStatsResponse)(nil), // 3: reviewer.v1.GetStatsResponse
+}
+var file_reviewer_v1_stats_proto_depIdxs = []int32{
+	1, // 0: reviewer.v1.GetStatsResponse.by_user:type_name -> reviewer.v1.UserStats
+	2, // 1: reviewer.v1.GetStatsResponse.by_pr:type_name -> reviewer.v1.PullRequestStats
+	0, // 2: reviewer.v1.StatsService.GetStats:input_type -> reviewer.v1.GetStatsRequest
+	3, // 3: reviewer.v1.StatsService.GetStats:output_type -> reviewer.v1.GetStatsResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_stats_proto_init() }
+func file_reviewer_v1_stats_proto_init() {
+	if File_reviewer_v1_stats_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_stats_proto_rawDesc), len(file_reviewer_v1_stats_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewer_v1_stats_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_stats_proto_depIdxs,
+		MessageInfos:      file_reviewer_v1_stats_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_stats_proto = out.File
+	file_reviewer_v1_stats_proto_goTypes = nil
+	file_reviewer_v1_stats_proto_depIdxs = nil
+}
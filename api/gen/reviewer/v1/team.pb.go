@@ -0,0 +1,276 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: reviewer/v1/team.proto
+
+package reviewerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTeamRequest) Reset() {
+	*x = CreateTeamRequest{}
+	mi := &file_reviewer_v1_team_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTeamRequest) ProtoMessage() {}
+
+func (x *CreateTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_team_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTeamRequest.ProtoReflect.Descriptor instead.
+func (*CreateTeamRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_team_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateTeamRequest) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type CreateTeamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTeamResponse) Reset() {
+	*x = CreateTeamResponse{}
+	mi := &file_reviewer_v1_team_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTeamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTeamResponse) ProtoMessage() {}
+
+func (x *CreateTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_team_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTeamResponse.ProtoReflect.Descriptor instead.
+func (*CreateTeamResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_team_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateTeamResponse) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type GetTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamRequest) Reset() {
+	*x = GetTeamRequest{}
+	mi := &file_reviewer_v1_team_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamRequest) ProtoMessage() {}
+
+func (x *GetTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_team_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamRequest.ProtoReflect.Descriptor instead.
+func (*GetTeamRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_team_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTeamRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+type GetTeamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamResponse) Reset() {
+	*x = GetTeamResponse{}
+	mi := &file_reviewer_v1_team_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamResponse) ProtoMessage() {}
+
+func (x *GetTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_team_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamResponse.ProtoReflect.Descriptor instead.
+func (*GetTeamResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_team_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTeamResponse) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+var File_reviewer_v1_team_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_team_proto_rawDesc = "" +
+	"\n" +
+	"\x16reviewer/v1/team.proto\x12\vreviewer.v1\x1a\x17reviewer/v1/types.proto\":\n" +
+	"\x11CreateTeamRequest\x12%\n" +
+	"\x04team\x18\x01 \x01(\v2\x11.reviewer.v1.TeamR\x04team\";\n" +
+	"\x12CreateTeamResponse\x12%\n" +
+	"\x04team\x18\x01 \x01(\v2\x11.reviewer.v1.TeamR\x04team\"-\n" +
+	"\x0eGetTeamRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\"8\n" +
+	"\x0fGetTeamResponse\x12%\n" +
+	"\x04team\x18\x01 \x01(\v2\x11.reviewer.v1.TeamR\x04team2\xa2\x01\n" +
+	"\vTeamService\x12M\n" +
+	"\n" +
+	"CreateTeam\x12\x1e.reviewer.v1.CreateTeamRequest\x1a\x1f.reviewer.v1.CreateTeamResponse\x12D\n" +
+	"\aGetTeam\x12\x1b.reviewer.v1.GetTeamRequest\x1a\x1c.reviewer.v1.GetTeamResponseB,Z*reviewer_pr/api/gen/reviewer/v1;reviewerv1b\x06proto3"
+
+var (
+	file_reviewer_v1_team_proto_rawDescOnce sync.Once
+	file_reviewer_v1_team_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_team_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_team_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_team_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_team_proto_rawDesc), len(file_reviewer_v1_team_proto_rawDesc)))
+	})
+	return file_reviewer_v1_team_proto_rawDescData
+}
+
+var file_reviewer_v1_team_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_reviewer_v1_team_proto_goTypes = []any{
+	(*CreateTeamRequest)(nil),  // 0: reviewer.v1.CreateTeamRequest
+	(*CreateTeamResponse)(nil), // 1: reviewer.v1.CreateTeamResponse
+	(*GetTeamRequest)(nil),     // 2: reviewer.v1.GetTeamRequest
+	(*GetTeamResponse)(nil),    // 3: reviewer.v1.GetTeamResponse
+	(*Team)(nil),               // 4: reviewer.v1.Team
+}
+var file_reviewer_v1_team_proto_depIdxs = []int32{
+	4, // 0: reviewer.v1.CreateTeamRequest.team:type_name -> reviewer.v1.Team
+	4, // 1: reviewer.v1.CreateTeamResponse.team:type_name -> reviewer.v1.Team
+	4, // 2: reviewer.v1.GetTeamResponse.team:type_name -> reviewer.v1.Team
+	0, // 3: reviewer.v1.TeamService.CreateTeam:input_type -> reviewer.v1.CreateTeamRequest
+	2, // 4: reviewer.v1.TeamService.GetTeam:input_type -> reviewer.v1.GetTeamRequest
+	1, // 5: reviewer.v1.TeamService.CreateTeam:output_type -> reviewer.v1.CreateTeamResponse
+	3, // 6: reviewer.v1.TeamService.GetTeam:output_type -> reviewer.v1.GetTeamResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_team_proto_init() }
+func file_reviewer_v1_team_proto_init() {
+	if File_reviewer_v1_team_proto != nil {
+		return
+	}
+	file_reviewer_v1_types_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_team_proto_rawDesc), len(file_reviewer_v1_team_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewer_v1_team_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_team_proto_depIdxs,
+		MessageInfos:      file_reviewer_v1_team_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_team_proto = out.File
+	file_reviewer_v1_team_proto_goTypes = nil
+	file_reviewer_v1_team_proto_depIdxs = nil
+}
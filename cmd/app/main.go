@@ -1,14 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
 	"os"
+	"os/signal"
+	"reviewer_pr/internal/auth"
 	"reviewer_pr/internal/config"
 	"reviewer_pr/internal/database"
+	"reviewer_pr/internal/digest"
+	"reviewer_pr/internal/grpcapi"
 	httpapi "reviewer_pr/internal/http"
 	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/notify"
 	"reviewer_pr/internal/repository"
 	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/scheduler"
+	"reviewer_pr/internal/server"
 	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/tenant"
+	"syscall"
+	"time"
+	// Digest subscriptions use IANA timezones; the runtime image has no tzdata.
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
@@ -16,31 +33,276 @@ import (
 
 func main() {
 	_ = godotenv.Load()
-	isDev := os.Getenv("ENV") == "development"
-	if err := logger.Init(isDev); err != nil {
+
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+
+	fs := flag.NewFlagSet("app", flag.ExitOnError)
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "path to YAML config file")
+	_ = fs.Parse(os.Args[1:])
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := logger.Init(cfg.DevelopmentLogging(), cfg.Log.Level); err != nil {
 		panic(err)
 	}
 
 	defer logger.Sync()
 
 	log := logger.L()
+	log.Info("configuration loaded", zap.String("env", cfg.Env), zap.String("file", *configPath))
 
-	cfg := config.Load(log)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	db := database.ConnectDB(&cfg.DB, log)
+	db, err := database.ConnectDB(ctx, &cfg.DB, log)
+	if err != nil {
+		log.Fatal("Не удалось подключиться к базе данных", zap.Error(err))
+	}
 	defer database.CloseDB(db, log)
 
+	replica, err := database.ConnectReplica(ctx, &cfg.DB, log)
+	if err != nil {
+		log.Fatal("Не удалось подключиться к реплике базы данных", zap.Error(err))
+	}
+	defer database.CloseDB(replica, log)
+
 	if err := database.AutoMigrate(db, log); err != nil {
 		log.Fatal("ошибка запуска автомиграции", zap.Error(err))
 	}
 
-	repos := repository.New(db)
-	services := service.New(repos, log)
+	repos := repository.NewWithReplica(db, replica)
+	tenantIDs := make([]string, 0, len(cfg.Auth.Tenants))
+	for _, t := range cfg.Auth.Tenants {
+		tenantIDs = append(tenantIDs, t.ID)
+	}
+	if err := repos.Tenants.Ensure(ctx, tenantIDs...); err != nil {
+		log.Fatal("failed to register tenants", zap.Error(err))
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("failed to get sql.DB", zap.Error(err))
+	}
+	jobs := scheduler.New(repos.JobRuns, scheduler.NewAdvisoryLocker(sqlDB), log)
+	serviceOpts := []service.Option{
+		service.WithReviewersPerPR(cfg.Assignment.ReviewersPerPR),
+		service.WithAssignmentStrategy(service.AssignmentStrategy(cfg.Assignment.Strategy)),
+		service.WithIdempotencyTTL(cfg.Idempotency.TTL),
+		service.WithScheduler(jobs),
+	}
+	if cfg.Assignment.Seed != 0 {
+		serviceOpts = append(serviceOpts, service.WithRandomSource(rand.NewSource(int64(cfg.Assignment.Seed)))) //nolint:gosec
+	}
+	if cfg.Notifications.Enabled {
+		notifier, err := newNotifier(cfg.Notifications, log)
+		if err != nil {
+			log.Fatal("failed to set up notifications", zap.Error(err))
+		}
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+			defer cancel()
+			if err := notifier.Close(closeCtx); err != nil {
+				log.Warn("pending notifications dropped", zap.Error(err))
+			}
+		}()
+		serviceOpts = append(serviceOpts, service.WithNotifier(notifier))
+	}
+	renderer, err := newDigestRenderer(cfg.Digest.Templates)
+	if err != nil {
+		log.Fatal("failed to load digest templates", zap.Error(err))
+	}
+	serviceOpts = append(serviceOpts, service.WithDigestRenderer(renderer))
+	if cfg.Digest.Enabled {
+		mailer, err := digest.NewSMTP(digest.SMTPConfig{
+			Host:     cfg.Digest.SMTP.Host,
+			Port:     cfg.Digest.SMTP.Port,
+			Username: cfg.Digest.SMTP.Username,
+			Password: cfg.Digest.SMTP.Password,
+			From:     cfg.Digest.SMTP.From,
+		})
+		if err != nil {
+			log.Fatal("failed to set up digest mailer", zap.Error(err))
+		}
+		serviceOpts = append(serviceOpts, service.WithMailer(mailer))
+	}
+	services := service.New(repos, log, serviceOpts...)
 	handlers := httpapi.New(services, log)
 
-	r := router.Router(handlers)
-	port := ":" + cfg.Port
-	if err := r.Run(port); err != nil {
-		log.Fatal("failed to run http server", zap.Error(err))
+	if cfg.Digest.Enabled {
+		if err := addJob(jobs, cfg.Scheduler, "digest", cfg.Digest.Interval, func(ctx context.Context, now time.Time) error {
+			return forEachTenant(ctx, repos.Tenants, func(ctx context.Context) error {
+				_, err := services.Digests.SendDue(ctx, now)
+				return err
+			})
+		}); err != nil {
+			log.Fatal("failed to schedule digest", zap.Error(err))
+		}
+	}
+	if cfg.SLA.Enabled {
+		if err := addJob(jobs, cfg.Scheduler, "sla", cfg.SLA.Interval, func(ctx context.Context, now time.Time) error {
+			return forEachTenant(ctx, repos.Tenants, func(ctx context.Context) error {
+				_, err := services.SLAs.CheckBreaches(ctx, now)
+				return err
+			})
+		}); err != nil {
+			log.Fatal("failed to schedule SLA checks", zap.Error(err))
+		}
+	}
+	if err := addJob(jobs, cfg.Scheduler, "idempotency", cfg.Idempotency.PurgeInterval, func(ctx context.Context, _ time.Time) error {
+		_, err := services.Idempotency.PurgeExpired(ctx)
+		return err
+	}); err != nil {
+		log.Fatal("failed to schedule idempotency key purge", zap.Error(err))
+	}
+	jobs.Start(ctx)
+
+	routerOpts := []router.Option{
+		router.WithSwagger(cfg.Features.Swagger),
+		router.WithStats(cfg.Features.Stats),
+	}
+	grpcOpts := []grpcapi.Option{
+		grpcapi.WithStats(cfg.Features.Stats),
+	}
+	if cfg.Auth.Enabled {
+		authenticator := auth.New(cfg.Auth)
+		routerOpts = append(routerOpts, router.WithAuth(authenticator))
+		grpcOpts = append(grpcOpts, grpcapi.WithAuth(authenticator))
+	}
+
+	r := router.Router(handlers, routerOpts...)
+	srv := server.New(":"+cfg.Server.Port, cfg.Server, r)
+
+	// A failing gRPC listener stops the whole process rather than leaving
+	// a half-working instance behind.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	grpcDone := make(chan struct{})
+	if cfg.GRPC.Enabled {
+		grpcSrv := grpcapi.New(services, log, grpcOpts...)
+		go func() {
+			defer close(grpcDone)
+			if err := server.RunGRPC(ctx, grpcSrv, ":"+cfg.GRPC.Port, cfg.Server.DrainDelay, cfg.Server.ShutdownTimeout, log); err != nil {
+				log.Error("grpc server stopped with error", zap.Error(err))
+				cancel()
+			}
+		}()
+	} else {
+		close(grpcDone)
+	}
+
+	if err := server.Run(ctx, srv, cfg.Server.DrainDelay, cfg.Server.ShutdownTimeout, services.Health.SetShuttingDown, log); err != nil {
+		log.Error("http server stopped with error", zap.Error(err))
+	}
+	cancel()
+	<-grpcDone
+
+	stopCtx, stopJobs := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer stopJobs()
+	if err := jobs.Stop(stopCtx); err != nil {
+		log.Warn("background jobs did not stop in time", zap.Error(err))
+	}
+
+	log.Info("shutdown complete")
+}
+
+func newNotifier(cfg config.Notifications, log *zap.Logger) (*notify.Dispatcher, error) {
+	webhook := notify.NewWebhook(cfg.WebhookURL,
+		notify.WithChannel(cfg.Channel),
+		notify.WithUsername(cfg.Username),
+	)
+	return notify.NewDispatcher(webhook, notify.Config{
+		Templates: notify.Templates{
+			Assigned:      cfg.Templates.Assigned,
+			Reassigned:    cfg.Templates.Reassigned,
+			Merged:        cfg.Templates.Merged,
+			ReviewOverdue: cfg.Templates.ReviewOverdue,
+			MergeOverdue:  cfg.Templates.MergeOverdue,
+		},
+		Handles:      cfg.Handles,
+		QueueSize:    cfg.QueueSize,
+		MaxAttempts:  cfg.MaxAttempts,
+		RetryBackoff: cfg.RetryBackoff,
+	}, log)
+}
+
+// addJob schedules run every interval, or as set in scheduler.schedules.
+func addJob(s *scheduler.Scheduler, cfg config.Scheduler, name string, interval time.Duration, run func(context.Context, time.Time) error) error {
+	schedule := scheduler.Every(interval)
+	if spec, ok := cfg.Schedules[name]; ok {
+		var err error
+		if schedule, err = scheduler.Parse(spec); err != nil {
+			return err
+		}
+	}
+	return s.Add(scheduler.Job{Name: name, Schedule: schedule, Run: run})
+}
+
+// forEachTenant runs a background job for every tenant in turn; a failing
+// tenant does not stop the others.
+func forEachTenant(ctx context.Context, tenants repository.TenantsRepo, run func(context.Context) error) error {
+	list, err := tenants.List(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, t := range list {
+		if err := run(tenant.WithContext(ctx, t.ID)); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", t.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func newDigestRenderer(cfg config.DigestTemplates) (*digest.Renderer, error) {
+	t := digest.Templates{Subject: cfg.Subject}
+	for _, f := range []struct {
+		path string
+		dst  *string
+	}{
+		{cfg.TextFile, &t.Text},
+		{cfg.HTMLFile, &t.HTML},
+	} {
+		if f.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(f.path) //nolint:gosec // path is provided by the operator
+		if err != nil {
+			return nil, err
+		}
+		*f.dst = string(data)
+	}
+	return digest.NewRenderer(t)
+}
+
+// runConfigCommand implements "app config print", which shows the effective
+// configuration with secrets redacted.
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: app config print [-config path]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config print", flag.ExitOnError)
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "path to YAML config file")
+	_ = fs.Parse(args[1:])
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
+	fmt.Print(string(out))
+	return 0
 }
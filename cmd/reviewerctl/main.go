@@ -0,0 +1,11 @@
+// reviewerctl is the admin CLI for the PR reviewer assignment service.
+package main
+
+import (
+	"os"
+	"reviewer_pr/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Execute())
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"reviewer_pr/internal/config"
+	"reviewer_pr/internal/tenant"
+	"strings"
+)
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+// Authenticator resolves static bearer tokens from the configuration to roles
+// and tenants.
+type Authenticator struct {
+	credentials []credential
+}
+
+type credential struct {
+	token  []byte
+	role   Role
+	tenant string
+}
+
+func New(cfg config.AuthConfig) *Authenticator {
+	a := &Authenticator{}
+	a.add(cfg.AdminToken, RoleAdmin, tenant.Default)
+	a.add(cfg.UserToken, RoleUser, tenant.Default)
+	for _, t := range cfg.Tenants {
+		a.add(t.AdminToken, RoleAdmin, t.ID)
+		a.add(t.UserToken, RoleUser, t.ID)
+	}
+	return a
+}
+
+func (a *Authenticator) add(token string, role Role, tenantID string) {
+	if token != "" {
+		a.credentials = append(a.credentials, credential{token: []byte(token), role: role, tenant: tenantID})
+	}
+}
+
+// Authenticate returns the role and tenant of token. Every credential is
+// compared so that the time taken does not tell which one matched.
+func (a *Authenticator) Authenticate(token string) (Role, string, bool) {
+	if token == "" {
+		return "", "", false
+	}
+	t := []byte(token)
+	var match *credential
+	for i := range a.credentials {
+		if subtle.ConstantTimeCompare(t, a.credentials[i].token) == 1 && match == nil {
+			match = &a.credentials[i]
+		}
+	}
+	if match == nil {
+		return "", "", false
+	}
+	return match.role, match.tenant, true
+}
+
+// Allows reports whether a caller with role r may act as required.
+func (r Role) Allows(required Role) bool {
+	return r == RoleAdmin || r == required
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header value.
+func BearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
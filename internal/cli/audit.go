@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"reviewer_pr/pkg/client"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) auditCommand() *cobra.Command {
+	var q client.AuditQuery
+	var since, until string
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show reviewer changes across pull requests, newest first",
+		Long: "Show the assignment history of every pull request: automatic picks with the strategy and " +
+			"seed that replay them, and manual additions, removals and reassignments. --since and --until " +
+			"take an RFC 3339 timestamp or a duration before now, e.g. 24h.",
+		Example: "  reviewerctl audit --since 24h\n" +
+			"  reviewerctl audit --user u3 --kind removed -o json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			var err error
+			if q.Since, err = parseTimeFlag("since", since, now); err != nil {
+				return err
+			}
+			if q.Until, err = parseTimeFlag("until", until, now); err != nil {
+				return err
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.ListAssignmentEvents(ctx, q)
+			if err != nil {
+				return err
+			}
+			return a.render(res, func(w io.Writer) error {
+				rows := make([][]string, 0, len(res.Events))
+				for _, e := range res.Events {
+					seed := "-"
+					if e.Strategy != "" {
+						seed = strconv.FormatInt(e.Seed, 10)
+					}
+					rows = append(rows, []string{
+						formatTime(&e.CreatedAt), e.PullRequestID, e.Kind, e.ReviewerID,
+						orDash(e.PreviousReviewerID), orDash(e.Reason), orDash(e.RequestedBy),
+						orDash(e.Strategy), seed,
+					})
+				}
+				if err := writeTable(w, []string{"AT", "PR", "KIND", "REVIEWER", "REPLACED", "REASON", "REQUESTED_BY", "STRATEGY", "SEED"}, rows); err != nil {
+					return err
+				}
+				writeMore(w, res.Total, res.Offset, len(res.Events))
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&q.PullRequestID, "pr", "", "only events of this pull request")
+	cmd.Flags().StringVar(&q.UserID, "user", "", "only events where the user is the reviewer, the replaced reviewer or the requester")
+	cmd.Flags().StringVar(&q.Kind, "kind", "", "only events of this kind: assigned, added, removed or reassigned")
+	cmd.Flags().StringVar(&since, "since", "", "only events at or after this time")
+	cmd.Flags().StringVar(&until, "until", "", "only events before this time")
+	pageFlags(cmd, &q.Page)
+	_ = cmd.RegisterFlagCompletionFunc("kind", cobra.FixedCompletions([]string{"assigned", "added", "removed", "reassigned"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// parseTimeFlag accepts an RFC 3339 timestamp or a duration before now.
+func parseTimeFlag(name, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("--%s: want an RFC 3339 timestamp or a duration such as 24h, got %q", name, value)
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reviewer_pr/pkg/client"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) exportCommand() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all data as NDJSON",
+		Long: "Export teams, users, pull requests and reviewer assignments as NDJSON. " +
+			"The export is written to stdout unless --file is set; an incomplete export is removed. " +
+			"--timeout applies only when set explicitly.",
+		Example: "  reviewerctl export -f backup.ndjson\n" +
+			"  reviewerctl export | gzip > backup.ndjson.gz",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.streamContext(cmd)
+			defer cancel()
+
+			if file == "" {
+				counts, err := c.Export(ctx, a.out)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(a.errOut, "exported %s\n", countsSummary(counts))
+				return nil
+			}
+
+			f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			counts, err := c.Export(ctx, f)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(file)
+				return err
+			}
+			return a.render(counts, countsTable(counts))
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "write the export to FILE instead of stdout")
+	return cmd
+}
+
+func (a *app) restoreCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore FILE",
+		Short: "Restore an NDJSON export into an empty database",
+		Long: "Restore an export made with \"reviewerctl export\". The database must be empty; " +
+			"the restore is applied in one transaction. --timeout applies only when set explicitly.",
+		Example: "  reviewerctl restore backup.ndjson\n" +
+			"  gunzip -c backup.ndjson.gz | reviewerctl restore -",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var in io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.streamContext(cmd)
+			defer cancel()
+
+			counts, err := c.Restore(ctx, in)
+			if err != nil {
+				return err
+			}
+			return a.render(counts, countsTable(counts))
+		},
+	}
+}
+
+// streamContext is like context, but export and restore may run far longer
+// than the default timeout, so only an explicit --timeout bounds them.
+func (a *app) streamContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	if f := cmd.Flag("timeout"); f != nil && f.Changed {
+		return a.context(cmd)
+	}
+	return context.WithCancel(cmd.Context())
+}
+
+func countsTable(c *client.BackupCounts) func(io.Writer) error {
+	return func(w io.Writer) error {
+		return writeTable(w, []string{"TEAMS", "USERS", "MEMBERSHIPS", "DIGESTS", "SLAS", "CODE_OWNERS", "SKILLS", "EXCLUSIONS", "PULL_REQUESTS", "REVIEWERS", "EVENTS"}, [][]string{{
+			strconv.FormatInt(c.Teams, 10),
+			strconv.FormatInt(c.Users, 10),
+			strconv.FormatInt(c.Memberships, 10),
+			strconv.FormatInt(c.Digests, 10),
+			strconv.FormatInt(c.TeamSLAs, 10),
+			strconv.FormatInt(c.CodeOwners, 10),
+			strconv.FormatInt(c.Skills, 10),
+			strconv.FormatInt(c.Exclusions, 10),
+			strconv.FormatInt(c.PullRequests, 10),
+			strconv.FormatInt(c.Reviewers, 10),
+			strconv.FormatInt(c.Events, 10),
+		}})
+	}
+}
+
+func countsSummary(c *client.BackupCounts) string {
+	return fmt.Sprintf("%d teams, %d users, %d memberships, %d digest subscriptions, %d team SLAs, %d code owner rules, %d user skills, %d reviewer exclusions, %d pull requests, %d reviewers, %d assignment events",
+		c.Teams, c.Users, c.Memberships, c.Digests, c.TeamSLAs, c.CodeOwners, c.Skills, c.Exclusions, c.PullRequests, c.Reviewers, c.Events)
+}
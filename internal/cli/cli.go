@@ -0,0 +1,141 @@
+// Package cli implements reviewerctl, the admin CLI that operates the service
+// through its HTTP API (see pkg/client).
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reviewer_pr/pkg/client"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultServer = "http://localhost:8080"
+
+type app struct {
+	out    io.Writer
+	errOut io.Writer
+
+	configPath string
+	profile    string
+	server     string
+	token      string
+	output     string
+	timeout    time.Duration
+}
+
+// Execute runs reviewerctl with os.Args and returns the process exit code.
+func Execute() int {
+	cmd := NewRootCommand(os.Stdout, os.Stderr)
+	cmd.SetArgs(os.Args[1:])
+	return Run(cmd)
+}
+
+// Run executes cmd and reports API errors the way reviewerctl prints them.
+func Run(cmd *cobra.Command) int {
+	if err := cmd.Execute(); err != nil {
+		printError(cmd.ErrOrStderr(), err)
+		return 1
+	}
+	return 0
+}
+
+func NewRootCommand(out, errOut io.Writer) *cobra.Command {
+	a := &app{out: out, errOut: errOut}
+
+	root := &cobra.Command{
+		Use:           "reviewerctl",
+		Short:         "Operate the PR reviewer assignment service",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.SetOut(out)
+	root.SetErr(errOut)
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", defaultConfigPath(), "path to the reviewerctl config file")
+	flags.StringVarP(&a.profile, "profile", "p", os.Getenv("REVIEWERCTL_PROFILE"), "config profile to use (default: current profile)")
+	flags.StringVar(&a.server, "server", os.Getenv("REVIEWERCTL_SERVER"), "API base URL, overrides the profile")
+	flags.StringVar(&a.token, "token", os.Getenv("REVIEWERCTL_TOKEN"), "bearer token, overrides the profile")
+	flags.StringVarP(&a.output, "output", "o", formatTable, "output format: table, json or yaml")
+	flags.DurationVar(&a.timeout, "timeout", 10*time.Second, "request timeout")
+
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp))
+	_ = root.RegisterFlagCompletionFunc("profile", a.completeProfiles)
+
+	root.AddCommand(
+		a.configCommand(),
+		a.teamCommand(),
+		a.userCommand(),
+		a.prCommand(),
+		a.statsCommand(),
+		a.jobsCommand(),
+		a.auditCommand(),
+		a.exportCommand(),
+		a.restoreCommand(),
+	)
+	return root
+}
+
+// client builds an API client from flags, environment and the selected profile,
+// in that order of precedence.
+func (a *app) client() (*client.Client, error) {
+	if err := validateFormat(a.output); err != nil {
+		return nil, err
+	}
+
+	cfg, err := LoadConfig(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+	p, err := cfg.Resolve(a.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	server := firstNonEmpty(a.server, p.Server, defaultServer)
+	token := firstNonEmpty(a.token, p.Token)
+
+	return client.New(server,
+		client.WithToken(token),
+		client.WithUserAgent("reviewerctl"),
+	)
+}
+
+func (a *app) context(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), a.timeout)
+}
+
+func printError(w io.Writer, err error) {
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		fmt.Fprintln(w, "Error:", err)
+		return
+	}
+
+	fmt.Fprintf(w, "Error: %s: %s\n", apiErr.Code, apiErr.Message)
+	for _, d := range apiErr.Details {
+		fmt.Fprintf(w, "  %s: %s\n", d.Field, d.Message)
+	}
+	if apiErr.RequestID != "" {
+		fmt.Fprintln(w, "request id:", apiErr.RequestID)
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func pageFlags(cmd *cobra.Command, page *client.Page) {
+	cmd.Flags().IntVar(&page.Limit, "limit", 0, "page size (default: server default)")
+	cmd.Flags().IntVar(&page.Offset, "offset", 0, "number of items to skip")
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"reviewer_pr/pkg/client"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) teamOwnersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "owners",
+		Aliases: []string{"codeowners"},
+		Short:   "Manage a team's code ownership rules",
+	}
+
+	get := &cobra.Command{
+		Use:   "get TEAM",
+		Short: "Show a team's code ownership rules",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.ownersCall(cmd, func(c *client.Client, ctx context.Context) (*client.CodeOwners, error) {
+				return c.GetCodeOwners(ctx, args[0])
+			})
+		},
+	}
+
+	importCmd := &cobra.Command{
+		Use:   "import TEAM FILE",
+		Short: "Replace a team's rules with a CODEOWNERS file",
+		Long: "Replace a team's rules with a CODEOWNERS file. Owners are written as @user_id or " +
+			"@org/team_name; the organization is ignored. Use \"-\" to read the file from stdin.",
+		Example: "  reviewerctl team owners import backend .github/CODEOWNERS",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readInput(cmd, args[1])
+			if err != nil {
+				return err
+			}
+			return a.ownersCall(cmd, func(c *client.Client, ctx context.Context) (*client.CodeOwners, error) {
+				return c.ImportCodeOwners(ctx, args[0], data)
+			})
+		},
+	}
+
+	remove := &cobra.Command{
+		Use:   "clear TEAM",
+		Short: "Remove a team's code ownership rules",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.ownersCall(cmd, func(c *client.Client, ctx context.Context) (*client.CodeOwners, error) {
+				return c.SetCodeOwners(ctx, client.CodeOwners{TeamName: args[0]})
+			})
+		},
+	}
+
+	cmd.AddCommand(get, importCmd, remove)
+	return cmd
+}
+
+// ownersCall runs fn and prints the resulting rules in order.
+func (a *app) ownersCall(cmd *cobra.Command, fn func(*client.Client, context.Context) (*client.CodeOwners, error)) error {
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.context(cmd)
+	defer cancel()
+
+	o, err := fn(c, ctx)
+	if err != nil {
+		return err
+	}
+	return a.render(o, func(w io.Writer) error {
+		rows := make([][]string, 0, len(o.Rules))
+		for i, r := range o.Rules {
+			owners := append([]string{}, r.Users...)
+			for _, t := range r.Teams {
+				owners = append(owners, "team:"+t)
+			}
+			rows = append(rows, []string{strconv.Itoa(i + 1), r.Pattern, strings.Join(owners, " ")})
+		}
+		return writeTable(w, []string{"#", "PATTERN", "OWNERS"}, rows)
+	})
+}
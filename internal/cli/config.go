@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultProfile = "default"
+
+type Profile struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token,omitempty"`
+}
+
+// Config is the reviewerctl config file: named server/token profiles and the
+// one used when --profile is not given.
+type Config struct {
+	CurrentProfile string             `yaml:"current_profile,omitempty"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+// defaultConfigPath is $REVIEWERCTL_CONFIG or <user config dir>/reviewerctl/config.yml.
+func defaultConfigPath() string {
+	if p := os.Getenv("REVIEWERCTL_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "reviewerctl.yml"
+	}
+	return filepath.Join(dir, "reviewerctl", "config.yml")
+}
+
+// LoadConfig reads the config file; a missing file yields an empty config.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{Profiles: map[string]Profile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]Profile{}
+	}
+	return cfg, nil
+}
+
+// Save writes the config with owner-only permissions, since it holds tokens.
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// Resolve returns the named profile, or the current one when name is empty.
+// Having no profiles at all is fine: flags and defaults are used instead.
+func (c *Config) Resolve(name string) (Profile, error) {
+	if name == "" {
+		name = firstNonEmpty(c.CurrentProfile, defaultProfile)
+		p := c.Profiles[name]
+		return p, nil
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found in config", name)
+	}
+	return p, nil
+}
+
+func (c *Config) profileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (a *app) configCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage connection profiles",
+	}
+
+	var server, token string
+	var use bool
+	setProfile := &cobra.Command{
+		Use:   "set-profile NAME",
+		Short: "Create or update a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfig(a.configPath)
+			if err != nil {
+				return err
+			}
+
+			p := cfg.Profiles[args[0]]
+			if cmd.Flags().Changed("server") {
+				p.Server = server
+			}
+			if cmd.Flags().Changed("token") {
+				p.Token = token
+			}
+			cfg.Profiles[args[0]] = p
+			if use || cfg.CurrentProfile == "" {
+				cfg.CurrentProfile = args[0]
+			}
+			return cfg.Save(a.configPath)
+		},
+	}
+	setProfile.Flags().StringVar(&server, "server", "", "API base URL")
+	setProfile.Flags().StringVar(&token, "token", "", "bearer token")
+	setProfile.Flags().BoolVar(&use, "use", false, "make it the current profile")
+
+	useProfile := &cobra.Command{
+		Use:               "use NAME",
+		Short:             "Switch the current profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfig(a.configPath)
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found in config", args[0])
+			}
+			cfg.CurrentProfile = args[0]
+			return cfg.Save(a.configPath)
+		},
+	}
+
+	view := &cobra.Command{
+		Use:   "view",
+		Short: "Show profiles with tokens masked",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfig(a.configPath)
+			if err != nil {
+				return err
+			}
+
+			rows := make([][]string, 0, len(cfg.Profiles))
+			for _, name := range cfg.profileNames() {
+				p := cfg.Profiles[name]
+				current := ""
+				if name == cfg.CurrentProfile {
+					current = "*"
+				}
+				rows = append(rows, []string{current, name, p.Server, maskToken(p.Token)})
+			}
+			return writeTable(a.out, []string{"CURRENT", "NAME", "SERVER", "TOKEN"}, rows)
+		},
+	}
+
+	cmd.AddCommand(setProfile, useProfile, view)
+	return cmd
+}
+
+func (a *app) completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := LoadConfig(a.configPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return cfg.profileNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func maskToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	if len(token) <= 4 {
+		return "****"
+	}
+	return "****" + token[len(token)-4:]
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reviewer_pr/pkg/client"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) digestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Manage daily email digests of pending reviews",
+	}
+
+	get := &cobra.Command{
+		Use:   "get USER_ID",
+		Short: "Show a user's digest subscription",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.digestCall(cmd, func(c *client.Client, ctx context.Context) (*client.DigestSubscription, error) {
+				return c.GetDigest(ctx, args[0])
+			})
+		},
+	}
+
+	var sub client.DigestSubscription
+	set := &cobra.Command{
+		Use:     "set USER_ID --email EMAIL",
+		Short:   "Subscribe a user or change the schedule",
+		Example: "  reviewerctl user digest set u2 --email bob@example.com --at 09:30 --tz Europe/Moscow",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sub.UserID, sub.Enabled = args[0], true
+			return a.digestCall(cmd, func(c *client.Client, ctx context.Context) (*client.DigestSubscription, error) {
+				return c.SetDigest(ctx, sub)
+			})
+		},
+	}
+	set.Flags().StringVar(&sub.Email, "email", "", "recipient address")
+	set.Flags().StringVar(&sub.SendAt, "at", "", "local time of day, HH:MM (default 09:00)")
+	set.Flags().StringVar(&sub.Timezone, "tz", "", "IANA time zone (default UTC)")
+	_ = set.MarkFlagRequired("email")
+
+	off := &cobra.Command{
+		Use:   "off USER_ID",
+		Short: "Unsubscribe a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.digestCall(cmd, func(c *client.Client, ctx context.Context) (*client.DigestSubscription, error) {
+				return c.SetDigest(ctx, client.DigestSubscription{UserID: args[0]})
+			})
+		},
+	}
+
+	var html bool
+	preview := &cobra.Command{
+		Use:   "preview USER_ID",
+		Short: "Print the digest a user would get now, without sending it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			p, err := c.PreviewDigest(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(p, func(w io.Writer) error {
+				if html {
+					_, err := io.WriteString(w, p.HTML)
+					return err
+				}
+				_, err := fmt.Fprintf(w, "Subject: %s\n\n%s", p.Subject, p.Text)
+				return err
+			})
+		},
+	}
+	preview.Flags().BoolVar(&html, "html", false, "print the HTML body instead of the text one")
+
+	cmd.AddCommand(get, set, off, preview)
+	return cmd
+}
+
+// digestCall runs fn and prints the resulting subscription.
+func (a *app) digestCall(cmd *cobra.Command, fn func(*client.Client, context.Context) (*client.DigestSubscription, error)) error {
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.context(cmd)
+	defer cancel()
+
+	s, err := fn(c, ctx)
+	if err != nil {
+		return err
+	}
+	return a.render(s, func(w io.Writer) error {
+		row := []string{s.UserID, yesNo(s.Enabled), s.Email, s.SendAt, s.Timezone, "-"}
+		if s.LastSentAt != nil {
+			row[5] = s.LastSentAt.Local().Format(time.RFC3339)
+		}
+		return writeTable(w, []string{"USER_ID", "ENABLED", "EMAIL", "SEND_AT", "TIMEZONE", "LAST_SENT"}, [][]string{row})
+	})
+}
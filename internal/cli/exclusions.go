@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"reviewer_pr/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) exclusionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exclusions",
+		Short: "Manage author-reviewer pairs that are never assigned",
+	}
+
+	list := &cobra.Command{
+		Use:     "list [USER_ID]",
+		Aliases: []string{"ls"},
+		Short:   "List exclusions, optionally only those involving a user",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			userID := ""
+			if len(args) > 0 {
+				userID = args[0]
+			}
+			return a.exclusionsCall(cmd, func(c *client.Client, ctx context.Context) ([]client.Exclusion, error) {
+				return c.GetExclusions(ctx, userID)
+			})
+		},
+	}
+
+	var reason string
+	add := &cobra.Command{
+		Use:     "add AUTHOR_ID REVIEWER_ID",
+		Short:   "Never assign REVIEWER_ID to pull requests of AUTHOR_ID",
+		Example: "  reviewerctl user exclusions add u1 u3 --reason \"pair programming\"",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.exclusionsCall(cmd, func(c *client.Client, ctx context.Context) ([]client.Exclusion, error) {
+				return c.AddExclusion(ctx, client.Exclusion{AuthorID: args[0], ReviewerID: args[1], Reason: reason})
+			})
+		},
+	}
+	add.Flags().StringVar(&reason, "reason", "", "why the pair is excluded")
+
+	remove := &cobra.Command{
+		Use:     "rm AUTHOR_ID REVIEWER_ID",
+		Aliases: []string{"remove"},
+		Short:   "Remove an exclusion",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.exclusionsCall(cmd, func(c *client.Client, ctx context.Context) ([]client.Exclusion, error) {
+				return c.RemoveExclusion(ctx, args[0], args[1])
+			})
+		},
+	}
+
+	cmd.AddCommand(list, add, remove)
+	return cmd
+}
+
+// exclusionsCall runs fn and prints the resulting exclusions.
+func (a *app) exclusionsCall(cmd *cobra.Command, fn func(*client.Client, context.Context) ([]client.Exclusion, error)) error {
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.context(cmd)
+	defer cancel()
+
+	res, err := fn(c, ctx)
+	if err != nil {
+		return err
+	}
+	return a.render(res, func(w io.Writer) error {
+		rows := make([][]string, 0, len(res))
+		for _, e := range res {
+			rows = append(rows, []string{e.AuthorID, e.ReviewerID, orDash(e.Reason)})
+		}
+		return writeTable(w, []string{"AUTHOR", "REVIEWER", "REASON"}, rows)
+	})
+}
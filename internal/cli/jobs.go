@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"io"
+	"reviewer_pr/pkg/client"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) jobsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Inspect and trigger background jobs",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List background jobs with their schedule and last run",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			jobs, err := c.ListJobs(ctx)
+			if err != nil {
+				return err
+			}
+			return a.render(jobs, func(w io.Writer) error {
+				rows := make([][]string, 0, len(jobs))
+				for _, j := range jobs {
+					last, status := "-", "-"
+					if j.LastRun != nil {
+						last, status = formatTime(&j.LastRun.StartedAt), j.LastRun.Status
+					}
+					rows = append(rows, []string{j.Job, j.Schedule, formatTime(j.NextRunAt), yesNo(j.Running), last, status})
+				}
+				return writeTable(w, []string{"JOB", "SCHEDULE", "NEXT_RUN", "RUNNING", "LAST_RUN", "STATUS"}, rows)
+			})
+		},
+	}
+
+	var q client.JobRunsQuery
+	runs := &cobra.Command{
+		Use:   "runs [JOB]",
+		Short: "Show the run history, newest first",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				q.Job = args[0]
+			}
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.ListJobRuns(ctx, q)
+			if err != nil {
+				return err
+			}
+			return a.render(res, func(w io.Writer) error {
+				rows := make([][]string, 0, len(res.Runs))
+				for _, r := range res.Runs {
+					rows = append(rows, jobRunRow(&r))
+				}
+				if err := writeTable(w, jobRunHeader, rows); err != nil {
+					return err
+				}
+				writeMore(w, res.Total, res.Offset, len(res.Runs))
+				return nil
+			})
+		},
+	}
+	pageFlags(runs, &q.Page)
+
+	run := &cobra.Command{
+		Use:     "run JOB",
+		Short:   "Run a job now, outside its schedule",
+		Long:    "Run a job now. The command returns once the run has started; use \"jobs runs JOB\" to see its outcome.",
+		Example: "  reviewerctl jobs run sla",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			r, err := c.TriggerJob(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(r, func(w io.Writer) error {
+				return writeTable(w, jobRunHeader, [][]string{jobRunRow(r)})
+			})
+		},
+	}
+
+	cmd.AddCommand(list, runs, run)
+	return cmd
+}
+
+var jobRunHeader = []string{"RUN_ID", "JOB", "TRIGGER", "STATUS", "STARTED", "DURATION", "ERROR"}
+
+func jobRunRow(r *client.JobRun) []string {
+	duration := "-"
+	if r.FinishedAt != nil {
+		duration = (time.Duration(r.DurationMs) * time.Millisecond).String()
+	}
+	return []string{
+		strconv.FormatUint(r.RunID, 10), r.Job, r.Trigger, r.Status,
+		formatTime(&r.StartedAt), duration, r.Error,
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"reviewer_pr/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) teamMemberCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "member",
+		Short: "Manage memberships of users in teams",
+		Long: "A user belongs to their primary team and may be a member of other teams. " +
+			"Reviewers are picked among the active members of all teams of the author.",
+	}
+
+	var role string
+	var active bool
+	set := &cobra.Command{
+		Use:   "set TEAM USER_ID",
+		Short: "Add an existing user to a team or change their membership",
+		Long: "Add an existing user to a team or change their role and membership activity. " +
+			"Omitted flags keep the current values; a new membership is an active member.",
+		Example: "  reviewerctl team member set payments u1 --role lead\n" +
+			"  reviewerctl team member set payments u1 --active=false",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m := client.TeamMembership{TeamName: args[0], UserID: args[1], Role: role}
+			if cmd.Flags().Changed("active") {
+				m.IsActive = &active
+			}
+			return a.memberCall(cmd, func(c *client.Client, ctx context.Context) (*client.Team, error) {
+				return c.SetTeamMember(ctx, m)
+			})
+		},
+	}
+	set.Flags().StringVar(&role, "role", "", "role in the team: member or lead")
+	set.Flags().BoolVar(&active, "active", true, "whether the user is picked as a reviewer for this team")
+
+	remove := &cobra.Command{
+		Use:     "rm TEAM USER_ID",
+		Aliases: []string{"remove"},
+		Short:   "Remove a user from a team other than their primary one",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.memberCall(cmd, func(c *client.Client, ctx context.Context) (*client.Team, error) {
+				return c.RemoveTeamMember(ctx, args[0], args[1])
+			})
+		},
+	}
+
+	cmd.AddCommand(set, remove)
+	return cmd
+}
+
+// memberCall runs fn and prints the resulting team.
+func (a *app) memberCall(cmd *cobra.Command, fn func(*client.Client, context.Context) (*client.Team, error)) error {
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.context(cmd)
+	defer cancel()
+
+	t, err := fn(c, ctx)
+	if err != nil {
+		return err
+	}
+	return a.render(t, teamTable(t))
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+var formats = []string{formatTable, formatJSON, formatYAML}
+
+func validateFormat(f string) error {
+	for _, known := range formats {
+		if f == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q (want %s)", f, strings.Join(formats, ", "))
+}
+
+// render prints v as JSON or YAML, or calls table for the table format.
+// YAML is produced from the JSON encoding so field names match the API.
+func (a *app) render(v any, table func(w io.Writer) error) error {
+	switch a.output {
+	case formatJSON:
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return err
+		}
+		blockStyle(&node)
+		enc := yaml.NewEncoder(a.out)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return table(a.out)
+	}
+}
+
+// blockStyle drops the flow style yaml.v3 keeps when it parses JSON.
+func blockStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+func writeTable(out io.Writer, header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// writeMore tells how to fetch the next page when a list was cut short.
+func writeMore(w io.Writer, total int64, offset, shown int) {
+	next := offset + shown
+	if shown == 0 || int64(next) >= total {
+		return
+	}
+	fmt.Fprintf(w, "\n%d of %d shown, next page: --offset %d\n", next, total, next)
+}
@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"reviewer_pr/pkg/client"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) prCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pr",
+		Aliases: []string{"pull-request"},
+		Short:   "Manage pull requests",
+	}
+
+	var name, author string
+	var files, labels []string
+	create := &cobra.Command{
+		Use:   "create ID",
+		Short: "Create a pull request and assign reviewers",
+		Long: "Create a pull request and assign reviewers. With --file, the code owners of the changed " +
+			"paths are assigned first (see \"team owners\"). With --label, reviewers whose skills match " +
+			"the labels are preferred (see \"user skills\"). The table explains why each reviewer was picked.",
+		Example: "  reviewerctl pr create pr-1 --name \"Add search\" --author u1 --label go --label postgres\n" +
+			"  git diff --name-only main | reviewerctl pr create pr-2 --name Billing --author u1 --file -",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			changed, err := changedFiles(cmd, files)
+			if err != nil {
+				return err
+			}
+			res, err := c.CreatePullRequestWithReasons(ctx, client.CreatePullRequest{
+				PullRequestID:   args[0],
+				PullRequestName: name,
+				AuthorID:        author,
+				ChangedFiles:    changed,
+				Labels:          labels,
+			})
+			if err != nil {
+				return err
+			}
+			// Structured output stays the pull request itself; the reasons
+			// are only added to the table.
+			return a.render(&res.PullRequest, func(w io.Writer) error {
+				if err := prTable(&res.PullRequest)(w); err != nil {
+					return err
+				}
+				if len(res.Assignment) > 0 {
+					fmt.Fprintln(w)
+					if err := assignmentTable(w, res.Assignment); err != nil {
+						return err
+					}
+				}
+				printSkipped(w, res.Skipped)
+				return nil
+			})
+		},
+	}
+	create.Flags().StringVar(&name, "name", "", "pull request title")
+	create.Flags().StringVar(&author, "author", "", "author user ID")
+	create.Flags().StringArrayVar(&files, "file", nil, "changed file path (repeatable); \"-\" reads one path per line from stdin")
+	create.Flags().StringArrayVar(&labels, "label", nil, "pull request label matched against reviewers' skills (repeatable)")
+	_ = create.MarkFlagRequired("name")
+	_ = create.MarkFlagRequired("author")
+
+	get := &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a pull request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			pr, err := c.V2GetPullRequest(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(pr, prTable(pr))
+		},
+	}
+
+	merge := &cobra.Command{
+		Use:   "merge ID",
+		Short: "Mark a pull request as merged",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			pr, err := c.MergePullRequest(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(pr, prTable(pr))
+		},
+	}
+
+	var oldReviewer string
+	var newReviewer, requestedBy string
+	reassign := &cobra.Command{
+		Use:   "reassign ID",
+		Short: "Replace a reviewer with a named user or another member of their team",
+		Example: "  reviewerctl pr reassign pr-1 --old u2\n" +
+			"  reviewerctl pr reassign pr-1 --old u2 --to u5 --by u1",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.ReassignReviewerTo(ctx, client.ReviewerReassign{
+				PullRequestID: args[0],
+				OldReviewerID: oldReviewer,
+				NewReviewerID: newReviewer,
+				RequestedBy:   requestedBy,
+			})
+			if err != nil {
+				return err
+			}
+			return a.render(res, func(w io.Writer) error {
+				fmt.Fprintf(w, "Replaced %s with %s (%s)\n", oldReviewer, res.ReplacedBy, res.Reason)
+				printSkipped(w, res.Skipped)
+				return prTable(&res.PullRequest)(w)
+			})
+		},
+	}
+	reassign.Flags().StringVar(&oldReviewer, "old", "", "reviewer user ID to replace")
+	reassign.Flags().StringVar(&newReviewer, "to", "", "user ID of the new reviewer (default: picked from the team)")
+	reassign.Flags().StringVar(&requestedBy, "by", "", "user ID requesting the change, recorded in the history")
+	_ = reassign.MarkFlagRequired("old")
+
+	cmd.AddCommand(create, get, merge, reassign, a.prReviewersCommand(), a.prHistoryCommand(), a.prSimulateCommand(), a.prOverdueCommand())
+	return cmd
+}
+
+func prTable(pr *client.PullRequest) func(io.Writer) error {
+	return func(w io.Writer) error {
+		merged := ""
+		if pr.MergedAt != nil {
+			merged = pr.MergedAt.Format(time.RFC3339)
+		}
+		return writeTable(w,
+			[]string{"ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS", "MERGED_AT"},
+			[][]string{{
+				pr.PullRequestID, pr.PullRequestName, pr.AuthorID, string(pr.Status),
+				strings.Join(pr.AssignedReviewers, ","), merged,
+			}},
+		)
+	}
+}
+
+// assignmentTable explains why each reviewer was picked.
+func assignmentTable(w io.Writer, picks []client.ReviewerPick) error {
+	rows := make([][]string, 0, len(picks))
+	for _, p := range picks {
+		detail := p.Pattern
+		if len(p.MatchedSkills) > 0 {
+			skills := make([]string, 0, len(p.MatchedSkills))
+			for _, s := range p.MatchedSkills {
+				if s.Proficiency > 0 {
+					skills = append(skills, fmt.Sprintf("%s:%d", s.Skill, s.Proficiency))
+				} else {
+					skills = append(skills, s.Skill)
+				}
+			}
+			detail = strings.TrimSpace(detail + " " + strings.Join(skills, ","))
+		}
+		rows = append(rows, []string{p.UserID, p.Reason, detail, strconv.FormatInt(p.OpenReviews, 10)})
+	}
+	return writeTable(w, []string{"REVIEWER", "REASON", "MATCHED", "OPEN_REVIEWS"}, rows)
+}
+
+// printSkipped lists the candidates that exclusion rules kept out.
+func printSkipped(w io.Writer, skipped []client.SkippedCandidate) {
+	if len(skipped) == 0 {
+		return
+	}
+	parts := make([]string, 0, len(skipped))
+	for _, s := range skipped {
+		parts = append(parts, fmt.Sprintf("%s (%s)", s.UserID, s.Reason))
+	}
+	fmt.Fprintf(w, "Skipped: %s\n", strings.Join(parts, ", "))
+}
+
+// changedFiles expands "-" in --file into the paths listed on stdin.
+func changedFiles(cmd *cobra.Command, files []string) ([]string, error) {
+	var out []string
+	for _, f := range files {
+		if f != "-" {
+			out = append(out, f)
+			continue
+		}
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				out = append(out, line)
+			}
+		}
+	}
+	return out, nil
+}
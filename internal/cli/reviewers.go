@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reviewer_pr/pkg/client"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) prReviewersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reviewers",
+		Short: "Add or remove named reviewers",
+	}
+
+	var requestedBy string
+	add := &cobra.Command{
+		Use:     "add ID USER_ID",
+		Short:   "Assign a named reviewer in addition to the current ones",
+		Example: "  reviewerctl pr reviewers add pr-1 u3 --by u1",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.reviewerCall(cmd, func(c *client.Client, ctx context.Context) (*client.PullRequest, error) {
+				return c.AddReviewer(ctx, client.ReviewerChange{PullRequestID: args[0], ReviewerID: args[1], RequestedBy: requestedBy})
+			})
+		},
+	}
+
+	remove := &cobra.Command{
+		Use:     "rm ID USER_ID",
+		Aliases: []string{"remove"},
+		Short:   "Unassign a reviewer without a replacement",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.reviewerCall(cmd, func(c *client.Client, ctx context.Context) (*client.PullRequest, error) {
+				return c.RemoveReviewer(ctx, client.ReviewerChange{PullRequestID: args[0], ReviewerID: args[1], RequestedBy: requestedBy})
+			})
+		},
+	}
+	for _, c := range []*cobra.Command{add, remove} {
+		c.Flags().StringVar(&requestedBy, "by", "", "user ID requesting the change, recorded in the history")
+	}
+
+	cmd.AddCommand(add, remove)
+	return cmd
+}
+
+// reviewerCall runs fn and prints the resulting pull request.
+func (a *app) reviewerCall(cmd *cobra.Command, fn func(*client.Client, context.Context) (*client.PullRequest, error)) error {
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.context(cmd)
+	defer cancel()
+
+	pr, err := fn(c, ctx)
+	if err != nil {
+		return err
+	}
+	return a.render(pr, prTable(pr))
+}
+
+func (a *app) prHistoryCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history ID",
+		Short: "Show how the reviewers of a pull request changed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			h, err := c.GetAssignmentHistory(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(h, func(w io.Writer) error {
+				rows := make([][]string, 0, len(h.Events))
+				for _, e := range h.Events {
+					seed := "-"
+					if e.Strategy != "" {
+						seed = strconv.FormatInt(e.Seed, 10)
+					}
+					rows = append(rows, []string{
+						formatTime(&e.CreatedAt), e.Kind, e.ReviewerID,
+						orDash(e.PreviousReviewerID), orDash(e.Reason), orDash(e.RequestedBy),
+						orDash(e.Strategy), seed,
+					})
+				}
+				return writeTable(w, []string{"AT", "KIND", "REVIEWER", "REPLACED", "REASON", "REQUESTED_BY", "STRATEGY", "SEED"}, rows)
+			})
+		},
+	}
+}
+
+func (a *app) prSimulateCommand() *cobra.Command {
+	var in client.SimulateAssignment
+	var files []string
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Show whom automatic assignment would pick, without creating anything",
+		Long: "Run reviewer selection for a hypothetical pull request of --author and print every " +
+			"candidate considered with its status, and the reviewers picked. With --count N, N pull " +
+			"requests are replayed one after another and the projected review load is printed.",
+		Example: "  reviewerctl pr simulate --author u1 --label go\n" +
+			"  reviewerctl pr simulate --author u1 --count 100 --strategy deterministic",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			if in.ChangedFiles, err = changedFiles(cmd, files); err != nil {
+				return err
+			}
+			res, err := c.SimulateAssignment(ctx, in)
+			if err != nil {
+				return err
+			}
+			return a.render(res, func(w io.Writer) error {
+				strategy := res.Strategy
+				if res.Seed != 0 {
+					strategy = fmt.Sprintf("%s (seed %d)", strategy, res.Seed)
+				}
+				fmt.Fprintf(w, "Strategy: %s, pull requests: %d\n\n", strategy, res.Count)
+
+				rows := make([][]string, 0, len(res.Candidates))
+				for _, cand := range res.Candidates {
+					rows = append(rows, []string{cand.UserID, cand.TeamName, cand.Status, strconv.FormatInt(cand.OpenReviews, 10)})
+				}
+				if err := writeTable(w, []string{"CANDIDATE", "TEAM", "STATUS", "OPEN_REVIEWS"}, rows); err != nil {
+					return err
+				}
+				if len(res.Assignment) > 0 {
+					fmt.Fprintln(w)
+					if err := assignmentTable(w, res.Assignment); err != nil {
+						return err
+					}
+				}
+				if res.Count < 2 {
+					return nil
+				}
+				fmt.Fprintln(w)
+				rows = make([][]string, 0, len(res.Load))
+				for _, l := range res.Load {
+					rows = append(rows, []string{
+						l.UserID, strconv.FormatInt(l.OpenReviews, 10), strconv.Itoa(l.Assigned), strconv.FormatInt(l.Projected, 10),
+					})
+				}
+				return writeTable(w, []string{"USER", "OPEN_REVIEWS", "ASSIGNED", "PROJECTED"}, rows)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&in.AuthorID, "author", "", "author user ID")
+	cmd.Flags().StringVar(&in.PullRequestID, "id", "", "ID of the hypothetical pull request (seeds the deterministic strategy)")
+	cmd.Flags().StringArrayVar(&files, "file", nil, "changed file path (repeatable); \"-\" reads one path per line from stdin")
+	cmd.Flags().StringArrayVar(&in.Labels, "label", nil, "pull request label matched against reviewers' skills (repeatable)")
+	cmd.Flags().IntVar(&in.ReviewersPerPR, "reviewers", 0, "reviewers per pull request (default: server setting)")
+	cmd.Flags().StringVar(&in.Strategy, "strategy", "", "tie-break strategy: random or deterministic (default: server setting)")
+	cmd.Flags().Int64Var(&in.Seed, "seed", 0, "seed of the random strategy (default: drawn by the server)")
+	cmd.Flags().IntVar(&in.Count, "count", 1, "number of pull requests to replay")
+	_ = cmd.MarkFlagRequired("author")
+	return cmd
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"reviewer_pr/pkg/client"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) skillsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "skills",
+		Aliases: []string{"tags"},
+		Short:   "Manage users' skills used to match pull request labels",
+	}
+
+	get := &cobra.Command{
+		Use:   "get USER_ID",
+		Short: "Show a user's skills",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.skillsCall(cmd, func(c *client.Client, ctx context.Context) (*client.UserSkills, error) {
+				return c.GetSkills(ctx, args[0])
+			})
+		},
+	}
+
+	var proficiency int
+	set := &cobra.Command{
+		Use:     "set USER_ID SKILL",
+		Short:   "Add a skill or change its proficiency",
+		Example: "  reviewerctl user skills set u2 postgres --proficiency 4",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.skillsCall(cmd, func(c *client.Client, ctx context.Context) (*client.UserSkills, error) {
+				return c.SetSkill(ctx, args[0], client.Skill{Skill: args[1], Proficiency: proficiency})
+			})
+		},
+	}
+	set.Flags().IntVar(&proficiency, "proficiency", 0, "proficiency from 1 to 5 (default unspecified)")
+
+	remove := &cobra.Command{
+		Use:     "rm USER_ID SKILL",
+		Aliases: []string{"remove"},
+		Short:   "Remove a skill",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.skillsCall(cmd, func(c *client.Client, ctx context.Context) (*client.UserSkills, error) {
+				return c.RemoveSkill(ctx, args[0], args[1])
+			})
+		},
+	}
+
+	cmd.AddCommand(get, set, remove)
+	return cmd
+}
+
+// skillsCall runs fn and prints the user's resulting skills.
+func (a *app) skillsCall(cmd *cobra.Command, fn func(*client.Client, context.Context) (*client.UserSkills, error)) error {
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.context(cmd)
+	defer cancel()
+
+	s, err := fn(c, ctx)
+	if err != nil {
+		return err
+	}
+	return a.render(s, func(w io.Writer) error {
+		rows := make([][]string, 0, len(s.Skills))
+		for _, sk := range s.Skills {
+			rows = append(rows, []string{sk.Skill, proficiencyText(sk.Proficiency)})
+		}
+		return writeTable(w, []string{"SKILL", "PROFICIENCY"}, rows)
+	})
+}
+
+func proficiencyText(p int) string {
+	if p == 0 {
+		return "-"
+	}
+	return strconv.Itoa(p)
+}
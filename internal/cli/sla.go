@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reviewer_pr/pkg/client"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) teamSLACommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sla",
+		Short: "Manage a team's review SLA",
+	}
+
+	get := &cobra.Command{
+		Use:   "get TEAM",
+		Short: "Show a team's SLA",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.slaCall(cmd, func(c *client.Client, ctx context.Context) (*client.TeamSLA, error) {
+				return c.GetTeamSLA(ctx, args[0])
+			})
+		},
+	}
+
+	var review, merge time.Duration
+	var autoReassign bool
+	set := &cobra.Command{
+		Use:   "set TEAM",
+		Short: "Set a team's SLA",
+		Long: "Set how long a reviewer may take to review after being assigned (--review) and how long " +
+			"a pull request may stay open (--merge). Omitted or zero limits are switched off.",
+		Example: "  reviewerctl team sla set backend --review 24h --merge 72h --auto-reassign\n" +
+			"  reviewerctl team sla set backend   # switch the SLA off",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.slaCall(cmd, func(c *client.Client, ctx context.Context) (*client.TeamSLA, error) {
+				return c.SetTeamSLA(ctx, client.TeamSLA{
+					TeamName:         args[0],
+					ReviewSLASeconds: int64(review / time.Second),
+					MergeSLASeconds:  int64(merge / time.Second),
+					AutoReassign:     autoReassign,
+				})
+			})
+		},
+	}
+	set.Flags().DurationVar(&review, "review", 0, "time to review after assignment, e.g. 24h")
+	set.Flags().DurationVar(&merge, "merge", 0, "time to merge after creation, e.g. 72h")
+	set.Flags().BoolVar(&autoReassign, "auto-reassign", false, "reassign reviewers who miss the review SLA")
+
+	cmd.AddCommand(get, set)
+	return cmd
+}
+
+// slaCall runs fn and prints the resulting SLA.
+func (a *app) slaCall(cmd *cobra.Command, fn func(*client.Client, context.Context) (*client.TeamSLA, error)) error {
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := a.context(cmd)
+	defer cancel()
+
+	s, err := fn(c, ctx)
+	if err != nil {
+		return err
+	}
+	return a.render(s, func(w io.Writer) error {
+		return writeTable(w, []string{"TEAM", "REVIEW", "MERGE", "AUTO_REASSIGN"}, [][]string{{
+			s.TeamName, slaLimit(s.ReviewSLASeconds), slaLimit(s.MergeSLASeconds), yesNo(s.AutoReassign),
+		}})
+	})
+}
+
+func (a *app) prOverdueCommand() *cobra.Command {
+	var q client.OverdueQuery
+	cmd := &cobra.Command{
+		Use:   "overdue",
+		Short: "List open pull requests breaching their team SLA",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.ListOverdue(ctx, q)
+			if err != nil {
+				return err
+			}
+			return a.render(res, func(w io.Writer) error {
+				rows := make([][]string, 0, len(res.PullRequests))
+				for _, pr := range res.PullRequests {
+					breaches := make([]string, 0, len(pr.Breaches))
+					for _, b := range pr.Breaches {
+						s := b.Kind
+						if b.ReviewerID != "" {
+							s += " by " + b.ReviewerID
+						}
+						breaches = append(breaches, fmt.Sprintf("%s (%s late)", s, time.Duration(b.OverdueSeconds)*time.Second))
+					}
+					rows = append(rows, []string{pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.TeamName, strings.Join(breaches, "; ")})
+				}
+				if err := writeTable(w, []string{"ID", "NAME", "AUTHOR", "TEAM", "BREACHES"}, rows); err != nil {
+					return err
+				}
+				writeMore(w, res.Total, res.Offset, len(res.PullRequests))
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&q.TeamName, "team", "", "only pull requests of this team's authors")
+	pageFlags(cmd, &q.Page)
+	return cmd
+}
+
+func slaLimit(seconds int64) string {
+	if seconds == 0 {
+		return "-"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
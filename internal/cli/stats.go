@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) statsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show review assignment statistics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			stats, err := c.GetStats(ctx)
+			if err != nil {
+				return err
+			}
+			return a.render(stats, func(w io.Writer) error {
+				users := make([][]string, 0, len(stats.ByUser))
+				for _, u := range stats.ByUser {
+					users = append(users, []string{u.UserID, u.Username, u.TeamName, strconv.FormatInt(u.ReviewCount, 10)})
+				}
+				if err := writeTable(w, []string{"USER_ID", "USERNAME", "TEAM", "REVIEWS"}, users); err != nil {
+					return err
+				}
+
+				fmt.Fprintln(w)
+				prs := make([][]string, 0, len(stats.ByPR))
+				for _, p := range stats.ByPR {
+					prs = append(prs, []string{p.PullRequestID, strconv.FormatInt(p.ReviewerCount, 10)})
+				}
+				return writeTable(w, []string{"PULL_REQUEST_ID", "REVIEWERS"}, prs)
+			})
+		},
+	}
+}
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reviewer_pr/pkg/client"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func (a *app) teamCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "team",
+		Short: "Manage teams",
+	}
+
+	var members, inactive []string
+	var file string
+	add := &cobra.Command{
+		Use:   "add NAME",
+		Short: "Create a team or update its members",
+		Long: "Create a team or update its members. Members are given as --member ID=USERNAME " +
+			"(repeatable) or read from a YAML/JSON file in the /team/add request format.",
+		Example: "  reviewerctl team add backend --member u1=Alice --member u2=Bob --inactive u2\n" +
+			"  reviewerctl team add backend -f team.yml",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			team, err := teamFromFlags(args[0], file, members, inactive)
+			if err != nil {
+				return err
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.AddTeam(ctx, team)
+			if err != nil {
+				return err
+			}
+			return a.render(res, teamTable(res))
+		},
+	}
+	add.Flags().StringArrayVar(&members, "member", nil, "team member as ID=USERNAME (repeatable)")
+	add.Flags().StringArrayVar(&inactive, "inactive", nil, "ID of a member to add as inactive (repeatable)")
+	add.Flags().StringVarP(&file, "file", "f", "", "read the team from a YAML or JSON file")
+
+	get := &cobra.Command{
+		Use:   "get NAME",
+		Short: "Show a team and its members",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.GetTeam(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(res, teamTable(res))
+		},
+	}
+
+	var page client.Page
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List teams with member and open pull request counts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.ListTeams(ctx, page)
+			if err != nil {
+				return err
+			}
+			return a.render(res, func(w io.Writer) error {
+				rows := make([][]string, 0, len(res.Teams))
+				for _, t := range res.Teams {
+					rows = append(rows, []string{
+						t.TeamName,
+						strconv.FormatInt(t.MemberCount, 10),
+						strconv.FormatInt(t.ActiveMemberCount, 10),
+						strconv.FormatInt(t.OpenPRCount, 10),
+					})
+				}
+				if err := writeTable(w, []string{"TEAM", "MEMBERS", "ACTIVE", "OPEN_PRS"}, rows); err != nil {
+					return err
+				}
+				writeMore(w, res.Total, res.Offset, len(res.Teams))
+				return nil
+			})
+		},
+	}
+	pageFlags(list, &page)
+
+	cmd.AddCommand(add, get, list, a.teamMemberCommand(), a.teamImportCommand(), a.teamSLACommand(), a.teamOwnersCommand())
+	return cmd
+}
+
+func (a *app) teamImportCommand() *cobra.Command {
+	var dryRun, keepMissing bool
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import teams and users from a CSV or YAML file",
+		Long: "Import teams and users from a CSV (team_name,user_id,username[,is_active]) or YAML/JSON " +
+			"file. Active users missing from the file whose teams are all in the file are deactivated " +
+			"unless --keep-missing is set; members of other teams are left alone. " +
+			"The import is applied in one transaction; use --dry-run to review the changes first.",
+		Example: "  reviewerctl team import org.csv --dry-run\n" +
+			"  cat org.yml | reviewerctl team import - --format yaml",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readInput(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			contentType, err := importContentType(format, args[0])
+			if err != nil {
+				return err
+			}
+
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.ImportTeams(ctx, contentType, data, client.ImportOptions{DryRun: dryRun, KeepMissing: keepMissing})
+			if err != nil {
+				return err
+			}
+			return a.render(res, importTable(res))
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the changes without applying them")
+	cmd.Flags().BoolVar(&keepMissing, "keep-missing", false, "do not deactivate users missing from the file")
+	cmd.Flags().StringVar(&format, "format", "", "file format: csv, yaml or json (default: from the file extension)")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"csv", "yaml", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// readInput reads a file, or stdin when name is "-".
+func readInput(cmd *cobra.Command, name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(cmd.InOrStdin())
+	}
+	return os.ReadFile(name)
+}
+
+func importContentType(format, file string) (client.ImportFormat, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	}
+	switch format {
+	case "csv":
+		return client.ImportCSV, nil
+	case "yaml", "yml":
+		return client.ImportYAML, nil
+	case "json":
+		return client.ImportJSON, nil
+	default:
+		return "", fmt.Errorf("cannot detect import format of %q, use --format csv|yaml|json", file)
+	}
+}
+
+func importTable(res *client.ImportResult) func(io.Writer) error {
+	return func(w io.Writer) error {
+		rows := make([][]string, 0, len(res.Changes))
+		for _, ch := range res.Changes {
+			line := "-"
+			if ch.Line > 0 {
+				line = strconv.Itoa(ch.Line)
+			}
+			fields := make([]string, 0, len(ch.Fields))
+			for _, f := range ch.Fields {
+				fields = append(fields, fmt.Sprintf("%s: %s -> %s", f.Field, f.From, f.To))
+			}
+			rows = append(rows, []string{line, ch.Action, ch.TeamName, ch.UserID, strings.Join(fields, "; ")})
+		}
+		if err := writeTable(w, []string{"LINE", "ACTION", "TEAM", "USER", "CHANGES"}, rows); err != nil {
+			return err
+		}
+
+		s := res.Summary
+		verb := "Applied"
+		if res.DryRun {
+			verb = "Dry run, nothing applied"
+		}
+		fmt.Fprintf(w, "\n%s: %d teams created, %d users created, %d updated, %d deactivated, %d unchanged\n",
+			verb, s.TeamsCreated, s.UsersCreated, s.UsersUpdated, s.UsersDeactivated, s.Unchanged)
+		return nil
+	}
+}
+
+func teamFromFlags(name, file string, members, inactive []string) (client.Team, error) {
+	team := client.Team{TeamName: name}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return team, err
+		}
+		// JSON is valid YAML, so one decoder covers both formats.
+		var doc struct {
+			Members []struct {
+				UserID   string `yaml:"user_id"`
+				Username string `yaml:"username"`
+				IsActive *bool  `yaml:"is_active"`
+				Role     string `yaml:"role"`
+				// MembershipActive is the activity of the membership in this team.
+				MembershipActive *bool `yaml:"membership_active"`
+			} `yaml:"members"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return team, fmt.Errorf("parse %s: %w", file, err)
+		}
+		for _, m := range doc.Members {
+			team.Members = append(team.Members, client.TeamMember{
+				UserID:           m.UserID,
+				Username:         m.Username,
+				IsActive:         m.IsActive == nil || *m.IsActive,
+				Role:             m.Role,
+				MembershipActive: m.MembershipActive,
+			})
+		}
+	}
+
+	off := make(map[string]bool, len(inactive))
+	for _, id := range inactive {
+		off[id] = true
+	}
+	for _, m := range members {
+		id, username, ok := strings.Cut(m, "=")
+		if !ok || id == "" || username == "" {
+			return team, fmt.Errorf("invalid --member %q, want ID=USERNAME", m)
+		}
+		team.Members = append(team.Members, client.TeamMember{UserID: id, Username: username, IsActive: !off[id]})
+	}
+
+	if len(team.Members) == 0 {
+		return team, fmt.Errorf("no members given, use --member or --file")
+	}
+	return team, nil
+}
+
+func teamTable(t *client.Team) func(io.Writer) error {
+	return func(w io.Writer) error {
+		fmt.Fprintf(w, "Team: %s\n", t.TeamName)
+		rows := make([][]string, 0, len(t.Members))
+		for _, m := range t.Members {
+			inTeam := "-"
+			if m.MembershipActive != nil {
+				inTeam = yesNo(*m.MembershipActive)
+			}
+			rows = append(rows, []string{m.UserID, m.Username, yesNo(m.IsActive), cmp.Or(m.Role, "-"), inTeam})
+		}
+		return writeTable(w, []string{"USER_ID", "USERNAME", "ACTIVE", "ROLE", "IN_TEAM"}, rows)
+	}
+}
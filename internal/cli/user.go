@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"io"
+	"reviewer_pr/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+func (a *app) userCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+
+	get := &cobra.Command{
+		Use:   "get USER_ID",
+		Short: "Show a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			u, err := c.GetUser(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return a.render(u, usersTable([]client.User{*u}))
+		},
+	}
+
+	var search client.UserSearch
+	find := &cobra.Command{
+		Use:   "search",
+		Short: "Find users by username prefix and/or team",
+		Example: "  reviewerctl user search --username al\n" +
+			"  reviewerctl user search --team backend --limit 100",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			res, err := c.SearchUsers(ctx, search)
+			if err != nil {
+				return err
+			}
+			return a.render(res, func(w io.Writer) error {
+				if err := usersTable(res.Users)(w); err != nil {
+					return err
+				}
+				writeMore(w, res.Total, res.Offset, len(res.Users))
+				return nil
+			})
+		},
+	}
+	find.Flags().StringVar(&search.Username, "username", "", "username prefix, case-insensitive")
+	find.Flags().StringVar(&search.TeamName, "team", "", "team name")
+	pageFlags(find, &search.Page)
+
+	cmd.AddCommand(
+		get,
+		find,
+		a.setActiveCommand("activate", "Mark users as active reviewers", true),
+		a.setActiveCommand("deactivate", "Stop assigning reviews to users", false),
+		a.setNotificationsCommand("mute", "Stop chat notifications to users", false),
+		a.setNotificationsCommand("unmute", "Resume chat notifications to users", true),
+		a.digestCommand(),
+		a.skillsCommand(),
+		a.setAutoAssignCommand("opt-out", "Stop picking users as reviewers automatically", false),
+		a.setAutoAssignCommand("opt-in", "Resume picking users as reviewers automatically", true),
+		a.exclusionsCommand(),
+	)
+	return cmd
+}
+
+func (a *app) setActiveCommand(use, short string, active bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " USER_ID...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			users := make([]client.User, 0, len(args))
+			for _, id := range args {
+				u, err := c.SetIsActive(ctx, id, active)
+				if err != nil {
+					return err
+				}
+				users = append(users, *u)
+			}
+			return a.render(users, usersTable(users))
+		},
+	}
+}
+
+func (a *app) setNotificationsCommand(use, short string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " USER_ID...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			type status struct {
+				UserID               string `json:"user_id"`
+				NotificationsEnabled bool   `json:"notifications_enabled"`
+			}
+			res := make([]status, 0, len(args))
+			for _, id := range args {
+				on, err := c.SetNotifications(ctx, id, enabled)
+				if err != nil {
+					return err
+				}
+				res = append(res, status{UserID: id, NotificationsEnabled: on})
+			}
+			return a.render(res, func(w io.Writer) error {
+				rows := make([][]string, 0, len(res))
+				for _, s := range res {
+					rows = append(rows, []string{s.UserID, yesNo(s.NotificationsEnabled)})
+				}
+				return writeTable(w, []string{"USER_ID", "NOTIFICATIONS"}, rows)
+			})
+		},
+	}
+}
+
+func (a *app) setAutoAssignCommand(use, short string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " USER_ID...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			type status struct {
+				UserID     string `json:"user_id"`
+				AutoAssign bool   `json:"auto_assign"`
+			}
+			res := make([]status, 0, len(args))
+			for _, id := range args {
+				on, err := c.SetAutoAssign(ctx, id, enabled)
+				if err != nil {
+					return err
+				}
+				res = append(res, status{UserID: id, AutoAssign: on})
+			}
+			return a.render(res, func(w io.Writer) error {
+				rows := make([][]string, 0, len(res))
+				for _, s := range res {
+					rows = append(rows, []string{s.UserID, yesNo(s.AutoAssign)})
+				}
+				return writeTable(w, []string{"USER_ID", "AUTO_ASSIGN"}, rows)
+			})
+		},
+	}
+}
+
+func usersTable(users []client.User) func(io.Writer) error {
+	return func(w io.Writer) error {
+		rows := make([][]string, 0, len(users))
+		for _, u := range users {
+			rows = append(rows, []string{u.UserID, u.Username, u.TeamName, yesNo(u.IsActive)})
+		}
+		return writeTable(w, []string{"USER_ID", "USERNAME", "TEAM", "ACTIVE"}, rows)
+	}
+}
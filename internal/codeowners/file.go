@@ -0,0 +1,46 @@
+package codeowners
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Entry is one rule line of a CODEOWNERS file. Owners are the tokens after
+// the pattern as written, e.g. "@alice" or "@org/backend".
+type Entry struct {
+	Line    int
+	Pattern string
+	Owners  []string
+}
+
+// Parse splits a CODEOWNERS file into entries, skipping blank lines and
+// comments. Patterns and owners are not checked.
+func Parse(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		for i, f := range fields {
+			if strings.HasPrefix(f, "#") {
+				fields = fields[:i]
+				break
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		entries = append(entries, Entry{Line: line, Pattern: fields[0], Owners: fields[1:]})
+	}
+	return entries, sc.Err()
+}
+
+// OwnerTeam reports whether a CODEOWNERS owner names a team ("@org/team")
+// and returns the team or user name without the "@" and organization.
+func OwnerTeam(owner string) (name string, team bool) {
+	owner = strings.TrimPrefix(owner, "@")
+	if i := strings.LastIndex(owner, "/"); i >= 0 {
+		return owner[i+1:], true
+	}
+	return owner, false
+}
@@ -0,0 +1,121 @@
+// Package codeowners matches file paths against CODEOWNERS patterns.
+//
+// Patterns follow the gitignore rules used by CODEOWNERS files: a pattern
+// that starts with or contains a "/" is anchored at the repository root,
+// other patterns match at any depth; a trailing "/" matches everything in
+// the directory; "*" and "?" do not cross "/", "**" does. As on GitHub, a
+// pattern matches every file under the directories it names, except when its
+// last segment is a wildcard: "docs/*" covers docs/a.md but not docs/x/b.md.
+package codeowners
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Pattern is a compiled CODEOWNERS pattern.
+type Pattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+// Compile parses a CODEOWNERS pattern.
+func Compile(pattern string) (*Pattern, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" {
+		return nil, errors.New("pattern is empty")
+	}
+	if strings.HasPrefix(p, "!") {
+		return nil, errors.New("negated patterns are not supported")
+	}
+	if strings.ContainsAny(p, "[]\\") {
+		return nil, errors.New("character classes and escapes are not supported")
+	}
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		// "/" owns the whole repository.
+		p, anchored, dirOnly = "**", true, false
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case p[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+			i++
+		}
+	}
+	last := p[strings.LastIndex(p, "/")+1:]
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case last != "**" && strings.ContainsAny(last, "*?"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+	}
+	return &Pattern{raw: pattern, re: re}, nil
+}
+
+// Match reports whether the file at path, relative to the repository root,
+// is covered by the pattern.
+func (p *Pattern) Match(path string) bool {
+	return p.re.MatchString(CleanPath(path))
+}
+
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// CleanPath strips the "./" and "/" prefixes changed file lists often carry.
+func CleanPath(path string) string {
+	path = strings.TrimSpace(path)
+	for {
+		switch {
+		case strings.HasPrefix(path, "./"):
+			path = path[2:]
+		case strings.HasPrefix(path, "/"):
+			path = path[1:]
+		default:
+			return path
+		}
+	}
+}
+
+// LastMatch returns the index of the last pattern matching path, or -1.
+// In a CODEOWNERS file the last matching rule decides the owners.
+func LastMatch(patterns []*Pattern, path string) int {
+	for i := len(patterns) - 1; i >= 0; i-- {
+		if patterns[i].Match(path) {
+			return i
+		}
+	}
+	return -1
+}
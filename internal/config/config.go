@@ -1,63 +1,352 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"time"
 
-	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Reviewer assignment strategies, see Assignment.Strategy.
+const (
+	StrategyRandom        = "random"
+	StrategyDeterministic = "deterministic"
 )
 
 type Config struct {
-	Port string
-	DB   DB
-	Auth AuthConfig
+	Env        string     `yaml:"env"`
+	Server     Server     `yaml:"server"`
+	GRPC       GRPC       `yaml:"grpc"`
+	DB         DB         `yaml:"db"`
+	Log        Log        `yaml:"log"`
+	Auth       AuthConfig `yaml:"auth"`
+	Assignment Assignment `yaml:"assignment"`
+	Features   Features   `yaml:"features"`
+
+	Idempotency   Idempotency   `yaml:"idempotency"`
+	Notifications Notifications `yaml:"notifications"`
+	Digest        Digest        `yaml:"digest"`
+	SLA           SLA           `yaml:"sla"`
+	Scheduler     Scheduler     `yaml:"scheduler"`
+}
+
+type Server struct {
+	Port              string        `yaml:"port"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay is how long the servers keep serving after readiness turns
+	// down on shutdown, so that load balancers stop routing to them first.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+}
+
+type GRPC struct {
+	Enabled bool   `yaml:"enabled"`
+	Port    string `yaml:"port"`
 }
 
 type DB struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
-	SSLMode  string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	ConnectRetries    int           `yaml:"connect_retries"`
+	ConnectBackoff    time.Duration `yaml:"connect_backoff"`
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff"`
+
+	// ReplicaDSN enables routing of lag-tolerant reads to a read replica.
+	ReplicaDSN string `yaml:"replica_dsn"`
 }
 
+func (d DB) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+type Log struct {
+	Level       string `yaml:"level"`
+	Development bool   `yaml:"development"`
+}
+
+// AuthConfig maps bearer tokens to roles. AdminToken and UserToken act for
+// the default tenant; Tenants add organizations with tokens of their own.
 type AuthConfig struct {
-	AdminToken string
-	UserToken  string
+	Enabled    bool         `yaml:"enabled"`
+	AdminToken string       `yaml:"admin_token"`
+	UserToken  string       `yaml:"user_token"`
+	Tenants    []TenantAuth `yaml:"tenants"`
+}
+
+// TenantAuth is an organization whose teams, users and pull requests are
+// only visible with its own tokens.
+type TenantAuth struct {
+	ID         string `yaml:"id"`
+	AdminToken string `yaml:"admin_token"`
+	UserToken  string `yaml:"user_token"`
+}
+
+type Assignment struct {
+	ReviewersPerPR int `yaml:"reviewers_per_pr"`
+	// Strategy breaks ties between equally ranked candidates: "random", or
+	// "deterministic" to derive them from the pull request and candidate IDs.
+	Strategy string `yaml:"strategy"`
+	// Seed makes the "random" strategy reproducible; 0 seeds from the clock.
+	Seed int `yaml:"seed"`
+}
+
+type Idempotency struct {
+	// TTL is how long a response stored for an Idempotency-Key is replayed.
+	TTL time.Duration `yaml:"ttl"`
+	// PurgeInterval is how often expired keys are deleted.
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// Notifications configures chat messages to reviewers through a Slack or
+// Mattermost incoming webhook.
+type Notifications struct {
+	Enabled    bool   `yaml:"enabled"`
+	WebhookURL string `yaml:"webhook_url"`
+	// Channel and Username override the webhook defaults when set.
+	Channel  string `yaml:"channel"`
+	Username string `yaml:"username"`
+	// Handles maps user IDs to chat handles, e.g. "<@U024BE7LH>" for Slack.
+	// Other users are mentioned as "@" + username.
+	Handles   map[string]string     `yaml:"handles"`
+	Templates NotificationTemplates `yaml:"templates"`
+
+	QueueSize    int           `yaml:"queue_size"`
+	MaxAttempts  int           `yaml:"max_attempts"`
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+}
+
+// NotificationTemplates are Go text/template sources; empty ones use the
+// built-in messages.
+type NotificationTemplates struct {
+	Assigned   string `yaml:"assigned"`
+	Reassigned string `yaml:"reassigned"`
+	Merged     string `yaml:"merged"`
+	// ReviewOverdue and MergeOverdue announce review SLA breaches.
+	ReviewOverdue string `yaml:"review_overdue"`
+	MergeOverdue  string `yaml:"merge_overdue"`
+}
+
+// Digest configures the daily email with the open pull requests each
+// subscribed reviewer is assigned to.
+type Digest struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often subscriptions are checked for a due digest.
+	Interval  time.Duration   `yaml:"interval"`
+	SMTP      SMTP            `yaml:"smtp"`
+	Templates DigestTemplates `yaml:"templates"`
+}
+
+type SMTP struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// Username enables PLAIN authentication; the server must offer STARTTLS
+	// unless it runs on localhost.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// DigestTemplates override the built-in digest: Subject is a Go text/template
+// source, TextFile and HTMLFile are paths to text/template and html/template
+// files.
+type DigestTemplates struct {
+	Subject  string `yaml:"subject"`
+	TextFile string `yaml:"text_file"`
+	HTMLFile string `yaml:"html_file"`
+}
+
+// SLA configures the background check of team review SLAs. The limits are
+// set per team through the API; breaches are listed even when the check is
+// off.
+type SLA struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often open pull requests are checked for breaches.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// Scheduler configures background jobs. They run on every replica; a
+// Postgres advisory lock lets one replica run each job at a time.
+type Scheduler struct {
+	// Schedules override job schedules by job name with a cron expression,
+	// @hourly/@daily/... or "@every 10m". Without an override a job runs
+	// every digest.interval, sla.interval or idempotency.purge_interval.
+	Schedules map[string]string `yaml:"schedules"`
 }
 
-func Load(log *zap.Logger) *Config {
+// JobNames lists the background jobs Scheduler.Schedules may refer to.
+var JobNames = []string{"digest", "sla", "idempotency"}
+
+type Features struct {
+	Swagger bool `yaml:"swagger"`
+	Stats   bool `yaml:"stats"`
+}
+
+// Insecure built-in values that are only acceptable outside production.
+const (
+	defaultDBPassword = "12341"
+	defaultAdminToken = "admin-token"
+	defaultUserToken  = "user-token"
+)
+
+func Default() *Config {
 	return &Config{
-		Port: getEnv("APP_PORT", "8080", log),
+		Env: EnvProduction,
+		Server: Server{
+			Port:              "8080",
+			ReadTimeout:       10 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      15 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			DrainDelay:        5 * time.Second,
+		},
+		GRPC: GRPC{
+			Enabled: true,
+			Port:    "9090",
+		},
 		DB: DB{
-			Host:     getEnv("DB_HOST", "localhost", log),
-			Port:     getEnv("DB_PORT", "5432", log),
-			User:     getEnv("DB_USER", "reviewer", log),
-			Password: getEnv("DB_PASSWORD", "12341", log),
-			Name:     getEnv("DB_NAME", "reviewer-pr-db", log),
-			SSLMode:  getEnv("DB_SSLMODE", "disable", log),
+			Host:            "localhost",
+			Port:            "5432",
+			User:            "reviewer",
+			Password:        defaultDBPassword,
+			Name:            "reviewer-pr-db",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+
+			ConnectRetries:    10,
+			ConnectBackoff:    500 * time.Millisecond,
+			ConnectMaxBackoff: 10 * time.Second,
+		},
+		Log: Log{
+			Level: "info",
 		},
 		Auth: AuthConfig{
-			AdminToken: getEnv("ADMIN_TOKEN", "admin-token", log),
-			UserToken:  getEnv("USER_TOKEN", "user-token", log),
+			AdminToken: defaultAdminToken,
+			UserToken:  defaultUserToken,
 		},
+		Assignment: Assignment{
+			ReviewersPerPR: 2,
+			Strategy:       StrategyRandom,
+		},
+		Features: Features{
+			Swagger: true,
+			Stats:   true,
+		},
+		Idempotency: Idempotency{
+			TTL:           24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Notifications: Notifications{
+			QueueSize:    1000,
+			MaxAttempts:  5,
+			RetryBackoff: time.Second,
+		},
+		Digest: Digest{
+			Interval: time.Minute,
+			SMTP: SMTP{
+				Port: 587,
+			},
+		},
+		SLA: SLA{
+			Interval: time.Minute,
+		},
+	}
+}
+
+// Load builds the effective configuration: built-in defaults, then the YAML
+// file at path (if any), then environment variables. The result is validated.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
+
+	return cfg, nil
 }
 
-func getEnv(key, defaultVal string, log *zap.Logger) string {
-	if val, exists := os.LookupEnv(key); exists {
-		return val
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
 	}
-	if defaultVal != "" {
-		log.Warn("Переменная окружения не установлена, используем значение по умолчанию",
-			zap.String("key", key),
-			zap.String("default", defaultVal),
-		)
-		return defaultVal
+	return nil
+}
+
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// DevelopmentLogging reports whether the human-friendly development logger should be used.
+func (c *Config) DevelopmentLogging() bool {
+	return c.Log.Development || c.Env == EnvDevelopment
+}
+
+// Redacted returns a copy of the config with secrets masked, suitable for printing.
+func (c *Config) Redacted() *Config {
+	cpy := *c
+	cpy.DB.Password = redact(c.DB.Password)
+	cpy.DB.ReplicaDSN = redact(c.DB.ReplicaDSN)
+	cpy.Auth.AdminToken = redact(c.Auth.AdminToken)
+	cpy.Auth.UserToken = redact(c.Auth.UserToken)
+	cpy.Auth.Tenants = make([]TenantAuth, len(c.Auth.Tenants))
+	for i, t := range c.Auth.Tenants {
+		cpy.Auth.Tenants[i] = TenantAuth{ID: t.ID, AdminToken: redact(t.AdminToken), UserToken: redact(t.UserToken)}
 	}
+	cpy.Notifications.WebhookURL = redact(c.Notifications.WebhookURL)
+	cpy.Digest.SMTP.Password = redact(c.Digest.SMTP.Password)
+	return &cpy
+}
+
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
 
-	log.Error("Обязательная переменная окружения не установлена и значение по умолчанию не задано",
-		zap.String("key", key),
-	)
-	panic("missing required environment variable: " + key)
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "******"
 }
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// envLoader applies environment overrides and collects parse errors so that
+// every malformed variable is reported at once.
+type envLoader struct {
+	errs []error
+}
+
+func (c *Config) applyEnv() error {
+	e := &envLoader{}
+
+	e.str("ENV", &c.Env)
+
+	e.str("APP_PORT", &c.Server.Port)
+	e.duration("HTTP_READ_TIMEOUT", &c.Server.ReadTimeout)
+	e.duration("HTTP_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout)
+	e.duration("HTTP_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	e.duration("HTTP_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	e.duration("HTTP_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	e.duration("HTTP_DRAIN_DELAY", &c.Server.DrainDelay)
+
+	e.boolean("GRPC_ENABLED", &c.GRPC.Enabled)
+	e.str("GRPC_PORT", &c.GRPC.Port)
+
+	e.str("DB_HOST", &c.DB.Host)
+	e.str("DB_PORT", &c.DB.Port)
+	e.str("DB_USER", &c.DB.User)
+	e.str("DB_PASSWORD", &c.DB.Password)
+	e.str("DB_NAME", &c.DB.Name)
+	e.str("DB_SSLMODE", &c.DB.SSLMode)
+	e.integer("DB_MAX_OPEN_CONNS", &c.DB.MaxOpenConns)
+	e.integer("DB_MAX_IDLE_CONNS", &c.DB.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &c.DB.ConnMaxLifetime)
+	e.duration("DB_CONN_MAX_IDLE_TIME", &c.DB.ConnMaxIdleTime)
+	e.integer("DB_CONNECT_RETRIES", &c.DB.ConnectRetries)
+	e.duration("DB_CONNECT_BACKOFF", &c.DB.ConnectBackoff)
+	e.duration("DB_CONNECT_MAX_BACKOFF", &c.DB.ConnectMaxBackoff)
+	e.str("DB_REPLICA_DSN", &c.DB.ReplicaDSN)
+
+	e.str("LOG_LEVEL", &c.Log.Level)
+	e.boolean("LOG_DEVELOPMENT", &c.Log.Development)
+
+	e.boolean("AUTH_ENABLED", &c.Auth.Enabled)
+	e.str("ADMIN_TOKEN", &c.Auth.AdminToken)
+	e.str("USER_TOKEN", &c.Auth.UserToken)
+
+	e.integer("ASSIGNMENT_REVIEWERS_PER_PR", &c.Assignment.ReviewersPerPR)
+	e.str("ASSIGNMENT_STRATEGY", &c.Assignment.Strategy)
+	e.integer("ASSIGNMENT_SEED", &c.Assignment.Seed)
+
+	e.boolean("FEATURE_SWAGGER", &c.Features.Swagger)
+	e.boolean("FEATURE_STATS", &c.Features.Stats)
+
+	e.duration("IDEMPOTENCY_TTL", &c.Idempotency.TTL)
+	e.duration("IDEMPOTENCY_PURGE_INTERVAL", &c.Idempotency.PurgeInterval)
+
+	e.boolean("NOTIFY_ENABLED", &c.Notifications.Enabled)
+	e.str("NOTIFY_WEBHOOK_URL", &c.Notifications.WebhookURL)
+	e.str("NOTIFY_CHANNEL", &c.Notifications.Channel)
+
+	e.boolean("DIGEST_ENABLED", &c.Digest.Enabled)
+	e.str("SMTP_HOST", &c.Digest.SMTP.Host)
+	e.integer("SMTP_PORT", &c.Digest.SMTP.Port)
+	e.str("SMTP_USERNAME", &c.Digest.SMTP.Username)
+	e.str("SMTP_PASSWORD", &c.Digest.SMTP.Password)
+	e.str("SMTP_FROM", &c.Digest.SMTP.From)
+
+	e.boolean("SLA_ENABLED", &c.SLA.Enabled)
+	e.duration("SLA_CHECK_INTERVAL", &c.SLA.Interval)
+
+	return errors.Join(e.errs...)
+}
+
+func (e *envLoader) str(key string, dst *string) {
+	if val, ok := os.LookupEnv(key); ok {
+		*dst = val
+	}
+}
+
+func (e *envLoader) integer(key string, dst *int) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: invalid integer %q", key, val))
+		return
+	}
+	*dst = n
+}
+
+func (e *envLoader) boolean(key string, dst *bool) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: invalid boolean %q", key, val))
+		return
+	}
+	*dst = b
+}
+
+func (e *envLoader) duration(key string, dst *time.Duration) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: invalid duration %q", key, val))
+		return
+	}
+	*dst = d
+}
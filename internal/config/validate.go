@@ -0,0 +1,266 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"reviewer_pr/internal/scheduler"
+	"reviewer_pr/internal/tenant"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var validSSLModes = map[string]bool{
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Validate checks the configuration and returns every problem found.
+// In production it additionally refuses the insecure built-in secrets.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		fail("env", "must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("server.port", "must be a number between 1 and 65535, got %q", c.Server.Port)
+	}
+	for _, t := range []struct {
+		field string
+		value time.Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.read_header_timeout", c.Server.ReadHeaderTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+		{"server.shutdown_timeout", c.Server.ShutdownTimeout},
+	} {
+		if t.value <= 0 {
+			fail(t.field, "must be positive")
+		}
+	}
+	if c.Server.DrainDelay < 0 {
+		fail("server.drain_delay", "must not be negative")
+	}
+
+	if c.GRPC.Enabled {
+		if port, err := strconv.Atoi(c.GRPC.Port); err != nil || port < 1 || port > 65535 {
+			fail("grpc.port", "must be a number between 1 and 65535, got %q", c.GRPC.Port)
+		} else if c.GRPC.Port == c.Server.Port {
+			fail("grpc.port", "must differ from server.port")
+		}
+	}
+
+	if c.DB.Host == "" {
+		fail("db.host", "is required")
+	}
+	if port, err := strconv.Atoi(c.DB.Port); err != nil || port < 1 || port > 65535 {
+		fail("db.port", "must be a number between 1 and 65535, got %q", c.DB.Port)
+	}
+	if c.DB.User == "" {
+		fail("db.user", "is required")
+	}
+	if c.DB.Name == "" {
+		fail("db.name", "is required")
+	}
+	if !validSSLModes[c.DB.SSLMode] {
+		fail("db.sslmode", "unsupported value %q", c.DB.SSLMode)
+	}
+	if c.DB.MaxOpenConns < 0 {
+		fail("db.max_open_conns", "must not be negative")
+	}
+	if c.DB.MaxIdleConns < 0 {
+		fail("db.max_idle_conns", "must not be negative")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		fail("db.max_idle_conns", "must not exceed db.max_open_conns (%d)", c.DB.MaxOpenConns)
+	}
+	if c.DB.ConnMaxLifetime < 0 {
+		fail("db.conn_max_lifetime", "must not be negative")
+	}
+	if c.DB.ConnMaxIdleTime < 0 {
+		fail("db.conn_max_idle_time", "must not be negative")
+	}
+	if c.DB.ConnectRetries < 0 {
+		fail("db.connect_retries", "must not be negative")
+	}
+	if c.DB.ConnectBackoff <= 0 {
+		fail("db.connect_backoff", "must be positive")
+	}
+	if c.DB.ConnectMaxBackoff < c.DB.ConnectBackoff {
+		fail("db.connect_max_backoff", "must not be less than db.connect_backoff")
+	}
+
+	if _, err := zapcore.ParseLevel(c.Log.Level); err != nil {
+		fail("log.level", "unknown level %q", c.Log.Level)
+	}
+
+	if c.Auth.Enabled {
+		if c.Auth.AdminToken == "" {
+			fail("auth.admin_token", "is required when auth is enabled")
+		}
+		if c.Auth.UserToken == "" {
+			fail("auth.user_token", "is required when auth is enabled")
+		}
+		if c.Auth.AdminToken != "" && c.Auth.AdminToken == c.Auth.UserToken {
+			fail("auth.user_token", "must differ from auth.admin_token")
+		}
+	}
+	tokens := map[string]string{c.Auth.AdminToken: "auth.admin_token", c.Auth.UserToken: "auth.user_token"}
+	tenants := make(map[string]bool, len(c.Auth.Tenants))
+	for i, t := range c.Auth.Tenants {
+		field := fmt.Sprintf("auth.tenants[%d]", i)
+		switch {
+		case !tenantIDPattern.MatchString(t.ID):
+			fail(field+".id", "must be lowercase letters, digits, '-' or '_', got %q", t.ID)
+		case t.ID == tenant.Default:
+			fail(field+".id", "%q is reserved for auth.admin_token and auth.user_token", tenant.Default)
+		case tenants[t.ID]:
+			fail(field+".id", "duplicate tenant %q", t.ID)
+		}
+		tenants[t.ID] = true
+		for _, tok := range []struct{ name, value string }{
+			{field + ".admin_token", t.AdminToken},
+			{field + ".user_token", t.UserToken},
+		} {
+			if tok.value == "" {
+				fail(tok.name, "is required")
+				continue
+			}
+			if other, ok := tokens[tok.value]; ok {
+				fail(tok.name, "must differ from %s", other)
+				continue
+			}
+			tokens[tok.value] = tok.name
+		}
+	}
+
+	if c.Assignment.ReviewersPerPR < 1 || c.Assignment.ReviewersPerPR > 10 {
+		fail("assignment.reviewers_per_pr", "must be between 1 and 10, got %d", c.Assignment.ReviewersPerPR)
+	}
+	if s := c.Assignment.Strategy; s != StrategyRandom && s != StrategyDeterministic {
+		fail("assignment.strategy", "must be %q or %q, got %q", StrategyRandom, StrategyDeterministic, s)
+	}
+
+	if c.Idempotency.TTL <= 0 {
+		fail("idempotency.ttl", "must be positive")
+	}
+	if c.Idempotency.PurgeInterval <= 0 {
+		fail("idempotency.purge_interval", "must be positive")
+	}
+
+	if n := c.Notifications; n.Enabled {
+		if u, err := url.Parse(n.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("notifications.webhook_url", "must be an http(s) URL when notifications are enabled")
+		}
+		if n.QueueSize < 1 {
+			fail("notifications.queue_size", "must be positive")
+		}
+		if n.MaxAttempts < 1 {
+			fail("notifications.max_attempts", "must be positive")
+		}
+		if n.RetryBackoff <= 0 {
+			fail("notifications.retry_backoff", "must be positive")
+		}
+		for _, t := range []struct {
+			field string
+			src   string
+		}{
+			{"notifications.templates.assigned", n.Templates.Assigned},
+			{"notifications.templates.reassigned", n.Templates.Reassigned},
+			{"notifications.templates.merged", n.Templates.Merged},
+			{"notifications.templates.review_overdue", n.Templates.ReviewOverdue},
+			{"notifications.templates.merge_overdue", n.Templates.MergeOverdue},
+		} {
+			if _, err := template.New(t.field).Parse(t.src); err != nil {
+				fail(t.field, "%v", err)
+			}
+		}
+	}
+
+	if d := c.Digest; d.Enabled {
+		if d.Interval <= 0 {
+			fail("digest.interval", "must be positive")
+		}
+		if d.SMTP.Host == "" {
+			fail("digest.smtp.host", "is required when the digest is enabled")
+		}
+		if d.SMTP.Port < 1 || d.SMTP.Port > 65535 {
+			fail("digest.smtp.port", "must be between 1 and 65535, got %d", d.SMTP.Port)
+		}
+		if _, err := mail.ParseAddress(d.SMTP.From); err != nil {
+			fail("digest.smtp.from", "must be an email address")
+		}
+		if _, err := template.New("subject").Parse(d.Templates.Subject); err != nil {
+			fail("digest.templates.subject", "%v", err)
+		}
+		for _, f := range []struct {
+			field string
+			path  string
+		}{
+			{"digest.templates.text_file", d.Templates.TextFile},
+			{"digest.templates.html_file", d.Templates.HTMLFile},
+		} {
+			if f.path == "" {
+				continue
+			}
+			if _, err := os.Stat(f.path); err != nil {
+				fail(f.field, "%v", err)
+			}
+		}
+	}
+
+	if c.SLA.Enabled && c.SLA.Interval <= 0 {
+		fail("sla.interval", "must be positive")
+	}
+
+	for name, spec := range c.Scheduler.Schedules {
+		field := "scheduler.schedules." + name
+		if !slices.Contains(JobNames, name) {
+			fail(field, "unknown job, want one of %s", strings.Join(JobNames, ", "))
+			continue
+		}
+		if _, err := scheduler.Parse(spec); err != nil {
+			fail(field, "%v", err)
+		}
+	}
+
+	if c.IsProduction() {
+		if c.DB.Password == "" || c.DB.Password == defaultDBPassword {
+			fail("db.password", "insecure default is not allowed in production")
+		}
+		if !c.Auth.Enabled {
+			fail("auth.enabled", "must be true in production")
+		}
+		if c.Auth.AdminToken == defaultAdminToken {
+			fail("auth.admin_token", "insecure default is not allowed in production")
+		}
+		if c.Auth.UserToken == defaultUserToken {
+			fail("auth.user_token", "insecure default is not allowed in production")
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
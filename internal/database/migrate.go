@@ -2,20 +2,55 @@ package database
 
 import (
 	"errors"
+	"fmt"
 	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func AutoMigrate(db *gorm.DB, log *zap.Logger) error {
-	if err := db.AutoMigrate(
+// Models lists every model managed by AutoMigrate.
+func Models() []any {
+	return []any{
+		&models.Tenant{},
 		&models.Team{},
 		&models.User{},
+		&models.UserSkill{},
+		&models.TeamMembership{},
+		&models.ReviewerExclusion{},
 		&models.PullRequest{},
 		&models.PRReviewer{},
-	); err != nil {
+		&models.AssignmentEvent{},
+		&models.IdempotencyKey{},
+		&models.DigestSubscription{},
+		&models.TeamSLA{},
+		&models.SLABreach{},
+		&models.JobRun{},
+		&models.CodeOwnerRule{},
+	}
+}
+
+func AutoMigrate(db *gorm.DB, log *zap.Logger) error {
+	m := db.Migrator()
+	backfill := m.HasTable("users") && !m.HasTable("team_memberships")
+	legacy, err := migrateToTenants(db, log)
+	if err == nil {
+		err = db.AutoMigrate(Models()...)
+	}
+	if err == nil {
+		err = copyLegacyTables(db, legacy, log)
+	}
+	if err == nil && backfill {
+		err = backfillMemberships(db, log)
+	}
+	if err == nil {
+		err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Tenant{ID: tenant.Default}).Error
+	}
+	if err != nil {
 		var pgErr *pgconn.PgError
 		if ok := errors.As(err, &pgErr); ok {
 			log.Error("ошибка миграции", zap.String("pg_code", pgErr.Code), zap.Error(err))
@@ -28,3 +63,155 @@ func AutoMigrate(db *gorm.DB, log *zap.Logger) error {
 	log.Info("Миграция выполнена успешно")
 	return nil
 }
+
+// tenantKeys are the primary keys, without tenant_id, of the tables that
+// predate tenants. assignment_events keeps its own key.
+var tenantKeys = []struct {
+	table string
+	key   []string
+}{
+	{"teams", []string{"team_name"}},
+	{"users", []string{"user_id"}},
+	{"user_skills", []string{"user_id", "skill"}},
+	{"reviewer_exclusions", []string{"author_id", "reviewer_id"}},
+	{"pull_requests", []string{"pull_request_id"}},
+	{"pr_reviewers", []string{"pull_request_id", "reviewer_id"}},
+	{"assignment_events", nil},
+	{"idempotency_keys", []string{"idempotency_key"}},
+	{"digest_subscriptions", []string{"user_id"}},
+	{"team_slas", []string{"team_name"}},
+	{"sla_breaches", []string{"pull_request_id", "reviewer_id", "kind"}},
+	{"code_owner_rules", []string{"team_name", "position"}},
+}
+
+// migrateToTenants moves a database created before tenants into the default
+// tenant. In Postgres every table gains tenant_id and its primary key is
+// prefixed with it; foreign keys are dropped with the old primary keys and
+// recreated by AutoMigrate. SQLite cannot change a primary key, so there the
+// tables are renamed and returned for copyLegacyTables.
+func migrateToTenants(db *gorm.DB, log *zap.Logger) ([]string, error) {
+	m := db.Migrator()
+	if !m.HasTable("teams") || m.HasColumn("teams", "tenant_id") {
+		return nil, nil
+	}
+	switch db.Dialector.Name() {
+	case "postgres":
+	case "sqlite":
+		return renameLegacyTables(db)
+	default:
+		return nil, nil
+	}
+
+	return nil, db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range tenantKeys {
+			if !m.HasTable(t.table) {
+				continue
+			}
+			stmts := []string{fmt.Sprintf(`ALTER TABLE %s ADD COLUMN tenant_id text NOT NULL DEFAULT '%s'`, t.table, tenant.Default)}
+			if len(t.key) > 0 {
+				stmts = append(stmts,
+					fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %s_pkey CASCADE`, t.table, t.table),
+					fmt.Sprintf(`ALTER TABLE %s ADD PRIMARY KEY (tenant_id, %s)`, t.table, strings.Join(t.key, ", ")),
+				)
+			}
+			for _, s := range stmts {
+				if err := tx.Exec(s).Error; err != nil {
+					return fmt.Errorf("%s: %w", t.table, err)
+				}
+			}
+		}
+		log.Info("Существующие данные перенесены в тенант по умолчанию", zap.String("tenant_id", tenant.Default))
+		return nil
+	})
+}
+
+func renameLegacyTables(db *gorm.DB) ([]string, error) {
+	m := db.Migrator()
+	var legacy []string
+	for _, t := range tenantKeys {
+		if !m.HasTable(t.table) {
+			continue
+		}
+		// Index names are global in SQLite; AutoMigrate recreates them.
+		var indexes []string
+		err := db.Raw(`SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL`, t.table).
+			Scan(&indexes).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range indexes {
+			if err := db.Exec(fmt.Sprintf("DROP INDEX %q", idx)).Error; err != nil {
+				return nil, err
+			}
+		}
+		if err := m.RenameTable(t.table, t.table+"_legacy"); err != nil {
+			return nil, fmt.Errorf("%s: %w", t.table, err)
+		}
+		legacy = append(legacy, t.table)
+	}
+	return legacy, nil
+}
+
+// copyLegacyTables fills the tables created by AutoMigrate from the renamed
+// ones; tenant_id takes its default.
+func copyLegacyTables(db *gorm.DB, tables []string, log *zap.Logger) error {
+	if len(tables) == 0 {
+		return nil
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			types, err := tx.Migrator().ColumnTypes(table + "_legacy")
+			if err != nil {
+				return err
+			}
+			cols := make([]string, 0, len(types))
+			for _, c := range types {
+				cols = append(cols, fmt.Sprintf("%q", c.Name()))
+			}
+			list := strings.Join(cols, ", ")
+			stmts := []string{
+				fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s_legacy", table, list, list, table),
+				fmt.Sprintf("DROP TABLE %s_legacy", table),
+			}
+			for _, s := range stmts {
+				if err := tx.Exec(s).Error; err != nil {
+					return fmt.Errorf("%s: %w", table, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		log.Info("Существующие данные перенесены в тенант по умолчанию", zap.String("tenant_id", tenant.Default))
+	}
+	return err
+}
+
+// backfillMemberships gives every user a membership in the team stored on
+// the user, which used to be the only team a user could belong to.
+func backfillMemberships(db *gorm.DB, log *zap.Logger) error {
+	res := db.Exec(`INSERT INTO team_memberships (tenant_id, team_name, user_id, role, is_active, created_at)
+		SELECT tenant_id, team_name, user_id, ?, ?, created_at FROM users`, models.MembershipMember, true)
+	if res.Error != nil {
+		return fmt.Errorf("team_memberships: %w", res.Error)
+	}
+	log.Info("Участники команд перенесены в членства", zap.Int64("rows", res.RowsAffected))
+	return nil
+}
+
+// PendingMigrations returns the tables of managed models that are missing in db.
+func PendingMigrations(db *gorm.DB) []string {
+	migrator := db.Migrator()
+	var pending []string
+	for _, m := range Models() {
+		if !migrator.HasTable(m) {
+			stmt := &gorm.Statement{DB: db}
+			if err := stmt.Parse(m); err != nil {
+				pending = append(pending, fmt.Sprintf("%T", m))
+				continue
+			}
+			pending = append(pending, stmt.Schema.Table)
+		}
+	}
+	return pending
+}
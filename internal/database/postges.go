@@ -1,26 +1,89 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"reviewer_pr/internal/config"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectDB(cfg *config.DB, log *zap.Logger) *gorm.DB {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
+// ConnectDB opens the primary database, retrying with exponential backoff while
+// Postgres is not reachable yet (e.g. right after docker-compose up).
+func ConnectDB(ctx context.Context, cfg *config.DB, log *zap.Logger) (*gorm.DB, error) {
+	return connect(ctx, cfg, cfg.DSN(), "primary", log)
+}
+
+// ConnectReplica opens the read replica. It returns nil when no replica is configured.
+func ConnectReplica(ctx context.Context, cfg *config.DB, log *zap.Logger) (*gorm.DB, error) {
+	if cfg.ReplicaDSN == "" {
+		return nil, nil
+	}
+	return connect(ctx, cfg, cfg.ReplicaDSN, "replica", log)
+}
+
+func connect(ctx context.Context, cfg *config.DB, dsn, role string, log *zap.Logger) (*gorm.DB, error) {
+	log = log.With(zap.String("db", role))
+	backoff := cfg.ConnectBackoff
+
+	for attempt := 0; ; attempt++ {
+		db, err := open(ctx, cfg, dsn)
+		if err == nil {
+			log.Info("Подключение к базе данных успешно установлено", zap.Int("attempt", attempt+1))
+			return db, nil
+		}
+
+		if attempt >= cfg.ConnectRetries {
+			return nil, fmt.Errorf("connect to %s database after %d attempts: %w", role, attempt+1, err)
+		}
+
+		log.Warn("Не удалось подключиться к базе данных, повтор",
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
 
+		backoff *= 2
+		if backoff > cfg.ConnectMaxBackoff {
+			backoff = cfg.ConnectMaxBackoff
+		}
+	}
+}
+
+func open(ctx context.Context, cfg *config.DB, dsn string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{PrepareStmt: false})
 	if err != nil {
-		log.Fatal("Не удалось подключиться к базе данных", zap.Error(err))
-		return nil
+		if db != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				_ = sqlDB.Close()
+			}
+		}
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	log.Info("Подключение к базе данных успешно установлено")
-	return db
+	if err := sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
 func CloseDB(db *gorm.DB, log *zap.Logger) {
@@ -0,0 +1,69 @@
+// Package digest builds and emails the daily list of open pull requests a
+// reviewer is assigned to.
+package digest
+
+import (
+	"fmt"
+	"reviewer_pr/internal/models"
+	"time"
+)
+
+// TimeOfDayLayout is the format of DigestSubscription.SendAt.
+const TimeOfDayLayout = "15:04"
+
+// Digest is the data passed to the templates.
+type Digest struct {
+	User models.User
+	// GeneratedAt is in the user's timezone.
+	GeneratedAt time.Time
+	// Items are ordered from the oldest pull request.
+	Items []Item
+}
+
+type Item struct {
+	PR     models.PullRequest
+	Author models.User
+	// Age is how long the pull request has been open.
+	Age time.Duration
+}
+
+// Location returns the timezone of sub.
+func Location(sub models.DigestSubscription) (*time.Location, error) {
+	return time.LoadLocation(sub.Timezone)
+}
+
+// Due reports whether today's digest for sub (in its timezone) is due at now
+// and has not been sent yet.
+func Due(sub models.DigestSubscription, now time.Time) (bool, error) {
+	loc, err := Location(sub)
+	if err != nil {
+		return false, err
+	}
+	at, err := time.Parse(TimeOfDayLayout, sub.SendAt)
+	if err != nil {
+		return false, fmt.Errorf("send_at %q: %w", sub.SendAt, err)
+	}
+
+	local := now.In(loc)
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), at.Hour(), at.Minute(), 0, 0, loc)
+	if local.Before(scheduled) {
+		return false, nil
+	}
+	return sub.LastSentAt == nil || sub.LastSentAt.Before(scheduled), nil
+}
+
+// FormatAge renders d as "3d 4h", "5h 12m" or "7m".
+func FormatAge(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
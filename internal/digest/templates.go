@@ -0,0 +1,108 @@
+package digest
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+const DefaultSubjectTemplate = `{{len .Items}} pull request{{if ne (len .Items) 1}}s{{end}} waiting for your review`
+
+const DefaultTextTemplate = `Hi {{.User.Username}},
+
+{{len .Items}} pull request{{if ne (len .Items) 1}}s are{{else}} is{{end}} waiting for your review:
+{{range .Items}}
+- {{.PR.Name}} ({{.PR.ID}}) by {{.Author.Username}}, open for {{age .Age}}{{end}}
+
+Generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}.
+`
+
+const DefaultHTMLTemplate = `<!DOCTYPE html>
+<html>
+<body>
+<p>Hi {{.User.Username}},</p>
+<p>{{len .Items}} pull request{{if ne (len .Items) 1}}s are{{else}} is{{end}} waiting for your review:</p>
+<table>
+<tr><th align="left">Pull request</th><th align="left">Author</th><th align="left">Open for</th></tr>
+{{- range .Items}}
+<tr><td>{{.PR.Name}} ({{.PR.ID}})</td><td>{{.Author.Username}}</td><td>{{age .Age}}</td></tr>
+{{- end}}
+</table>
+<p>Generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}.</p>
+</body>
+</html>
+`
+
+// Templates are Go template sources; empty ones use the defaults above. HTML
+// is parsed with html/template, so values are escaped.
+type Templates struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Rendered is a digest ready to be sent.
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Renderer turns digests into email bodies.
+type Renderer struct {
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+}
+
+var defaultRenderer = func() *Renderer {
+	r, err := NewRenderer(Templates{})
+	if err != nil {
+		panic(err)
+	}
+	return r
+}()
+
+// DefaultRenderer renders the built-in templates.
+func DefaultRenderer() *Renderer {
+	return defaultRenderer
+}
+
+func NewRenderer(t Templates) (*Renderer, error) {
+	funcs := map[string]any{"age": FormatAge}
+
+	subject, err := template.New("subject").Funcs(funcs).Option("missingkey=error").Parse(orDefault(t.Subject, DefaultSubjectTemplate))
+	if err != nil {
+		return nil, err
+	}
+	text, err := template.New("text").Funcs(funcs).Option("missingkey=error").Parse(orDefault(t.Text, DefaultTextTemplate))
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("html").Funcs(funcs).Option("missingkey=error").Parse(orDefault(t.HTML, DefaultHTMLTemplate))
+	if err != nil {
+		return nil, err
+	}
+	return &Renderer{subject: subject, text: text, html: html}, nil
+}
+
+func (r *Renderer) Render(d Digest) (*Rendered, error) {
+	var subject, text, html bytes.Buffer
+	if err := r.subject.Execute(&subject, d); err != nil {
+		return nil, err
+	}
+	if err := r.text.Execute(&text, d); err != nil {
+		return nil, err
+	}
+	if err := r.html.Execute(&html, d); err != nil {
+		return nil, err
+	}
+	return &Rendered{Subject: subject.String(), Text: text.String(), HTML: html.String()}, nil
+}
+
+func orDefault(src, def string) string {
+	if src == "" {
+		return def
+	}
+	return src
+}
@@ -0,0 +1,83 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/service"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain is set on the ErrorInfo detail; its Reason carries the same
+// error code as the "code" field of HTTP error responses.
+const ErrorDomain = "reviewer_pr"
+
+func toStatus(ctx context.Context, err error) error {
+	var serr *service.Error
+	if !errors.As(err, &serr) {
+		logger.FromContext(ctx, zap.NewNop()).Error("unhandled service error", zap.Error(err))
+		return status.Error(codes.Internal, "internal server error")
+	}
+	return statusFromServiceErr(serr).Err()
+}
+
+func statusFromServiceErr(serr *service.Error) *status.Status {
+	st := status.New(mapSerErrToCode(serr.Code), serr.Msg)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: string(serr.Code),
+		Domain: ErrorDomain,
+	}}
+	if len(serr.Details) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, d := range serr.Details {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       d.Field,
+				Description: d.Message,
+			})
+		}
+		details = append(details, br)
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+func mapSerErrToCode(code service.ErrorCode) codes.Code {
+	switch code {
+	case service.ErrorCodeValidation,
+		service.ErrorCodeInvalidRequest,
+		service.ErrorCodeIdempotencyKeyReused:
+		return codes.InvalidArgument
+	case service.ErrorCodeTeamExists,
+		service.ErrorCodePRExists:
+		return codes.AlreadyExists
+	case service.ErrorCodePRMerged,
+		service.ErrorCodeNotAssigned,
+		service.ErrorCodeNoCandidate,
+		service.ErrorCodeAlreadyAssigned,
+		service.ErrorCodeReviewerNotAllowed,
+		service.ErrorCodeReviewerLimit,
+		service.ErrorCodeResourceInUse:
+		return codes.FailedPrecondition
+	case service.ErrorCodePreconditionFailed,
+		service.ErrorCodeIdempotencyInProgress:
+		return codes.Aborted
+	case service.ErrorCodeNotFound:
+		return codes.NotFound
+	case service.ErrorCodeUnauthorized:
+		return codes.Unauthenticated
+	case service.ErrorCodeForbidden:
+		return codes.PermissionDenied
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,34 @@
+package grpcapi
+
+import (
+	"context"
+	"reviewer_pr/internal/service"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthServer reports the overall ("") service as serving only while the
+// readiness checks of /readyz pass. Shutting down switches it to NOT_SERVING
+// for good.
+type healthServer struct {
+	*health.Server
+	readiness service.HealthService
+}
+
+func newHealthServer(readiness service.HealthService) *healthServer {
+	h := &healthServer{Server: health.NewServer(), readiness: readiness}
+	readiness.OnShuttingDown(h.Shutdown)
+	return h
+}
+
+func (h *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() == "" {
+		status := healthpb.HealthCheckResponse_SERVING
+		if !h.readiness.Readiness(ctx).Ready {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		h.SetServingStatus("", status)
+	}
+	return h.Server.Check(ctx, req)
+}
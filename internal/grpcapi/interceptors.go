@@ -0,0 +1,121 @@
+package grpcapi
+
+import (
+	"context"
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/requestid"
+	"reviewer_pr/internal/tenant"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRequestID carries the same value as the X-Request-ID HTTP header.
+const MetadataRequestID = "x-request-id"
+
+// adminMethods require auth.RoleAdmin, matching the admin-only HTTP routes.
+var adminMethods = map[string]bool{
+	reviewerv1.TeamService_CreateTeam_FullMethodName:  true,
+	reviewerv1.UserService_SetIsActive_FullMethodName: true,
+}
+
+type ctxKeyCaller struct{}
+
+// caller is filled in by authenticate so that accessLog, which runs before
+// it, can attribute the call.
+type caller struct {
+	actor  auth.Role
+	tenant string
+}
+
+// requestContext propagates x-request-id (or generates one), echoes it in the
+// response header and stores a request-scoped logger in the context.
+func (s *Server) requestContext(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	requestID := requestid.OrNew(firstMetadata(ctx, MetadataRequestID))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, requestID))
+
+	reqLog := s.log.With(zap.String("request_id", requestID))
+	return handler(logger.WithContext(ctx, reqLog), req)
+}
+
+// accessLog writes one structured log entry per call.
+func (s *Server) accessLog(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	c := &caller{}
+	resp, err := handler(context.WithValue(ctx, ctxKeyCaller{}, c), req)
+
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", info.FullMethod),
+		zap.String("code", code.String()),
+		zap.Duration("latency", time.Since(start)),
+	}
+	if c.actor != "" {
+		fields = append(fields, zap.String("actor", string(c.actor)), zap.String("tenant", c.tenant))
+	}
+
+	log := logger.FromContext(ctx, s.log)
+	switch code {
+	case codes.OK:
+		log.Info("grpc request", fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		log.Error("grpc request", append(fields, zap.Error(err))...)
+	default:
+		log.Warn("grpc request", fields...)
+	}
+	return resp, err
+}
+
+func (s *Server) recovery(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			logger.FromContext(ctx, s.log).Error("grpc handler panicked",
+				zap.String("method", info.FullMethod),
+				zap.Any("panic", p),
+				zap.ByteString("stack", debug.Stack()),
+			)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// authenticate checks the bearer token from the "authorization" metadata with
+// the same Authenticator and role rules as the HTTP API, and scopes the call
+// to the token's tenant.
+func (s *Server) authenticate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.auth == nil {
+		return handler(ctx, req)
+	}
+
+	role, tenantID, ok := s.auth.Authenticate(auth.BearerToken(firstMetadata(ctx, "authorization")))
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+	if c, ok := ctx.Value(ctxKeyCaller{}).(*caller); ok {
+		c.actor, c.tenant = role, tenantID
+	}
+	if adminMethods[info.FullMethod] && !role.Allows(auth.RoleAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+	}
+
+	return handler(tenant.WithContext(ctx, tenantID), req)
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package grpcapi
+
+import (
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/service"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toTeamPB(t *service.TeamWithMembers) *reviewerv1.Team {
+	members := make([]*reviewerv1.TeamMember, 0, len(t.Members))
+	for _, u := range t.Members {
+		members = append(members, &reviewerv1.TeamMember{
+			UserId:   u.ID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		})
+	}
+	return &reviewerv1.Team{
+		TeamName: t.Team.Name,
+		Members:  members,
+	}
+}
+
+func toUserPB(u *models.User) *reviewerv1.User {
+	return &reviewerv1.User{
+		UserId:   u.ID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+		IsActive: u.IsActive,
+	}
+}
+
+func toStatusPB(s models.PullRequestStatus) reviewerv1.PullRequestStatus {
+	switch s {
+	case models.PRStatusOpen:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case models.PRStatusMerged:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
+
+func toPullRequestPB(pr *models.PullRequest, reviewerIDs []string) *reviewerv1.PullRequest {
+	out := &reviewerv1.PullRequest{
+		PullRequestId:     pr.ID,
+		PullRequestName:   pr.Name,
+		AuthorId:          pr.AuthorID,
+		Status:            toStatusPB(pr.Status),
+		AssignedReviewers: reviewerIDs,
+		CreatedAt:         timestamppb.New(pr.CreatedAt),
+	}
+	if pr.MergedAt != nil {
+		out.MergedAt = timestamppb.New(*pr.MergedAt)
+	}
+	return out
+}
+
+func reviewerIDs(reviewers []models.PRReviewer) []string {
+	ids := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		ids = append(ids, r.ReviewerID)
+	}
+	return ids
+}
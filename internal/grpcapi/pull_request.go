@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"context"
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+	"reviewer_pr/internal/service"
+)
+
+type pullRequestServer struct {
+	reviewerv1.UnimplementedPullRequestServiceServer
+	*Server
+}
+
+func (s *pullRequestServer) CreatePullRequest(ctx context.Context, req *reviewerv1.CreatePullRequestRequest) (*reviewerv1.CreatePullRequestResponse, error) {
+	res, err := s.services.PRs.CreateWithAutoAssign(ctx, service.CreatePRInput{
+		ID:       req.GetPullRequestId(),
+		Name:     req.GetPullRequestName(),
+		AuthorID: req.GetAuthorId(),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	ids := make([]string, 0, len(res.Reviewers))
+	for _, u := range res.Reviewers {
+		ids = append(ids, u.ID)
+	}
+	return &reviewerv1.CreatePullRequestResponse{PullRequest: toPullRequestPB(res.PR, ids)}, nil
+}
+
+func (s *pullRequestServer) GetPullRequest(ctx context.Context, req *reviewerv1.GetPullRequestRequest) (*reviewerv1.GetPullRequestResponse, error) {
+	res, err := s.services.PRs.GetPR(ctx, req.GetPullRequestId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &reviewerv1.GetPullRequestResponse{PullRequest: toPullRequestPB(res.PR, reviewerIDs(res.Reviewers))}, nil
+}
+
+func (s *pullRequestServer) MergePullRequest(ctx context.Context, req *reviewerv1.MergePullRequestRequest) (*reviewerv1.MergePullRequestResponse, error) {
+	if _, err := s.services.PRs.Merge(ctx, req.GetPullRequestId()); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	res, err := s.services.PRs.GetPR(ctx, req.GetPullRequestId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &reviewerv1.MergePullRequestResponse{PullRequest: toPullRequestPB(res.PR, reviewerIDs(res.Reviewers))}, nil
+}
+
+func (s *pullRequestServer) ReassignReviewer(ctx context.Context, req *reviewerv1.ReassignReviewerRequest) (*reviewerv1.ReassignReviewerResponse, error) {
+	out, err := s.services.PRs.ReassignReviewer(ctx, service.ReassignInput{
+		PRID:          req.GetPullRequestId(),
+		OldReviewerID: req.GetOldReviewerId(),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	res, err := s.services.PRs.GetPR(ctx, out.PR.ID)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &reviewerv1.ReassignReviewerResponse{
+		PullRequest: toPullRequestPB(res.PR, reviewerIDs(res.Reviewers)),
+		ReplacedBy:  out.ReplacedByID,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package grpcapi
+
+import (
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/service"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Server holds the dependencies shared by the gRPC service implementations.
+type Server struct {
+	services *service.Services
+	log      *zap.Logger
+	auth     *auth.Authenticator
+	stats    bool
+}
+
+type Option func(*Server)
+
+// WithAuth requires a bearer token in the "authorization" metadata of every call.
+func WithAuth(a *auth.Authenticator) Option {
+	return func(s *Server) { s.auth = a }
+}
+
+// WithStats controls whether StatsService is registered, like the /stats route.
+func WithStats(enabled bool) Option {
+	return func(s *Server) { s.stats = enabled }
+}
+
+// New builds a gRPC server exposing the Team, User, PullRequest and Stats
+// services on top of the same service layer as the HTTP API.
+func New(services *service.Services, log *zap.Logger, opts ...Option) *grpc.Server {
+	s := &Server{services: services, log: log, stats: true}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		s.requestContext,
+		s.accessLog,
+		s.recovery,
+		s.authenticate,
+	))
+
+	reviewerv1.RegisterTeamServiceServer(srv, &teamServer{Server: s})
+	reviewerv1.RegisterUserServiceServer(srv, &userServer{Server: s})
+	reviewerv1.RegisterPullRequestServiceServer(srv, &pullRequestServer{Server: s})
+	if s.stats {
+		reviewerv1.RegisterStatsServiceServer(srv, &statsServer{Server: s})
+	}
+
+	healthpb.RegisterHealthServer(srv, newHealthServer(services.Health))
+
+	return srv
+}
@@ -0,0 +1,38 @@
+package grpcapi
+
+import (
+	"context"
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+)
+
+type statsServer struct {
+	reviewerv1.UnimplementedStatsServiceServer
+	*Server
+}
+
+func (s *statsServer) GetStats(ctx context.Context, _ *reviewerv1.GetStatsRequest) (*reviewerv1.GetStatsResponse, error) {
+	stats, err := s.services.Stats.GetStats(ctx)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	resp := &reviewerv1.GetStatsResponse{
+		ByUser: make([]*reviewerv1.UserStats, 0, len(stats.ByUser)),
+		ByPr:   make([]*reviewerv1.PullRequestStats, 0, len(stats.ByPR)),
+	}
+	for _, u := range stats.ByUser {
+		resp.ByUser = append(resp.ByUser, &reviewerv1.UserStats{
+			UserId:      u.UserID,
+			Username:    u.Username,
+			TeamName:    u.TeamName,
+			ReviewCount: u.ReviewCount,
+		})
+	}
+	for _, p := range stats.ByPR {
+		resp.ByPr = append(resp.ByPr, &reviewerv1.PullRequestStats{
+			PullRequestId: p.PullRequestID,
+			ReviewerCount: p.ReviewerCount,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,41 @@
+package grpcapi
+
+import (
+	"context"
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+	"reviewer_pr/internal/service"
+)
+
+type teamServer struct {
+	reviewerv1.UnimplementedTeamServiceServer
+	*Server
+}
+
+func (s *teamServer) CreateTeam(ctx context.Context, req *reviewerv1.CreateTeamRequest) (*reviewerv1.CreateTeamResponse, error) {
+	team := req.GetTeam()
+	in := service.CreateTeamInput{
+		TeamName: team.GetTeamName(),
+		Members:  make([]service.CreateTeamMemberInput, 0, len(team.GetMembers())),
+	}
+	for _, m := range team.GetMembers() {
+		in.Members = append(in.Members, service.CreateTeamMemberInput{
+			UserID:   m.GetUserId(),
+			Username: m.GetUsername(),
+			IsActive: m.GetIsActive(),
+		})
+	}
+
+	res, err := s.services.Teams.AddTeam(ctx, in)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &reviewerv1.CreateTeamResponse{Team: toTeamPB(res)}, nil
+}
+
+func (s *teamServer) GetTeam(ctx context.Context, req *reviewerv1.GetTeamRequest) (*reviewerv1.GetTeamResponse, error) {
+	res, err := s.services.Teams.GetTeam(ctx, req.GetTeamName())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &reviewerv1.GetTeamResponse{Team: toTeamPB(res)}, nil
+}
@@ -0,0 +1,48 @@
+package grpcapi
+
+import (
+	"context"
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+)
+
+type userServer struct {
+	reviewerv1.UnimplementedUserServiceServer
+	*Server
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *reviewerv1.GetUserRequest) (*reviewerv1.GetUserResponse, error) {
+	u, err := s.services.Users.GetUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &reviewerv1.GetUserResponse{User: toUserPB(u)}, nil
+}
+
+func (s *userServer) SetIsActive(ctx context.Context, req *reviewerv1.SetIsActiveRequest) (*reviewerv1.SetIsActiveResponse, error) {
+	u, err := s.services.Users.SetIsActive(ctx, req.GetUserId(), req.GetIsActive())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &reviewerv1.SetIsActiveResponse{User: toUserPB(u)}, nil
+}
+
+func (s *userServer) GetReviews(ctx context.Context, req *reviewerv1.GetReviewsRequest) (*reviewerv1.GetReviewsResponse, error) {
+	prs, err := s.services.PRs.GetReviewsByUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	out := make([]*reviewerv1.PullRequestShort, 0, len(prs))
+	for _, pr := range prs {
+		out = append(out, &reviewerv1.PullRequestShort{
+			PullRequestId:   pr.ID,
+			PullRequestName: pr.Name,
+			AuthorId:        pr.AuthorID,
+			Status:          toStatusPB(pr.Status),
+		})
+	}
+	return &reviewerv1.GetReviewsResponse{
+		UserId:       req.GetUserId(),
+		PullRequests: out,
+	}, nil
+}
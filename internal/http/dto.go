@@ -6,6 +6,21 @@ type TeamMemberDTO struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	// Role and MembershipActive describe the membership in this team.
+	Role             string `json:"role,omitempty"`
+	MembershipActive *bool  `json:"membership_active,omitempty"`
+}
+
+type setTeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"`
+	IsActive *bool  `json:"is_active"`
+}
+
+type removeTeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
 }
 
 type TeamDTO struct {
@@ -18,6 +33,8 @@ type UserDTO struct {
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	// AutoAssignOptOut is omitted for users who can be picked automatically.
+	AutoAssignOptOut bool `json:"auto_assign_opt_out,omitempty"`
 }
 
 type PullRequestDTO struct {
@@ -26,11 +43,41 @@ type PullRequestDTO struct {
 	AuthorID          string   `json:"author_id"`
 	Status            string   `json:"status"` // "OPEN" / "MERGED"
 	AssignedReviewers []string `json:"assigned_reviewers"`
+	Labels            []string `json:"labels,omitempty"`
 
 	CreatedAt *time.Time `json:"createdAt,omitempty"`
 	MergedAt  *time.Time `json:"mergedAt,omitempty"`
 }
 
+type ReviewersDTO struct {
+	PullRequestID string   `json:"pull_request_id"`
+	Reviewers     []string `json:"reviewers"`
+	ReplacedBy    string   `json:"replaced_by,omitempty"`
+}
+
+type AssignmentEventDTO struct {
+	// PullRequestID is only set in the audit log, which spans pull requests.
+	PullRequestID      string    `json:"pull_request_id,omitempty"`
+	Kind               string    `json:"kind"`
+	ReviewerID         string    `json:"reviewer_id"`
+	PreviousReviewerID string    `json:"previous_reviewer_id,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	RequestedBy        string    `json:"requested_by,omitempty"`
+	Strategy           string    `json:"strategy,omitempty"`
+	Seed               int64     `json:"seed,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type AssignmentHistoryDTO struct {
+	PullRequestID string               `json:"pull_request_id"`
+	Events        []AssignmentEventDTO `json:"events"`
+}
+
+type AuditLogDTO struct {
+	Events []AssignmentEventDTO `json:"events"`
+	PageDTO
+}
+
 type PullRequestShortDTO struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
@@ -54,3 +101,229 @@ type StatsResponseDTO struct {
 	ByUser []UserStatsDTO `json:"by_user"`
 	ByPR   []PRStatsDTO   `json:"by_pr"`
 }
+
+type CheckDTO struct {
+	Status string `json:"status"`
+}
+
+type ReadinessDTO struct {
+	Status            string              `json:"status"` // "ready" / "not_ready"
+	Checks            map[string]CheckDTO `json:"checks"`
+	PendingMigrations []string            `json:"pending_migrations,omitempty"`
+}
+
+type FieldChangeDTO struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type ImportChangeDTO struct {
+	Line     int              `json:"line,omitempty"`
+	Action   string           `json:"action"`
+	TeamName string           `json:"team_name"`
+	UserID   string           `json:"user_id,omitempty"`
+	Fields   []FieldChangeDTO `json:"fields,omitempty"`
+}
+
+type ImportSummaryDTO struct {
+	TeamsCreated     int `json:"teams_created"`
+	UsersCreated     int `json:"users_created"`
+	UsersUpdated     int `json:"users_updated"`
+	UsersDeactivated int `json:"users_deactivated"`
+	Unchanged        int `json:"unchanged"`
+}
+
+type ImportResultDTO struct {
+	DryRun  bool              `json:"dry_run"`
+	Summary ImportSummaryDTO  `json:"summary"`
+	Changes []ImportChangeDTO `json:"changes"`
+}
+
+type BackupCountsDTO struct {
+	Teams        int64 `json:"teams"`
+	Users        int64 `json:"users"`
+	Memberships  int64 `json:"memberships"`
+	Digests      int64 `json:"digest_subscriptions"`
+	TeamSLAs     int64 `json:"team_slas"`
+	CodeOwners   int64 `json:"code_owner_rules"`
+	Skills       int64 `json:"user_skills"`
+	Exclusions   int64 `json:"reviewer_exclusions"`
+	PullRequests int64 `json:"pull_requests"`
+	Reviewers    int64 `json:"reviewers"`
+	Events       int64 `json:"assignment_events"`
+}
+
+type PageDTO struct {
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+type TeamSummaryDTO struct {
+	TeamName          string `json:"team_name"`
+	MemberCount       int64  `json:"member_count"`
+	ActiveMemberCount int64  `json:"active_member_count"`
+	OpenPRCount       int64  `json:"open_pr_count"`
+}
+
+type TeamListDTO struct {
+	Teams []TeamSummaryDTO `json:"teams"`
+	PageDTO
+}
+
+type UserListDTO struct {
+	Users []UserDTO `json:"users"`
+	PageDTO
+}
+
+type DigestSubscriptionDTO struct {
+	UserID     string     `json:"user_id"`
+	Enabled    bool       `json:"enabled"`
+	Email      string     `json:"email,omitempty"`
+	SendAt     string     `json:"send_at,omitempty"`
+	Timezone   string     `json:"timezone,omitempty"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+}
+
+type DigestItemDTO struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	AuthorName      string    `json:"author_name"`
+	CreatedAt       time.Time `json:"created_at"`
+	AgeSeconds      int64     `json:"age_seconds"`
+}
+
+type DigestPreviewDTO struct {
+	UserID       string          `json:"user_id"`
+	Timezone     string          `json:"timezone"`
+	Subject      string          `json:"subject"`
+	Text         string          `json:"text"`
+	HTML         string          `json:"html"`
+	PullRequests []DigestItemDTO `json:"pull_requests"`
+}
+
+type TeamSLADTO struct {
+	TeamName         string `json:"team_name"`
+	ReviewSLASeconds int64  `json:"review_sla_seconds"`
+	MergeSLASeconds  int64  `json:"merge_sla_seconds"`
+	AutoReassign     bool   `json:"auto_reassign"`
+}
+
+// ReviewerPickDTO explains why a reviewer was assigned.
+type ReviewerPickDTO struct {
+	UserID        string     `json:"user_id"`
+	Reason        string     `json:"reason"` // "code_owner" / "skills" / "team"
+	Pattern       string     `json:"pattern,omitempty"`
+	MatchedSkills []SkillDTO `json:"matched_skills,omitempty"`
+	OpenReviews   int64      `json:"open_reviews"`
+}
+
+type SkillDTO struct {
+	Skill       string `json:"skill"`
+	Proficiency int    `json:"proficiency,omitempty"`
+}
+
+type UserSkillsDTO struct {
+	UserID string     `json:"user_id"`
+	Skills []SkillDTO `json:"skills"`
+}
+
+// SkippedCandidateDTO is a candidate that exclusion rules kept from being
+// picked automatically.
+type SkippedCandidateDTO struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"` // "opted_out" / "excluded"
+}
+
+// SimulationDTO is a dry run of automatic assignment.
+type SimulationDTO struct {
+	AuthorID   string                  `json:"author_id"`
+	Strategy   string                  `json:"strategy"`
+	Seed       int64                   `json:"seed,omitempty"`
+	Count      int                     `json:"count"`
+	Candidates []SimulatedCandidateDTO `json:"candidates"`
+	Assignment []ReviewerPickDTO       `json:"assignment"`
+	Load       []ProjectedLoadDTO      `json:"load"`
+}
+
+type SimulatedCandidateDTO struct {
+	UserID      string `json:"user_id"`
+	TeamName    string `json:"team_name"`
+	Status      string `json:"status"` // "picked" / "capacity" / "author" / "inactive" / "opted_out" / "excluded"
+	OpenReviews int64  `json:"open_reviews"`
+}
+
+type ProjectedLoadDTO struct {
+	UserID      string `json:"user_id"`
+	OpenReviews int64  `json:"open_reviews"`
+	Assigned    int    `json:"assigned"`
+	Projected   int64  `json:"projected"`
+}
+
+type ExclusionDTO struct {
+	AuthorID   string    `json:"author_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ExclusionListDTO struct {
+	Exclusions []ExclusionDTO `json:"exclusions"`
+}
+
+type CodeOwnersDTO struct {
+	TeamName string             `json:"team_name"`
+	Rules    []CodeOwnerRuleDTO `json:"rules"`
+}
+
+type CodeOwnerRuleDTO struct {
+	Pattern string   `json:"pattern"`
+	Users   []string `json:"users"`
+	Teams   []string `json:"teams"`
+}
+
+type SLABreachDTO struct {
+	Kind           string    `json:"kind"` // "review" / "merge"
+	ReviewerID     string    `json:"reviewer_id,omitempty"`
+	Deadline       time.Time `json:"deadline"`
+	OverdueSeconds int64     `json:"overdue_seconds"`
+}
+
+type OverduePullRequestDTO struct {
+	PullRequestShortDTO
+	TeamName  string         `json:"team_name"`
+	CreatedAt time.Time      `json:"created_at"`
+	Breaches  []SLABreachDTO `json:"breaches"`
+}
+
+type JobRunDTO struct {
+	RunID       uint64     `json:"run_id"`
+	Job         string     `json:"job"`
+	Trigger     string     `json:"trigger"` // "schedule" / "manual"
+	Status      string     `json:"status"`  // "running" / "succeeded" / "failed"
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+	Error       string     `json:"error,omitempty"`
+}
+
+type JobDTO struct {
+	Job       string     `json:"job"`
+	Schedule  string     `json:"schedule"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	Running   bool       `json:"running"`
+	LastRun   *JobRunDTO `json:"last_run,omitempty"`
+}
+
+type JobRunListDTO struct {
+	Runs []JobRunDTO `json:"runs"`
+	PageDTO
+}
+
+type OverdueListDTO struct {
+	PullRequests []OverduePullRequestDTO `json:"pull_requests"`
+	PageDTO
+}
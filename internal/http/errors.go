@@ -2,52 +2,94 @@ package httpapi
 
 import (
 	"net/http"
+	"reviewer_pr/internal/logger"
 	"reviewer_pr/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-type ErrorBody struct {
-	Code    string `json:"code"`
+type FieldErrorDTO struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
+type ErrorBody struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Details []FieldErrorDTO `json:"details,omitempty"`
+}
+
 type ErrorResponse struct {
-	Error ErrorBody `json:"error"`
+	Error     ErrorBody `json:"error"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+func writeErr(c *gin.Context, status int, code, msg string) {
+	writeErrBody(c, status, ErrorBody{Code: code, Message: msg})
 }
 
-func writeSerErr(c *gin.Context, err error) {
+func writeErrBody(c *gin.Context, status int, body ErrorBody) {
+	c.Set(ctxKeyErrorCode, body.Code)
+	c.JSON(status, ErrorResponse{
+		Error:     body,
+		RequestID: requestIDFrom(c),
+	})
+}
+
+func (h *Handler) writeSerErr(c *gin.Context, err error) {
 	if serr, ok := err.(*service.Error); ok {
-		status := mapSerErrToStatus(serr.Code)
-		c.JSON(status, ErrorResponse{
-			Error: ErrorBody{
-				Code:    string(serr.Code),
-				Message: serr.Msg,
-			},
-		})
+		body := ErrorBody{
+			Code:    string(serr.Code),
+			Message: serr.Msg,
+		}
+		for _, d := range serr.Details {
+			body.Details = append(body.Details, FieldErrorDTO{Field: d.Field, Message: d.Message})
+		}
+		writeErrBody(c, mapSerErrToStatus(serr.Code), body)
 		return
 	}
 
-	c.JSON(http.StatusInternalServerError, ErrorResponse{
-		Error: ErrorBody{
-			Code:    "INTERNAL",
-			Message: "internal server error",
-		},
-	})
+	logger.FromContext(c.Request.Context(), h.log).Error("unhandled service error", zap.Error(err))
+	writeErr(c, http.StatusInternalServerError, string(service.ErrorCodeInternal), "internal server error")
+}
+
+// writeBindErr reports a body that could not be decoded at all.
+func (h *Handler) writeBindErr(c *gin.Context, err error) {
+	logger.FromContext(c.Request.Context(), h.log).Debug("failed to decode request body", zap.Error(err))
+	writeErr(c, http.StatusBadRequest, string(service.ErrorCodeInvalidRequest), "request body is not valid JSON")
 }
 
 func mapSerErrToStatus(code service.ErrorCode) int {
 	switch code {
 	case service.ErrorCodeTeamExists:
 		return http.StatusBadRequest // /team/add -> 400
+	case service.ErrorCodeValidation,
+		service.ErrorCodeInvalidRequest:
+		return http.StatusBadRequest
 	case service.ErrorCodePRExists:
 		return http.StatusConflict // /pullRequest/create -> 409
+	case service.ErrorCodeIdempotencyKeyReused:
+		return http.StatusUnprocessableEntity
+	case service.ErrorCodeIdempotencyInProgress:
+		return http.StatusConflict
 	case service.ErrorCodePRMerged,
 		service.ErrorCodeNotAssigned,
-		service.ErrorCodeNoCandidate:
+		service.ErrorCodeNoCandidate,
+		service.ErrorCodeAlreadyAssigned,
+		service.ErrorCodeReviewerNotAllowed,
+		service.ErrorCodeReviewerLimit:
 		return http.StatusConflict // /pullRequest/reassign -> 409
 	case service.ErrorCodeNotFound:
 		return http.StatusNotFound // 404
+	case service.ErrorCodePreconditionFailed:
+		return http.StatusPreconditionFailed
+	case service.ErrorCodeResourceInUse:
+		return http.StatusConflict
+	case service.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case service.ErrorCodeForbidden:
+		return http.StatusForbidden
 	default:
 		return http.StatusInternalServerError
 	}
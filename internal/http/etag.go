@@ -0,0 +1,62 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// ETags are the resource version, so they only change when the resource does.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion returns the version the client expects to modify. A missing
+// header or "*" means the write is unconditional and yields 0.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	raw := strings.TrimSpace(c.GetHeader(HeaderIfMatch))
+	if raw == "" || raw == "*" {
+		return 0, true
+	}
+
+	version, ok := parseETag(raw)
+	if !ok {
+		writeErr(c, http.StatusBadRequest, string(service.ErrorCodeInvalidRequest), "If-Match must be a single ETag returned by the API")
+		return 0, false
+	}
+	return version, true
+}
+
+// notModified answers a conditional GET with 304 when the client copy is current.
+func notModified(c *gin.Context, version int64) bool {
+	for _, tag := range strings.Split(c.GetHeader(HeaderIfNoneMatch), ",") {
+		tag = strings.TrimSpace(tag)
+		if v, ok := parseETag(tag); tag == "*" || (ok && v == version) {
+			c.Header(HeaderETag, etag(version))
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+func parseETag(tag string) (int64, bool) {
+	tag = strings.TrimPrefix(tag, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAudit lists the reviewer changes of every pull request, newest first.
+func (h *Handler) AdminAudit(c *gin.Context) {
+	page, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+	since, ok := timeQuery(c, "since")
+	if !ok {
+		return
+	}
+	until, ok := timeQuery(c, "until")
+	if !ok {
+		return
+	}
+
+	events, err := h.services.PRs.ListAssignmentEvents(c.Request.Context(), service.ListAssignmentEventsInput{
+		PullRequestID: c.Query("pull_request_id"),
+		UserID:        c.Query("user_id"),
+		Kind:          c.Query("kind"),
+		Since:         since,
+		Until:         until,
+		Page:          page,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuditLogDTO(events))
+}
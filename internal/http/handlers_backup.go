@@ -0,0 +1,53 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"reviewer_pr/internal/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const contentTypeNDJSON = "application/x-ndjson"
+
+// AdminExport streams all data as NDJSON. Once the first line is sent the
+// status can no longer change, so a failure mid-stream shows up as a missing
+// "end" record. The server write timeout does not apply: a large export may
+// take longer, and the client can still cancel it by disconnecting.
+func (h *Handler) AdminExport(c *gin.Context) {
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Warn("cannot lift write deadline for export", zap.Error(err))
+	}
+
+	filename := fmt.Sprintf("reviewer_pr-%s.ndjson", time.Now().UTC().Format("20060102-150405"))
+	c.Header("Content-Type", contentTypeNDJSON)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if _, err := h.services.Backup.Export(c.Request.Context(), c.Writer); err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			h.writeSerErr(c, err)
+			return
+		}
+		logger.FromContext(c.Request.Context(), h.log).Error("export interrupted", zap.Error(err))
+	}
+}
+
+// AdminRestore loads an NDJSON export into an empty database. Like the
+// export, the upload is not limited by the server read timeout.
+func (h *Handler) AdminRestore(c *gin.Context) {
+	if err := http.NewResponseController(c.Writer).SetReadDeadline(time.Time{}); err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Warn("cannot lift read deadline for restore", zap.Error(err))
+	}
+
+	counts, err := h.services.Backup.Restore(c.Request.Context(), c.Request.Body)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"restored": BackupCountsDTO(*counts)})
+}
@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) TeamGetCodeOwners(c *gin.Context) {
+	res, err := h.services.CodeOwners.GetCodeOwners(c.Request.Context(), c.Query("team_name"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCodeOwnersDTO(res))
+}
+
+type setCodeOwnersRequest struct {
+	TeamName string             `json:"team_name"`
+	Rules    []CodeOwnerRuleDTO `json:"rules"`
+}
+
+// TeamSetCodeOwners replaces the team rules; an empty list removes them.
+func (h *Handler) TeamSetCodeOwners(c *gin.Context) {
+	var req setCodeOwnersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	in := service.TeamCodeOwners{TeamName: req.TeamName, Rules: make([]service.CodeOwnerRule, 0, len(req.Rules))}
+	for _, r := range req.Rules {
+		in.Rules = append(in.Rules, service.CodeOwnerRule{Pattern: r.Pattern, Users: r.Users, Teams: r.Teams})
+	}
+
+	res, err := h.services.CodeOwners.SetCodeOwners(c.Request.Context(), in)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCodeOwnersDTO(res))
+}
+
+// TeamImportCodeOwners replaces the team rules with a CODEOWNERS file sent
+// as the request body.
+func (h *Handler) TeamImportCodeOwners(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxImportBodySize+1))
+	if err != nil || len(body) > maxImportBodySize {
+		writeErr(c, http.StatusBadRequest, string(service.ErrorCodeInvalidRequest), "request body is too large or unreadable")
+		return
+	}
+
+	rules, err := service.ParseCodeOwners(bytes.NewReader(body))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	res, err := h.services.CodeOwners.SetCodeOwners(c.Request.Context(), service.TeamCodeOwners{
+		TeamName: c.Query("team_name"),
+		Rules:    rules,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCodeOwnersDTO(res))
+}
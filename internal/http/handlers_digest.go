@@ -0,0 +1,89 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) UserGetDigest(c *gin.Context) {
+	userID := c.Query("user_id")
+	sub, err := h.services.Digests.GetSubscription(c.Request.Context(), userID)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDigestSubscriptionDTO(userID, sub))
+}
+
+type setDigestRequest struct {
+	UserID   string `json:"user_id"`
+	Enabled  *bool  `json:"enabled"`
+	Email    string `json:"email"`
+	SendAt   string `json:"send_at"`
+	Timezone string `json:"timezone"`
+}
+
+// UserSetDigest subscribes the user to the daily digest or, with
+// enabled=false, unsubscribes them.
+func (h *Handler) UserSetDigest(c *gin.Context) {
+	var req setDigestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+	if req.Enabled == nil {
+		h.writeSerErr(c, service.NewValidationErr(service.FieldError{Field: "enabled", Message: "is required"}))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if !*req.Enabled {
+		if err := h.services.Digests.Unsubscribe(ctx, req.UserID); err != nil {
+			h.writeSerErr(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, toDigestSubscriptionDTO(req.UserID, nil))
+		return
+	}
+
+	sub, err := h.services.Digests.Subscribe(ctx, service.SubscribeDigestInput{
+		UserID:   req.UserID,
+		Email:    req.Email,
+		SendAt:   req.SendAt,
+		Timezone: req.Timezone,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDigestSubscriptionDTO(req.UserID, sub))
+}
+
+// UserDigestPreview renders the user's digest without sending it: as JSON by
+// default, or only the text or HTML body with ?format=text|html.
+func (h *Handler) UserDigestPreview(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "text" && format != "html" {
+		h.writeSerErr(c, service.NewValidationErr(service.FieldError{Field: "format", Message: "must be one of json, text, html"}))
+		return
+	}
+
+	p, err := h.services.Digests.Preview(c.Request.Context(), c.Query("user_id"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	switch format {
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(p.Rendered.Text))
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(p.Rendered.HTML))
+	default:
+		c.JSON(http.StatusOK, toDigestPreviewDTO(p))
+	}
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserGetExclusions lists the exclusions involving ?user_id=, or all of them.
+func (h *Handler) UserGetExclusions(c *gin.Context) {
+	rows, err := h.services.Exclusions.ListExclusions(c.Request.Context(), c.Query("user_id"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toExclusionListDTO(rows))
+}
+
+type exclusionRequest struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Reason     string `json:"reason"`
+}
+
+// UserAddExclusion forbids automatic and manual picks of the reviewer for
+// the author's pull requests and returns the author's exclusions.
+func (h *Handler) UserAddExclusion(c *gin.Context) {
+	var req exclusionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	_, err := h.services.Exclusions.AddExclusion(c.Request.Context(), service.ExclusionInput{
+		AuthorID:   req.AuthorID,
+		ReviewerID: req.ReviewerID,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	h.writeExclusions(c, req.AuthorID)
+}
+
+func (h *Handler) UserRemoveExclusion(c *gin.Context) {
+	var req exclusionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	if err := h.services.Exclusions.RemoveExclusion(c.Request.Context(), req.AuthorID, req.ReviewerID); err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	h.writeExclusions(c, req.AuthorID)
+}
+
+func (h *Handler) writeExclusions(c *gin.Context, userID string) {
+	rows, err := h.services.Exclusions.ListExclusions(c.Request.Context(), userID)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toExclusionListDTO(rows))
+}
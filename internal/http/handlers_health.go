@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Health is kept for backward compatibility and reports readiness as plain text.
+func (h *Handler) Health(c *gin.Context) {
+	if !h.services.Health.Readiness(c.Request.Context()).Ready {
+		c.String(http.StatusServiceUnavailable, "unavailable")
+		return
+	}
+	c.String(http.StatusOK, "ok")
+}
+
+func (h *Handler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "alive"})
+}
+
+func (h *Handler) Readyz(c *gin.Context) {
+	res := h.services.Health.Readiness(c.Request.Context())
+
+	dto := ReadinessDTO{
+		Status: "ready",
+		Checks: map[string]CheckDTO{
+			"database":   {Status: string(res.Database.Status)},
+			"migrations": {Status: string(res.Migrations.Status)},
+		},
+		PendingMigrations: res.PendingMigrations,
+	}
+	if res.Replica != nil {
+		dto.Checks["replica"] = CheckDTO{Status: string(res.Replica.Status)}
+	}
+	if res.ShuttingDown {
+		dto.Checks["shutdown"] = CheckDTO{Status: string(service.CheckStatusDown)}
+	}
+
+	status := http.StatusOK
+	if !res.Ready {
+		dto.Status = "not_ready"
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, dto)
+}
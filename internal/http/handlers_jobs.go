@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) AdminJobs(c *gin.Context) {
+	jobs, err := h.services.Jobs.ListJobs(c.Request.Context())
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": toJobDTOs(jobs)})
+}
+
+func (h *Handler) AdminJobRuns(c *gin.Context) {
+	page, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	runs, err := h.services.Jobs.ListRuns(c.Request.Context(), service.ListJobRunsInput{
+		JobName: c.Query("job"),
+		Page:    page,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toJobRunListDTO(runs))
+}
+
+type triggerJobRequest struct {
+	Job string `json:"job"`
+}
+
+// AdminTriggerJob starts a job now. It answers 202 with the new run as soon
+// as the run is recorded; GET /admin/jobs/runs shows the outcome.
+func (h *Handler) AdminTriggerJob(c *gin.Context) {
+	var req triggerJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	run, err := h.services.Jobs.TriggerJob(c.Request.Context(), req.Job)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, toJobRunDTO(run))
+}
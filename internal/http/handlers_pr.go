@@ -1,6 +1,7 @@
 package httpapi
 
 import (
+	"context"
 	"net/http"
 	"reviewer_pr/internal/service"
 
@@ -11,49 +12,38 @@ type createPRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	// ChangedFiles are optional; they select code owners as reviewers.
+	ChangedFiles []string `json:"changed_files"`
+	// Labels are optional; they prefer reviewers with matching skills.
+	Labels []string `json:"labels"`
 }
 
 func (h *Handler) PRCreate(c *gin.Context) {
 	var req createPRRequest
-	if err := c.ShouldBindJSON(&req); err != nil ||
-		req.PullRequestID == "" || req.PullRequestName == "" || req.AuthorID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "NOT_FOUND",
-				Message: "invalid request body",
-			},
-		})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
 		return
 	}
 
 	in := service.CreatePRInput{
-		ID:       req.PullRequestID,
-		Name:     req.PullRequestName,
-		AuthorID: req.AuthorID,
+		ID:           req.PullRequestID,
+		Name:         req.PullRequestName,
+		AuthorID:     req.AuthorID,
+		ChangedFiles: req.ChangedFiles,
+		Labels:       req.Labels,
 	}
 
 	res, err := h.services.PRs.CreateWithAutoAssign(c.Request.Context(), in)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
-	reviewerIDs := make([]string, 0, len(res.Reviewers))
-	for _, u := range res.Reviewers {
-		reviewerIDs = append(reviewerIDs, u.ID)
-	}
-
-	dto := PullRequestDTO{
-		PullRequestID:     res.PR.ID,
-		PullRequestName:   res.PR.Name,
-		AuthorID:          res.PR.AuthorID,
-		Status:            string(res.PR.Status),
-		AssignedReviewers: reviewerIDs,
-		CreatedAt:         &res.PR.CreatedAt,
-		MergedAt:          res.PR.MergedAt,
-	}
-
-	c.JSON(http.StatusCreated, gin.H{"pr": dto})
+	c.JSON(http.StatusCreated, gin.H{
+		"pr":         toPullRequestDTO(res.PR, userIDs(res.Reviewers)),
+		"assignment": toReviewerPickDTOs(res.Picks),
+		"skipped":    toSkippedCandidateDTOs(res.Skipped),
+	})
 }
 
 type mergePRRequest struct {
@@ -62,97 +52,149 @@ type mergePRRequest struct {
 
 func (h *Handler) PRMerge(c *gin.Context) {
 	var req mergePRRequest
-	if err := c.ShouldBindJSON(&req); err != nil || req.PullRequestID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "INVALID_REQUEST",
-				Message: "invalid request body",
-			},
-		})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
 		return
 	}
 
 	pr, err := h.services.PRs.Merge(c.Request.Context(), req.PullRequestID)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
 	reviewers, err := h.services.PRs.GetReviewersForPR(c.Request.Context(), pr.ID)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
-	reviewerIDs := make([]string, 0, len(reviewers))
-	for _, r := range reviewers {
-		reviewerIDs = append(reviewerIDs, r.ReviewerID)
-	}
-
-	dto := PullRequestDTO{
-		PullRequestID:     pr.ID,
-		PullRequestName:   pr.Name,
-		AuthorID:          pr.AuthorID,
-		Status:            string(pr.Status),
-		AssignedReviewers: reviewerIDs,
-		CreatedAt:         &pr.CreatedAt,
-		MergedAt:          pr.MergedAt,
-	}
-
-	c.JSON(http.StatusOK, gin.H{"pr": dto})
+	c.JSON(http.StatusOK, gin.H{"pr": toPullRequestDTO(pr, reviewerIDs(reviewers))})
 }
 
 type reassignPRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldReviewerID string `json:"old_reviewer_id"`
+	// NewReviewerID is optional; without it the replacement is picked.
+	NewReviewerID string `json:"new_reviewer_id"`
+	RequestedBy   string `json:"requested_by"`
 }
 
 func (h *Handler) PRReassign(c *gin.Context) {
 	var req reassignPRRequest
-	if err := c.ShouldBindJSON(&req); err != nil || req.PullRequestID == "" || req.OldReviewerID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "INVALID_REQUEST",
-				Message: "invalid request body",
-			},
-		})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
 		return
 	}
 
 	in := service.ReassignInput{
 		PRID:          req.PullRequestID,
 		OldReviewerID: req.OldReviewerID,
+		NewReviewerID: req.NewReviewerID,
+		RequestedBy:   req.RequestedBy,
 	}
 
 	out, err := h.services.PRs.ReassignReviewer(c.Request.Context(), in)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
 	reviewers, err := h.services.PRs.GetReviewersForPR(c.Request.Context(), out.PR.ID)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr":          toPullRequestDTO(out.PR, reviewerIDs(reviewers)),
+		"replaced_by": out.ReplacedByID,
+		"reason":      out.Reason,
+		"skipped":     toSkippedCandidateDTOs(out.Skipped),
+	})
+}
+
+type reviewerChangeRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	RequestedBy   string `json:"requested_by"`
+}
+
+// PRAddReviewer assigns a named reviewer in addition to the current ones.
+func (h *Handler) PRAddReviewer(c *gin.Context) {
+	h.changeReviewer(c, h.services.PRs.AddReviewer)
+}
+
+// PRRemoveReviewer unassigns a reviewer without picking a replacement.
+func (h *Handler) PRRemoveReviewer(c *gin.Context) {
+	h.changeReviewer(c, h.services.PRs.RemoveReviewer)
+}
+
+func (h *Handler) changeReviewer(c *gin.Context, change func(context.Context, service.ReviewerChangeInput) (*service.PRDetails, error)) {
+	var req reviewerChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	res, err := change(c.Request.Context(), service.ReviewerChangeInput{
+		PRID:        req.PullRequestID,
+		ReviewerID:  req.ReviewerID,
+		RequestedBy: req.RequestedBy,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
 		return
 	}
 
-	reviewerIDs := make([]string, 0, len(reviewers))
-	for _, r := range reviewers {
-		reviewerIDs = append(reviewerIDs, r.ReviewerID)
+	c.JSON(http.StatusOK, gin.H{"pr": toPullRequestDTO(res.PR, reviewerIDs(res.Reviewers))})
+}
+
+func (h *Handler) PRHistory(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	events, err := h.services.PRs.GetAssignmentHistory(c.Request.Context(), prID)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
 	}
 
-	dto := PullRequestDTO{
-		PullRequestID:     out.PR.ID,
-		PullRequestName:   out.PR.Name,
-		AuthorID:          out.PR.AuthorID,
-		Status:            string(out.PR.Status),
-		AssignedReviewers: reviewerIDs,
-		CreatedAt:         &out.PR.CreatedAt,
-		MergedAt:          out.PR.MergedAt,
+	c.JSON(http.StatusOK, toAssignmentHistoryDTO(prID, events))
+}
+
+type simulatePRRequest struct {
+	AuthorID string `json:"author_id"`
+	// PullRequestID is optional; the deterministic strategy seeds from it.
+	PullRequestID  string   `json:"pull_request_id"`
+	ChangedFiles   []string `json:"changed_files"`
+	Labels         []string `json:"labels"`
+	ReviewersPerPR int      `json:"reviewers_per_pr"`
+	Strategy       string   `json:"strategy"`
+	Seed           int64    `json:"seed"`
+	// Count is the number of pull requests to replay, one by default.
+	Count int `json:"count"`
+}
+
+func (h *Handler) PRSimulate(c *gin.Context) {
+	var req simulatePRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"pr":          dto,
-		"replaced_by": out.ReplacedByID,
+	res, err := h.services.PRs.Simulate(c.Request.Context(), service.SimulateInput{
+		AuthorID:       req.AuthorID,
+		PRID:           req.PullRequestID,
+		ChangedFiles:   req.ChangedFiles,
+		Labels:         req.Labels,
+		ReviewersPerPR: req.ReviewersPerPR,
+		Strategy:       service.AssignmentStrategy(req.Strategy),
+		Seed:           req.Seed,
+		Count:          req.Count,
 	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toSimulationDTO(res))
 }
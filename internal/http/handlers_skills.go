@@ -0,0 +1,66 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) UserGetSkills(c *gin.Context) {
+	skills, err := h.services.Skills.GetSkills(c.Request.Context(), c.Query("user_id"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserSkillsDTO(skills))
+}
+
+type setSkillRequest struct {
+	UserID      string `json:"user_id"`
+	Skill       string `json:"skill"`
+	Proficiency int    `json:"proficiency"`
+}
+
+// UserSetSkill adds a skill to the user or changes its proficiency.
+func (h *Handler) UserSetSkill(c *gin.Context) {
+	var req setSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	skills, err := h.services.Skills.SetSkill(c.Request.Context(), service.SetSkillInput{
+		UserID:      req.UserID,
+		Skill:       req.Skill,
+		Proficiency: req.Proficiency,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserSkillsDTO(skills))
+}
+
+type removeSkillRequest struct {
+	UserID string `json:"user_id"`
+	Skill  string `json:"skill"`
+}
+
+func (h *Handler) UserRemoveSkill(c *gin.Context) {
+	var req removeSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	skills, err := h.services.Skills.RemoveSkill(c.Request.Context(), req.UserID, req.Skill)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserSkillsDTO(skills))
+}
@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) TeamGetSLA(c *gin.Context) {
+	sla, err := h.services.SLAs.GetTeamSLA(c.Request.Context(), c.Query("team_name"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTeamSLADTO(sla))
+}
+
+type setSLARequest struct {
+	TeamName         string `json:"team_name"`
+	ReviewSLASeconds int64  `json:"review_sla_seconds"`
+	MergeSLASeconds  int64  `json:"merge_sla_seconds"`
+	AutoReassign     bool   `json:"auto_reassign"`
+}
+
+// TeamSetSLA replaces the team SLA; zero limits switch tracking off.
+func (h *Handler) TeamSetSLA(c *gin.Context) {
+	var req setSLARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	sla, err := h.services.SLAs.SetTeamSLA(c.Request.Context(), service.TeamSLA{
+		TeamName:     req.TeamName,
+		ReviewWithin: seconds(req.ReviewSLASeconds),
+		MergeWithin:  seconds(req.MergeSLASeconds),
+		AutoReassign: req.AutoReassign,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTeamSLADTO(sla))
+}
+
+func (h *Handler) PROverdue(c *gin.Context) {
+	page, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	res, err := h.services.SLAs.ListOverdue(c.Request.Context(), service.ListOverdueInput{
+		TeamName: c.Query("team_name"),
+		Page:     page,
+	}, now)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toOverdueListDTO(res, now))
+}
+
+// seconds converts a count of seconds without overflowing: values beyond the
+// SLA bound stay out of range for validation.
+func seconds(n int64) time.Duration {
+	if n > int64(service.MaxSLA/time.Second) {
+		return service.MaxSLA + time.Second
+	}
+	return time.Duration(n) * time.Second
+}
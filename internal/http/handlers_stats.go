@@ -9,12 +9,7 @@ import (
 func (h *Handler) GetStats(c *gin.Context) {
 	stats, err := h.services.Stats.GetStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "NOT_FOUND",
-				Message: "failed to get stats",
-			},
-		})
+		h.writeSerErr(c, err)
 		return
 	}
 
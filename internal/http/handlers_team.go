@@ -1,89 +1,225 @@
 package httpapi
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"reviewer_pr/internal/service"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const maxImportBodySize = 1 << 20
+
+// importFormats maps the request Content-Type to the import file format.
+var importFormats = map[string]service.ImportFormat{
+	"text/csv":           service.ImportFormatCSV,
+	"application/yaml":   service.ImportFormatYAML,
+	"application/x-yaml": service.ImportFormatYAML,
+	"text/yaml":          service.ImportFormatYAML,
+	"application/json":   service.ImportFormatYAML,
+}
+
 func (h *Handler) TeamAdd(c *gin.Context) {
 	var req TeamDTO
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "NOT_FOUND",
-				Message: "invalid request body",
-			},
-		})
+		h.writeBindErr(c, err)
 		return
 	}
 
 	in := service.CreateTeamInput{
 		TeamName: req.TeamName,
-		Members:  make([]service.CreateTeamMemberInput, 0, len(req.Members)),
+		Members:  toTeamMemberInputs(req.Members),
 	}
 
-	for _, m := range req.Members {
-		in.Members = append(in.Members, service.CreateTeamMemberInput{
-			UserID:   m.UserID,
-			Username: m.Username,
-			IsActive: m.IsActive,
-		})
+	res, err := h.services.Teams.AddTeam(c.Request.Context(), in)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
 	}
 
-	res, err := h.services.Teams.AddTeam(c.Request.Context(), in)
+	c.JSON(http.StatusCreated, gin.H{"team": toTeamDTO(res)})
+}
+
+func (h *Handler) TeamGet(c *gin.Context) {
+	teamName := c.Query("team_name")
+
+	res, err := h.services.Teams.GetTeam(c.Request.Context(), teamName)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
-	members := make([]TeamMemberDTO, 0, len(res.Members))
-	for _, u := range res.Members {
-		members = append(members, TeamMemberDTO{
-			UserID:   u.ID,
-			Username: u.Username,
-			IsActive: u.IsActive,
-		})
+	c.JSON(http.StatusOK, toTeamDTO(res))
+}
+
+// TeamSetMember adds an existing user to the team or changes their role and
+// membership activity.
+func (h *Handler) TeamSetMember(c *gin.Context) {
+	var req setTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"team": TeamDTO{
-			TeamName: res.Team.Name,
-			Members:  members,
-		},
+	res, err := h.services.Teams.SetMembership(c.Request.Context(), service.SetMembershipInput{
+		TeamName: req.TeamName,
+		UserID:   req.UserID,
+		Role:     req.Role,
+		IsActive: req.IsActive,
 	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team": toTeamDTO(res)})
 }
 
-func (h *Handler) TeamGet(c *gin.Context) {
-	teamName := c.Query("team_name")
-	if teamName == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "NOT_FOUND",
-				Message: "team_name is required",
-			},
-		})
+func (h *Handler) TeamRemoveMember(c *gin.Context) {
+	var req removeTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
 		return
 	}
 
-	res, err := h.services.Teams.GetTeam(c.Request.Context(), teamName)
+	res, err := h.services.Teams.RemoveMembership(c.Request.Context(), req.TeamName, req.UserID)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
-	members := make([]TeamMemberDTO, 0, len(res.Members))
-	for _, u := range res.Members {
-		members = append(members, TeamMemberDTO{
-			UserID:   u.ID,
-			Username: u.Username,
-			IsActive: u.IsActive,
-		})
+	c.JSON(http.StatusOK, gin.H{"team": toTeamDTO(res)})
+}
+
+func (h *Handler) TeamList(c *gin.Context) {
+	page, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.services.Teams.ListTeams(c.Request.Context(), page)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, TeamDTO{
-		TeamName: res.Team.Name,
-		Members:  members,
+	c.JSON(http.StatusOK, toTeamListDTO(res))
+}
+
+// TeamImport creates teams and upserts their members from a CSV or YAML file
+// sent as the request body.
+func (h *Handler) TeamImport(c *gin.Context) {
+	format, ok := importFormats[c.ContentType()]
+	if !ok {
+		writeErr(c, http.StatusUnsupportedMediaType, string(service.ErrorCodeInvalidRequest),
+			"Content-Type must be text/csv, application/yaml or application/json")
+		return
+	}
+
+	dryRun, ok := boolQuery(c, "dry_run", false)
+	if !ok {
+		return
+	}
+	deactivateMissing, ok := boolQuery(c, "deactivate_missing", true)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxImportBodySize+1))
+	if err != nil || len(body) > maxImportBodySize {
+		writeErr(c, http.StatusBadRequest, string(service.ErrorCodeInvalidRequest), "request body is too large or unreadable")
+		return
+	}
+
+	rows, err := service.ParseImport(format, bytes.NewReader(body))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	res, err := h.services.Teams.Import(c.Request.Context(), service.ImportInput{
+		Rows:              rows,
+		DryRun:            dryRun,
+		DeactivateMissing: deactivateMissing,
 	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toImportResultDTO(res))
+}
+
+// boolQuery reads an optional boolean query parameter, answering 400 when it
+// is malformed.
+func boolQuery(c *gin.Context, name string, def bool) (bool, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, true
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		writeErrBody(c, http.StatusBadRequest, ErrorBody{
+			Code:    string(service.ErrorCodeValidation),
+			Message: "request validation failed",
+			Details: []FieldErrorDTO{{Field: name, Message: "must be true or false"}},
+		})
+		return false, false
+	}
+	return v, true
+}
+
+// intQuery reads an optional integer query parameter, answering 400 when it
+// is malformed.
+func intQuery(c *gin.Context, name string, def int) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return def, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		writeErrBody(c, http.StatusBadRequest, ErrorBody{
+			Code:    string(service.ErrorCodeValidation),
+			Message: "request validation failed",
+			Details: []FieldErrorDTO{{Field: name, Message: "must be an integer"}},
+		})
+		return 0, false
+	}
+	return v, true
+}
+
+// timeQuery reads an optional RFC 3339 timestamp query parameter, answering
+// 400 when it is malformed.
+func timeQuery(c *gin.Context, name string) (time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return time.Time{}, true
+	}
+	v, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		writeErrBody(c, http.StatusBadRequest, ErrorBody{
+			Code:    string(service.ErrorCodeValidation),
+			Message: "request validation failed",
+			Details: []FieldErrorDTO{{Field: name, Message: "must be an RFC 3339 timestamp"}},
+		})
+		return time.Time{}, false
+	}
+	return v, true
+}
+
+// pageQuery reads the limit and offset query parameters; the service applies
+// defaults and bounds.
+func pageQuery(c *gin.Context) (service.Page, bool) {
+	limit, ok := intQuery(c, "limit", 0)
+	if !ok {
+		return service.Page{}, false
+	}
+	offset, ok := intQuery(c, "offset", 0)
+	if !ok {
+		return service.Page{}, false
+	}
+	return service.Page{Limit: limit, Offset: offset}, true
 }
@@ -2,69 +2,135 @@ package httpapi
 
 import (
 	"net/http"
+	"reviewer_pr/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type setIsActiveRequest struct {
 	UserID   string `json:"user_id"`
-	IsActive bool   `json:"is_active"`
+	IsActive *bool  `json:"is_active"`
 }
 
 func (h *Handler) UserSetIsActive(c *gin.Context) {
 	var req setIsActiveRequest
-	if err := c.ShouldBindJSON(&req); err != nil || req.UserID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "NOT_FOUND",
-				Message: "invalid request body",
-			},
-		})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+	if req.IsActive == nil {
+		h.writeSerErr(c, service.NewValidationErr(service.FieldError{Field: "is_active", Message: "is required"}))
+		return
+	}
+
+	u, err := h.services.Users.SetIsActive(c.Request.Context(), req.UserID, *req.IsActive)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": toUserDTO(u)})
+}
+
+type setNotificationsRequest struct {
+	UserID  string `json:"user_id"`
+	Enabled *bool  `json:"enabled"`
+}
+
+func (h *Handler) UserSetNotifications(c *gin.Context) {
+	var req setNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+	if req.Enabled == nil {
+		h.writeSerErr(c, service.NewValidationErr(service.FieldError{Field: "enabled", Message: "is required"}))
 		return
 	}
 
-	u, err := h.services.Users.SetIsActive(c.Request.Context(), req.UserID, req.IsActive)
+	u, err := h.services.Users.SetNotifications(c.Request.Context(), req.UserID, *req.Enabled)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"user": UserDTO{
-			UserID:   u.ID,
-			Username: u.Username,
-			TeamName: u.TeamName,
-			IsActive: u.IsActive,
-		},
+		"user_id":               u.ID,
+		"notifications_enabled": !u.NotificationsMuted,
 	})
 }
 
-func (h *Handler) UserGetReview(c *gin.Context) {
-	userID := c.Query("user_id")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: ErrorBody{
-				Code:    "NOT_FOUND",
-				Message: "user_id is required",
-			},
-		})
+type setAutoAssignRequest struct {
+	UserID  string `json:"user_id"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// UserSetAutoAssign opts the user in to or out of automatic reviewer picks.
+func (h *Handler) UserSetAutoAssign(c *gin.Context) {
+	var req setAutoAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+	if req.Enabled == nil {
+		h.writeSerErr(c, service.NewValidationErr(service.FieldError{Field: "enabled", Message: "is required"}))
+		return
+	}
+
+	u, err := h.services.Users.SetAutoAssign(c.Request.Context(), req.UserID, *req.Enabled)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":     u.ID,
+		"auto_assign": !u.AutoAssignOptOut,
+	})
+}
+
+func (h *Handler) UserGet(c *gin.Context) {
+	u, err := h.services.Users.GetUser(c.Request.Context(), c.Query("user_id"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+// UserSearch finds users by username prefix (?username=) and/or team.
+func (h *Handler) UserSearch(c *gin.Context) {
+	page, ok := pageQuery(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.services.Users.SearchUsers(c.Request.Context(), service.SearchUsersInput{
+		UsernamePrefix: c.Query("username"),
+		TeamName:       c.Query("team_name"),
+		Page:           page,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
 		return
 	}
 
+	c.JSON(http.StatusOK, toUserListDTO(res))
+}
+
+func (h *Handler) UserGetReview(c *gin.Context) {
+	userID := c.Query("user_id")
+
 	prs, err := h.services.PRs.GetReviewsByUser(c.Request.Context(), userID)
 	if err != nil {
-		writeSerErr(c, err)
+		h.writeSerErr(c, err)
 		return
 	}
 
 	out := make([]PullRequestShortDTO, 0, len(prs))
-	for _, pr := range prs {
-		out = append(out, PullRequestShortDTO{
-			PullRequestID:   pr.ID,
-			PullRequestName: pr.Name,
-			AuthorID:        pr.AuthorID,
-			Status:          string(pr.Status),
-		})
+	for i := range prs {
+		out = append(out, toPullRequestShortDTO(&prs[i]))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
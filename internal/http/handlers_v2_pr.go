@@ -0,0 +1,208 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type updatePRRequest struct {
+	PullRequestName *string `json:"pull_request_name"`
+	Status          *string `json:"status"`
+}
+
+type reassignReviewerRequest struct {
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+	RequestedBy   string `json:"requested_by"`
+}
+
+func (h *Handler) V2PRCreate(c *gin.Context) {
+	var req createPRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	res, err := h.services.PRs.CreateWithAutoAssign(c.Request.Context(), service.CreatePRInput{
+		ID:           req.PullRequestID,
+		Name:         req.PullRequestName,
+		AuthorID:     req.AuthorID,
+		ChangedFiles: req.ChangedFiles,
+		Labels:       req.Labels,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v2/pull-requests/"+url.PathEscape(res.PR.ID))
+	c.Header(HeaderETag, etag(res.PR.Version))
+	c.JSON(http.StatusCreated, toPullRequestDTO(res.PR, userIDs(res.Reviewers)))
+}
+
+func (h *Handler) V2PRGet(c *gin.Context) {
+	res, err := h.services.PRs.GetPR(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	if notModified(c, res.PR.Version) {
+		return
+	}
+
+	c.Header(HeaderETag, etag(res.PR.Version))
+	c.JSON(http.StatusOK, toPullRequestDTO(res.PR, reviewerIDs(res.Reviewers)))
+}
+
+func (h *Handler) V2PRUpdate(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req updatePRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	in := service.UpdatePRInput{
+		ID:              c.Param("id"),
+		Name:            req.PullRequestName,
+		ExpectedVersion: version,
+	}
+	if req.Status != nil {
+		status := models.PullRequestStatus(*req.Status)
+		in.Status = &status
+	}
+
+	res, err := h.services.PRs.UpdatePR(c.Request.Context(), in)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.Header(HeaderETag, etag(res.PR.Version))
+	c.JSON(http.StatusOK, toPullRequestDTO(res.PR, reviewerIDs(res.Reviewers)))
+}
+
+func (h *Handler) V2PRDelete(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.PRs.DeletePR(c.Request.Context(), c.Param("id"), version); err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) V2PRReviewersGet(c *gin.Context) {
+	res, err := h.services.PRs.GetPR(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	if notModified(c, res.PR.Version) {
+		return
+	}
+
+	c.Header(HeaderETag, etag(res.PR.Version))
+	c.JSON(http.StatusOK, ReviewersDTO{
+		PullRequestID: res.PR.ID,
+		Reviewers:     reviewerIDs(res.Reviewers),
+	})
+}
+
+// V2PRReviewersReassign replaces old_reviewer_id with new_reviewer_id or, when
+// it is empty, with a reviewer picked from the same team.
+func (h *Handler) V2PRReviewersReassign(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req reassignReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	out, err := h.services.PRs.ReassignReviewer(ctx, service.ReassignInput{
+		PRID:            c.Param("id"),
+		OldReviewerID:   req.OldReviewerID,
+		NewReviewerID:   req.NewReviewerID,
+		RequestedBy:     req.RequestedBy,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	res, err := h.services.PRs.GetPR(ctx, out.PR.ID)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.Header(HeaderETag, etag(res.PR.Version))
+	c.JSON(http.StatusOK, ReviewersDTO{
+		PullRequestID: res.PR.ID,
+		Reviewers:     reviewerIDs(res.Reviewers),
+		ReplacedBy:    out.ReplacedByID,
+	})
+}
+
+// V2PRReviewerAdd assigns user_id as an additional reviewer.
+func (h *Handler) V2PRReviewerAdd(c *gin.Context) {
+	h.v2ChangeReviewer(c, h.services.PRs.AddReviewer)
+}
+
+// V2PRReviewerRemove unassigns user_id without picking a replacement.
+func (h *Handler) V2PRReviewerRemove(c *gin.Context) {
+	h.v2ChangeReviewer(c, h.services.PRs.RemoveReviewer)
+}
+
+func (h *Handler) v2ChangeReviewer(c *gin.Context, change func(context.Context, service.ReviewerChangeInput) (*service.PRDetails, error)) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	res, err := change(c.Request.Context(), service.ReviewerChangeInput{
+		PRID:            c.Param("id"),
+		ReviewerID:      c.Param("user_id"),
+		RequestedBy:     c.Query("requested_by"),
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.Header(HeaderETag, etag(res.PR.Version))
+	c.JSON(http.StatusOK, ReviewersDTO{
+		PullRequestID: res.PR.ID,
+		Reviewers:     reviewerIDs(res.Reviewers),
+	})
+}
+
+func (h *Handler) V2PREvents(c *gin.Context) {
+	prID := c.Param("id")
+	events, err := h.services.PRs.GetAssignmentHistory(c.Request.Context(), prID)
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAssignmentHistoryDTO(prID, events))
+}
@@ -0,0 +1,87 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/url"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type updateTeamRequest struct {
+	Members []TeamMemberDTO `json:"members"`
+}
+
+func (h *Handler) V2TeamCreate(c *gin.Context) {
+	var req TeamDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	res, err := h.services.Teams.AddTeam(c.Request.Context(), service.CreateTeamInput{
+		TeamName: req.TeamName,
+		Members:  toTeamMemberInputs(req.Members),
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v2/teams/"+url.PathEscape(res.Team.Name))
+	c.Header(HeaderETag, etag(res.Team.Version))
+	c.JSON(http.StatusCreated, toTeamDTO(res))
+}
+
+func (h *Handler) V2TeamGet(c *gin.Context) {
+	res, err := h.services.Teams.GetTeam(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	if notModified(c, res.Team.Version) {
+		return
+	}
+
+	c.Header(HeaderETag, etag(res.Team.Version))
+	c.JSON(http.StatusOK, toTeamDTO(res))
+}
+
+func (h *Handler) V2TeamUpdate(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req updateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	res, err := h.services.Teams.UpdateMembers(c.Request.Context(), service.UpdateTeamInput{
+		TeamName:        c.Param("name"),
+		Members:         toTeamMemberInputs(req.Members),
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.Header(HeaderETag, etag(res.Team.Version))
+	c.JSON(http.StatusOK, toTeamDTO(res))
+}
+
+func (h *Handler) V2TeamDelete(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Teams.DeleteTeam(c.Request.Context(), c.Param("name"), version); err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
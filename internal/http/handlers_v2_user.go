@@ -0,0 +1,67 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type updateUserRequest struct {
+	Username *string `json:"username"`
+	IsActive *bool   `json:"is_active"`
+}
+
+func (h *Handler) V2UserGet(c *gin.Context) {
+	u, err := h.services.Users.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	if notModified(c, u.Version) {
+		return
+	}
+
+	c.Header(HeaderETag, etag(u.Version))
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+func (h *Handler) V2UserUpdate(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req updateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.writeBindErr(c, err)
+		return
+	}
+
+	u, err := h.services.Users.UpdateUser(c.Request.Context(), service.UpdateUserInput{
+		UserID:          c.Param("id"),
+		Username:        req.Username,
+		IsActive:        req.IsActive,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+
+	c.Header(HeaderETag, etag(u.Version))
+	c.JSON(http.StatusOK, toUserDTO(u))
+}
+
+func (h *Handler) V2UserDelete(c *gin.Context) {
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Users.DeleteUser(c.Request.Context(), c.Param("id"), version); err != nil {
+		h.writeSerErr(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
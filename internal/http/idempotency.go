@@ -0,0 +1,123 @@
+package httpapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotentBodySize = 1 << 20
+)
+
+// replayedHeaders are stored with the response and sent again on replay. The
+// request ID matches the one in a stored error body.
+var replayedHeaders = []string{"Location", HeaderETag, HeaderRequestID}
+
+// bodyCaptureWriter keeps a copy of the response so it can be stored for replay.
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes POST requests carrying an Idempotency-Key safe to retry:
+// the first response is stored and replayed for identical retries.
+func (h *Handler) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodySize+1))
+		if err != nil || len(body) > maxIdempotentBodySize {
+			writeErr(c, http.StatusBadRequest, string(service.ErrorCodeInvalidRequest), "request body is too large or unreadable")
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		stored, err := h.services.Idempotency.Begin(ctx, service.BeginIdempotentInput{
+			Key:         key,
+			RequestHash: requestHash(c.Request.Method, c.Request.URL.RequestURI(), body),
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+		})
+		if err != nil {
+			h.writeSerErr(c, err)
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			c.Header(HeaderIdempotentReplayed, "true")
+			for name, value := range stored.Headers {
+				c.Header(name, value)
+			}
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		w := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		log := logger.FromContext(ctx, h.log)
+		status := w.Status()
+		// Server errors are not stored so that the client can retry them.
+		if status >= http.StatusInternalServerError {
+			if err := h.services.Idempotency.Release(ctx, key); err != nil {
+				log.Error("release idempotency key", zap.String("idempotency_key", key), zap.Error(err))
+			}
+			return
+		}
+
+		resp := service.StoredResponse{
+			StatusCode:  status,
+			ContentType: w.Header().Get("Content-Type"),
+			Headers:     make(map[string]string),
+			Body:        w.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				resp.Headers[name] = value
+			}
+		}
+		if err := h.services.Idempotency.Complete(ctx, key, resp); err != nil {
+			log.Error("store idempotent response", zap.String("idempotency_key", key), zap.Error(err))
+		}
+	}
+}
+
+func requestHash(method, uri string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(method))
+	sum.Write([]byte{0})
+	sum.Write([]byte(uri))
+	sum.Write([]byte{0})
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
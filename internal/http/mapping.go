@@ -0,0 +1,384 @@
+package httpapi
+
+import (
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/service"
+	"strings"
+	"time"
+)
+
+// Mappings from domain models to DTOs, shared by the v1 and v2 APIs so that
+// both versions always render resources identically.
+
+func toTeamDTO(t *service.TeamWithMembers) TeamDTO {
+	members := make([]TeamMemberDTO, 0, len(t.Members))
+	for _, m := range t.Members {
+		members = append(members, TeamMemberDTO{
+			UserID:           m.ID,
+			Username:         m.Username,
+			IsActive:         m.IsActive,
+			Role:             m.Role,
+			MembershipActive: &m.MembershipActive,
+		})
+	}
+	return TeamDTO{
+		TeamName: t.Team.Name,
+		Members:  members,
+	}
+}
+
+func toTeamMemberInputs(members []TeamMemberDTO) []service.CreateTeamMemberInput {
+	out := make([]service.CreateTeamMemberInput, 0, len(members))
+	for _, m := range members {
+		out = append(out, service.CreateTeamMemberInput{
+			UserID:           m.UserID,
+			Username:         m.Username,
+			IsActive:         m.IsActive,
+			Role:             m.Role,
+			MembershipActive: m.MembershipActive,
+		})
+	}
+	return out
+}
+
+func toUserDTO(u *models.User) UserDTO {
+	return UserDTO{
+		UserID:   u.ID,
+		Username: u.Username,
+		TeamName: u.TeamName,
+		IsActive: u.IsActive,
+
+		AutoAssignOptOut: u.AutoAssignOptOut,
+	}
+}
+
+func toPullRequestDTO(pr *models.PullRequest, reviewerIDs []string) PullRequestDTO {
+	return PullRequestDTO{
+		PullRequestID:     pr.ID,
+		PullRequestName:   pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            string(pr.Status),
+		AssignedReviewers: reviewerIDs,
+		Labels:            strings.Fields(pr.Labels),
+		CreatedAt:         &pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+	}
+}
+
+func toPullRequestShortDTO(pr *models.PullRequest) PullRequestShortDTO {
+	return PullRequestShortDTO{
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorID:        pr.AuthorID,
+		Status:          string(pr.Status),
+	}
+}
+
+func reviewerIDs(reviewers []models.PRReviewer) []string {
+	ids := make([]string, 0, len(reviewers))
+	for _, r := range reviewers {
+		ids = append(ids, r.ReviewerID)
+	}
+	return ids
+}
+
+func userIDs(users []models.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func toImportResultDTO(r *service.ImportResult) ImportResultDTO {
+	changes := make([]ImportChangeDTO, 0, len(r.Changes))
+	for _, ch := range r.Changes {
+		dto := ImportChangeDTO{
+			Line:     ch.Line,
+			Action:   string(ch.Action),
+			TeamName: ch.TeamName,
+			UserID:   ch.UserID,
+		}
+		for _, f := range ch.Fields {
+			dto.Fields = append(dto.Fields, FieldChangeDTO{Field: f.Field, From: f.From, To: f.To})
+		}
+		changes = append(changes, dto)
+	}
+
+	return ImportResultDTO{
+		DryRun: r.DryRun,
+		Summary: ImportSummaryDTO{
+			TeamsCreated:     r.Summary.TeamsCreated,
+			UsersCreated:     r.Summary.UsersCreated,
+			UsersUpdated:     r.Summary.UsersUpdated,
+			UsersDeactivated: r.Summary.UsersDeactivated,
+			Unchanged:        r.Summary.Unchanged,
+		},
+		Changes: changes,
+	}
+}
+
+func toPageDTO(total int64, p service.Page) PageDTO {
+	return PageDTO{Total: total, Limit: p.Limit, Offset: p.Offset}
+}
+
+func toTeamListDTO(l *service.TeamList) TeamListDTO {
+	teams := make([]TeamSummaryDTO, 0, len(l.Teams))
+	for _, t := range l.Teams {
+		teams = append(teams, TeamSummaryDTO{
+			TeamName:          t.TeamName,
+			MemberCount:       t.MemberCount,
+			ActiveMemberCount: t.ActiveMemberCount,
+			OpenPRCount:       t.OpenPRCount,
+		})
+	}
+	return TeamListDTO{Teams: teams, PageDTO: toPageDTO(l.Total, l.Page)}
+}
+
+func toUserListDTO(l *service.UserList) UserListDTO {
+	users := make([]UserDTO, 0, len(l.Users))
+	for i := range l.Users {
+		users = append(users, toUserDTO(&l.Users[i]))
+	}
+	return UserListDTO{Users: users, PageDTO: toPageDTO(l.Total, l.Page)}
+}
+
+func toDigestSubscriptionDTO(userID string, s *models.DigestSubscription) DigestSubscriptionDTO {
+	if s == nil {
+		return DigestSubscriptionDTO{UserID: userID}
+	}
+	return DigestSubscriptionDTO{
+		UserID:     s.UserID,
+		Enabled:    true,
+		Email:      s.Email,
+		SendAt:     s.SendAt,
+		Timezone:   s.Timezone,
+		LastSentAt: s.LastSentAt,
+	}
+}
+
+func toDigestPreviewDTO(p *service.DigestPreview) DigestPreviewDTO {
+	items := make([]DigestItemDTO, 0, len(p.Digest.Items))
+	for _, it := range p.Digest.Items {
+		items = append(items, DigestItemDTO{
+			PullRequestID:   it.PR.ID,
+			PullRequestName: it.PR.Name,
+			AuthorID:        it.Author.ID,
+			AuthorName:      it.Author.Username,
+			CreatedAt:       it.PR.CreatedAt,
+			AgeSeconds:      int64(it.Age / time.Second),
+		})
+	}
+	return DigestPreviewDTO{
+		UserID:       p.Digest.User.ID,
+		Timezone:     p.Digest.GeneratedAt.Location().String(),
+		Subject:      p.Rendered.Subject,
+		Text:         p.Rendered.Text,
+		HTML:         p.Rendered.HTML,
+		PullRequests: items,
+	}
+}
+
+func toTeamSLADTO(s *service.TeamSLA) TeamSLADTO {
+	return TeamSLADTO{
+		TeamName:         s.TeamName,
+		ReviewSLASeconds: int64(s.ReviewWithin / time.Second),
+		MergeSLASeconds:  int64(s.MergeWithin / time.Second),
+		AutoReassign:     s.AutoReassign,
+	}
+}
+
+func toAssignmentHistoryDTO(prID string, events []models.AssignmentEvent) AssignmentHistoryDTO {
+	out := AssignmentHistoryDTO{PullRequestID: prID, Events: make([]AssignmentEventDTO, 0, len(events))}
+	for _, e := range events {
+		out.Events = append(out.Events, toAssignmentEventDTO(e))
+	}
+	return out
+}
+
+func toAuditLogDTO(l *service.AssignmentEventList) AuditLogDTO {
+	events := make([]AssignmentEventDTO, 0, len(l.Events))
+	for _, e := range l.Events {
+		dto := toAssignmentEventDTO(e)
+		dto.PullRequestID = e.PullRequestID
+		events = append(events, dto)
+	}
+	return AuditLogDTO{Events: events, PageDTO: toPageDTO(l.Total, l.Page)}
+}
+
+func toAssignmentEventDTO(e models.AssignmentEvent) AssignmentEventDTO {
+	return AssignmentEventDTO{
+		Kind:               string(e.Kind),
+		ReviewerID:         e.ReviewerID,
+		PreviousReviewerID: e.PreviousReviewerID,
+		Reason:             e.Reason,
+		RequestedBy:        e.RequestedBy,
+		Strategy:           e.Strategy,
+		Seed:               e.Seed,
+		CreatedAt:          e.CreatedAt,
+	}
+}
+
+func toReviewerPickDTOs(picks []service.ReviewerPick) []ReviewerPickDTO {
+	out := make([]ReviewerPickDTO, 0, len(picks))
+	for _, p := range picks {
+		out = append(out, ReviewerPickDTO{
+			UserID:        p.User.ID,
+			Reason:        string(p.Reason),
+			Pattern:       p.Pattern,
+			MatchedSkills: toSkillDTOs(p.Skills),
+			OpenReviews:   p.OpenReviews,
+		})
+	}
+	return out
+}
+
+func toSkillDTOs(skills []service.Skill) []SkillDTO {
+	if len(skills) == 0 {
+		return nil
+	}
+	out := make([]SkillDTO, 0, len(skills))
+	for _, s := range skills {
+		out = append(out, SkillDTO{Skill: s.Name, Proficiency: s.Proficiency})
+	}
+	return out
+}
+
+func toSkippedCandidateDTOs(skipped []service.SkippedCandidate) []SkippedCandidateDTO {
+	out := make([]SkippedCandidateDTO, 0, len(skipped))
+	for _, s := range skipped {
+		out = append(out, SkippedCandidateDTO{UserID: s.User.ID, Reason: string(s.Reason)})
+	}
+	return out
+}
+
+func toSimulationDTO(s *service.Simulation) SimulationDTO {
+	out := SimulationDTO{
+		AuthorID:   s.Author.ID,
+		Strategy:   string(s.Strategy),
+		Seed:       s.Seed,
+		Count:      s.Count,
+		Candidates: make([]SimulatedCandidateDTO, 0, len(s.Candidates)),
+		Assignment: toReviewerPickDTOs(s.Picks),
+		Load:       make([]ProjectedLoadDTO, 0, len(s.Load)),
+	}
+	for _, c := range s.Candidates {
+		out.Candidates = append(out.Candidates, SimulatedCandidateDTO{
+			UserID:      c.User.ID,
+			TeamName:    c.User.TeamName,
+			Status:      string(c.Status),
+			OpenReviews: c.OpenReviews,
+		})
+	}
+	for _, l := range s.Load {
+		out.Load = append(out.Load, ProjectedLoadDTO{
+			UserID:      l.User.ID,
+			OpenReviews: l.OpenReviews,
+			Assigned:    l.Assigned,
+			Projected:   l.OpenReviews + int64(l.Assigned),
+		})
+	}
+	return out
+}
+
+func toExclusionListDTO(rows []models.ReviewerExclusion) ExclusionListDTO {
+	out := ExclusionListDTO{Exclusions: make([]ExclusionDTO, 0, len(rows))}
+	for _, e := range rows {
+		out.Exclusions = append(out.Exclusions, ExclusionDTO{
+			AuthorID:   e.AuthorID,
+			ReviewerID: e.ReviewerID,
+			Reason:     e.Reason,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	return out
+}
+
+func toUserSkillsDTO(s *service.UserSkills) UserSkillsDTO {
+	skills := toSkillDTOs(s.Skills)
+	if skills == nil {
+		skills = []SkillDTO{}
+	}
+	return UserSkillsDTO{UserID: s.UserID, Skills: skills}
+}
+
+func toCodeOwnersDTO(o *service.TeamCodeOwners) CodeOwnersDTO {
+	rules := make([]CodeOwnerRuleDTO, 0, len(o.Rules))
+	for _, r := range o.Rules {
+		rules = append(rules, CodeOwnerRuleDTO{
+			Pattern: r.Pattern,
+			Users:   emptyIfNil(r.Users),
+			Teams:   emptyIfNil(r.Teams),
+		})
+	}
+	return CodeOwnersDTO{TeamName: o.TeamName, Rules: rules}
+}
+
+func emptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func toOverdueListDTO(l *service.OverdueList, now time.Time) OverdueListDTO {
+	prs := make([]OverduePullRequestDTO, 0, len(l.PullRequests))
+	for i := range l.PullRequests {
+		o := &l.PullRequests[i]
+		breaches := make([]SLABreachDTO, 0, len(o.Breaches))
+		for _, b := range o.Breaches {
+			breaches = append(breaches, SLABreachDTO{
+				Kind:           string(b.Kind),
+				ReviewerID:     b.ReviewerID,
+				Deadline:       b.Deadline.UTC(),
+				OverdueSeconds: int64(now.Sub(b.Deadline) / time.Second),
+			})
+		}
+		prs = append(prs, OverduePullRequestDTO{
+			PullRequestShortDTO: toPullRequestShortDTO(&o.PR),
+			TeamName:            o.TeamName,
+			CreatedAt:           o.PR.CreatedAt,
+			Breaches:            breaches,
+		})
+	}
+	return OverdueListDTO{PullRequests: prs, PageDTO: toPageDTO(l.Total, l.Page)}
+}
+
+func toJobRunDTO(r *models.JobRun) JobRunDTO {
+	return JobRunDTO{
+		RunID:       r.ID,
+		Job:         r.JobName,
+		Trigger:     string(r.Trigger),
+		Status:      string(r.Status),
+		ScheduledAt: r.ScheduledAt,
+		StartedAt:   r.StartedAt,
+		FinishedAt:  r.FinishedAt,
+		DurationMs:  r.DurationMs,
+		Error:       r.Error,
+	}
+}
+
+func toJobDTOs(jobs []service.JobStatus) []JobDTO {
+	out := make([]JobDTO, 0, len(jobs))
+	for _, j := range jobs {
+		dto := JobDTO{Job: j.Name, Schedule: j.Schedule, Running: j.Running}
+		if !j.NextRun.IsZero() {
+			next := j.NextRun
+			dto.NextRunAt = &next
+		}
+		if j.LastRun != nil {
+			last := toJobRunDTO(j.LastRun)
+			dto.LastRun = &last
+		}
+		out = append(out, dto)
+	}
+	return out
+}
+
+func toJobRunListDTO(l *service.JobRunList) JobRunListDTO {
+	runs := make([]JobRunDTO, 0, len(l.Runs))
+	for i := range l.Runs {
+		runs = append(runs, toJobRunDTO(&l.Runs[i]))
+	}
+	return JobRunListDTO{Runs: runs, PageDTO: toPageDTO(l.Total, l.Page)}
+}
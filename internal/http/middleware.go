@@ -0,0 +1,122 @@
+package httpapi
+
+import (
+	"net/http"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/requestid"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/tenant"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	ctxKeyRequestID = "request_id"
+	ctxKeyActor     = "actor"
+	ctxKeyTenant    = "tenant"
+	ctxKeyErrorCode = "error_code"
+)
+
+// RequestContext propagates the incoming X-Request-ID (or generates a new one)
+// and stores a request-scoped logger in the request context.
+func (h *Handler) RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := requestid.OrNew(c.GetHeader(HeaderRequestID))
+
+		c.Set(ctxKeyRequestID, requestID)
+		c.Header(HeaderRequestID, requestID)
+
+		reqLog := h.log.With(zap.String("request_id", requestID))
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), reqLog))
+
+		c.Next()
+	}
+}
+
+// AccessLog writes one structured log entry per request once it has been handled.
+func (h *Handler) AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("bytes", c.Writer.Size()),
+		}
+		if actor := c.GetString(ctxKeyActor); actor != "" {
+			fields = append(fields, zap.String("actor", actor))
+		}
+		if t := c.GetString(ctxKeyTenant); t != "" {
+			fields = append(fields, zap.String("tenant", t))
+		}
+		if code := c.GetString(ctxKeyErrorCode); code != "" {
+			fields = append(fields, zap.String("error_code", code))
+		}
+
+		log := logger.FromContext(c.Request.Context(), h.log)
+		switch {
+		case status >= 500:
+			log.Error("http request", fields...)
+		case status >= 400:
+			log.Warn("http request", fields...)
+		default:
+			log.Info("http request", fields...)
+		}
+	}
+}
+
+func requestIDFrom(c *gin.Context) string {
+	return c.GetString(ctxKeyRequestID)
+}
+
+// Auth authenticates the bearer token, records the caller role as the actor
+// and scopes the request to the token's tenant.
+func (h *Handler) Auth(a *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, tenantID, ok := a.Authenticate(auth.BearerToken(c.GetHeader("Authorization")))
+		if !ok {
+			writeErr(c, http.StatusUnauthorized, string(service.ErrorCodeUnauthorized), "missing or invalid bearer token")
+			c.Abort()
+			return
+		}
+		c.Set(ctxKeyActor, string(role))
+		c.Set(ctxKeyTenant, tenantID)
+		c.Request = c.Request.WithContext(tenant.WithContext(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
+
+// RequireRole rejects callers whose role does not allow the route. Without
+// authentication configured every caller is allowed.
+func (h *Handler) RequireRole(required auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, authenticated := c.Get(ctxKeyActor)
+		if !authenticated {
+			c.Next()
+			return
+		}
+		if role, _ := actor.(string); !auth.Role(role).Allows(required) {
+			writeErr(c, http.StatusForbidden, string(service.ErrorCodeForbidden), "insufficient permissions")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sync"
@@ -14,9 +15,17 @@ var (
 	initOnce sync.Once
 )
 
-func Init(development bool) error {
+type ctxKey struct{}
+
+func Init(development bool, level string) error {
 	var err error
 	initOnce.Do(func() {
+		var lvl zapcore.Level
+		lvl, err = zapcore.ParseLevel(level)
+		if err != nil {
+			return
+		}
+
 		var cfg zap.Config
 		if development {
 			cfg = zap.NewDevelopmentConfig()
@@ -26,12 +35,13 @@ func Init(development bool) error {
 			cfg = zap.NewProductionConfig()
 			cfg.EncoderConfig.TimeKey = "time"
 		}
+		cfg.Level = zap.NewAtomicLevelAt(lvl)
 		log, err = cfg.Build()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
 			return
 		}
-		log.Info("Logger initialized", zap.Bool("development", development))
+		log.Info("Logger initialized", zap.Bool("development", development), zap.String("level", lvl.String()))
 	})
 	return err
 }
@@ -48,3 +58,17 @@ func Sync() {
 		_ = log.Sync()
 	}
 }
+
+// WithContext returns a copy of ctx carrying the request-scoped logger l.
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the request-scoped logger stored in ctx, or fallback
+// when the context carries none.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return fallback
+}
@@ -2,12 +2,26 @@ package models
 
 import "time"
 
+// Tenant is an organization. Its ID is the leading primary key column of
+// every table holding its teams, users and pull requests.
+type Tenant struct {
+	ID        string    `gorm:"column:tenant_id;primaryKey"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Tenant) TableName() string {
+	return "tenants"
+}
+
 type Team struct {
+	TenantID  string    `gorm:"column:tenant_id;primaryKey;default:'default'"`
 	Name      string    `gorm:"column:team_name;primaryKey"`
+	Version   int64     `gorm:"column:version;not null;default:1"`
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
-	Users []User `gorm:"foreignKey:TeamName;references:Name"`
+	Users       []User           `gorm:"foreignKey:TenantID,TeamName;references:TenantID,Name"`
+	Memberships []TeamMembership `gorm:"foreignKey:TenantID,TeamName;references:TenantID,Name"`
 }
 
 func (Team) TableName() string {
@@ -15,20 +29,52 @@ func (Team) TableName() string {
 }
 
 type User struct {
-	ID        string    `gorm:"column:user_id;primaryKey"`
-	Username  string    `gorm:"column:username;not null"`
+	TenantID string `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	ID       string `gorm:"column:user_id;primaryKey"`
+	Username string `gorm:"column:username;not null"`
+	// TeamName is the primary team. The user is always its member and may
+	// belong to other teams through memberships.
 	TeamName  string    `gorm:"column:team_name;not null;index"`
 	IsActive  bool      `gorm:"column:is_active;not null;default:true"`
+	Version   int64     `gorm:"column:version;not null;default:1"`
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	// NotificationsMuted opts the user out of chat notifications.
+	NotificationsMuted bool `gorm:"column:notifications_muted;not null;default:false"`
+	// AutoAssignOptOut keeps the user out of automatic reviewer picks; they
+	// can still be added to a pull request by name.
+	AutoAssignOptOut bool `gorm:"column:auto_assign_opt_out;not null;default:false"`
 
-	Team *Team `gorm:"foreignKey:TeamName;references:Name"`
+	Team        *Team            `gorm:"foreignKey:TenantID,TeamName;references:TenantID,Name"`
+	Skills      []UserSkill      `gorm:"foreignKey:TenantID,UserID;references:TenantID,ID;constraint:OnDelete:CASCADE"`
+	Memberships []TeamMembership `gorm:"foreignKey:TenantID,UserID;references:TenantID,ID;constraint:OnDelete:CASCADE"`
 }
 
 func (User) TableName() string {
 	return "users"
 }
 
+const (
+	MembershipMember = "member"
+	MembershipLead   = "lead"
+)
+
+// TeamMembership puts a user in a team. Reviewers are picked among the
+// active members of the author's teams; an inactive membership keeps the
+// user listed in the team without making them a candidate there.
+type TeamMembership struct {
+	TenantID  string    `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	TeamName  string    `gorm:"column:team_name;primaryKey"`
+	UserID    string    `gorm:"column:user_id;primaryKey;index"`
+	Role      string    `gorm:"column:role;not null;default:'member'"`
+	IsActive  bool      `gorm:"column:is_active;not null;default:true"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (TeamMembership) TableName() string {
+	return "team_memberships"
+}
+
 type PullRequestStatus string
 
 const (
@@ -37,15 +83,20 @@ const (
 )
 
 type PullRequest struct {
+	TenantID  string            `gorm:"column:tenant_id;primaryKey;default:'default'"`
 	ID        string            `gorm:"column:pull_request_id;primaryKey"`
 	Name      string            `gorm:"column:pull_request_name;not null"`
 	AuthorID  string            `gorm:"column:author_id;not null;index"`
 	Status    PullRequestStatus `gorm:"column:status;type:text;not null;default:'OPEN'"`
+	Version   int64             `gorm:"column:version;not null;default:1"`
 	CreatedAt time.Time         `gorm:"column:created_at;autoCreateTime"`
 	MergedAt  *time.Time        `gorm:"column:merged_at"`
+	// Labels are space-separated lowercase tags matched against reviewer
+	// skills.
+	Labels string `gorm:"column:labels;not null;default:''"`
 
-	Author    *User        `gorm:"foreignKey:AuthorID;references:ID"`
-	Reviewers []PRReviewer `gorm:"foreignKey:PullRequestID;references:ID"`
+	Author    *User        `gorm:"foreignKey:TenantID,AuthorID;references:TenantID,ID"`
+	Reviewers []PRReviewer `gorm:"foreignKey:TenantID,PullRequestID;references:TenantID,ID"`
 }
 
 func (PullRequest) TableName() string {
@@ -53,14 +104,214 @@ func (PullRequest) TableName() string {
 }
 
 type PRReviewer struct {
+	TenantID      string    `gorm:"column:tenant_id;primaryKey;default:'default'"`
 	PullRequestID string    `gorm:"column:pull_request_id;primaryKey"`
 	ReviewerID    string    `gorm:"column:reviewer_id;primaryKey;index"`
 	AssignedAt    time.Time `gorm:"column:assigned_at;autoCreateTime"`
 
-	PullRequest *PullRequest `gorm:"foreignKey:PullRequestID;references:ID"`
-	Reviewer    *User        `gorm:"foreignKey:ReviewerID;references:ID"`
+	PullRequest *PullRequest `gorm:"foreignKey:TenantID,PullRequestID;references:TenantID,ID"`
+	Reviewer    *User        `gorm:"foreignKey:TenantID,ReviewerID;references:TenantID,ID"`
 }
 
 func (PRReviewer) TableName() string {
 	return "pr_reviewers"
 }
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyInProgress IdempotencyStatus = "IN_PROGRESS"
+	IdempotencyCompleted  IdempotencyStatus = "COMPLETED"
+)
+
+type IdempotencyKey struct {
+	TenantID     string            `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	Key          string            `gorm:"column:idempotency_key;primaryKey"`
+	RequestHash  string            `gorm:"column:request_hash;not null"`
+	Method       string            `gorm:"column:method;not null"`
+	Path         string            `gorm:"column:path;not null"`
+	Status       IdempotencyStatus `gorm:"column:status;type:text;not null"`
+	ResponseCode int               `gorm:"column:response_code"`
+	ContentType  string            `gorm:"column:content_type"`
+	// ResponseHeaders are the replayed response headers, such as Location.
+	ResponseHeaders map[string]string `gorm:"column:response_headers;type:text;serializer:json"`
+	ResponseBody    []byte            `gorm:"column:response_body"`
+	CreatedAt       time.Time         `gorm:"column:created_at;autoCreateTime"`
+	ExpiresAt       time.Time         `gorm:"column:expires_at;not null;index"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// DigestSubscription schedules a daily email with the open pull requests a
+// user has to review.
+type DigestSubscription struct {
+	TenantID string `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	UserID   string `gorm:"column:user_id;primaryKey"`
+	Email    string `gorm:"column:email;not null"`
+	// SendAt is the local time of day, "15:04", in Timezone (IANA name).
+	SendAt     string     `gorm:"column:send_at;not null"`
+	Timezone   string     `gorm:"column:timezone;not null"`
+	LastSentAt *time.Time `gorm:"column:last_sent_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (DigestSubscription) TableName() string {
+	return "digest_subscriptions"
+}
+
+// TeamSLA limits how long open pull requests authored by a team may wait.
+// A zero limit is not enforced.
+type TeamSLA struct {
+	TenantID string `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	TeamName string `gorm:"column:team_name;primaryKey"`
+	// ReviewSeconds is allowed between a reviewer's assignment and the merge.
+	ReviewSeconds int64 `gorm:"column:review_sla_seconds;not null;default:0"`
+	// MergeSeconds is allowed between creation and the merge.
+	MergeSeconds int64 `gorm:"column:merge_sla_seconds;not null;default:0"`
+	// AutoReassign replaces reviewers that breach the review SLA.
+	AutoReassign bool      `gorm:"column:auto_reassign;not null;default:false"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (TeamSLA) TableName() string {
+	return "team_slas"
+}
+
+type SLABreachKind string
+
+const (
+	SLABreachReview SLABreachKind = "review"
+	SLABreachMerge  SLABreachKind = "merge"
+)
+
+// SLABreach records an escalated breach so that it is raised only once.
+type SLABreach struct {
+	TenantID      string `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	PullRequestID string `gorm:"column:pull_request_id;primaryKey"`
+	// ReviewerID is empty for merge breaches.
+	ReviewerID string        `gorm:"column:reviewer_id;primaryKey"`
+	Kind       SLABreachKind `gorm:"column:kind;type:text;primaryKey"`
+	DetectedAt time.Time     `gorm:"column:detected_at;not null"`
+}
+
+func (SLABreach) TableName() string {
+	return "sla_breaches"
+}
+
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+// JobRun is one execution of a background job by any replica.
+type JobRun struct {
+	ID      uint64     `gorm:"column:run_id;primaryKey;autoIncrement"`
+	JobName string     `gorm:"column:job_name;not null;index:idx_job_runs_job_started,priority:1"`
+	Trigger JobTrigger `gorm:"column:triggered_by;type:text;not null"`
+	// ScheduledAt is the schedule slot the run belongs to; nil for manual runs.
+	ScheduledAt *time.Time   `gorm:"column:scheduled_at"`
+	Status      JobRunStatus `gorm:"column:status;type:text;not null"`
+	StartedAt   time.Time    `gorm:"column:started_at;not null;index:idx_job_runs_job_started,priority:2"`
+	FinishedAt  *time.Time   `gorm:"column:finished_at"`
+	DurationMs  int64        `gorm:"column:duration_ms;not null;default:0"`
+	Error       string       `gorm:"column:error;not null;default:''"`
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// CodeOwnerRule maps a CODEOWNERS path pattern to the reviewers that own the
+// matching files of pull requests authored by the team. Rules are ordered by
+// Position and the last matching one wins.
+type CodeOwnerRule struct {
+	TenantID string `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	TeamName string `gorm:"column:team_name;primaryKey"`
+	Position int    `gorm:"column:position;primaryKey;autoIncrement:false"`
+	Pattern  string `gorm:"column:pattern;not null"`
+	// Users and Teams are space-separated user IDs and team names.
+	Users string `gorm:"column:users;not null;default:''"`
+	Teams string `gorm:"column:teams;not null;default:''"`
+}
+
+func (CodeOwnerRule) TableName() string {
+	return "code_owner_rules"
+}
+
+// UserSkill is an area of expertise of a user, matched against pull request
+// labels when reviewers are picked. Proficiency runs from 1 to 5; 0 means
+// it is not specified.
+type UserSkill struct {
+	TenantID    string `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	UserID      string `gorm:"column:user_id;primaryKey"`
+	Skill       string `gorm:"column:skill;primaryKey;index"`
+	Proficiency int    `gorm:"column:proficiency;not null;default:0"`
+}
+
+func (UserSkill) TableName() string {
+	return "user_skills"
+}
+
+// ReviewerExclusion forbids picking ReviewerID as a reviewer of pull requests
+// authored by AuthorID.
+type ReviewerExclusion struct {
+	TenantID   string    `gorm:"column:tenant_id;primaryKey;default:'default'"`
+	AuthorID   string    `gorm:"column:author_id;primaryKey"`
+	ReviewerID string    `gorm:"column:reviewer_id;primaryKey;index"`
+	Reason     string    `gorm:"column:reason;not null;default:''"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+}
+
+func (ReviewerExclusion) TableName() string {
+	return "reviewer_exclusions"
+}
+
+type AssignmentEventKind string
+
+const (
+	// AssignmentAssigned: a reviewer was picked when the pull request was created.
+	AssignmentAssigned AssignmentEventKind = "assigned"
+	// AssignmentAdded: a named reviewer was added to an open pull request.
+	AssignmentAdded AssignmentEventKind = "added"
+	// AssignmentRemoved: a reviewer was taken off without a replacement.
+	AssignmentRemoved AssignmentEventKind = "removed"
+	// AssignmentReassigned: PreviousReviewerID was replaced with ReviewerID.
+	AssignmentReassigned AssignmentEventKind = "reassigned"
+)
+
+// AssignmentEvent records a change of a pull request's reviewers.
+type AssignmentEvent struct {
+	ID                 uint64              `gorm:"column:event_id;primaryKey;autoIncrement"`
+	TenantID           string              `gorm:"column:tenant_id;not null;default:'default';index"`
+	PullRequestID      string              `gorm:"column:pull_request_id;not null;index"`
+	Kind               AssignmentEventKind `gorm:"column:kind;type:text;not null"`
+	ReviewerID         string              `gorm:"column:reviewer_id;not null"`
+	PreviousReviewerID string              `gorm:"column:previous_reviewer_id;not null;default:''"`
+	// Reason is why the reviewer was chosen: "code_owner", "skills" and
+	// "team" for automatic picks, "manual" for a named reviewer.
+	Reason string `gorm:"column:reason;not null;default:''"`
+	// RequestedBy is the user who asked for a manual change, if known.
+	RequestedBy string `gorm:"column:requested_by;not null;default:''"`
+	// Strategy and Seed replay an automatic pick: candidates sorted by ID
+	// were shuffled with Seed before ranking. Both are empty for manual ones.
+	Strategy  string    `gorm:"column:strategy;not null;default:''"`
+	Seed      int64     `gorm:"column:seed;not null;default:0"`
+	CreatedAt time.Time `gorm:"column:created_at;not null"`
+}
+
+func (AssignmentEvent) TableName() string {
+	return "assignment_events"
+}
@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Config struct {
+	Templates Templates
+	// Handles maps user IDs to chat handles such as "<@U024BE7LH>"; other
+	// users are mentioned as "@" + username.
+	Handles map[string]string
+	// QueueSize bounds the messages waiting for delivery; when the queue is
+	// full new messages are dropped.
+	QueueSize int
+	// MaxAttempts is how many times a message is sent before it is dropped.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry; it doubles after each.
+	RetryBackoff time.Duration
+}
+
+// Dispatcher renders events into messages and delivers them in the
+// background, retrying failed sends. Users with muted notifications are not
+// notified and are named without a mention.
+type Dispatcher struct {
+	sender    Sender
+	templates templates
+	handles   map[string]string
+	log       *zap.Logger
+
+	maxAttempts int
+	backoff     time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan job
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type job struct {
+	kind EventKind
+	prID string
+	msg  Message
+}
+
+func NewDispatcher(sender Sender, cfg Config, log *zap.Logger) (*Dispatcher, error) {
+	tmpl, err := parseTemplates(cfg.Templates)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		sender:      sender,
+		templates:   tmpl,
+		handles:     cfg.Handles,
+		log:         log,
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     cfg.RetryBackoff,
+		queue:       make(chan job, cfg.QueueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go d.run()
+	return d, nil
+}
+
+func (d *Dispatcher) Notify(ctx context.Context, e Event) {
+	log := logger.FromContext(ctx, d.log)
+
+	recipients := e.Reviewers[:0:0]
+	for _, u := range e.Reviewers {
+		if !u.NotificationsMuted {
+			recipients = append(recipients, u)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+	e.Reviewers = recipients
+
+	text, err := d.templates.render(e, d.handle)
+	if err != nil {
+		log.Error("notification template failed", zap.String("event", string(e.Kind)), zap.Error(err))
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	select {
+	case d.queue <- job{kind: e.Kind, prID: e.PR.ID, msg: Message{Text: text}}:
+	default:
+		log.Warn("notification dropped: queue is full",
+			zap.String("event", string(e.Kind)),
+			zap.String("pull_request_id", e.PR.ID),
+		)
+	}
+}
+
+func (d *Dispatcher) handle(u models.User) string {
+	if u.NotificationsMuted {
+		return u.Username
+	}
+	if h, ok := d.handles[u.ID]; ok {
+		return h
+	}
+	return "@" + u.Username
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for j := range d.queue {
+		d.deliver(j)
+	}
+}
+
+func (d *Dispatcher) deliver(j job) {
+	fields := []zap.Field{zap.String("event", string(j.kind)), zap.String("pull_request_id", j.prID)}
+	delay := d.backoff
+
+	for attempt := 1; ; attempt++ {
+		err := d.sender.Send(d.ctx, j.msg)
+		if err == nil {
+			return
+		}
+
+		var perm permanentError
+		if errors.As(err, &perm) || attempt >= d.maxAttempts {
+			d.log.Error("notification failed", append(fields, zap.Int("attempts", attempt), zap.Error(err))...)
+			return
+		}
+		d.log.Warn("notification failed, retrying", append(fields, zap.Int("attempt", attempt), zap.Error(err))...)
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-d.ctx.Done():
+			d.log.Error("notification dropped on shutdown", fields...)
+			return
+		}
+	}
+}
+
+// Close stops accepting events and waits until queued messages are delivered
+// or ctx ends, in which case pending retries are abandoned.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-d.done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,69 @@
+// Package notify tells reviewers about pull request events through a chat
+// incoming webhook (Slack or Mattermost).
+package notify
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"time"
+)
+
+type EventKind string
+
+const (
+	EventAssigned   EventKind = "assigned"
+	EventReassigned EventKind = "reassigned"
+	EventMerged     EventKind = "merged"
+
+	// EventReviewOverdue: a reviewer has not finished within the team SLA.
+	EventReviewOverdue EventKind = "review_overdue"
+	// EventMergeOverdue: the pull request has been open longer than the team SLA.
+	EventMergeOverdue EventKind = "merge_overdue"
+)
+
+// Event is something reviewers should hear about.
+type Event struct {
+	Kind   EventKind
+	PR     models.PullRequest
+	Author models.User
+	// Reviewers are the recipients: the newly assigned reviewers for
+	// EventAssigned and EventReassigned, the late reviewer for
+	// EventReviewOverdue, every reviewer otherwise.
+	Reviewers []models.User
+	// Replaced is the reviewer taken off the pull request (EventReassigned only).
+	Replaced *models.User
+	// Overdue is how far past the SLA deadline the pull request is (overdue
+	// events only).
+	Overdue time.Duration
+}
+
+// Notifier delivers events. Notify must not block the caller on delivery.
+type Notifier interface {
+	Notify(ctx context.Context, e Event)
+}
+
+// Nop drops every event; it is used when notifications are disabled.
+type Nop struct{}
+
+func (Nop) Notify(context.Context, Event) {}
+
+// Message is one chat message.
+type Message struct {
+	Text string
+}
+
+// Sender posts a message to the chat. Errors wrapped with Permanent are not
+// retried.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"fmt"
+	"reviewer_pr/internal/models"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Default message templates. Templates see .PR (ID, Name, Status), .Author,
+// .Reviewers, .Replaced and .Overdue, where people are rendered as chat
+// handles.
+const (
+	DefaultAssignedTemplate      = `{{.Reviewers}}: please review *{{.PR.Name}}* ({{.PR.ID}}) by {{.Author}}`
+	DefaultReassignedTemplate    = `{{.Reviewers}}: please review *{{.PR.Name}}* ({{.PR.ID}}) by {{.Author}} instead of {{.Replaced}}`
+	DefaultMergedTemplate        = `*{{.PR.Name}}* ({{.PR.ID}}) by {{.Author}} was merged. Thanks, {{.Reviewers}}!`
+	DefaultReviewOverdueTemplate = `{{.Reviewers}}: review of *{{.PR.Name}}* ({{.PR.ID}}) by {{.Author}} is {{.Overdue}} overdue`
+	DefaultMergeOverdueTemplate  = `*{{.PR.Name}}* ({{.PR.ID}}) by {{.Author}} is {{.Overdue}} past its merge deadline. Reviewers: {{.Reviewers}}`
+)
+
+// Templates holds the template source for each event kind; empty fields use
+// the defaults.
+type Templates struct {
+	Assigned      string
+	Reassigned    string
+	Merged        string
+	ReviewOverdue string
+	MergeOverdue  string
+}
+
+type templates map[EventKind]*template.Template
+
+func parseTemplates(t Templates) (templates, error) {
+	out := make(templates, 5)
+	for kind, src := range map[EventKind]string{
+		EventAssigned:      firstNonEmpty(t.Assigned, DefaultAssignedTemplate),
+		EventReassigned:    firstNonEmpty(t.Reassigned, DefaultReassignedTemplate),
+		EventMerged:        firstNonEmpty(t.Merged, DefaultMergedTemplate),
+		EventReviewOverdue: firstNonEmpty(t.ReviewOverdue, DefaultReviewOverdueTemplate),
+		EventMergeOverdue:  firstNonEmpty(t.MergeOverdue, DefaultMergeOverdueTemplate),
+	} {
+		tmpl, err := template.New(string(kind)).Option("missingkey=error").Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s template: %w", kind, err)
+		}
+		out[kind] = tmpl
+	}
+	return out, nil
+}
+
+type templateData struct {
+	PR        models.PullRequest
+	Author    string
+	Reviewers string
+	Replaced  string
+	Overdue   string
+}
+
+func (t templates) render(e Event, handle func(models.User) string) (string, error) {
+	tmpl, ok := t[e.Kind]
+	if !ok {
+		return "", fmt.Errorf("no template for event %q", e.Kind)
+	}
+
+	names := make([]string, 0, len(e.Reviewers))
+	for _, u := range e.Reviewers {
+		names = append(names, handle(u))
+	}
+	data := templateData{
+		PR:        e.PR,
+		Author:    handle(e.Author),
+		Reviewers: strings.Join(names, ", "),
+	}
+	if e.Replaced != nil {
+		data.Replaced = handle(*e.Replaced)
+	}
+	if e.Overdue > 0 {
+		data.Overdue = formatDuration(e.Overdue)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// formatDuration renders d rounded to minutes without zero units: "26h",
+// "1h30m", "45m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d == 0 {
+		return "0m"
+	}
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook posts messages to a Slack incoming webhook. Mattermost accepts the
+// same payload.
+type Webhook struct {
+	url      string
+	channel  string
+	username string
+	client   *http.Client
+}
+
+type WebhookOption func(*Webhook)
+
+// WithChannel overrides the channel configured for the webhook.
+func WithChannel(channel string) WebhookOption {
+	return func(w *Webhook) { w.channel = channel }
+}
+
+// WithUsername sets the name the messages are posted under.
+func WithUsername(username string) WebhookOption {
+	return func(w *Webhook) { w.username = username }
+}
+
+func WithHTTPClient(c *http.Client) WebhookOption {
+	return func(w *Webhook) { w.client = c }
+}
+
+func NewWebhook(url string, opts ...WebhookOption) *Webhook {
+	w := &Webhook{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+type webhookPayload struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+func (w *Webhook) Send(ctx context.Context, m Message) error {
+	body, err := json.Marshal(webhookPayload{Text: m.Text, Channel: w.channel, Username: w.username})
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("webhook answered %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	// A bad payload or revoked hook will not get better on retry.
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
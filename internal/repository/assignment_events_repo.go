@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AssignmentEventsRepo interface {
+	// Add records events; a zero CreatedAt is set to the current time.
+	Add(ctx context.Context, events ...models.AssignmentEvent) error
+	// List returns the events of a pull request, oldest first.
+	List(ctx context.Context, prID string) ([]models.AssignmentEvent, error)
+	// Search returns the events of every pull request matching f, newest first.
+	Search(ctx context.Context, f EventFilter, limit, offset int) ([]models.AssignmentEvent, int64, error)
+}
+
+// EventFilter selects assignment events; zero fields match everything.
+type EventFilter struct {
+	PullRequestID string
+	// UserID matches the reviewer, the replaced reviewer and the requester.
+	UserID string
+	Kind   models.AssignmentEventKind
+	// Since is inclusive, Until exclusive.
+	Since time.Time
+	Until time.Time
+}
+
+type assignmentEventsRepo struct {
+	db *gorm.DB
+}
+
+func NewAssignmentEventsRepo(db *gorm.DB) AssignmentEventsRepo {
+	return &assignmentEventsRepo{db: db}
+}
+
+func (r *assignmentEventsRepo) Add(ctx context.Context, events ...models.AssignmentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	for i := range events {
+		events[i].TenantID = tenant.FromContext(ctx)
+		if events[i].CreatedAt.IsZero() {
+			events[i].CreatedAt = now
+		}
+	}
+	return r.db.WithContext(ctx).Create(&events).Error
+}
+
+func (r *assignmentEventsRepo) List(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+	var events []models.AssignmentEvent
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "assignment_events")).Where("pull_request_id = ?", prID).Order("event_id").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *assignmentEventsRepo) Search(ctx context.Context, f EventFilter, limit, offset int) ([]models.AssignmentEvent, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.AssignmentEvent{}).Scopes(inTenant(ctx, "assignment_events"))
+	if f.PullRequestID != "" {
+		q = q.Where("pull_request_id = ?", f.PullRequestID)
+	}
+	if f.UserID != "" {
+		q = q.Where("reviewer_id = ? OR previous_reviewer_id = ? OR requested_by = ?", f.UserID, f.UserID, f.UserID)
+	}
+	if f.Kind != "" {
+		q = q.Where("kind = ?", f.Kind)
+	}
+	if !f.Since.IsZero() {
+		q = q.Where("created_at >= ?", f.Since.UTC())
+	}
+	if !f.Until.IsZero() {
+		q = q.Where("created_at < ?", f.Until.UTC())
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []models.AssignmentEvent
+	err := q.Order("event_id DESC").Limit(limit).Offset(offset).Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
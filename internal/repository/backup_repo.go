@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+const insertBatchSize = 500
+
+// BackupCounts is the number of rows the tenant has in every table covered by
+// export/restore.
+type BackupCounts struct {
+	Teams        int64
+	Users        int64
+	Memberships  int64
+	Digests      int64
+	TeamSLAs     int64
+	CodeOwners   int64
+	Skills       int64
+	Exclusions   int64
+	PullRequests int64
+	Reviewers    int64
+	Events       int64
+}
+
+func (c BackupCounts) Empty() bool {
+	return c == BackupCounts{}
+}
+
+// BackupRepo reads and writes the tenant's share of whole tables for export
+// and restore. The Each* methods stream rows from a cursor in primary key
+// order, so memory use does not grow with the table size.
+type BackupRepo interface {
+	Counts(ctx context.Context) (BackupCounts, error)
+	EachTeam(ctx context.Context, fn func(*models.Team) error) error
+	EachUser(ctx context.Context, fn func(*models.User) error) error
+	EachMembership(ctx context.Context, fn func(*models.TeamMembership) error) error
+	EachDigest(ctx context.Context, fn func(*models.DigestSubscription) error) error
+	EachTeamSLA(ctx context.Context, fn func(*models.TeamSLA) error) error
+	EachCodeOwnerRule(ctx context.Context, fn func(*models.CodeOwnerRule) error) error
+	EachSkill(ctx context.Context, fn func(*models.UserSkill) error) error
+	EachExclusion(ctx context.Context, fn func(*models.ReviewerExclusion) error) error
+	EachPullRequest(ctx context.Context, fn func(*models.PullRequest) error) error
+	EachReviewer(ctx context.Context, fn func(*models.PRReviewer) error) error
+	// EachEvent streams assignment events in the order they were recorded.
+	EachEvent(ctx context.Context, fn func(*models.AssignmentEvent) error) error
+	// Insert* write rows as they are, keeping versions and timestamps.
+	InsertTeams(ctx context.Context, teams []models.Team) error
+	InsertUsers(ctx context.Context, users []models.User) error
+	InsertMemberships(ctx context.Context, memberships []models.TeamMembership) error
+	// EnsurePrimaryMemberships adds the missing memberships of users in their
+	// primary team, for exports made before memberships existed.
+	EnsurePrimaryMemberships(ctx context.Context) error
+	InsertDigests(ctx context.Context, digests []models.DigestSubscription) error
+	InsertTeamSLAs(ctx context.Context, slas []models.TeamSLA) error
+	InsertCodeOwnerRules(ctx context.Context, rules []models.CodeOwnerRule) error
+	InsertSkills(ctx context.Context, skills []models.UserSkill) error
+	InsertExclusions(ctx context.Context, exclusions []models.ReviewerExclusion) error
+	InsertPullRequests(ctx context.Context, prs []models.PullRequest) error
+	InsertReviewers(ctx context.Context, reviewers []models.PRReviewer) error
+	// InsertEvents assigns new event IDs in the order of events.
+	InsertEvents(ctx context.Context, events []models.AssignmentEvent) error
+}
+
+type backupRepo struct {
+	db *gorm.DB
+}
+
+func NewBackupRepo(db *gorm.DB) BackupRepo {
+	return &backupRepo{db: db}
+}
+
+func (r *backupRepo) Counts(ctx context.Context) (BackupCounts, error) {
+	var c BackupCounts
+	db := r.db.WithContext(ctx)
+	for _, q := range []struct {
+		model any
+		table string
+		dst   *int64
+	}{
+		{&models.Team{}, "teams", &c.Teams},
+		{&models.User{}, "users", &c.Users},
+		{&models.TeamMembership{}, "team_memberships", &c.Memberships},
+		{&models.DigestSubscription{}, "digest_subscriptions", &c.Digests},
+		{&models.TeamSLA{}, "team_slas", &c.TeamSLAs},
+		{&models.CodeOwnerRule{}, "code_owner_rules", &c.CodeOwners},
+		{&models.UserSkill{}, "user_skills", &c.Skills},
+		{&models.ReviewerExclusion{}, "reviewer_exclusions", &c.Exclusions},
+		{&models.PullRequest{}, "pull_requests", &c.PullRequests},
+		{&models.PRReviewer{}, "pr_reviewers", &c.Reviewers},
+		{&models.AssignmentEvent{}, "assignment_events", &c.Events},
+	} {
+		if err := db.Model(q.model).Scopes(inTenant(ctx, q.table)).Count(q.dst).Error; err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+func (r *backupRepo) EachTeam(ctx context.Context, fn func(*models.Team) error) error {
+	return each(ctx, r.db, "teams", "team_name", fn)
+}
+
+func (r *backupRepo) EachUser(ctx context.Context, fn func(*models.User) error) error {
+	return each(ctx, r.db, "users", "user_id", fn)
+}
+
+func (r *backupRepo) EachMembership(ctx context.Context, fn func(*models.TeamMembership) error) error {
+	return each(ctx, r.db, "team_memberships", "team_name, user_id", fn)
+}
+
+func (r *backupRepo) EachDigest(ctx context.Context, fn func(*models.DigestSubscription) error) error {
+	return each(ctx, r.db, "digest_subscriptions", "user_id", fn)
+}
+
+func (r *backupRepo) EachTeamSLA(ctx context.Context, fn func(*models.TeamSLA) error) error {
+	return each(ctx, r.db, "team_slas", "team_name", fn)
+}
+
+func (r *backupRepo) EachCodeOwnerRule(ctx context.Context, fn func(*models.CodeOwnerRule) error) error {
+	return each(ctx, r.db, "code_owner_rules", "team_name, position", fn)
+}
+
+func (r *backupRepo) EachSkill(ctx context.Context, fn func(*models.UserSkill) error) error {
+	return each(ctx, r.db, "user_skills", "user_id, skill", fn)
+}
+
+func (r *backupRepo) EachExclusion(ctx context.Context, fn func(*models.ReviewerExclusion) error) error {
+	return each(ctx, r.db, "reviewer_exclusions", "author_id, reviewer_id", fn)
+}
+
+func (r *backupRepo) EachPullRequest(ctx context.Context, fn func(*models.PullRequest) error) error {
+	return each(ctx, r.db, "pull_requests", "pull_request_id", fn)
+}
+
+func (r *backupRepo) EachReviewer(ctx context.Context, fn func(*models.PRReviewer) error) error {
+	return each(ctx, r.db, "pr_reviewers", "pull_request_id, reviewer_id", fn)
+}
+
+func (r *backupRepo) EachEvent(ctx context.Context, fn func(*models.AssignmentEvent) error) error {
+	return each(ctx, r.db, "assignment_events", "event_id", fn)
+}
+
+func each[T any](ctx context.Context, db *gorm.DB, table, order string, fn func(*T) error) error {
+	rows, err := db.WithContext(ctx).Model(new(T)).Scopes(inTenant(ctx, table)).Order(order).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v T
+		if err := db.ScanRows(rows, &v); err != nil {
+			return err
+		}
+		if err := fn(&v); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *backupRepo) InsertTeams(ctx context.Context, teams []models.Team) error {
+	if len(teams) == 0 {
+		return nil
+	}
+	for i := range teams {
+		teams[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(teams, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertUsers(ctx context.Context, users []models.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	rows := make([]map[string]any, 0, len(users))
+	for i := range users {
+		users[i].TenantID = tenant.FromContext(ctx)
+		rows = append(rows, userRow(&users[i]))
+	}
+	return r.db.WithContext(ctx).Model(&models.User{}).CreateInBatches(rows, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertMemberships(ctx context.Context, memberships []models.TeamMembership) error {
+	if len(memberships) == 0 {
+		return nil
+	}
+	rows := make([]map[string]any, 0, len(memberships))
+	for _, m := range memberships {
+		rows = append(rows, map[string]any{
+			"tenant_id":  tenant.FromContext(ctx),
+			"team_name":  m.TeamName,
+			"user_id":    m.UserID,
+			"role":       m.Role,
+			"is_active":  m.IsActive,
+			"created_at": m.CreatedAt,
+		})
+	}
+	return r.db.WithContext(ctx).Model(&models.TeamMembership{}).CreateInBatches(rows, insertBatchSize).Error
+}
+
+func (r *backupRepo) EnsurePrimaryMemberships(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec(`INSERT INTO team_memberships (tenant_id, team_name, user_id, role, is_active, created_at)
+		SELECT users.tenant_id, users.team_name, users.user_id, ?, ?, users.created_at FROM users
+		WHERE users.tenant_id = ? AND NOT EXISTS (SELECT 1 FROM team_memberships
+			WHERE team_memberships.tenant_id = users.tenant_id AND team_memberships.user_id = users.user_id
+				AND team_memberships.team_name = users.team_name)`,
+		models.MembershipMember, true, tenant.FromContext(ctx)).Error
+}
+
+func (r *backupRepo) InsertDigests(ctx context.Context, digests []models.DigestSubscription) error {
+	if len(digests) == 0 {
+		return nil
+	}
+	for i := range digests {
+		digests[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(digests, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertTeamSLAs(ctx context.Context, slas []models.TeamSLA) error {
+	if len(slas) == 0 {
+		return nil
+	}
+	for i := range slas {
+		slas[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(slas, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertCodeOwnerRules(ctx context.Context, rules []models.CodeOwnerRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	for i := range rules {
+		rules[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(rules, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertSkills(ctx context.Context, skills []models.UserSkill) error {
+	if len(skills) == 0 {
+		return nil
+	}
+	for i := range skills {
+		skills[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(skills, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertExclusions(ctx context.Context, exclusions []models.ReviewerExclusion) error {
+	if len(exclusions) == 0 {
+		return nil
+	}
+	for i := range exclusions {
+		exclusions[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(exclusions, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertPullRequests(ctx context.Context, prs []models.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+	for i := range prs {
+		prs[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(prs, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertReviewers(ctx context.Context, reviewers []models.PRReviewer) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	for i := range reviewers {
+		reviewers[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(reviewers, insertBatchSize).Error
+}
+
+func (r *backupRepo) InsertEvents(ctx context.Context, events []models.AssignmentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	for i := range events {
+		events[i].ID = 0
+		events[i].TenantID = tenant.FromContext(ctx)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(events, insertBatchSize).Error
+}
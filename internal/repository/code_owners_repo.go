@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+type CodeOwnersRepo interface {
+	// List returns the team's rules by position.
+	List(ctx context.Context, teamName string) ([]models.CodeOwnerRule, error)
+	// Replace swaps the team's rules for rules, numbering them in order.
+	Replace(ctx context.Context, teamName string, rules []models.CodeOwnerRule) error
+	Delete(ctx context.Context, teamName string) error
+}
+
+type codeOwnersRepo struct {
+	db *gorm.DB
+}
+
+func NewCodeOwnersRepo(db *gorm.DB) CodeOwnersRepo {
+	return &codeOwnersRepo{db: db}
+}
+
+func (r *codeOwnersRepo) List(ctx context.Context, teamName string) ([]models.CodeOwnerRule, error) {
+	var rules []models.CodeOwnerRule
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "code_owner_rules")).Where("team_name = ?", teamName).Order("position").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *codeOwnersRepo) Replace(ctx context.Context, teamName string, rules []models.CodeOwnerRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Scopes(inTenant(ctx, "code_owner_rules")).Where("team_name = ?", teamName).Delete(&models.CodeOwnerRule{}).Error
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		for i := range rules {
+			rules[i].TenantID = tenant.FromContext(ctx)
+			rules[i].TeamName, rules[i].Position = teamName, i+1
+		}
+		return tx.CreateInBatches(rules, 200).Error
+	})
+}
+
+func (r *codeOwnersRepo) Delete(ctx context.Context, teamName string) error {
+	return r.db.WithContext(ctx).Scopes(inTenant(ctx, "code_owner_rules")).Where("team_name = ?", teamName).Delete(&models.CodeOwnerRule{}).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DigestsRepo interface {
+	// Upsert creates the subscription or replaces its email and schedule.
+	Upsert(ctx context.Context, s *models.DigestSubscription) error
+	Get(ctx context.Context, userID string) (*models.DigestSubscription, error)
+	Delete(ctx context.Context, userID string) (bool, error)
+	List(ctx context.Context) ([]models.DigestSubscription, error)
+	MarkSent(ctx context.Context, userID string, at time.Time) error
+}
+
+type digestsRepo struct {
+	db *gorm.DB
+}
+
+func NewDigestsRepo(db *gorm.DB) DigestsRepo {
+	return &digestsRepo{db: db}
+}
+
+func (r *digestsRepo) Upsert(ctx context.Context, s *models.DigestSubscription) error {
+	s.TenantID = tenant.FromContext(ctx)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "send_at", "timezone", "updated_at"}),
+	}).Create(s).Error
+}
+
+func (r *digestsRepo) Get(ctx context.Context, userID string) (*models.DigestSubscription, error) {
+	var s models.DigestSubscription
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "digest_subscriptions")).Where("user_id = ?", userID).First(&s).Error
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *digestsRepo) Delete(ctx context.Context, userID string) (bool, error) {
+	res := r.db.WithContext(ctx).Scopes(inTenant(ctx, "digest_subscriptions")).Where("user_id = ?", userID).Delete(&models.DigestSubscription{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *digestsRepo) List(ctx context.Context) ([]models.DigestSubscription, error) {
+	var subs []models.DigestSubscription
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "digest_subscriptions")).Order("user_id").Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *digestsRepo) MarkSent(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.DigestSubscription{}).
+		Scopes(inTenant(ctx, "digest_subscriptions")).
+		Where("user_id = ?", userID).
+		Update("last_sent_at", at).Error
+}
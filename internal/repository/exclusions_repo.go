@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExclusionsRepo interface {
+	// List returns the exclusions in which userID is the author or the
+	// reviewer, or every exclusion when userID is empty.
+	List(ctx context.Context, userID string) ([]models.ReviewerExclusion, error)
+	// Upsert adds the exclusion or updates its reason.
+	Upsert(ctx context.Context, e *models.ReviewerExclusion) error
+	Delete(ctx context.Context, authorID, reviewerID string) (bool, error)
+	DeleteUser(ctx context.Context, userID string) error
+	// Excluded returns those of reviewerIDs that must not review authorID.
+	Excluded(ctx context.Context, authorID string, reviewerIDs []string) ([]string, error)
+}
+
+type exclusionsRepo struct {
+	db *gorm.DB
+}
+
+func NewExclusionsRepo(db *gorm.DB) ExclusionsRepo {
+	return &exclusionsRepo{db: db}
+}
+
+func (r *exclusionsRepo) List(ctx context.Context, userID string) ([]models.ReviewerExclusion, error) {
+	q := r.db.WithContext(ctx).Scopes(inTenant(ctx, "reviewer_exclusions"))
+	if userID != "" {
+		q = q.Where("author_id = ? OR reviewer_id = ?", userID, userID)
+	}
+	var out []models.ReviewerExclusion
+	if err := q.Order("author_id, reviewer_id").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *exclusionsRepo) Upsert(ctx context.Context, e *models.ReviewerExclusion) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	e.TenantID = tenant.FromContext(ctx)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "author_id"}, {Name: "reviewer_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason"}),
+	}).Create(e).Error
+}
+
+func (r *exclusionsRepo) Delete(ctx context.Context, authorID, reviewerID string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Scopes(inTenant(ctx, "reviewer_exclusions")).
+		Where("author_id = ? AND reviewer_id = ?", authorID, reviewerID).
+		Delete(&models.ReviewerExclusion{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *exclusionsRepo) DeleteUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).
+		Scopes(inTenant(ctx, "reviewer_exclusions")).
+		Where("(author_id = ? OR reviewer_id = ?)", userID, userID).
+		Delete(&models.ReviewerExclusion{}).Error
+}
+
+func (r *exclusionsRepo) Excluded(ctx context.Context, authorID string, reviewerIDs []string) ([]string, error) {
+	if len(reviewerIDs) == 0 {
+		return nil, nil
+	}
+	var out []string
+	err := r.db.WithContext(ctx).Model(&models.ReviewerExclusion{}).
+		Scopes(inTenant(ctx, "reviewer_exclusions")).
+		Where("author_id = ? AND reviewer_id IN ?", authorID, reviewerIDs).
+		Pluck("reviewer_id", &out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepo interface {
+	// Reserve inserts rec unless a record with the same key already exists.
+	Reserve(ctx context.Context, rec *models.IdempotencyKey) (bool, error)
+	Get(ctx context.Context, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, key string, code int, contentType string, headers map[string]string, body []byte) error
+	Delete(ctx context.Context, key string) error
+	// DeleteCreated removes the key only if it is still the record created at
+	// createdAt, and reports whether it did.
+	DeleteCreated(ctx context.Context, key string, createdAt time.Time) (bool, error)
+	// DeleteExpired removes the expired keys of every tenant.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyRepo struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepo(db *gorm.DB) IdempotencyRepo {
+	return &idempotencyRepo{db: db}
+}
+
+func (r *idempotencyRepo) Reserve(ctx context.Context, rec *models.IdempotencyKey) (bool, error) {
+	rec.TenantID = tenant.FromContext(ctx)
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *idempotencyRepo) Get(ctx context.Context, key string) (*models.IdempotencyKey, error) {
+	var rec models.IdempotencyKey
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "idempotency_keys")).Where("idempotency_key = ?", key).First(&rec).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, key string, code int, contentType string, headers map[string]string, body []byte) error {
+	return r.db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Scopes(inTenant(ctx, "idempotency_keys")).
+		Where("idempotency_key = ?", key).
+		Select("status", "response_code", "content_type", "response_headers", "response_body").
+		Updates(&models.IdempotencyKey{
+			Status:          models.IdempotencyCompleted,
+			ResponseCode:    code,
+			ContentType:     contentType,
+			ResponseHeaders: headers,
+			ResponseBody:    body,
+		}).Error
+}
+
+func (r *idempotencyRepo) Delete(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Scopes(inTenant(ctx, "idempotency_keys")).Where("idempotency_key = ?", key).Delete(&models.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyRepo) DeleteCreated(ctx context.Context, key string, createdAt time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Scopes(inTenant(ctx, "idempotency_keys")).
+		Where("idempotency_key = ? AND created_at = ?", key, createdAt).
+		Delete(&models.IdempotencyKey{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *idempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return res.RowsAffected, res.Error
+}
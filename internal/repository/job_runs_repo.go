@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type JobRunsRepo interface {
+	Create(ctx context.Context, run *models.JobRun) error
+	// Finish stores the outcome of a run created earlier.
+	Finish(ctx context.Context, run *models.JobRun) error
+	// LastScheduledAt returns the latest schedule slot the job was run for, or
+	// the zero time.
+	LastScheduledAt(ctx context.Context, jobName string) (time.Time, error)
+	// Last returns the most recent run of the job, or nil.
+	Last(ctx context.Context, jobName string) (*models.JobRun, error)
+	// List returns runs newest first; an empty jobName lists every job.
+	List(ctx context.Context, jobName string, limit, offset int) ([]models.JobRun, int64, error)
+}
+
+type jobRunsRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRunsRepo(db *gorm.DB) JobRunsRepo {
+	return &jobRunsRepo{db: db}
+}
+
+func (r *jobRunsRepo) Create(ctx context.Context, run *models.JobRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *jobRunsRepo) Finish(ctx context.Context, run *models.JobRun) error {
+	return r.db.WithContext(ctx).Model(&models.JobRun{}).Where("run_id = ?", run.ID).Updates(map[string]any{
+		"status":      run.Status,
+		"finished_at": run.FinishedAt,
+		"duration_ms": run.DurationMs,
+		"error":       run.Error,
+	}).Error
+}
+
+func (r *jobRunsRepo) LastScheduledAt(ctx context.Context, jobName string) (time.Time, error) {
+	var run models.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job_name = ? AND scheduled_at IS NOT NULL", jobName).
+		Order("scheduled_at DESC").
+		First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return *run.ScheduledAt, nil
+}
+
+func (r *jobRunsRepo) Last(ctx context.Context, jobName string) (*models.JobRun, error) {
+	var run models.JobRun
+	err := r.db.WithContext(ctx).Where("job_name = ?", jobName).Order("started_at DESC, run_id DESC").First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *jobRunsRepo) List(ctx context.Context, jobName string, limit, offset int) ([]models.JobRun, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.JobRun{})
+	if jobName != "" {
+		q = q.Where("job_name = ?", jobName)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var runs []models.JobRun
+	err := q.Order("started_at DESC, run_id DESC").Limit(limit).Offset(offset).Find(&runs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MembershipsRepo interface {
+	// Upsert adds the membership or updates its role and activity.
+	Upsert(ctx context.Context, m *models.TeamMembership) error
+	Get(ctx context.Context, teamName, userID string) (*models.TeamMembership, error)
+	Delete(ctx context.Context, teamName, userID string) (bool, error)
+	DeleteUser(ctx context.Context, userID string) error
+	// List returns all memberships ordered by user and team.
+	List(ctx context.Context) ([]models.TeamMembership, error)
+	// ListByUser returns the user's memberships ordered by team.
+	ListByUser(ctx context.Context, userID string) ([]models.TeamMembership, error)
+	// Members returns the users of the team with their membership, ordered by
+	// user ID.
+	Members(ctx context.Context, teamName string) ([]TeamMember, error)
+}
+
+// TeamMember is a user together with their membership in one team.
+type TeamMember struct {
+	User             models.User `gorm:"embedded"`
+	Role             string
+	MembershipActive bool
+}
+
+type membershipsRepo struct {
+	db *gorm.DB
+}
+
+func NewMembershipsRepo(db *gorm.DB) MembershipsRepo {
+	return &membershipsRepo{db: db}
+}
+
+// Upsert writes a map rather than the struct so that is_active = false is
+// not replaced with the column default.
+func (r *membershipsRepo) Upsert(ctx context.Context, m *models.TeamMembership) error {
+	m.TenantID = tenant.FromContext(ctx)
+	if m.Role == "" {
+		m.Role = models.MembershipMember
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	return r.db.WithContext(ctx).Model(&models.TeamMembership{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "team_name"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "is_active"}),
+	}).Create(map[string]any{
+		"tenant_id":  m.TenantID,
+		"team_name":  m.TeamName,
+		"user_id":    m.UserID,
+		"role":       m.Role,
+		"is_active":  m.IsActive,
+		"created_at": m.CreatedAt,
+	}).Error
+}
+
+func (r *membershipsRepo) Get(ctx context.Context, teamName, userID string) (*models.TeamMembership, error) {
+	var m models.TeamMembership
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_memberships")).
+		Where("team_name = ? AND user_id = ?", teamName, userID).
+		First(&m).Error
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *membershipsRepo) Delete(ctx context.Context, teamName, userID string) (bool, error) {
+	res := r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_memberships")).
+		Where("team_name = ? AND user_id = ?", teamName, userID).
+		Delete(&models.TeamMembership{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *membershipsRepo) DeleteUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_memberships")).Where("user_id = ?", userID).Delete(&models.TeamMembership{}).Error
+}
+
+func (r *membershipsRepo) List(ctx context.Context) ([]models.TeamMembership, error) {
+	var out []models.TeamMembership
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_memberships")).Order("user_id, team_name").Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *membershipsRepo) ListByUser(ctx context.Context, userID string) ([]models.TeamMembership, error) {
+	var out []models.TeamMembership
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_memberships")).Where("user_id = ?", userID).Order("team_name").Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *membershipsRepo) Members(ctx context.Context, teamName string) ([]TeamMember, error) {
+	var out []TeamMember
+	err := r.db.WithContext(ctx).Model(&models.User{}).
+		Select("users.*, team_memberships.role AS role, team_memberships.is_active AS membership_active").
+		Joins("JOIN team_memberships ON team_memberships.tenant_id = users.tenant_id AND team_memberships.user_id = users.user_id").
+		Scopes(inTenant(ctx, "users")).
+		Where("team_memberships.team_name = ?", teamName).
+		Order("users.user_id").
+		Scan(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
 	"time"
 
 	"gorm.io/gorm"
@@ -15,8 +16,27 @@ type PRRepo interface {
 	SetPullRequestMerged(ctx context.Context, id string, mergedAt time.Time) (bool, error)
 	AddReviewers(ctx context.Context, prID string, reviewerIDs []string) error
 	ReplaceReviewer(ctx context.Context, prID, oldID, newID string) error
+	// Lock blocks concurrent writers of the pull request until the current
+	// transaction ends; it must be called inside Repository.Transaction.
+	Lock(ctx context.Context, id string) error
+	// AddReviewer assigns one more reviewer and increments the version.
+	AddReviewer(ctx context.Context, prID, reviewerID string) error
+	// RemoveReviewer unassigns the reviewer and increments the version; it
+	// reports false when the reviewer was not assigned.
+	RemoveReviewer(ctx context.Context, prID, reviewerID string) (bool, error)
 	GetPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]models.PullRequest, error)
 	GetReviewersForPR(ctx context.Context, prID string) ([]models.PRReviewer, error)
+	// Update applies fields and increments the version. With expectedVersion > 0
+	// it only succeeds while the stored version still matches.
+	Update(ctx context.Context, id string, expectedVersion int64, fields map[string]any) (bool, error)
+	// Delete removes the pull request together with its reviewer assignments
+	// and their history.
+	Delete(ctx context.Context, id string, expectedVersion int64) (bool, error)
+	// CountByUser returns how many pull requests reference the user as author or reviewer.
+	CountByUser(ctx context.Context, userID string) (int64, error)
+	// OpenReviewCounts returns how many open pull requests each of userIDs
+	// reviews; users without open reviews are missing from the map.
+	OpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int64, error)
 	GetUserReviewStats(ctx context.Context) ([]UserReviewStats, error)
 	GetPRReviewStats(ctx context.Context) ([]PRReviewStats, error)
 }
@@ -30,12 +50,13 @@ func NewPRRepo(db *gorm.DB) PRRepo {
 }
 
 func (r *prRepo) Create(ctx context.Context, pr *models.PullRequest) error {
+	pr.TenantID = tenant.FromContext(ctx)
 	return r.db.WithContext(ctx).Create(&pr).Error
 }
 
 func (r *prRepo) GetPullRequestByID(ctx context.Context, id string) (*models.PullRequest, error) {
 	var pr models.PullRequest
-	err := r.db.WithContext(ctx).Where("pull_request_id = ?", id).First(&pr).Error
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "pull_requests")).Where("pull_request_id = ?", id).First(&pr).Error
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +66,7 @@ func (r *prRepo) GetPullRequestByID(ctx context.Context, id string) (*models.Pul
 func (r *prRepo) GetPullRequestWithReviewers(ctx context.Context, id string) (*models.PullRequest, []models.PRReviewer, error) {
 	var pr models.PullRequest
 	err := r.db.WithContext(ctx).
+		Scopes(inTenant(ctx, "pull_requests")).
 		Where("pull_request_id = ?", id).
 		First(&pr).Error
 	if err != nil {
@@ -53,6 +75,7 @@ func (r *prRepo) GetPullRequestWithReviewers(ctx context.Context, id string) (*m
 
 	var reviewers []models.PRReviewer
 	err = r.db.WithContext(ctx).
+		Scopes(inTenant(ctx, "pr_reviewers")).
 		Where("pull_request_id = ?", id).
 		Find(&reviewers).Error
 	if err != nil {
@@ -63,9 +86,10 @@ func (r *prRepo) GetPullRequestWithReviewers(ctx context.Context, id string) (*m
 }
 
 func (r *prRepo) SetPullRequestMerged(ctx context.Context, id string, mergedAt time.Time) (bool, error) {
-	res := r.db.WithContext(ctx).Model(&models.PullRequest{}).Where("pull_request_id = ? AND status = ?", id, models.PRStatusOpen).Updates(map[string]any{
+	res := r.db.WithContext(ctx).Model(&models.PullRequest{}).Scopes(inTenant(ctx, "pull_requests")).Where("pull_request_id = ? AND status = ?", id, models.PRStatusOpen).Updates(map[string]any{
 		"status":    models.PRStatusMerged,
 		"merged_at": mergedAt,
+		"version":   gorm.Expr("version + 1"),
 	})
 	if res.Error != nil {
 		return false, res.Error
@@ -82,6 +106,7 @@ func (r *prRepo) AddReviewers(ctx context.Context, prID string, reviewerIDs []st
 	now := time.Now().UTC()
 	for _, id := range reviewerIDs {
 		reviewers = append(reviewers, models.PRReviewer{
+			TenantID:      tenant.FromContext(ctx),
 			PullRequestID: prID,
 			ReviewerID:    id,
 			AssignedAt:    now,
@@ -93,10 +118,15 @@ func (r *prRepo) AddReviewers(ctx context.Context, prID string, reviewerIDs []st
 
 func (r *prRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.WithContext(ctx).Where("pull_request_id = ? AND reviewer_id = ?", prID, oldID).Delete(&models.PRReviewer{}).Error; err != nil {
+		err := tx.WithContext(ctx).
+			Scopes(inTenant(ctx, "pr_reviewers")).
+			Where("pull_request_id = ? AND reviewer_id = ?", prID, oldID).
+			Delete(&models.PRReviewer{}).Error
+		if err != nil {
 			return err
 		}
 		reviewer := models.PRReviewer{
+			TenantID:      tenant.FromContext(ctx),
 			PullRequestID: prID,
 			ReviewerID:    newID,
 			AssignedAt:    time.Now().UTC(),
@@ -104,15 +134,153 @@ func (r *prRepo) ReplaceReviewer(ctx context.Context, prID, oldID, newID string)
 		if err := tx.WithContext(ctx).Create(&reviewer).Error; err != nil {
 			return err
 		}
+		return bumpPRVersion(ctx, tx, prID)
+	})
+}
+
+// Lock writes the row without changing it: unlike SELECT ... FOR UPDATE,
+// this takes the write lock on SQLite as well.
+func (r *prRepo) Lock(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&models.PullRequest{}).
+		Scopes(inTenant(ctx, "pull_requests")).
+		Where("pull_request_id = ?", id).
+		Update("version", gorm.Expr("version")).Error
+}
+
+func (r *prRepo) AddReviewer(ctx context.Context, prID, reviewerID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reviewer := models.PRReviewer{
+			TenantID:      tenant.FromContext(ctx),
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+			AssignedAt:    time.Now().UTC(),
+		}
+		if err := tx.WithContext(ctx).Create(&reviewer).Error; err != nil {
+			return err
+		}
+		return bumpPRVersion(ctx, tx, prID)
+	})
+}
+
+func (r *prRepo) RemoveReviewer(ctx context.Context, prID, reviewerID string) (bool, error) {
+	var removed bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.WithContext(ctx).
+			Scopes(inTenant(ctx, "pr_reviewers")).
+			Where("pull_request_id = ? AND reviewer_id = ?", prID, reviewerID).
+			Delete(&models.PRReviewer{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		removed = true
+		return bumpPRVersion(ctx, tx, prID)
+	})
+	return removed, err
+}
+
+func bumpPRVersion(ctx context.Context, tx *gorm.DB, prID string) error {
+	return tx.WithContext(ctx).Model(&models.PullRequest{}).
+		Scopes(inTenant(ctx, "pull_requests")).
+		Where("pull_request_id = ?", prID).
+		Update("version", gorm.Expr("version + 1")).Error
+}
+
+func (r *prRepo) Update(ctx context.Context, id string, expectedVersion int64, fields map[string]any) (bool, error) {
+	updates := make(map[string]any, len(fields)+1)
+	for k, v := range fields {
+		updates[k] = v
+	}
+	updates["version"] = gorm.Expr("version + 1")
+
+	res := r.db.WithContext(ctx).Model(&models.PullRequest{}).
+		Scopes(inTenant(ctx, "pull_requests"), matchVersion(expectedVersion)).
+		Where("pull_request_id = ?", id).
+		Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *prRepo) Delete(ctx context.Context, id string, expectedVersion int64) (bool, error) {
+	var deleted bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var n int64
+		err := tx.WithContext(ctx).Model(&models.PullRequest{}).
+			Scopes(inTenant(ctx, "pull_requests"), matchVersion(expectedVersion)).
+			Where("pull_request_id = ?", id).
+			Count(&n).Error
+		if err != nil || n == 0 {
+			return err
+		}
+
+		err = tx.WithContext(ctx).Scopes(inTenant(ctx, "pr_reviewers")).Where("pull_request_id = ?", id).Delete(&models.PRReviewer{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.WithContext(ctx).Scopes(inTenant(ctx, "assignment_events")).Where("pull_request_id = ?", id).Delete(&models.AssignmentEvent{}).Error
+		if err != nil {
+			return err
+		}
+		res := tx.WithContext(ctx).
+			Scopes(inTenant(ctx, "pull_requests"), matchVersion(expectedVersion)).
+			Where("pull_request_id = ?", id).
+			Delete(&models.PullRequest{})
+		if res.Error != nil {
+			return res.Error
+		}
+		deleted = res.RowsAffected > 0
 		return nil
 	})
+	return deleted, err
+}
+
+func (r *prRepo) CountByUser(ctx context.Context, userID string) (int64, error) {
+	var authored, reviewing int64
+	if err := r.db.WithContext(ctx).Model(&models.PullRequest{}).Scopes(inTenant(ctx, "pull_requests")).Where("author_id = ?", userID).Count(&authored).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.WithContext(ctx).Model(&models.PRReviewer{}).Scopes(inTenant(ctx, "pr_reviewers")).Where("reviewer_id = ?", userID).Count(&reviewing).Error; err != nil {
+		return 0, err
+	}
+	return authored + reviewing, nil
+}
+
+func (r *prRepo) OpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
 
+	var rows []struct {
+		ReviewerID string
+		Count      int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.PRReviewer{}).
+		Select("pr_reviewers.reviewer_id, COUNT(*) AS count").
+		Joins("JOIN pull_requests ON pull_requests.tenant_id = pr_reviewers.tenant_id AND pull_requests.pull_request_id = pr_reviewers.pull_request_id").
+		Scopes(inTenant(ctx, "pr_reviewers")).
+		Where("pr_reviewers.reviewer_id IN ? AND pull_requests.status = ?", userIDs, models.PRStatusOpen).
+		Group("pr_reviewers.reviewer_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ReviewerID] = row.Count
+	}
+	return counts, nil
 }
 
 func (r *prRepo) GetPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]models.PullRequest, error) {
 	var prs []models.PullRequest
 
-	err := r.db.WithContext(ctx).Model(&models.PullRequest{}).Joins("JOIN pr_reviewers ON pr_reviewers.pull_request_id = pull_requests.pull_request_id").Where("pr_reviewers.reviewer_id = ?", reviewerID).Order("pull_requests.created_at DESC").Find(&prs).Error
+	err := r.db.WithContext(ctx).Model(&models.PullRequest{}).
+		Joins("JOIN pr_reviewers ON pr_reviewers.tenant_id = pull_requests.tenant_id AND pr_reviewers.pull_request_id = pull_requests.pull_request_id").
+		Scopes(inTenant(ctx, "pull_requests")).
+		Where("pr_reviewers.reviewer_id = ?", reviewerID).
+		Order("pull_requests.created_at DESC").
+		Find(&prs).Error
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +289,7 @@ func (r *prRepo) GetPullRequestsByReviewer(ctx context.Context, reviewerID strin
 
 func (r *prRepo) GetReviewersForPR(ctx context.Context, prID string) ([]models.PRReviewer, error) {
 	var reviewers []models.PRReviewer
-	err := r.db.WithContext(ctx).Where("pull_request_id = ?", prID).Find(&reviewers).Error
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "pr_reviewers")).Where("pull_request_id = ?", prID).Find(&reviewers).Error
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +319,8 @@ func (r *prRepo) GetUserReviewStats(ctx context.Context) ([]UserReviewStats, err
 			users.team_name AS team_name,
 			COUNT(*) AS review_count`,
 		).
-		Joins("JOIN users ON users.user_id = pr_reviewers.reviewer_id").
+		Joins("JOIN users ON users.tenant_id = pr_reviewers.tenant_id AND users.user_id = pr_reviewers.reviewer_id").
+		Scopes(inTenant(ctx, "pr_reviewers")).
 		Group("pr_reviewers.reviewer_id, users.username, users.team_name").
 		Scan(&rows).Error
 
@@ -168,6 +337,7 @@ func (r *prRepo) GetPRReviewStats(ctx context.Context) ([]PRReviewStats, error)
 	err := r.db.WithContext(ctx).
 		Table("pr_reviewers").
 		Select("pull_request_id, COUNT(*) AS reviewer_count").
+		Scopes(inTenant(ctx, "pr_reviewers")).
 		Group("pull_request_id").
 		Scan(&rows).Error
 
@@ -1,23 +1,115 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"database/sql"
+	"reviewer_pr/internal/tenant"
+
+	"gorm.io/gorm"
+)
 
 type Repository struct {
-	DB    *gorm.DB
-	Teams TeamsRepo
-	Users UsersRepo
-	PRs   PRRepo
+	DB      *gorm.DB
+	Tenants TenantsRepo
+	Teams   TeamsRepo
+	Users   UsersRepo
+	PRs     PRRepo
+
+	Idempotency IdempotencyRepo
+	Backup      BackupRepo
+	Digests     DigestsRepo
+	SLAs        SLARepo
+	JobRuns     JobRunsRepo
+	CodeOwners  CodeOwnersRepo
+	Skills      SkillsRepo
+	Memberships MembershipsRepo
+	Events      AssignmentEventsRepo
+	Exclusions  ExclusionsRepo
+
+	reader *Repository
 }
 
 func buildRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		DB:    db,
-		Teams: NewTeamsRepo(db),
-		Users: NewUsersRepo(db),
-		PRs:   NewPRRepo(db),
+		DB:      db,
+		Tenants: NewTenantsRepo(db),
+		Teams:   NewTeamsRepo(db),
+		Users:   NewUsersRepo(db),
+		PRs:     NewPRRepo(db),
+
+		Idempotency: NewIdempotencyRepo(db),
+		Backup:      NewBackupRepo(db),
+		Digests:     NewDigestsRepo(db),
+		SLAs:        NewSLARepo(db),
+		JobRuns:     NewJobRunsRepo(db),
+		CodeOwners:  NewCodeOwnersRepo(db),
+		Skills:      NewSkillsRepo(db),
+		Memberships: NewMembershipsRepo(db),
+		Events:      NewAssignmentEventsRepo(db),
+		Exclusions:  NewExclusionsRepo(db),
 	}
 }
 
 func New(db *gorm.DB) *Repository {
 	return buildRepository(db)
 }
+
+// NewWithReplica builds repositories that write to primary and can serve
+// lag-tolerant reads from replica. A nil replica falls back to the primary.
+func NewWithReplica(primary, replica *gorm.DB) *Repository {
+	r := buildRepository(primary)
+	if replica != nil {
+		r.reader = buildRepository(replica)
+	}
+	return r
+}
+
+// Reader returns repositories bound to the read replica, or the primary ones
+// when no replica is configured. Use it only for queries that tolerate
+// replication lag and never inside a write transaction.
+func (r *Repository) Reader() *Repository {
+	if r.reader != nil {
+		return r.reader
+	}
+	return r
+}
+
+// Transaction runs fn with repositories bound to one database transaction.
+// Returning an error from fn rolls back everything fn wrote.
+func (r *Repository) Transaction(ctx context.Context, fn func(tx *Repository) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(buildRepository(tx))
+	})
+}
+
+// Snapshot runs fn in a read-only transaction that sees a consistent view of
+// the database for its whole duration.
+func (r *Repository) Snapshot(ctx context.Context, fn func(tx *Repository) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(buildRepository(tx))
+	}, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+}
+
+// inTenant restricts a query to the rows of the tenant in ctx. table
+// qualifies the column, so that joined tables do not make it ambiguous.
+func inTenant(ctx context.Context, table string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(table+".tenant_id = ?", tenant.FromContext(ctx))
+	}
+}
+
+// matchVersion restricts an update to rows still at expectedVersion;
+// zero disables the check.
+func matchVersion(expectedVersion int64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if expectedVersion <= 0 {
+			return db
+		}
+		return db.Where("version = ?", expectedVersion)
+	}
+}
+
+// HasReplica reports whether reads may be served by a separate replica.
+func (r *Repository) HasReplica() bool {
+	return r.reader != nil
+}
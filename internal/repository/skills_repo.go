@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SkillsRepo interface {
+	// List returns the user's skills by name.
+	List(ctx context.Context, userID string) ([]models.UserSkill, error)
+	Count(ctx context.Context, userID string) (int64, error)
+	// Upsert adds the skill or updates its proficiency.
+	Upsert(ctx context.Context, s *models.UserSkill) error
+	Delete(ctx context.Context, userID, skill string) (bool, error)
+	DeleteUser(ctx context.Context, userID string) error
+	// Matching returns the skills of userIDs that are among skills.
+	Matching(ctx context.Context, userIDs, skills []string) ([]models.UserSkill, error)
+}
+
+type skillsRepo struct {
+	db *gorm.DB
+}
+
+func NewSkillsRepo(db *gorm.DB) SkillsRepo {
+	return &skillsRepo{db: db}
+}
+
+func (r *skillsRepo) List(ctx context.Context, userID string) ([]models.UserSkill, error) {
+	var skills []models.UserSkill
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "user_skills")).Where("user_id = ?", userID).Order("skill").Find(&skills).Error
+	if err != nil {
+		return nil, err
+	}
+	return skills, nil
+}
+
+func (r *skillsRepo) Count(ctx context.Context, userID string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&models.UserSkill{}).Scopes(inTenant(ctx, "user_skills")).Where("user_id = ?", userID).Count(&n).Error
+	return n, err
+}
+
+// Upsert writes a map rather than the struct so that proficiency 0 is not
+// replaced with the column default.
+func (r *skillsRepo) Upsert(ctx context.Context, s *models.UserSkill) error {
+	return r.db.WithContext(ctx).Model(&models.UserSkill{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "user_id"}, {Name: "skill"}},
+		DoUpdates: clause.AssignmentColumns([]string{"proficiency"}),
+	}).Create(map[string]any{
+		"tenant_id":   tenant.FromContext(ctx),
+		"user_id":     s.UserID,
+		"skill":       s.Skill,
+		"proficiency": s.Proficiency,
+	}).Error
+}
+
+func (r *skillsRepo) Delete(ctx context.Context, userID, skill string) (bool, error) {
+	res := r.db.WithContext(ctx).Scopes(inTenant(ctx, "user_skills")).Where("user_id = ? AND skill = ?", userID, skill).Delete(&models.UserSkill{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *skillsRepo) DeleteUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Scopes(inTenant(ctx, "user_skills")).Where("user_id = ?", userID).Delete(&models.UserSkill{}).Error
+}
+
+func (r *skillsRepo) Matching(ctx context.Context, userIDs, skills []string) ([]models.UserSkill, error) {
+	if len(userIDs) == 0 || len(skills) == 0 {
+		return nil, nil
+	}
+	var out []models.UserSkill
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "user_skills")).Where("user_id IN ? AND skill IN ?", userIDs, skills).Order("user_id, skill").Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SLARepo interface {
+	Get(ctx context.Context, teamName string) (*models.TeamSLA, error)
+	Upsert(ctx context.Context, s *models.TeamSLA) error
+	Delete(ctx context.Context, teamName string) (bool, error)
+	List(ctx context.Context) ([]models.TeamSLA, error)
+	// OverdueReviews returns the reviewers of open pull requests authored by
+	// the team that were assigned before assignedBefore, oldest first.
+	OverdueReviews(ctx context.Context, teamName string, assignedBefore time.Time) ([]OverdueReview, error)
+	// OverdueMerges returns open pull requests authored by the team that were
+	// created before createdBefore, oldest first.
+	OverdueMerges(ctx context.Context, teamName string, createdBefore time.Time) ([]models.PullRequest, error)
+	// RecordBreach stores b and reports false when it was already recorded.
+	RecordBreach(ctx context.Context, b *models.SLABreach) (bool, error)
+}
+
+type OverdueReview struct {
+	PullRequest models.PullRequest `gorm:"embedded"`
+	ReviewerID  string             `gorm:"column:reviewer_id"`
+	AssignedAt  time.Time          `gorm:"column:assigned_at"`
+}
+
+type slaRepo struct {
+	db *gorm.DB
+}
+
+func NewSLARepo(db *gorm.DB) SLARepo {
+	return &slaRepo{db: db}
+}
+
+func (r *slaRepo) Get(ctx context.Context, teamName string) (*models.TeamSLA, error) {
+	var s models.TeamSLA
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_slas")).Where("team_name = ?", teamName).First(&s).Error
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Upsert writes a map rather than the struct so that auto_reassign = false
+// is not replaced with the column default.
+func (r *slaRepo) Upsert(ctx context.Context, s *models.TeamSLA) error {
+	s.UpdatedAt = time.Now().UTC()
+	return r.db.WithContext(ctx).Model(&models.TeamSLA{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "team_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"review_sla_seconds", "merge_sla_seconds", "auto_reassign", "updated_at"}),
+	}).Create(map[string]any{
+		"tenant_id":          tenant.FromContext(ctx),
+		"team_name":          s.TeamName,
+		"review_sla_seconds": s.ReviewSeconds,
+		"merge_sla_seconds":  s.MergeSeconds,
+		"auto_reassign":      s.AutoReassign,
+		"updated_at":         s.UpdatedAt,
+	}).Error
+}
+
+func (r *slaRepo) Delete(ctx context.Context, teamName string) (bool, error) {
+	res := r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_slas")).Where("team_name = ?", teamName).Delete(&models.TeamSLA{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *slaRepo) List(ctx context.Context) ([]models.TeamSLA, error) {
+	var slas []models.TeamSLA
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "team_slas")).Order("team_name").Find(&slas).Error
+	if err != nil {
+		return nil, err
+	}
+	return slas, nil
+}
+
+func (r *slaRepo) OverdueReviews(ctx context.Context, teamName string, assignedBefore time.Time) ([]OverdueReview, error) {
+	var rows []OverdueReview
+	err := r.db.WithContext(ctx).Table("pull_requests").
+		Select("pull_requests.*, pr_reviewers.reviewer_id, pr_reviewers.assigned_at").
+		Joins("JOIN pr_reviewers ON pr_reviewers.tenant_id = pull_requests.tenant_id AND pr_reviewers.pull_request_id = pull_requests.pull_request_id").
+		Joins("JOIN users ON users.tenant_id = pull_requests.tenant_id AND users.user_id = pull_requests.author_id").
+		Scopes(inTenant(ctx, "pull_requests")).
+		Where("users.team_name = ? AND pull_requests.status = ? AND pr_reviewers.assigned_at <= ?",
+			teamName, models.PRStatusOpen, assignedBefore).
+		Order("pr_reviewers.assigned_at, pull_requests.pull_request_id, pr_reviewers.reviewer_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *slaRepo) OverdueMerges(ctx context.Context, teamName string, createdBefore time.Time) ([]models.PullRequest, error) {
+	var prs []models.PullRequest
+	err := r.db.WithContext(ctx).Model(&models.PullRequest{}).
+		Joins("JOIN users ON users.tenant_id = pull_requests.tenant_id AND users.user_id = pull_requests.author_id").
+		Scopes(inTenant(ctx, "pull_requests")).
+		Where("users.team_name = ? AND pull_requests.status = ? AND pull_requests.created_at <= ?",
+			teamName, models.PRStatusOpen, createdBefore).
+		Order("pull_requests.created_at, pull_requests.pull_request_id").
+		Find(&prs).Error
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+func (r *slaRepo) RecordBreach(ctx context.Context, b *models.SLABreach) (bool, error) {
+	b.TenantID = tenant.FromContext(ctx)
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(b)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
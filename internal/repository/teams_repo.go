@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -10,7 +12,24 @@ import (
 type TeamsRepo interface {
 	Create(ctx context.Context, team *models.Team) error
 	GetTeamByName(ctx context.Context, name string) (*models.Team, error)
+	// GetTeamMembers returns the users with a membership in the team, active
+	// or not.
 	GetTeamMembers(ctx context.Context, teamName string) ([]models.User, error)
+	// BumpVersion increments the team version. With expectedVersion > 0 it only
+	// succeeds while the stored version still matches.
+	BumpVersion(ctx context.Context, name string, expectedVersion int64) (bool, error)
+	Delete(ctx context.Context, name string, expectedVersion int64) (bool, error)
+	// List returns a page of teams ordered by name with their member and open
+	// pull request counts.
+	List(ctx context.Context, limit, offset int) ([]TeamSummary, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+type TeamSummary struct {
+	TeamName          string
+	MemberCount       int64
+	ActiveMemberCount int64
+	OpenPRCount       int64
 }
 
 type teamsRepo struct {
@@ -22,12 +41,13 @@ func NewTeamsRepo(db *gorm.DB) TeamsRepo {
 }
 
 func (r *teamsRepo) Create(ctx context.Context, team *models.Team) error {
+	team.TenantID = tenant.FromContext(ctx)
 	return r.db.WithContext(ctx).Create(team).Error
 }
 
 func (r *teamsRepo) GetTeamByName(ctx context.Context, name string) (*models.Team, error) {
 	var team models.Team
-	err := r.db.WithContext(ctx).Where("team_name = ?", name).First(&team).Error
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "teams")).Where("team_name = ?", name).First(&team).Error
 	if err != nil {
 		return nil, err
 	}
@@ -36,9 +56,76 @@ func (r *teamsRepo) GetTeamByName(ctx context.Context, name string) (*models.Tea
 
 func (r *teamsRepo) GetTeamMembers(ctx context.Context, teamName string) ([]models.User, error) {
 	var users []models.User
-	err := r.db.WithContext(ctx).Where("team_name = ?", teamName).Find(&users).Error
+	err := r.db.WithContext(ctx).
+		Joins("JOIN team_memberships ON team_memberships.tenant_id = users.tenant_id AND team_memberships.user_id = users.user_id").
+		Scopes(inTenant(ctx, "users")).
+		Where("team_memberships.team_name = ?", teamName).
+		Order("users.user_id").
+		Find(&users).Error
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
+
+func (r *teamsRepo) BumpVersion(ctx context.Context, name string, expectedVersion int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.Team{}).
+		Scopes(inTenant(ctx, "teams"), matchVersion(expectedVersion)).
+		Where("team_name = ?", name).
+		Updates(map[string]any{
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now().UTC(),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *teamsRepo) Delete(ctx context.Context, name string, expectedVersion int64) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Scopes(inTenant(ctx, "teams"), matchVersion(expectedVersion)).
+		Where("team_name = ?", name).
+		Delete(&models.Team{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *teamsRepo) List(ctx context.Context, limit, offset int) ([]TeamSummary, error) {
+	var rows []TeamSummary
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Team{}).
+		Select(`
+			teams.team_name AS team_name,
+			(SELECT COUNT(*) FROM team_memberships m
+				WHERE m.tenant_id = teams.tenant_id AND m.team_name = teams.team_name) AS member_count,
+			(SELECT COUNT(*) FROM team_memberships m
+				JOIN users ON users.tenant_id = m.tenant_id AND users.user_id = m.user_id
+				WHERE m.tenant_id = teams.tenant_id AND m.team_name = teams.team_name
+					AND m.is_active = TRUE AND users.is_active = TRUE) AS active_member_count,
+			(SELECT COUNT(*) FROM pull_requests
+				JOIN team_memberships m ON m.tenant_id = pull_requests.tenant_id AND m.user_id = pull_requests.author_id
+				WHERE m.tenant_id = teams.tenant_id AND m.team_name = teams.team_name AND pull_requests.status = ?) AS open_pr_count`,
+			models.PRStatusOpen,
+		).
+		Scopes(inTenant(ctx, "teams")).
+		Order("teams.team_name").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *teamsRepo) Count(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&models.Team{}).Scopes(inTenant(ctx, "teams")).Count(&n).Error
+	return n, err
+}
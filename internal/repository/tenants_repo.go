@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"reviewer_pr/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantsRepo lists the organizations. Unlike the repositories of tenant
+// data, it ignores the tenant in the context.
+type TenantsRepo interface {
+	// Ensure adds the tenants that do not exist yet.
+	Ensure(ctx context.Context, ids ...string) error
+	List(ctx context.Context) ([]models.Tenant, error)
+}
+
+type tenantsRepo struct {
+	db *gorm.DB
+}
+
+func NewTenantsRepo(db *gorm.DB) TenantsRepo {
+	return &tenantsRepo{db: db}
+}
+
+func (r *tenantsRepo) Ensure(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	rows := make([]models.Tenant, 0, len(ids))
+	for _, id := range ids {
+		rows = append(rows, models.Tenant{ID: id})
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *tenantsRepo) List(ctx context.Context) ([]models.Tenant, error) {
+	var out []models.Tenant
+	if err := r.db.WithContext(ctx).Order("tenant_id").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
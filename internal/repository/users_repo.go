@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/tenant"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -11,8 +14,30 @@ import (
 type UsersRepo interface {
 	UpsertUser(ctx context.Context, u *models.User) error
 	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	List(ctx context.Context) ([]models.User, error)
 	SetUserActive(ctx context.Context, id string, active bool) error
-	GetActiveTeamMembersExcept(ctx context.Context, teamName, exceptUserID string) ([]models.User, error)
+	// Update applies fields and increments the version. With expectedVersion > 0
+	// it only succeeds while the stored version still matches.
+	Update(ctx context.Context, id string, expectedVersion int64, fields map[string]any) (bool, error)
+	Delete(ctx context.Context, id string, expectedVersion int64) (bool, error)
+	// GetActiveTeamMembersExcept returns active users with an active
+	// membership in one of teamNames, except exceptUserID.
+	GetActiveTeamMembersExcept(ctx context.Context, teamNames []string, exceptUserID string) ([]models.User, error)
+	// GetActiveExcept returns active users listed in userIDs or actively
+	// belonging to one of teamNames, except exceptUserID.
+	GetActiveExcept(ctx context.Context, userIDs, teamNames []string, exceptUserID string) ([]models.User, error)
+	// Search returns a page of users matching f, ordered by username
+	// regardless of case.
+	Search(ctx context.Context, f UserFilter, limit, offset int) ([]models.User, error)
+	Count(ctx context.Context, f UserFilter) (int64, error)
+}
+
+// UserFilter narrows Search; empty fields match every user.
+type UserFilter struct {
+	// UsernamePrefix matches case-insensitively.
+	UsernamePrefix string
+	// TeamName matches members of the team, not only its primary members.
+	TeamName string
 }
 
 type usersRepo struct {
@@ -23,33 +48,169 @@ func NewUsersRepo(db *gorm.DB) UsersRepo {
 	return &usersRepo{db: db}
 }
 
+// UpsertUser inserts the user or overwrites username and activity. The
+// primary team of an existing user is kept; use Update to change it.
 func (r *usersRepo) UpsertUser(ctx context.Context, u *models.User) error {
-	return r.db.WithContext(ctx).Clauses(
+	now := time.Now().UTC()
+	if u.Version == 0 {
+		u.Version = 1
+	}
+	u.TenantID = tenant.FromContext(ctx)
+	u.CreatedAt, u.UpdatedAt = now, now
+
+	return r.db.WithContext(ctx).Model(&models.User{}).Clauses(
 		clause.OnConflict{
-			Columns:   []clause.Column{{Name: "user_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{"username", "team_name", "is_active", "updated_at"}),
+			Columns: []clause.Column{{Name: "tenant_id"}, {Name: "user_id"}},
+			DoUpdates: append(
+				clause.AssignmentColumns([]string{"username", "is_active", "updated_at"}),
+				clause.Assignment{Column: clause.Column{Name: "version"}, Value: gorm.Expr("users.version + 1")},
+			),
 		},
-	).Create(u).Error
+	).Create(userRow(u)).Error
+}
+
+// userRow maps a user to column values. Writing a map rather than the struct
+// keeps is_active = false, which GORM would replace with the column default.
+func userRow(u *models.User) map[string]any {
+	return map[string]any{
+		"tenant_id":  u.TenantID,
+		"user_id":    u.ID,
+		"username":   u.Username,
+		"team_name":  u.TeamName,
+		"is_active":  u.IsActive,
+		"version":    u.Version,
+		"created_at": u.CreatedAt,
+		"updated_at": u.UpdatedAt,
+
+		"notifications_muted": u.NotificationsMuted,
+		"auto_assign_opt_out": u.AutoAssignOptOut,
+	}
 }
 
 func (r *usersRepo) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	var u models.User
-	err := r.db.WithContext(ctx).Where("user_id = ?", id).First(&u).Error
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "users")).Where("user_id = ?", id).First(&u).Error
 	if err != nil {
 		return nil, err
 	}
 	return &u, nil
 }
 
+func (r *usersRepo) List(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "users")).Order("user_id").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *usersRepo) SetUserActive(ctx context.Context, id string, active bool) error {
-	return r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", id).Update("is_active", active).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Scopes(inTenant(ctx, "users")).Where("user_id = ?", id).Updates(map[string]any{
+		"is_active": active,
+		"version":   gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (r *usersRepo) Update(ctx context.Context, id string, expectedVersion int64, fields map[string]any) (bool, error) {
+	updates := make(map[string]any, len(fields)+1)
+	for k, v := range fields {
+		updates[k] = v
+	}
+	updates["version"] = gorm.Expr("version + 1")
+
+	res := r.db.WithContext(ctx).Model(&models.User{}).
+		Scopes(inTenant(ctx, "users"), matchVersion(expectedVersion)).
+		Where("user_id = ?", id).
+		Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *usersRepo) Delete(ctx context.Context, id string, expectedVersion int64) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Scopes(inTenant(ctx, "users"), matchVersion(expectedVersion)).
+		Where("user_id = ?", id).
+		Delete(&models.User{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
 }
 
-func (r *usersRepo) GetActiveTeamMembersExcept(ctx context.Context, teamName, exceptUserID string) ([]models.User, error) {
+// activeMemberOf matches users with an active membership in one of teamNames.
+const activeMemberOf = `EXISTS (SELECT 1 FROM team_memberships
+	WHERE team_memberships.tenant_id = users.tenant_id AND team_memberships.user_id = users.user_id
+		AND team_memberships.team_name IN ? AND team_memberships.is_active = TRUE)`
+
+func (r *usersRepo) GetActiveTeamMembersExcept(ctx context.Context, teamNames []string, exceptUserID string) ([]models.User, error) {
+	if len(teamNames) == 0 {
+		return nil, nil
+	}
 	var users []models.User
-	err := r.db.WithContext(ctx).Where("team_name = ? AND is_active = TRUE AND user_id <> ?", teamName, exceptUserID).Find(&users).Error
+	err := r.db.WithContext(ctx).Scopes(inTenant(ctx, "users")).
+		Where("is_active = TRUE AND user_id <> ?", exceptUserID).
+		Where(activeMemberOf, teamNames).
+		Order("user_id").
+		Find(&users).Error
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
+
+func (r *usersRepo) GetActiveExcept(ctx context.Context, userIDs, teamNames []string, exceptUserID string) ([]models.User, error) {
+	q := r.db.WithContext(ctx).Scopes(inTenant(ctx, "users")).Where("is_active = TRUE AND user_id <> ?", exceptUserID)
+	switch {
+	case len(userIDs) > 0 && len(teamNames) > 0:
+		q = q.Where("user_id IN ? OR "+activeMemberOf, userIDs, teamNames)
+	case len(userIDs) > 0:
+		q = q.Where("user_id IN ?", userIDs)
+	case len(teamNames) > 0:
+		q = q.Where(activeMemberOf, teamNames)
+	default:
+		return nil, nil
+	}
+
+	var users []models.User
+	if err := q.Order("user_id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (f UserFilter) scope(db *gorm.DB) *gorm.DB {
+	if f.UsernamePrefix != "" {
+		db = db.Where(`LOWER(username) LIKE ? ESCAPE '\'`, likeEscaper.Replace(strings.ToLower(f.UsernamePrefix))+"%")
+	}
+	if f.TeamName != "" {
+		db = db.Where(`EXISTS (SELECT 1 FROM team_memberships
+			WHERE team_memberships.tenant_id = users.tenant_id AND team_memberships.user_id = users.user_id
+				AND team_memberships.team_name = ?)`, f.TeamName)
+	}
+	return db
+}
+
+func (r *usersRepo) Search(ctx context.Context, f UserFilter, limit, offset int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).
+		Scopes(inTenant(ctx, "users"), f.scope).
+		Order("LOWER(username)").Order("user_id").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *usersRepo) Count(ctx context.Context, f UserFilter) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Scopes(inTenant(ctx, "users"), f.scope).Count(&n).Error
+	return n, err
+}
@@ -0,0 +1,38 @@
+// Package requestid generates and validates the request identifiers shared by
+// the HTTP (X-Request-ID) and gRPC (x-request-id) APIs.
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const MaxLength = 128
+
+// Valid reports whether a client-supplied id may be propagated as is.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// New returns a random 32-character hex id.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// OrNew returns id when it is valid and a freshly generated one otherwise.
+func OrNew(id string) string {
+	if Valid(id) {
+		return id
+	}
+	return New()
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"net/http"
 	"reviewer_pr/api"
+	"reviewer_pr/internal/auth"
 	httpapi "reviewer_pr/internal/http"
 
 	"github.com/gin-contrib/cors"
@@ -11,41 +12,155 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func Router(h *httpapi.Handler) *gin.Engine {
-	r := gin.Default()
+type options struct {
+	auth    *auth.Authenticator
+	swagger bool
+	stats   bool
+}
+
+type Option func(*options)
+
+// WithAuth requires a bearer token on every API route.
+func WithAuth(a *auth.Authenticator) Option {
+	return func(o *options) { o.auth = a }
+}
+
+func WithSwagger(enabled bool) Option {
+	return func(o *options) { o.swagger = enabled }
+}
+
+func WithStats(enabled bool) Option {
+	return func(o *options) { o.stats = enabled }
+}
+
+func Router(h *httpapi.Handler, opts ...Option) *gin.Engine {
+	o := options{swagger: true, stats: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := gin.New()
+	r.Use(h.RequestContext(), h.AccessLog(), gin.Recovery())
 
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Authorization", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowOrigins: []string{"*"},
+		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{
+			"Authorization", "Content-Type", httpapi.HeaderRequestID, httpapi.HeaderIdempotencyKey,
+			httpapi.HeaderIfMatch, httpapi.HeaderIfNoneMatch,
+		},
+		ExposeHeaders: []string{
+			"Content-Length", "Location", httpapi.HeaderRequestID, httpapi.HeaderIdempotentReplayed, httpapi.HeaderETag,
+		},
 		AllowCredentials: true,
 	}))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.String(200, "ok")
-	})
+	r.GET("/health", h.Health)
+	r.GET("/livez", h.Livez)
+	r.GET("/readyz", h.Readyz)
+
+	if o.swagger {
+		r.GET("/openapi.yml", func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/x-yaml", api.OpenAPISpec)
+		})
+
+		r.GET("/swagger/*any", ginSwagger.WrapHandler(
+			swaggerFiles.Handler,
+			ginSwagger.URL("/openapi.yml"),
+		))
+
+		r.GET("/openapi-v2.yml", func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/x-yaml", api.OpenAPIV2Spec)
+		})
+	}
+
+	v1 := r.Group("/")
+	if o.auth != nil {
+		v1.Use(h.Auth(o.auth))
+	}
+	v1.Use(h.Idempotency())
+	admin := h.RequireRole(auth.RoleAdmin)
+
+	v1.POST("/team/add", admin, h.TeamAdd)
+	v1.GET("/team/get", h.TeamGet)
+	v1.GET("/team/list", h.TeamList)
+	v1.POST("/team/import", admin, h.TeamImport)
+	v1.POST("/team/setMember", admin, h.TeamSetMember)
+	v1.POST("/team/removeMember", admin, h.TeamRemoveMember)
+	v1.GET("/team/sla", h.TeamGetSLA)
+	v1.POST("/team/setSla", admin, h.TeamSetSLA)
+	v1.GET("/team/codeOwners", h.TeamGetCodeOwners)
+	v1.POST("/team/setCodeOwners", admin, h.TeamSetCodeOwners)
+	v1.POST("/team/importCodeOwners", admin, h.TeamImportCodeOwners)
+
+	v1.POST("/users/setIsActive", admin, h.UserSetIsActive)
+	v1.POST("/users/setNotifications", h.UserSetNotifications)
+	v1.GET("/users/digest", h.UserGetDigest)
+	v1.POST("/users/setDigest", h.UserSetDigest)
+	v1.GET("/users/digestPreview", h.UserDigestPreview)
+	v1.GET("/users/get", h.UserGet)
+	v1.GET("/users/search", h.UserSearch)
+	v1.GET("/users/getReview", h.UserGetReview)
+	v1.GET("/users/skills", h.UserGetSkills)
+	v1.POST("/users/setSkill", admin, h.UserSetSkill)
+	v1.POST("/users/removeSkill", admin, h.UserRemoveSkill)
+	v1.POST("/users/setAutoAssign", admin, h.UserSetAutoAssign)
+	v1.GET("/users/exclusions", h.UserGetExclusions)
+	v1.POST("/users/addExclusion", admin, h.UserAddExclusion)
+	v1.POST("/users/removeExclusion", admin, h.UserRemoveExclusion)
+
+	v1.POST("/pullRequest/create", h.PRCreate)
+	v1.POST("/pullRequest/merge", h.PRMerge)
+	v1.POST("/pullRequest/reassign", h.PRReassign)
+	v1.POST("/pullRequest/addReviewer", h.PRAddReviewer)
+	v1.POST("/pullRequest/removeReviewer", h.PRRemoveReviewer)
+	v1.GET("/pullRequest/history", h.PRHistory)
+	v1.POST("/pullRequest/simulate", h.PRSimulate)
+	v1.GET("/pullRequest/overdue", h.PROverdue)
+
+	if o.stats {
+		v1.GET("/stats", h.GetStats)
+	}
 
-	r.GET("/openapi.yml", func(c *gin.Context) {
-		c.Data(http.StatusOK, "application/x-yaml", api.OpenAPISpec)
-	})
+	// Operational endpoints are admin-only and skip the idempotency middleware:
+	// their bodies are streamed, not buffered.
+	ops := r.Group("/admin")
+	if o.auth != nil {
+		ops.Use(h.Auth(o.auth))
+	}
+	ops.Use(admin)
 
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(
-		swaggerFiles.Handler,
-		ginSwagger.URL("/openapi.yml"),
-	))
+	ops.GET("/export", h.AdminExport)
+	ops.POST("/restore", h.AdminRestore)
+	ops.GET("/audit", h.AdminAudit)
+	ops.GET("/jobs", h.AdminJobs)
+	ops.GET("/jobs/runs", h.AdminJobRuns)
+	ops.POST("/jobs/trigger", h.AdminTriggerJob)
 
-	r.POST("/team/add", h.TeamAdd)
-	r.GET("/team/get", h.TeamGet)
+	v2 := r.Group("/api/v2")
+	if o.auth != nil {
+		v2.Use(h.Auth(o.auth))
+	}
+	v2.Use(h.Idempotency())
 
-	r.POST("/users/setIsActive", h.UserSetIsActive)
-	r.GET("/users/getReview", h.UserGetReview)
+	v2.POST("/teams", admin, h.V2TeamCreate)
+	v2.GET("/teams/:name", h.V2TeamGet)
+	v2.PATCH("/teams/:name", admin, h.V2TeamUpdate)
+	v2.DELETE("/teams/:name", admin, h.V2TeamDelete)
 
-	r.POST("/pullRequest/create", h.PRCreate)
-	r.POST("/pullRequest/merge", h.PRMerge)
-	r.POST("/pullRequest/reassign", h.PRReassign)
+	v2.GET("/users/:id", h.V2UserGet)
+	v2.PATCH("/users/:id", admin, h.V2UserUpdate)
+	v2.DELETE("/users/:id", admin, h.V2UserDelete)
 
-	r.GET("/stats", h.GetStats)
+	v2.POST("/pull-requests", h.V2PRCreate)
+	v2.GET("/pull-requests/:id", h.V2PRGet)
+	v2.PATCH("/pull-requests/:id", h.V2PRUpdate)
+	v2.DELETE("/pull-requests/:id", admin, h.V2PRDelete)
+	v2.GET("/pull-requests/:id/reviewers", h.V2PRReviewersGet)
+	v2.POST("/pull-requests/:id/reviewers", h.V2PRReviewersReassign)
+	v2.PUT("/pull-requests/:id/reviewers/:user_id", h.V2PRReviewerAdd)
+	v2.DELETE("/pull-requests/:id/reviewers/:user_id", h.V2PRReviewerRemove)
+	v2.GET("/pull-requests/:id/events", h.V2PREvents)
 
 	return r
 }
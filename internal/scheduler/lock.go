@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// Locker elects the replica that runs a job. TryLock does not wait: ok is
+// false while someone else holds key. When ok is true, unlock must be called.
+type Locker interface {
+	TryLock(ctx context.Context, key string) (unlock func(), ok bool, err error)
+}
+
+// LocalLocker only excludes holders within this process. It is enough for a
+// single replica and for tests.
+type LocalLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{held: make(map[string]bool)}
+}
+
+func (l *LocalLocker) TryLock(_ context.Context, key string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, key)
+	}, true, nil
+}
+
+// AdvisoryLocker uses Postgres session advisory locks, so a job runs on one
+// replica at a time. Each held lock pins a pooled connection.
+type AdvisoryLocker struct {
+	db *sql.DB
+}
+
+func NewAdvisoryLocker(db *sql.DB) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db}
+}
+
+func (l *AdvisoryLocker) TryLock(ctx context.Context, key string) (func(), bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	id := lockID(key)
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&ok); err != nil {
+		_ = conn.Close()
+		return nil, false, err
+	}
+	if !ok {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	return func() {
+		// The lock belongs to the session: if it cannot be released, the
+		// connection is discarded instead of going back to the pool.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", id); err != nil {
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}, true, nil
+}
+
+// lockID maps a key to the 64-bit advisory lock namespace.
+func lockID(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("reviewer_pr/" + key))
+	return int64(h.Sum64()) //nolint:gosec // wrapping is fine for a lock ID
+}
@@ -0,0 +1,306 @@
+// Package scheduler runs periodic background jobs. Every replica runs the
+// scheduler; a Locker makes sure each run happens on one of them, and every
+// run is recorded in a Store.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reviewer_pr/internal/models"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownJob = errors.New("unknown job")
+	// ErrJobRunning is returned by Trigger while the job runs here or on
+	// another replica.
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// Job is a task run on Schedule. Run gets the time the run was due.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      func(ctx context.Context, now time.Time) error
+}
+
+// Store records job runs; repository.JobRunsRepo implements it.
+type Store interface {
+	Create(ctx context.Context, run *models.JobRun) error
+	Finish(ctx context.Context, run *models.JobRun) error
+	LastScheduledAt(ctx context.Context, jobName string) (time.Time, error)
+}
+
+// JobInfo describes a registered job.
+type JobInfo struct {
+	Name     string
+	Schedule string
+	NextRun  time.Time
+	Running  bool
+}
+
+// finishRetryDelay is the pause between attempts to record a run outcome.
+const finishRetryDelay = 50 * time.Millisecond
+
+type Scheduler struct {
+	store  Store
+	locker Locker
+	log    *zap.Logger
+
+	mu      sync.Mutex
+	jobs    map[string]*entry
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	wg      sync.WaitGroup
+}
+
+type entry struct {
+	Job
+	running atomic.Bool
+	// next is guarded by Scheduler.mu.
+	next time.Time
+}
+
+func New(store Store, locker Locker, log *zap.Logger) *Scheduler {
+	return &Scheduler{store: store, locker: locker, log: log, jobs: make(map[string]*entry)}
+}
+
+// Add registers a job; it must be called before Start.
+func (s *Scheduler) Add(j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return errors.New("scheduler already started")
+	}
+	if _, ok := s.jobs[j.Name]; ok {
+		return fmt.Errorf("job %q registered twice", j.Name)
+	}
+	s.jobs[j.Name] = &entry{Job: j}
+	return nil
+}
+
+// Start runs every job on its schedule until Stop is called or ctx is
+// cancelled. A failed run is logged and the job runs again at its next slot.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.started = true
+	for _, e := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(e)
+	}
+}
+
+// Stop cancels running jobs and waits for them to record their outcome, at
+// most until ctx is done.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Jobs lists the registered jobs by name.
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]JobInfo, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		out = append(out, JobInfo{
+			Name:     e.Name,
+			Schedule: e.Schedule.String(),
+			NextRun:  e.next,
+			Running:  e.running.Load(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Trigger starts a manual run of the job in the background and returns its
+// record.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*models.JobRun, error) {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	jobCtx := s.ctx
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	if jobCtx == nil {
+		return nil, errors.New("scheduler is not started")
+	}
+
+	run, finish, err := s.begin(ctx, e, nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *run
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		finish(jobCtx)
+	}()
+	return &snapshot, nil
+}
+
+func (s *Scheduler) loop(e *entry) {
+	defer s.wg.Done()
+	log := s.log.With(zap.String("job", e.Name))
+
+	for {
+		next := e.Schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Error("job has no next run")
+			return
+		}
+		s.mu.Lock()
+		e.next = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		_, finish, err := s.begin(s.ctx, e, &next)
+		switch {
+		case errors.Is(err, ErrJobRunning):
+			log.Debug("job skipped, already running")
+		case err != nil:
+			log.Error("job not started", zap.Error(err))
+		case finish != nil:
+			finish(s.ctx)
+		}
+	}
+}
+
+// begin takes the job lock and records the run. For a scheduled run (slot
+// set) it returns a nil finish when another replica already ran that slot.
+// finish executes the job, records the outcome and releases the lock.
+func (s *Scheduler) begin(ctx context.Context, e *entry, slot *time.Time) (*models.JobRun, func(context.Context), error) {
+	if !e.running.CompareAndSwap(false, true) {
+		return nil, nil, ErrJobRunning
+	}
+	release := func() { e.running.Store(false) }
+
+	unlock, ok, err := s.locker.TryLock(ctx, "job:"+e.Name)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	if !ok {
+		release()
+		return nil, nil, ErrJobRunning
+	}
+	release = func() {
+		unlock()
+		e.running.Store(false)
+	}
+
+	run := &models.JobRun{
+		JobName:   e.Name,
+		Trigger:   models.JobTriggerManual,
+		Status:    models.JobRunRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	if slot != nil {
+		last, err := s.store.LastScheduledAt(ctx, e.Name)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		if !last.Before(*slot) {
+			release()
+			return nil, nil, nil
+		}
+		at := slot.UTC()
+		run.Trigger, run.ScheduledAt = models.JobTriggerSchedule, &at
+	}
+	if err := s.store.Create(ctx, run); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	finish := func(ctx context.Context) {
+		defer release()
+		s.execute(ctx, e, run)
+	}
+	return run, finish, nil
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry, run *models.JobRun) {
+	log := s.log.With(zap.String("job", e.Name), zap.Uint64("run_id", run.ID))
+
+	now := run.StartedAt
+	if run.ScheduledAt != nil {
+		now = *run.ScheduledAt
+	}
+	err := safeRun(ctx, e.Job, now)
+
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Status = models.JobRunSucceeded
+	if err != nil {
+		run.Status, run.Error = models.JobRunFailed, err.Error()
+		log.Error("job failed", zap.Error(err), zap.Int64("duration_ms", run.DurationMs))
+	} else {
+		log.Info("job finished", zap.Int64("duration_ms", run.DurationMs))
+	}
+
+	// The outcome is recorded even when the run was cut short by Stop. A
+	// failed write is retried: otherwise the run would stay "running" and
+	// look like it still holds the job.
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	for {
+		err := s.store.Finish(storeCtx, run)
+		if err == nil {
+			return
+		}
+		log.Warn("job run not recorded, retrying", zap.Error(err))
+		select {
+		case <-storeCtx.Done():
+			log.Error("job run not recorded", zap.Error(err))
+			return
+		case <-time.After(finishRetryDelay):
+		}
+	}
+}
+
+// safeRun turns a panicking job into a failed run.
+func safeRun(ctx context.Context, j Job, now time.Time) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.Run(ctx, now)
+}
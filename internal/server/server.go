@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reviewer_pr/internal/config"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func New(addr string, cfg config.Server, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// Run serves HTTP until ctx is cancelled and then drains in-flight requests
+// for at most shutdownTimeout. onShutdown is called before draining starts;
+// new requests are still served for drainDelay after it, while load balancers
+// notice that /readyz fails.
+func Run(ctx context.Context, srv *http.Server, drainDelay, shutdownTimeout time.Duration, onShutdown func(), log *zap.Logger) error {
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("http server started", zap.String("addr", srv.Addr))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info("shutdown signal received, draining http server", zap.Duration("timeout", shutdownTimeout))
+	if onShutdown != nil {
+		onShutdown()
+	}
+	waitDrain(drainDelay, log)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	log.Info("http server stopped")
+	return <-errCh
+}
+
+// RunGRPC serves gRPC on addr until ctx is cancelled, keeps serving for
+// drainDelay and then stops gracefully, forcing the stop once shutdownTimeout
+// elapses.
+func RunGRPC(ctx context.Context, srv *grpc.Server, addr string, drainDelay, shutdownTimeout time.Duration, log *zap.Logger) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("grpc server started", zap.String("addr", addr))
+		errCh <- srv.Serve(lis)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info("shutdown signal received, draining grpc server", zap.Duration("timeout", shutdownTimeout))
+	waitDrain(drainDelay, log)
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		log.Warn("grpc graceful stop timed out, closing connections")
+		srv.Stop()
+	}
+
+	log.Info("grpc server stopped")
+	return nil
+}
+
+// waitDrain keeps the server running for delay after it was marked not ready.
+func waitDrain(delay time.Duration, log *zap.Logger) {
+	if delay <= 0 {
+		return
+	}
+	log.Info("waiting for load balancers to drain", zap.Duration("delay", delay))
+	time.Sleep(delay)
+}
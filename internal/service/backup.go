@@ -0,0 +1,1000 @@
+package service
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Export format: NDJSON, one record per line with a "type" field. The header
+// comes first, then teams, users, team memberships, digest subscriptions, team
+// SLAs, code owner rules, user skills, reviewer exclusions, pull requests,
+// reviewer assignments and the assignment history in this order (restore checks references
+// against the records read so far), and an "end" record with the number of
+// records of each kind. A stream without the end record is truncated. Exports
+// without memberships restore every user as a member of their primary team;
+// kinds added later are simply absent from older exports.
+const (
+	BackupFormat  = "reviewer_pr.backup"
+	BackupVersion = 1
+
+	maxBackupLineSize = 1 << 20
+	maxBackupErrors   = 100
+	backupBatchSize   = 500
+)
+
+const (
+	recordHeader      = "header"
+	recordTeam        = "team"
+	recordUser        = "user"
+	recordMembership  = "membership"
+	recordDigest      = "digest_subscription"
+	recordTeamSLA     = "team_sla"
+	recordCodeOwner   = "code_owner_rule"
+	recordSkill       = "user_skill"
+	recordExclusion   = "reviewer_exclusion"
+	recordPullRequest = "pull_request"
+	recordReviewer    = "reviewer"
+	recordEvent       = "assignment_event"
+	recordEnd         = "end"
+)
+
+// recordOrder is the position of each record kind in the stream.
+var recordOrder = map[string]int{
+	recordHeader:      0,
+	recordTeam:        1,
+	recordUser:        2,
+	recordMembership:  3,
+	recordDigest:      4,
+	recordTeamSLA:     5,
+	recordCodeOwner:   6,
+	recordSkill:       7,
+	recordExclusion:   8,
+	recordPullRequest: 9,
+	recordReviewer:    10,
+	recordEvent:       11,
+	recordEnd:         12,
+}
+
+type BackupService interface {
+	// Export writes all teams, users, memberships, digest subscriptions, team SLAs, code
+	// owner rules, user skills, reviewer exclusions, pull requests, reviewer assignments
+	// and assignment events to w from a consistent snapshot, one record at a time.
+	Export(ctx context.Context, w io.Writer) (*repository.BackupCounts, error)
+	// Restore loads an export into an empty database in one transaction.
+	Restore(ctx context.Context, r io.Reader) (*repository.BackupCounts, error)
+}
+
+type backupService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+}
+
+func NewBackupService(repo *repository.Repository, log *zap.Logger) BackupService {
+	return &backupService{repo: repo, log: log}
+}
+
+type backupHeader struct {
+	Type       string    `json:"type"`
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+type backupTeam struct {
+	Type      string    `json:"type"`
+	TeamName  string    `json:"team_name"`
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type backupUser struct {
+	Type      string    `json:"type"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	TeamName  string    `json:"team_name"`
+	IsActive  bool      `json:"is_active"`
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	NotificationsMuted bool `json:"notifications_muted,omitempty"`
+	AutoAssignOptOut   bool `json:"auto_assign_opt_out,omitempty"`
+}
+
+type backupMembership struct {
+	Type      string    `json:"type"`
+	TeamName  string    `json:"team_name"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type backupDigest struct {
+	Type       string     `json:"type"`
+	UserID     string     `json:"user_id"`
+	Email      string     `json:"email"`
+	SendAt     string     `json:"send_at"`
+	Timezone   string     `json:"timezone"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type backupTeamSLA struct {
+	Type             string    `json:"type"`
+	TeamName         string    `json:"team_name"`
+	ReviewSLASeconds int64     `json:"review_sla_seconds"`
+	MergeSLASeconds  int64     `json:"merge_sla_seconds"`
+	AutoReassign     bool      `json:"auto_reassign"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// backupCodeOwnerRule is one rule of a team; owners may refer to users and
+// teams deleted since, as they can in the database.
+type backupCodeOwnerRule struct {
+	Type     string   `json:"type"`
+	TeamName string   `json:"team_name"`
+	Position int      `json:"position"`
+	Pattern  string   `json:"pattern"`
+	Users    []string `json:"users,omitempty"`
+	Teams    []string `json:"teams,omitempty"`
+}
+
+type backupSkill struct {
+	Type        string `json:"type"`
+	UserID      string `json:"user_id"`
+	Skill       string `json:"skill"`
+	Proficiency int    `json:"proficiency,omitempty"`
+}
+
+type backupExclusion struct {
+	Type       string    `json:"type"`
+	AuthorID   string    `json:"author_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type backupPullRequest struct {
+	Type            string                   `json:"type"`
+	PullRequestID   string                   `json:"pull_request_id"`
+	PullRequestName string                   `json:"pull_request_name"`
+	AuthorID        string                   `json:"author_id"`
+	Status          models.PullRequestStatus `json:"status"`
+	Version         int64                    `json:"version"`
+	CreatedAt       time.Time                `json:"created_at"`
+	MergedAt        *time.Time               `json:"merged_at,omitempty"`
+	Labels          []string                 `json:"labels,omitempty"`
+}
+
+type backupReviewer struct {
+	Type          string    `json:"type"`
+	PullRequestID string    `json:"pull_request_id"`
+	ReviewerID    string    `json:"reviewer_id"`
+	AssignedAt    time.Time `json:"assigned_at"`
+}
+
+// backupEvent is one entry of a pull request's assignment history. Events are
+// written in the order they happened and get new IDs on restore; reviewers
+// may have been deleted since.
+type backupEvent struct {
+	Type               string                     `json:"type"`
+	PullRequestID      string                     `json:"pull_request_id"`
+	Kind               models.AssignmentEventKind `json:"kind"`
+	ReviewerID         string                     `json:"reviewer_id"`
+	PreviousReviewerID string                     `json:"previous_reviewer_id,omitempty"`
+	Reason             string                     `json:"reason,omitempty"`
+	RequestedBy        string                     `json:"requested_by,omitempty"`
+	Strategy           string                     `json:"strategy,omitempty"`
+	Seed               int64                      `json:"seed,omitempty"`
+	CreatedAt          time.Time                  `json:"created_at"`
+}
+
+type backupEnd struct {
+	Type   string       `json:"type"`
+	Counts backupCounts `json:"counts"`
+}
+
+type backupCounts struct {
+	Teams        int64 `json:"teams"`
+	Users        int64 `json:"users"`
+	Memberships  int64 `json:"memberships"`
+	Digests      int64 `json:"digest_subscriptions"`
+	TeamSLAs     int64 `json:"team_slas"`
+	CodeOwners   int64 `json:"code_owner_rules"`
+	Skills       int64 `json:"user_skills"`
+	Exclusions   int64 `json:"reviewer_exclusions"`
+	PullRequests int64 `json:"pull_requests"`
+	Reviewers    int64 `json:"reviewers"`
+	Events       int64 `json:"assignment_events"`
+}
+
+func (s *backupService) Export(ctx context.Context, w io.Writer) (*repository.BackupCounts, error) {
+	enc := json.NewEncoder(w)
+	var counts repository.BackupCounts
+
+	err := s.repo.Snapshot(ctx, func(tx *repository.Repository) error {
+		if err := enc.Encode(backupHeader{
+			Type:       recordHeader,
+			Format:     BackupFormat,
+			Version:    BackupVersion,
+			ExportedAt: time.Now().UTC(),
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachTeam(ctx, func(t *models.Team) error {
+			counts.Teams++
+			return enc.Encode(backupTeam{
+				Type: recordTeam, TeamName: t.Name, Version: t.Version,
+				CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachUser(ctx, func(u *models.User) error {
+			counts.Users++
+			return enc.Encode(backupUser{
+				Type: recordUser, UserID: u.ID, Username: u.Username, TeamName: u.TeamName,
+				IsActive: u.IsActive, Version: u.Version, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt,
+				NotificationsMuted: u.NotificationsMuted, AutoAssignOptOut: u.AutoAssignOptOut,
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachMembership(ctx, func(m *models.TeamMembership) error {
+			counts.Memberships++
+			return enc.Encode(backupMembership{
+				Type: recordMembership, TeamName: m.TeamName, UserID: m.UserID, Role: m.Role,
+				IsActive: m.IsActive, CreatedAt: m.CreatedAt,
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachDigest(ctx, func(d *models.DigestSubscription) error {
+			counts.Digests++
+			return enc.Encode(backupDigest{
+				Type: recordDigest, UserID: d.UserID, Email: d.Email, SendAt: d.SendAt, Timezone: d.Timezone,
+				LastSentAt: d.LastSentAt, CreatedAt: d.CreatedAt, UpdatedAt: d.UpdatedAt,
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachTeamSLA(ctx, func(sla *models.TeamSLA) error {
+			counts.TeamSLAs++
+			return enc.Encode(backupTeamSLA{
+				Type: recordTeamSLA, TeamName: sla.TeamName, ReviewSLASeconds: sla.ReviewSeconds,
+				MergeSLASeconds: sla.MergeSeconds, AutoReassign: sla.AutoReassign, UpdatedAt: sla.UpdatedAt,
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachCodeOwnerRule(ctx, func(r *models.CodeOwnerRule) error {
+			counts.CodeOwners++
+			return enc.Encode(backupCodeOwnerRule{
+				Type: recordCodeOwner, TeamName: r.TeamName, Position: r.Position, Pattern: r.Pattern,
+				Users: strings.Fields(r.Users), Teams: strings.Fields(r.Teams),
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachSkill(ctx, func(sk *models.UserSkill) error {
+			counts.Skills++
+			return enc.Encode(backupSkill{Type: recordSkill, UserID: sk.UserID, Skill: sk.Skill, Proficiency: sk.Proficiency})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachExclusion(ctx, func(e *models.ReviewerExclusion) error {
+			counts.Exclusions++
+			return enc.Encode(backupExclusion{
+				Type: recordExclusion, AuthorID: e.AuthorID, ReviewerID: e.ReviewerID, Reason: e.Reason, CreatedAt: e.CreatedAt,
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachPullRequest(ctx, func(pr *models.PullRequest) error {
+			counts.PullRequests++
+			return enc.Encode(backupPullRequest{
+				Type: recordPullRequest, PullRequestID: pr.ID, PullRequestName: pr.Name, AuthorID: pr.AuthorID,
+				Status: pr.Status, Version: pr.Version, CreatedAt: pr.CreatedAt, MergedAt: pr.MergedAt,
+				Labels: strings.Fields(pr.Labels),
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachReviewer(ctx, func(r *models.PRReviewer) error {
+			counts.Reviewers++
+			return enc.Encode(backupReviewer{
+				Type: recordReviewer, PullRequestID: r.PullRequestID, ReviewerID: r.ReviewerID, AssignedAt: r.AssignedAt,
+			})
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Backup.EachEvent(ctx, func(e *models.AssignmentEvent) error {
+			counts.Events++
+			return enc.Encode(backupEvent{
+				Type: recordEvent, PullRequestID: e.PullRequestID, Kind: e.Kind, ReviewerID: e.ReviewerID,
+				PreviousReviewerID: e.PreviousReviewerID, Reason: e.Reason, RequestedBy: e.RequestedBy,
+				Strategy: e.Strategy, Seed: e.Seed, CreatedAt: e.CreatedAt,
+			})
+		}); err != nil {
+			return err
+		}
+
+		return enc.Encode(backupEnd{Type: recordEnd, Counts: backupCounts(counts)})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("data exported", countsFields(counts)...)
+	return &counts, nil
+}
+
+func (s *backupService) Restore(ctx context.Context, r io.Reader) (*repository.BackupCounts, error) {
+	var counts repository.BackupCounts
+
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		existing, err := tx.Backup.Counts(ctx)
+		if err != nil {
+			return err
+		}
+		if !existing.Empty() {
+			return NewErr(ErrorCodeResourceInUse, "restore requires an empty database")
+		}
+
+		rs := newRestorer(ctx, tx)
+		if err := rs.run(r); err != nil {
+			return err
+		}
+		counts = rs.counts
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("data restored", countsFields(counts)...)
+	return &counts, nil
+}
+
+func countsFields(c repository.BackupCounts) []zap.Field {
+	return []zap.Field{
+		zap.Int64("teams", c.Teams),
+		zap.Int64("users", c.Users),
+		zap.Int64("memberships", c.Memberships),
+		zap.Int64("digest_subscriptions", c.Digests),
+		zap.Int64("team_slas", c.TeamSLAs),
+		zap.Int64("code_owner_rules", c.CodeOwners),
+		zap.Int64("user_skills", c.Skills),
+		zap.Int64("reviewer_exclusions", c.Exclusions),
+		zap.Int64("pull_requests", c.PullRequests),
+		zap.Int64("reviewers", c.Reviewers),
+		zap.Int64("assignment_events", c.Events),
+	}
+}
+
+// restorer validates records as they are read and inserts them in batches.
+// After the first validation error it only keeps validating so that the
+// report lists every problem; the transaction is rolled back anyway.
+type restorer struct {
+	ctx context.Context
+	tx  *repository.Repository
+	v   validator
+
+	counts repository.BackupCounts
+	stage  int
+	ended  bool
+
+	teams       map[string]struct{}
+	users       map[string]struct{}
+	memberships map[[2]string]struct{}
+	digests     map[string]struct{}
+	slas        map[string]struct{}
+	codeOwners  map[codeOwnerKey]struct{}
+	skills      map[[2]string]struct{}
+	exclusions  map[[2]string]struct{}
+	prs         map[string]struct{}
+	reviewers   map[[2]string]struct{}
+
+	pendingTeams       []models.Team
+	pendingUsers       []models.User
+	pendingMemberships []models.TeamMembership
+	pendingDigests     []models.DigestSubscription
+	pendingSLAs        []models.TeamSLA
+	pendingCodeOwners  []models.CodeOwnerRule
+	pendingSkills      []models.UserSkill
+	pendingExclusions  []models.ReviewerExclusion
+	pendingPRs         []models.PullRequest
+	pendingReviewers   []models.PRReviewer
+	pendingEvents      []models.AssignmentEvent
+}
+
+func newRestorer(ctx context.Context, tx *repository.Repository) *restorer {
+	return &restorer{
+		ctx:         ctx,
+		tx:          tx,
+		stage:       -1,
+		teams:       make(map[string]struct{}),
+		users:       make(map[string]struct{}),
+		memberships: make(map[[2]string]struct{}),
+		digests:     make(map[string]struct{}),
+		slas:        make(map[string]struct{}),
+		codeOwners:  make(map[codeOwnerKey]struct{}),
+		skills:      make(map[[2]string]struct{}),
+		exclusions:  make(map[[2]string]struct{}),
+		prs:         make(map[string]struct{}),
+		reviewers:   make(map[[2]string]struct{}),
+	}
+}
+
+func (rs *restorer) run(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxBackupLineSize)
+
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		if rs.ended {
+			rs.v.add(rowField(line, "type"), "no records may follow %q", recordEnd)
+			break
+		}
+		if err := rs.record(line, sc.Bytes()); err != nil {
+			return err
+		}
+		if len(rs.v.errs) >= maxBackupErrors {
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("line %d is longer than %d bytes", line+1, maxBackupLineSize))
+		}
+		return err
+	}
+
+	if err := rs.v.err(); err != nil {
+		return err
+	}
+	if rs.stage < 0 {
+		return NewErr(ErrorCodeInvalidRequest, "export is empty")
+	}
+	if !rs.ended {
+		return NewErr(ErrorCodeInvalidRequest, "export is truncated: missing end record")
+	}
+	if err := rs.flush(); err != nil {
+		return err
+	}
+	return rs.tx.Backup.EnsurePrimaryMemberships(rs.ctx)
+}
+
+func (rs *restorer) record(line int, data []byte) error {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("line %d is not valid JSON", line))
+	}
+
+	stage, ok := recordOrder[head.Type]
+	if !ok {
+		rs.v.add(rowField(line, "type"), "unknown record type %q", head.Type)
+		return nil
+	}
+	if rs.stage < 0 && head.Type != recordHeader {
+		return NewErr(ErrorCodeInvalidRequest, "export must start with a header record")
+	}
+	if stage < rs.stage || (stage == rs.stage && head.Type == recordHeader) {
+		rs.v.add(rowField(line, "type"), "records must be ordered: header, teams, users, memberships, digest subscriptions, team SLAs, code owner rules, user skills, reviewer exclusions, pull requests, reviewers, assignment events, end")
+		return nil
+	}
+	if stage > rs.stage {
+		// Everything referenced by the next kind has been read: write it out.
+		if err := rs.flush(); err != nil {
+			return err
+		}
+		rs.stage = stage
+	}
+
+	switch head.Type {
+	case recordHeader:
+		var h backupHeader
+		if err := json.Unmarshal(data, &h); err != nil {
+			return NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("line %d: %v", line, err))
+		}
+		if h.Format != BackupFormat || h.Version != BackupVersion {
+			return NewErr(ErrorCodeInvalidRequest,
+				fmt.Sprintf("unsupported export %s v%d, expected %s v%d", h.Format, h.Version, BackupFormat, BackupVersion))
+		}
+	case recordTeam:
+		return rs.team(line, data)
+	case recordUser:
+		return rs.user(line, data)
+	case recordMembership:
+		return rs.membership(line, data)
+	case recordDigest:
+		return rs.digest(line, data)
+	case recordTeamSLA:
+		return rs.teamSLA(line, data)
+	case recordCodeOwner:
+		return rs.codeOwnerRule(line, data)
+	case recordSkill:
+		return rs.skill(line, data)
+	case recordExclusion:
+		return rs.exclusion(line, data)
+	case recordPullRequest:
+		return rs.pullRequest(line, data)
+	case recordReviewer:
+		return rs.reviewer(line, data)
+	case recordEvent:
+		return rs.event(line, data)
+	case recordEnd:
+		var e backupEnd
+		if err := json.Unmarshal(data, &e); err != nil {
+			return NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("line %d: %v", line, err))
+		}
+		if repository.BackupCounts(e.Counts) != rs.counts {
+			rs.v.add(rowField(line, "counts"), "do not match the records in the export")
+		}
+		rs.ended = true
+	}
+	return nil
+}
+
+func (rs *restorer) decode(line int, data []byte, dst any) bool {
+	if err := json.Unmarshal(data, dst); err != nil {
+		rs.v.add(rowField(line, "type"), "invalid record: %v", err)
+		return false
+	}
+	return true
+}
+
+func (rs *restorer) team(line int, data []byte) error {
+	var t backupTeam
+	if !rs.decode(line, data, &t) {
+		return nil
+	}
+	rs.counts.Teams++
+
+	n := len(rs.v.errs)
+	rs.v.id(rowField(line, "team_name"), t.TeamName)
+	if _, dup := rs.teams[t.TeamName]; dup {
+		rs.v.add(rowField(line, "team_name"), "duplicate team %q", t.TeamName)
+	}
+	rs.teams[t.TeamName] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingTeams = append(rs.pendingTeams, models.Team{
+		Name: t.TeamName, Version: max(t.Version, 1), CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+	})
+	return rs.flushIfFull(len(rs.pendingTeams))
+}
+
+func (rs *restorer) user(line int, data []byte) error {
+	var u backupUser
+	if !rs.decode(line, data, &u) {
+		return nil
+	}
+	rs.counts.Users++
+
+	n := len(rs.v.errs)
+	rs.v.id(rowField(line, "user_id"), u.UserID)
+	rs.v.text(rowField(line, "username"), u.Username, MaxUsernameLength)
+	if _, dup := rs.users[u.UserID]; dup {
+		rs.v.add(rowField(line, "user_id"), "duplicate user %q", u.UserID)
+	}
+	if _, ok := rs.teams[u.TeamName]; !ok {
+		rs.v.add(rowField(line, "team_name"), "team %q is not in the export", u.TeamName)
+	}
+	rs.users[u.UserID] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingUsers = append(rs.pendingUsers, models.User{
+		ID: u.UserID, Username: u.Username, TeamName: u.TeamName, IsActive: u.IsActive,
+		Version: max(u.Version, 1), CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt,
+		NotificationsMuted: u.NotificationsMuted, AutoAssignOptOut: u.AutoAssignOptOut,
+	})
+	return rs.flushIfFull(len(rs.pendingUsers))
+}
+
+func (rs *restorer) membership(line int, data []byte) error {
+	var m backupMembership
+	if !rs.decode(line, data, &m) {
+		return nil
+	}
+	rs.counts.Memberships++
+
+	n := len(rs.v.errs)
+	key := [2]string{m.TeamName, m.UserID}
+	if _, ok := rs.teams[m.TeamName]; !ok {
+		rs.v.add(rowField(line, "team_name"), "team %q is not in the export", m.TeamName)
+	}
+	if _, ok := rs.users[m.UserID]; !ok {
+		rs.v.add(rowField(line, "user_id"), "user %q is not in the export", m.UserID)
+	}
+	rs.v.membershipRole(rowField(line, "role"), m.Role)
+	if _, dup := rs.memberships[key]; dup {
+		rs.v.add(rowField(line, "user_id"), "duplicate membership of %q in %q", m.UserID, m.TeamName)
+	}
+	rs.memberships[key] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingMemberships = append(rs.pendingMemberships, models.TeamMembership{
+		TeamName: m.TeamName, UserID: m.UserID, Role: cmp.Or(m.Role, models.MembershipMember),
+		IsActive: m.IsActive, CreatedAt: m.CreatedAt,
+	})
+	return rs.flushIfFull(len(rs.pendingMemberships))
+}
+
+func (rs *restorer) digest(line int, data []byte) error {
+	var d backupDigest
+	if !rs.decode(line, data, &d) {
+		return nil
+	}
+	rs.counts.Digests++
+
+	n := len(rs.v.errs)
+	rs.check(line, SubscribeDigestInput{UserID: d.UserID, Email: d.Email, SendAt: d.SendAt, Timezone: d.Timezone}.Validate())
+	if _, ok := rs.users[d.UserID]; !ok {
+		rs.v.add(rowField(line, "user_id"), "user %q is not in the export", d.UserID)
+	}
+	if _, dup := rs.digests[d.UserID]; dup {
+		rs.v.add(rowField(line, "user_id"), "duplicate digest subscription of %q", d.UserID)
+	}
+	rs.digests[d.UserID] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingDigests = append(rs.pendingDigests, models.DigestSubscription{
+		UserID: d.UserID, Email: d.Email, SendAt: d.SendAt, Timezone: d.Timezone,
+		LastSentAt: d.LastSentAt, CreatedAt: d.CreatedAt, UpdatedAt: d.UpdatedAt,
+	})
+	return rs.flushIfFull(len(rs.pendingDigests))
+}
+
+func (rs *restorer) teamSLA(line int, data []byte) error {
+	var sla backupTeamSLA
+	if !rs.decode(line, data, &sla) {
+		return nil
+	}
+	rs.counts.TeamSLAs++
+
+	n := len(rs.v.errs)
+	rs.check(line, TeamSLA{
+		TeamName:     sla.TeamName,
+		ReviewWithin: slaSeconds(sla.ReviewSLASeconds),
+		MergeWithin:  slaSeconds(sla.MergeSLASeconds),
+		AutoReassign: sla.AutoReassign,
+	}.Validate())
+	if _, ok := rs.teams[sla.TeamName]; !ok {
+		rs.v.add(rowField(line, "team_name"), "team %q is not in the export", sla.TeamName)
+	}
+	if _, dup := rs.slas[sla.TeamName]; dup {
+		rs.v.add(rowField(line, "team_name"), "duplicate SLA of %q", sla.TeamName)
+	}
+	rs.slas[sla.TeamName] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingSLAs = append(rs.pendingSLAs, models.TeamSLA{
+		TeamName: sla.TeamName, ReviewSeconds: sla.ReviewSLASeconds, MergeSeconds: sla.MergeSLASeconds,
+		AutoReassign: sla.AutoReassign, UpdatedAt: sla.UpdatedAt,
+	})
+	return rs.flushIfFull(len(rs.pendingSLAs))
+}
+
+// slaSeconds converts stored seconds for validation without overflowing.
+func slaSeconds(n int64) time.Duration {
+	if n < 0 || n > int64(MaxSLA/time.Second) {
+		return -1
+	}
+	return time.Duration(n) * time.Second
+}
+
+type codeOwnerKey struct {
+	team     string
+	position int
+}
+
+func (rs *restorer) codeOwnerRule(line int, data []byte) error {
+	var r backupCodeOwnerRule
+	if !rs.decode(line, data, &r) {
+		return nil
+	}
+	rs.counts.CodeOwners++
+
+	n := len(rs.v.errs)
+	key := codeOwnerKey{r.TeamName, r.Position}
+	if _, ok := rs.teams[r.TeamName]; !ok {
+		rs.v.add(rowField(line, "team_name"), "team %q is not in the export", r.TeamName)
+	}
+	if r.Position < 1 {
+		rs.v.add(rowField(line, "position"), "must be positive")
+	}
+	rs.v.codeOwnerRule(0, CodeOwnerRule{Line: line, Pattern: r.Pattern, Users: r.Users, Teams: r.Teams})
+	if _, dup := rs.codeOwners[key]; dup {
+		rs.v.add(rowField(line, "position"), "duplicate rule %d of %q", r.Position, r.TeamName)
+	}
+	rs.codeOwners[key] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingCodeOwners = append(rs.pendingCodeOwners, models.CodeOwnerRule{
+		TeamName: r.TeamName, Position: r.Position, Pattern: r.Pattern,
+		Users: strings.Join(r.Users, " "), Teams: strings.Join(r.Teams, " "),
+	})
+	return rs.flushIfFull(len(rs.pendingCodeOwners))
+}
+
+func (rs *restorer) skill(line int, data []byte) error {
+	var sk backupSkill
+	if !rs.decode(line, data, &sk) {
+		return nil
+	}
+	rs.counts.Skills++
+
+	n := len(rs.v.errs)
+	key := [2]string{sk.UserID, sk.Skill}
+	rs.check(line, SetSkillInput{UserID: sk.UserID, Skill: sk.Skill, Proficiency: sk.Proficiency}.Validate())
+	if _, ok := rs.users[sk.UserID]; !ok {
+		rs.v.add(rowField(line, "user_id"), "user %q is not in the export", sk.UserID)
+	}
+	if _, dup := rs.skills[key]; dup {
+		rs.v.add(rowField(line, "skill"), "duplicate skill %q of %q", sk.Skill, sk.UserID)
+	}
+	rs.skills[key] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingSkills = append(rs.pendingSkills, models.UserSkill{UserID: sk.UserID, Skill: sk.Skill, Proficiency: sk.Proficiency})
+	return rs.flushIfFull(len(rs.pendingSkills))
+}
+
+func (rs *restorer) exclusion(line int, data []byte) error {
+	var e backupExclusion
+	if !rs.decode(line, data, &e) {
+		return nil
+	}
+	rs.counts.Exclusions++
+
+	n := len(rs.v.errs)
+	key := [2]string{e.AuthorID, e.ReviewerID}
+	rs.check(line, ExclusionInput{AuthorID: e.AuthorID, ReviewerID: e.ReviewerID, Reason: e.Reason}.Validate())
+	if _, ok := rs.users[e.AuthorID]; !ok {
+		rs.v.add(rowField(line, "author_id"), "user %q is not in the export", e.AuthorID)
+	}
+	if _, ok := rs.users[e.ReviewerID]; !ok {
+		rs.v.add(rowField(line, "reviewer_id"), "user %q is not in the export", e.ReviewerID)
+	}
+	if _, dup := rs.exclusions[key]; dup {
+		rs.v.add(rowField(line, "reviewer_id"), "duplicate exclusion of %q for %q", e.ReviewerID, e.AuthorID)
+	}
+	rs.exclusions[key] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingExclusions = append(rs.pendingExclusions, models.ReviewerExclusion{
+		AuthorID: e.AuthorID, ReviewerID: e.ReviewerID, Reason: e.Reason, CreatedAt: e.CreatedAt,
+	})
+	return rs.flushIfFull(len(rs.pendingExclusions))
+}
+
+func (rs *restorer) pullRequest(line int, data []byte) error {
+	var pr backupPullRequest
+	if !rs.decode(line, data, &pr) {
+		return nil
+	}
+	rs.counts.PullRequests++
+
+	n := len(rs.v.errs)
+	rs.v.id(rowField(line, "pull_request_id"), pr.PullRequestID)
+	rs.v.text(rowField(line, "pull_request_name"), pr.PullRequestName, MaxPRNameLength)
+	for i, l := range pr.Labels {
+		rs.v.tag(rowField(line, fmt.Sprintf("labels[%d]", i)), l)
+	}
+	if _, dup := rs.prs[pr.PullRequestID]; dup {
+		rs.v.add(rowField(line, "pull_request_id"), "duplicate pull request %q", pr.PullRequestID)
+	}
+	if _, ok := rs.users[pr.AuthorID]; !ok {
+		rs.v.add(rowField(line, "author_id"), "user %q is not in the export", pr.AuthorID)
+	}
+	switch pr.Status {
+	case models.PRStatusOpen:
+	case models.PRStatusMerged:
+		if pr.MergedAt == nil {
+			rs.v.add(rowField(line, "merged_at"), "is required for merged pull requests")
+		}
+	default:
+		rs.v.add(rowField(line, "status"), "must be %s or %s", models.PRStatusOpen, models.PRStatusMerged)
+	}
+	rs.prs[pr.PullRequestID] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingPRs = append(rs.pendingPRs, models.PullRequest{
+		ID: pr.PullRequestID, Name: pr.PullRequestName, AuthorID: pr.AuthorID, Status: pr.Status,
+		Version: max(pr.Version, 1), CreatedAt: pr.CreatedAt, MergedAt: pr.MergedAt,
+		Labels: strings.Join(pr.Labels, " "),
+	})
+	return rs.flushIfFull(len(rs.pendingPRs))
+}
+
+func (rs *restorer) reviewer(line int, data []byte) error {
+	var r backupReviewer
+	if !rs.decode(line, data, &r) {
+		return nil
+	}
+	rs.counts.Reviewers++
+
+	n := len(rs.v.errs)
+	key := [2]string{r.PullRequestID, r.ReviewerID}
+	if _, ok := rs.prs[r.PullRequestID]; !ok {
+		rs.v.add(rowField(line, "pull_request_id"), "pull request %q is not in the export", r.PullRequestID)
+	}
+	if _, ok := rs.users[r.ReviewerID]; !ok {
+		rs.v.add(rowField(line, "reviewer_id"), "user %q is not in the export", r.ReviewerID)
+	}
+	if _, dup := rs.reviewers[key]; dup {
+		rs.v.add(rowField(line, "reviewer_id"), "duplicate assignment of %q to %q", r.ReviewerID, r.PullRequestID)
+	}
+	rs.reviewers[key] = struct{}{}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingReviewers = append(rs.pendingReviewers, models.PRReviewer{
+		PullRequestID: r.PullRequestID, ReviewerID: r.ReviewerID, AssignedAt: r.AssignedAt,
+	})
+	return rs.flushIfFull(len(rs.pendingReviewers))
+}
+
+// check reports the errors of an input validation under the record's line.
+func (rs *restorer) check(line int, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return
+	}
+	for _, fe := range e.Details {
+		rs.v.add(rowField(line, fe.Field), "%s", fe.Message)
+	}
+}
+
+func (rs *restorer) event(line int, data []byte) error {
+	var e backupEvent
+	if !rs.decode(line, data, &e) {
+		return nil
+	}
+	rs.counts.Events++
+
+	n := len(rs.v.errs)
+	if _, ok := rs.prs[e.PullRequestID]; !ok {
+		rs.v.add(rowField(line, "pull_request_id"), "pull request %q is not in the export", e.PullRequestID)
+	}
+	switch e.Kind {
+	case models.AssignmentAssigned, models.AssignmentAdded, models.AssignmentRemoved, models.AssignmentReassigned:
+	default:
+		rs.v.add(rowField(line, "kind"), "must be one of assigned, added, removed, reassigned")
+	}
+	rs.v.id(rowField(line, "reviewer_id"), e.ReviewerID)
+	if e.PreviousReviewerID != "" {
+		rs.v.id(rowField(line, "previous_reviewer_id"), e.PreviousReviewerID)
+	}
+	if e.RequestedBy != "" {
+		rs.v.id(rowField(line, "requested_by"), e.RequestedBy)
+	}
+	switch AssignmentStrategy(e.Strategy) {
+	case "", StrategyRandom, StrategyDeterministic:
+	default:
+		rs.v.add(rowField(line, "strategy"), "must be one of %s, %s", StrategyRandom, StrategyDeterministic)
+	}
+	if len(rs.v.errs) > n {
+		return nil
+	}
+
+	rs.pendingEvents = append(rs.pendingEvents, models.AssignmentEvent{
+		PullRequestID: e.PullRequestID, Kind: e.Kind, ReviewerID: e.ReviewerID, PreviousReviewerID: e.PreviousReviewerID,
+		Reason: e.Reason, RequestedBy: e.RequestedBy, Strategy: e.Strategy, Seed: e.Seed, CreatedAt: e.CreatedAt,
+	})
+	return rs.flushIfFull(len(rs.pendingEvents))
+}
+
+func (rs *restorer) flushIfFull(pending int) error {
+	if pending < backupBatchSize {
+		return nil
+	}
+	return rs.flush()
+}
+
+// flush writes buffered records unless validation already failed.
+func (rs *restorer) flush() error {
+	if len(rs.v.errs) > 0 {
+		return nil
+	}
+
+	ctx, b := rs.ctx, rs.tx.Backup
+	if err := b.InsertTeams(ctx, rs.pendingTeams); err != nil {
+		return err
+	}
+	if err := b.InsertUsers(ctx, rs.pendingUsers); err != nil {
+		return err
+	}
+	if err := b.InsertMemberships(ctx, rs.pendingMemberships); err != nil {
+		return err
+	}
+	if err := b.InsertDigests(ctx, rs.pendingDigests); err != nil {
+		return err
+	}
+	if err := b.InsertTeamSLAs(ctx, rs.pendingSLAs); err != nil {
+		return err
+	}
+	if err := b.InsertCodeOwnerRules(ctx, rs.pendingCodeOwners); err != nil {
+		return err
+	}
+	if err := b.InsertSkills(ctx, rs.pendingSkills); err != nil {
+		return err
+	}
+	if err := b.InsertExclusions(ctx, rs.pendingExclusions); err != nil {
+		return err
+	}
+	if err := b.InsertPullRequests(ctx, rs.pendingPRs); err != nil {
+		return err
+	}
+	if err := b.InsertReviewers(ctx, rs.pendingReviewers); err != nil {
+		return err
+	}
+	if err := b.InsertEvents(ctx, rs.pendingEvents); err != nil {
+		return err
+	}
+
+	rs.pendingTeams = rs.pendingTeams[:0]
+	rs.pendingUsers = rs.pendingUsers[:0]
+	rs.pendingMemberships = rs.pendingMemberships[:0]
+	rs.pendingDigests = rs.pendingDigests[:0]
+	rs.pendingSLAs = rs.pendingSLAs[:0]
+	rs.pendingCodeOwners = rs.pendingCodeOwners[:0]
+	rs.pendingSkills = rs.pendingSkills[:0]
+	rs.pendingExclusions = rs.pendingExclusions[:0]
+	rs.pendingPRs = rs.pendingPRs[:0]
+	rs.pendingReviewers = rs.pendingReviewers[:0]
+	rs.pendingEvents = rs.pendingEvents[:0]
+	return nil
+}
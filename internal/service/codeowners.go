@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reviewer_pr/internal/codeowners"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type CodeOwnersService interface {
+	// GetCodeOwners returns the team's rules in order; the last rule matching
+	// a file decides its owners.
+	GetCodeOwners(ctx context.Context, teamName string) (*TeamCodeOwners, error)
+	// SetCodeOwners replaces the team's rules; no rules removes them.
+	SetCodeOwners(ctx context.Context, in TeamCodeOwners) (*TeamCodeOwners, error)
+}
+
+// TeamCodeOwners are the ownership rules applied to pull requests authored
+// by the team.
+type TeamCodeOwners struct {
+	TeamName string
+	Rules    []CodeOwnerRule
+}
+
+// CodeOwnerRule gives the files matching Pattern to Users and to the members
+// of Teams. A rule without owners leaves the files unowned. Line is the line
+// in a CODEOWNERS file and is used to report errors.
+type CodeOwnerRule struct {
+	Line    int
+	Pattern string
+	Users   []string
+	Teams   []string
+}
+
+type codeOwnersService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+}
+
+func NewCodeOwnersService(repo *repository.Repository, log *zap.Logger) CodeOwnersService {
+	return &codeOwnersService{repo: repo, log: log}
+}
+
+func (s *codeOwnersService) GetCodeOwners(ctx context.Context, teamName string) (*TeamCodeOwners, error) {
+	if err := checkTeam(ctx, s.repo, teamName); err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.CodeOwners.List(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TeamCodeOwners{TeamName: teamName, Rules: make([]CodeOwnerRule, 0, len(rules))}
+	for _, r := range rules {
+		out.Rules = append(out.Rules, CodeOwnerRule{
+			Pattern: r.Pattern,
+			Users:   strings.Fields(r.Users),
+			Teams:   strings.Fields(r.Teams),
+		})
+	}
+	return out, nil
+}
+
+func (s *codeOwnersService) SetCodeOwners(ctx context.Context, in TeamCodeOwners) (*TeamCodeOwners, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkTeam(ctx, s.repo, in.TeamName); err != nil {
+		return nil, err
+	}
+	if err := s.checkOwners(ctx, in.Rules); err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.CodeOwnerRule, 0, len(in.Rules))
+	for _, r := range in.Rules {
+		rows = append(rows, models.CodeOwnerRule{
+			Pattern: r.Pattern,
+			Users:   strings.Join(r.Users, " "),
+			Teams:   strings.Join(r.Teams, " "),
+		})
+	}
+	if err := s.repo.CodeOwners.Replace(ctx, in.TeamName, rows); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("code owners set",
+		zap.String("team_name", in.TeamName),
+		zap.Int("rules", len(in.Rules)),
+	)
+	return s.GetCodeOwners(ctx, in.TeamName)
+}
+
+// checkOwners reports owners that do not exist. Owners deleted later are
+// skipped during assignment.
+func (s *codeOwnersService) checkOwners(ctx context.Context, rules []CodeOwnerRule) error {
+	users := make(map[string]bool)
+	teams := make(map[string]bool)
+	var v validator
+	for i, r := range rules {
+		for j, id := range r.Users {
+			found, ok := users[id]
+			if !ok {
+				_, err := s.repo.Users.GetUserByID(ctx, id)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				found = err == nil
+				users[id] = found
+			}
+			if !found {
+				v.add(r.field(i, fmt.Sprintf("users[%d]", j)), "user not found")
+			}
+		}
+		for j, name := range r.Teams {
+			found, ok := teams[name]
+			if !ok {
+				_, err := s.repo.Teams.GetTeamByName(ctx, name)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				found = err == nil
+				teams[name] = found
+			}
+			if !found {
+				v.add(r.field(i, fmt.Sprintf("teams[%d]", j)), "team not found")
+			}
+		}
+	}
+	return v.err()
+}
+
+// field names a rule field by its CODEOWNERS line when there is one.
+func (r CodeOwnerRule) field(i int, name string) string {
+	if r.Line > 0 {
+		return rowField(r.Line, name)
+	}
+	return fmt.Sprintf("rules[%d].%s", i, name)
+}
+
+// ParseCodeOwners reads a CODEOWNERS file. Owners are written as "@user_id"
+// or "@org/team_name"; the organization is ignored.
+func ParseCodeOwners(r io.Reader) ([]CodeOwnerRule, error) {
+	entries, err := codeowners.Parse(r)
+	if err != nil {
+		return nil, NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("invalid CODEOWNERS file: %v", err))
+	}
+
+	var v validator
+	rules := make([]CodeOwnerRule, 0, len(entries))
+	for _, e := range entries {
+		rule := CodeOwnerRule{Line: e.Line, Pattern: e.Pattern}
+		for _, owner := range e.Owners {
+			if !strings.HasPrefix(owner, "@") {
+				v.add(rowField(e.Line, "owners"), "%q: only @user and @org/team owners are supported", owner)
+				continue
+			}
+			if name, team := codeowners.OwnerTeam(owner); team {
+				rule.Teams = append(rule.Teams, name)
+			} else {
+				rule.Users = append(rule.Users, name)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
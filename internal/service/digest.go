@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reviewer_pr/internal/digest"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultDigestSendAt   = "09:00"
+	DefaultDigestTimezone = "UTC"
+)
+
+type DigestService interface {
+	// GetSubscription returns nil when the user has no digest.
+	GetSubscription(ctx context.Context, userID string) (*models.DigestSubscription, error)
+	// Subscribe creates or reschedules the user's daily digest.
+	Subscribe(ctx context.Context, in SubscribeDigestInput) (*models.DigestSubscription, error)
+	Unsubscribe(ctx context.Context, userID string) error
+	// Preview renders the digest the user would get now, without sending it.
+	Preview(ctx context.Context, userID string) (*DigestPreview, error)
+	// SendDue emails every digest due at now and returns how many were sent.
+	// Digests without open pull requests are skipped but count as delivered
+	// for the day.
+	SendDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// SubscribeDigestInput schedules the digest at SendAt ("15:04") in Timezone;
+// empty values mean DefaultDigestSendAt and DefaultDigestTimezone.
+type SubscribeDigestInput struct {
+	UserID   string
+	Email    string
+	SendAt   string
+	Timezone string
+}
+
+type DigestPreview struct {
+	Digest   digest.Digest
+	Rendered *digest.Rendered
+	// Subscription is nil when the user is not subscribed; the preview then
+	// uses DefaultDigestTimezone.
+	Subscription *models.DigestSubscription
+}
+
+type digestService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+	opts options
+}
+
+func NewDigestService(repo *repository.Repository, log *zap.Logger, opts ...Option) DigestService {
+	return &digestService{repo: repo, log: log, opts: buildOptions(opts)}
+}
+
+func (s *digestService) GetSubscription(ctx context.Context, userID string) (*models.DigestSubscription, error) {
+	if _, err := s.user(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.subscription(ctx, userID)
+}
+
+func (s *digestService) Subscribe(ctx context.Context, in SubscribeDigestInput) (*models.DigestSubscription, error) {
+	if in.SendAt == "" {
+		in.SendAt = DefaultDigestSendAt
+	}
+	if in.Timezone == "" {
+		in.Timezone = DefaultDigestTimezone
+	}
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.user(ctx, in.UserID); err != nil {
+		return nil, err
+	}
+
+	sub := &models.DigestSubscription{
+		UserID:   in.UserID,
+		Email:    in.Email,
+		SendAt:   in.SendAt,
+		Timezone: in.Timezone,
+	}
+	if err := s.repo.Digests.Upsert(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("digest subscription saved",
+		zap.String("user_id", in.UserID),
+		zap.String("send_at", in.SendAt),
+		zap.String("timezone", in.Timezone),
+	)
+	return s.repo.Digests.Get(ctx, in.UserID)
+}
+
+func (s *digestService) Unsubscribe(ctx context.Context, userID string) error {
+	if _, err := s.user(ctx, userID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.Digests.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		logger.FromContext(ctx, s.log).Info("digest subscription removed", zap.String("user_id", userID))
+	}
+	return nil
+}
+
+func (s *digestService) Preview(ctx context.Context, userID string) (*DigestPreview, error) {
+	u, err := s.user(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.subscription(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tz := DefaultDigestTimezone
+	if sub != nil {
+		tz = sub.Timezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.build(ctx, s.repo.Reader(), u, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := s.opts.digestRenderer.Render(d)
+	if err != nil {
+		return nil, err
+	}
+	return &DigestPreview{Digest: d, Rendered: rendered, Subscription: sub}, nil
+}
+
+func (s *digestService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	if s.opts.mailer == nil {
+		return 0, errors.New("digest mailer is not configured")
+	}
+	subs, err := s.repo.Digests.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		sent int
+		errs []error
+	)
+	for _, sub := range subs {
+		due, err := digest.Due(sub, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", sub.UserID, err))
+			continue
+		}
+		if !due {
+			continue
+		}
+		ok, err := s.send(ctx, sub, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", sub.UserID, err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// send emails one digest and records it as delivered. It reports false when
+// there was nothing to send.
+func (s *digestService) send(ctx context.Context, sub models.DigestSubscription, now time.Time) (bool, error) {
+	u, err := s.repo.Users.GetUserByID(ctx, sub.UserID)
+	if err != nil {
+		return false, err
+	}
+	loc, err := digest.Location(sub)
+	if err != nil {
+		return false, err
+	}
+
+	d, err := s.build(ctx, s.repo, u, now.In(loc))
+	if err != nil {
+		return false, err
+	}
+	if len(d.Items) > 0 {
+		rendered, err := s.opts.digestRenderer.Render(d)
+		if err != nil {
+			return false, err
+		}
+		if err := s.opts.mailer.Send(ctx, digest.Mail{
+			To:      sub.Email,
+			Subject: rendered.Subject,
+			Text:    rendered.Text,
+			HTML:    rendered.HTML,
+		}); err != nil {
+			return false, err
+		}
+	}
+	if err := s.repo.Digests.MarkSent(ctx, sub.UserID, now.UTC()); err != nil {
+		return false, err
+	}
+
+	if len(d.Items) > 0 {
+		s.log.Info("digest sent", zap.String("user_id", sub.UserID), zap.Int("pull_requests", len(d.Items)))
+	}
+	return len(d.Items) > 0, nil
+}
+
+// build collects the open pull requests u reviews, oldest first.
+func (s *digestService) build(ctx context.Context, repo *repository.Repository, u *models.User, now time.Time) (digest.Digest, error) {
+	d := digest.Digest{User: *u, GeneratedAt: now}
+
+	prs, err := repo.PRs.GetPullRequestsByReviewer(ctx, u.ID)
+	if err != nil {
+		return d, err
+	}
+
+	authors := make(map[string]models.User)
+	for _, pr := range prs {
+		if pr.Status != models.PRStatusOpen {
+			continue
+		}
+		author, ok := authors[pr.AuthorID]
+		if !ok {
+			a, err := repo.Users.GetUserByID(ctx, pr.AuthorID)
+			switch {
+			case err == nil:
+				author = *a
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				author = models.User{ID: pr.AuthorID, Username: pr.AuthorID}
+			default:
+				return d, err
+			}
+			authors[pr.AuthorID] = author
+		}
+		d.Items = append(d.Items, digest.Item{PR: pr, Author: author, Age: now.Sub(pr.CreatedAt)})
+	}
+
+	sort.Slice(d.Items, func(i, j int) bool {
+		a, b := d.Items[i].PR, d.Items[j].PR
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return d, nil
+}
+
+func (s *digestService) user(ctx context.Context, userID string) (*models.User, error) {
+	if err := validateID("user_id", userID); err != nil {
+		return nil, err
+	}
+	u, err := s.repo.Users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErr(ErrorCodeNotFound, "user not found")
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *digestService) subscription(ctx context.Context, userID string) (*models.DigestSubscription, error) {
+	sub, err := s.repo.Digests.Get(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return sub, err
+}
@@ -11,11 +11,39 @@ const (
 	ErrorCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	ErrorCodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	ErrorCodeNotFound    ErrorCode = "NOT_FOUND"
+
+	// ErrorCodeAlreadyAssigned: the named user already reviews the pull request.
+	ErrorCodeAlreadyAssigned ErrorCode = "ALREADY_ASSIGNED"
+	// ErrorCodeReviewerNotAllowed: the named user may not review the pull
+	// request (author, inactive or outside the allowed teams).
+	ErrorCodeReviewerNotAllowed ErrorCode = "REVIEWER_NOT_ALLOWED"
+	// ErrorCodeReviewerLimit: the pull request has no free reviewer place.
+	ErrorCodeReviewerLimit ErrorCode = "REVIEWER_LIMIT"
+
+	ErrorCodePreconditionFailed ErrorCode = "PRECONDITION_FAILED"
+	ErrorCodeResourceInUse      ErrorCode = "RESOURCE_IN_USE"
+
+	ErrorCodeIdempotencyKeyReused  ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrorCodeIdempotencyInProgress ErrorCode = "IDEMPOTENCY_IN_PROGRESS"
+
+	ErrorCodeValidation     ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeInvalidRequest ErrorCode = "INVALID_REQUEST"
+	ErrorCodeInternal       ErrorCode = "INTERNAL"
+
+	ErrorCodeUnauthorized ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden    ErrorCode = "FORBIDDEN"
 )
 
+// FieldError describes why a single input field was rejected.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
 type Error struct {
-	Code ErrorCode
-	Msg  string
+	Code    ErrorCode
+	Msg     string
+	Details []FieldError
 }
 
 func (e *Error) Error() string {
@@ -31,3 +59,11 @@ func NewErr(code ErrorCode, msg string) *Error {
 		Msg:  msg,
 	}
 }
+
+func NewValidationErr(details ...FieldError) *Error {
+	return &Error{
+		Code:    ErrorCodeValidation,
+		Msg:     "request validation failed",
+		Details: details,
+	}
+}
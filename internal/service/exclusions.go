@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ExclusionService manages the author-reviewer pairs that automatic
+// assignment never makes.
+type ExclusionService interface {
+	// ListExclusions returns the exclusions involving userID, as author or
+	// reviewer, or every exclusion when userID is empty.
+	ListExclusions(ctx context.Context, userID string) ([]models.ReviewerExclusion, error)
+	// AddExclusion adds the pair or updates its reason.
+	AddExclusion(ctx context.Context, in ExclusionInput) (*models.ReviewerExclusion, error)
+	RemoveExclusion(ctx context.Context, authorID, reviewerID string) error
+}
+
+type ExclusionInput struct {
+	AuthorID   string
+	ReviewerID string
+	Reason     string
+}
+
+type exclusionService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+}
+
+func NewExclusionService(repo *repository.Repository, log *zap.Logger) ExclusionService {
+	return &exclusionService{repo: repo, log: log}
+}
+
+func (s *exclusionService) ListExclusions(ctx context.Context, userID string) ([]models.ReviewerExclusion, error) {
+	if userID != "" {
+		if err := validateID("user_id", userID); err != nil {
+			return nil, err
+		}
+		if err := s.checkUser(ctx, "user", userID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Reader().Exclusions.List(ctx, userID)
+}
+
+func (s *exclusionService) AddExclusion(ctx context.Context, in ExclusionInput) (*models.ReviewerExclusion, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, "author", in.AuthorID); err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, "reviewer", in.ReviewerID); err != nil {
+		return nil, err
+	}
+
+	e := &models.ReviewerExclusion{AuthorID: in.AuthorID, ReviewerID: in.ReviewerID, Reason: in.Reason}
+	if err := s.repo.Exclusions.Upsert(ctx, e); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("reviewer exclusion added",
+		zap.String("author_id", in.AuthorID),
+		zap.String("reviewer_id", in.ReviewerID),
+	)
+	return e, nil
+}
+
+func (s *exclusionService) RemoveExclusion(ctx context.Context, authorID, reviewerID string) error {
+	var v validator
+	v.id("author_id", authorID)
+	v.id("reviewer_id", reviewerID)
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	ok, err := s.repo.Exclusions.Delete(ctx, authorID, reviewerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return NewErr(ErrorCodeNotFound, "exclusion not found")
+	}
+
+	logger.FromContext(ctx, s.log).Info("reviewer exclusion removed",
+		zap.String("author_id", authorID),
+		zap.String("reviewer_id", reviewerID),
+	)
+	return nil
+}
+
+// checkUser reports a missing user as "<role> not found".
+func (s *exclusionService) checkUser(ctx context.Context, role, userID string) error {
+	if _, err := s.repo.Users.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewErr(ErrorCodeNotFound, role+" not found")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"reviewer_pr/internal/database"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/repository"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type HealthService interface {
+	Readiness(ctx context.Context) *Readiness
+	SetShuttingDown()
+	// OnShuttingDown registers fn to be called by SetShuttingDown.
+	OnShuttingDown(fn func())
+}
+
+type CheckStatus string
+
+const (
+	CheckStatusUp   CheckStatus = "up"
+	CheckStatusDown CheckStatus = "down"
+)
+
+// Check is the status of one readiness check. Failures are logged, not
+// reported, since /readyz is public.
+type Check struct {
+	Status CheckStatus
+}
+
+type Readiness struct {
+	Ready             bool
+	ShuttingDown      bool
+	Database          Check
+	Replica           *Check
+	Migrations        Check
+	PendingMigrations []string
+}
+
+type healthService struct {
+	repo         *repository.Repository
+	log          *zap.Logger
+	shuttingDown atomic.Bool
+
+	mu         sync.Mutex
+	onShutdown []func()
+}
+
+func NewHealthService(repo *repository.Repository, log *zap.Logger) HealthService {
+	return &healthService{repo: repo, log: log}
+}
+
+// SetShuttingDown makes the service report not ready so load balancers drain it
+// before the HTTP server stops accepting connections.
+func (s *healthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, fn := range s.onShutdown {
+		fn()
+	}
+}
+
+func (s *healthService) OnShuttingDown(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onShutdown = append(s.onShutdown, fn)
+}
+
+func (s *healthService) Readiness(ctx context.Context) *Readiness {
+	res := &Readiness{
+		ShuttingDown: s.shuttingDown.Load(),
+		Database:     Check{Status: CheckStatusUp},
+		Migrations:   Check{Status: CheckStatusUp},
+	}
+
+	if err := ping(ctx, s.repo.DB); err != nil {
+		logger.FromContext(ctx, s.log).Warn("database ping failed", zap.Error(err))
+		res.Database = Check{Status: CheckStatusDown}
+		res.Migrations = Check{Status: CheckStatusDown}
+		return res
+	}
+
+	if s.repo.HasReplica() {
+		res.Replica = &Check{Status: CheckStatusUp}
+		if err := ping(ctx, s.repo.Reader().DB); err != nil {
+			logger.FromContext(ctx, s.log).Warn("replica ping failed", zap.Error(err))
+			res.Replica = &Check{Status: CheckStatusDown}
+		}
+	}
+
+	res.PendingMigrations = database.PendingMigrations(s.repo.DB.WithContext(ctx))
+	if len(res.PendingMigrations) > 0 {
+		res.Migrations = Check{Status: CheckStatusDown}
+	}
+
+	res.Ready = !res.ShuttingDown &&
+		res.Database.Status == CheckStatusUp &&
+		res.Migrations.Status == CheckStatusUp &&
+		(res.Replica == nil || res.Replica.Status == CheckStatusUp)
+	return res
+}
+
+func ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
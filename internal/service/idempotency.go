@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	IdempotencyKeyField     = "Idempotency-Key"
+	MaxIdempotencyKeyLength = 255
+
+	// A request that stays in progress longer than this is considered abandoned
+	// (e.g. the replica crashed) and its key may be reused.
+	idempotencyAbandonAfter = time.Minute
+)
+
+type IdempotencyService interface {
+	// Begin reserves the key for the request. It returns the stored response
+	// when the same request was already completed, or nil if the caller should
+	// execute the request and then call Complete or Release.
+	Begin(ctx context.Context, in BeginIdempotentInput) (*StoredResponse, error)
+	Complete(ctx context.Context, key string, resp StoredResponse) error
+	Release(ctx context.Context, key string) error
+	// PurgeExpired deletes the expired keys of every tenant.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type BeginIdempotentInput struct {
+	Key         string
+	RequestHash string
+	Method      string
+	Path        string
+}
+
+type StoredResponse struct {
+	StatusCode  int
+	ContentType string
+	// Headers are the response headers replayed with the body.
+	Headers map[string]string
+	Body    []byte
+}
+
+type idempotencyService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func NewIdempotencyService(repo *repository.Repository, log *zap.Logger, opts ...Option) IdempotencyService {
+	o := buildOptions(opts)
+	return &idempotencyService{
+		repo: repo,
+		log:  log,
+		ttl:  o.idempotencyTTL,
+		now:  o.clock,
+	}
+}
+
+func (in BeginIdempotentInput) Validate() error {
+	var v validator
+	if v.required(IdempotencyKeyField, in.Key) {
+		if len(in.Key) > MaxIdempotencyKeyLength {
+			v.add(IdempotencyKeyField, "must be at most %d characters", MaxIdempotencyKeyLength)
+		}
+		for _, r := range in.Key {
+			if r < 0x20 || r > 0x7e {
+				v.add(IdempotencyKeyField, "must contain only printable ASCII characters")
+				break
+			}
+		}
+	}
+	return v.err()
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, in BeginIdempotentInput) (*StoredResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	rec := &models.IdempotencyKey{
+		Key:         in.Key,
+		RequestHash: in.RequestHash,
+		Method:      in.Method,
+		Path:        in.Path,
+		Status:      models.IdempotencyInProgress,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+
+	// The second attempt covers a key that expired or was released concurrently.
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := s.repo.Idempotency.Reserve(ctx, rec)
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		existing, err := s.repo.Idempotency.Get(ctx, in.Key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		abandoned := existing.Status == models.IdempotencyInProgress && now.Sub(existing.CreatedAt) > idempotencyAbandonAfter
+		if !existing.ExpiresAt.After(now) || abandoned {
+			// Only the retry that removes the record it read may reserve the
+			// key again; the others see it in progress.
+			deleted, err := s.repo.Idempotency.DeleteCreated(ctx, in.Key, existing.CreatedAt)
+			if err != nil {
+				return nil, err
+			}
+			if !deleted {
+				break
+			}
+			continue
+		}
+
+		if existing.RequestHash != in.RequestHash {
+			return nil, NewErr(ErrorCodeIdempotencyKeyReused, "idempotency key was already used with a different request")
+		}
+		if existing.Status != models.IdempotencyCompleted {
+			return nil, NewErr(ErrorCodeIdempotencyInProgress, "a request with this idempotency key is still in progress")
+		}
+
+		logger.FromContext(ctx, s.log).Info("replaying idempotent response",
+			zap.String("idempotency_key", in.Key),
+			zap.Int("status", existing.ResponseCode),
+		)
+		return &StoredResponse{
+			StatusCode:  existing.ResponseCode,
+			ContentType: existing.ContentType,
+			Headers:     existing.ResponseHeaders,
+			Body:        existing.ResponseBody,
+		}, nil
+	}
+
+	return nil, NewErr(ErrorCodeIdempotencyInProgress, "a request with this idempotency key is still in progress")
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, key string, resp StoredResponse) error {
+	return s.repo.Idempotency.Complete(ctx, key, resp.StatusCode, resp.ContentType, resp.Headers, resp.Body)
+}
+
+func (s *idempotencyService) Release(ctx context.Context, key string) error {
+	return s.repo.Idempotency.Delete(ctx, key)
+}
+
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.Idempotency.DeleteExpired(ctx, s.now().UTC())
+}
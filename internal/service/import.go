@@ -0,0 +1,542 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+const MaxImportRows = 10000
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatYAML ImportFormat = "yaml"
+)
+
+// ImportRow is one team member from an import file. Line is the line in the
+// source file and is used to report errors and changes. A user listed in
+// several teams has one row per team; the first one sets the primary team,
+// unless the current primary team is not in the import.
+type ImportRow struct {
+	Line     int
+	TeamName string
+	UserID   string
+	Username string
+	IsActive bool
+}
+
+type ImportInput struct {
+	Rows   []ImportRow
+	DryRun bool
+	// DeactivateMissing marks active users that are not in Rows as inactive
+	// when every team they belong to is listed in Rows. Members of teams the
+	// import does not mention are left alone.
+	DeactivateMissing bool
+}
+
+type ImportAction string
+
+const (
+	ImportCreateTeam     ImportAction = "create_team"
+	ImportCreateUser     ImportAction = "create_user"
+	ImportUpdateUser     ImportAction = "update_user"
+	ImportDeactivateUser ImportAction = "deactivate_user"
+)
+
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// ImportChange is one line of the import diff. Line is 0 for changes that do
+// not come from the file, such as deactivating a missing user.
+type ImportChange struct {
+	Line     int
+	Action   ImportAction
+	TeamName string
+	UserID   string
+	Fields   []FieldChange
+}
+
+type ImportSummary struct {
+	TeamsCreated     int
+	UsersCreated     int
+	UsersUpdated     int
+	UsersDeactivated int
+	Unchanged        int
+}
+
+type ImportResult struct {
+	DryRun  bool
+	Changes []ImportChange
+	Summary ImportSummary
+}
+
+// ParseImport reads team members from CSV or YAML (JSON is accepted as YAML).
+//
+// CSV needs a header with team_name, user_id and username columns and an
+// optional is_active column. YAML has the shape of a list of /team/add bodies:
+// {teams: [{team_name, members: [{user_id, username, is_active}]}]}.
+// Missing is_active means active.
+func ParseImport(format ImportFormat, r io.Reader) ([]ImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseImportCSV(r)
+	case ImportFormatYAML:
+		return parseImportYAML(r)
+	default:
+		return nil, NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("unsupported import format %q", format))
+	}
+}
+
+func parseImportCSV(r io.Reader) ([]ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("invalid CSV: %v", err))
+	}
+
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	var v validator
+	for _, name := range []string{"team_name", "user_id", "username"} {
+		if _, ok := cols[name]; !ok {
+			v.add("header", "missing column %q", name)
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	get := func(rec []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var rows []ImportRow
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("invalid CSV: %v", err))
+		}
+		line, _ := cr.FieldPos(0)
+
+		row := ImportRow{
+			Line:     line,
+			TeamName: get(rec, "team_name"),
+			UserID:   get(rec, "user_id"),
+			Username: get(rec, "username"),
+			IsActive: true,
+		}
+		if raw := get(rec, "is_active"); raw != "" {
+			active, err := strconv.ParseBool(raw)
+			if err != nil {
+				v.add(rowField(line, "is_active"), "must be true or false")
+			}
+			row.IsActive = active
+		}
+		rows = append(rows, row)
+	}
+	return rows, v.err()
+}
+
+func parseImportYAML(r io.Reader) ([]ImportRow, error) {
+	var doc struct {
+		Teams []struct {
+			TeamName string      `yaml:"team_name"`
+			Members  []yaml.Node `yaml:"members"`
+		} `yaml:"teams"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, NewErr(ErrorCodeInvalidRequest, fmt.Sprintf("invalid YAML: %v", err))
+	}
+
+	var v validator
+	var rows []ImportRow
+	for _, team := range doc.Teams {
+		for _, node := range team.Members {
+			var m struct {
+				UserID   string `yaml:"user_id"`
+				Username string `yaml:"username"`
+				IsActive *bool  `yaml:"is_active"`
+			}
+			if err := node.Decode(&m); err != nil {
+				v.add(rowField(node.Line, "members"), "must be an object with user_id, username and is_active")
+				continue
+			}
+			rows = append(rows, ImportRow{
+				Line:     node.Line,
+				TeamName: team.TeamName,
+				UserID:   m.UserID,
+				Username: m.Username,
+				IsActive: m.IsActive == nil || *m.IsActive,
+			})
+		}
+	}
+	return rows, v.err()
+}
+
+func rowField(line int, field string) string {
+	return fmt.Sprintf("rows[%d].%s", line, field)
+}
+
+func (in ImportInput) Validate() error {
+	var v validator
+	if len(in.Rows) == 0 {
+		v.add("rows", "import contains no members")
+	}
+	if len(in.Rows) > MaxImportRows {
+		v.add("rows", "must contain at most %d members", MaxImportRows)
+	}
+
+	type member struct{ team, user string }
+	seen := make(map[member]int, len(in.Rows))
+	users := make(map[string]ImportRow, len(in.Rows))
+	for _, row := range in.Rows {
+		v.id(rowField(row.Line, "team_name"), row.TeamName)
+		v.id(rowField(row.Line, "user_id"), row.UserID)
+		v.text(rowField(row.Line, "username"), row.Username, MaxUsernameLength)
+
+		if row.UserID == "" {
+			continue
+		}
+		key := member{row.TeamName, row.UserID}
+		if first, dup := seen[key]; dup {
+			v.add(rowField(row.Line, "user_id"), "user %q is already listed in team %q on line %d", row.UserID, row.TeamName, first)
+			continue
+		}
+		seen[key] = row.Line
+		first, ok := users[row.UserID]
+		if !ok {
+			users[row.UserID] = row
+		} else if first.Username != row.Username || first.IsActive != row.IsActive {
+			v.add(rowField(row.Line, "user_id"), "user %q has another username or is_active on line %d", row.UserID, first.Line)
+		}
+	}
+	return v.err()
+}
+
+// Import brings teams and users in line with the import in one transaction:
+// any failure leaves the database untouched. With DryRun it only computes
+// the changes.
+func (s *teamService) Import(ctx context.Context, in ImportInput) (*ImportResult, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{DryRun: in.DryRun}
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		plan, err := planImport(ctx, tx, in)
+		if err != nil {
+			return err
+		}
+		result.Changes = plan.changes
+		result.Summary = plan.summary
+
+		if in.DryRun {
+			return nil
+		}
+		return plan.apply(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("teams imported",
+		zap.Bool("dry_run", in.DryRun),
+		zap.Int("rows", len(in.Rows)),
+		zap.Int("teams_created", result.Summary.TeamsCreated),
+		zap.Int("users_created", result.Summary.UsersCreated),
+		zap.Int("users_updated", result.Summary.UsersUpdated),
+		zap.Int("users_deactivated", result.Summary.UsersDeactivated),
+	)
+	return result, nil
+}
+
+// importedTeamsOnly reports whether the user belongs to at least one team and
+// only to teams listed in the import.
+func importedTeamsOnly(userTeams []string, imported map[string]bool) bool {
+	for _, team := range userTeams {
+		if _, ok := imported[team]; !ok {
+			return false
+		}
+	}
+	return len(userTeams) > 0
+}
+
+type importPlan struct {
+	changes []ImportChange
+	summary ImportSummary
+
+	newTeams []string
+	// creates are new users, updates existing users whose fields changed.
+	creates    []models.User
+	updates    []models.User
+	deactivate []string
+	// join and leave change memberships; joined memberships are active
+	// members, kept ones keep their role and activity.
+	join        []models.TeamMembership
+	leave       []models.TeamMembership
+	bumpedTeams map[string]struct{}
+}
+
+func planImport(ctx context.Context, tx *repository.Repository, in ImportInput) (*importPlan, error) {
+	plan := &importPlan{bumpedTeams: make(map[string]struct{})}
+
+	existing, err := tx.Users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]models.User, len(existing))
+	for _, u := range existing {
+		users[u.ID] = u
+	}
+	memberships, err := tx.Memberships.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string][]string)
+	for _, m := range memberships {
+		current[m.UserID] = append(current[m.UserID], m.TeamName)
+	}
+
+	// New teams and users must have IDs in the current format; existing ones
+	// are matched as stored.
+	var v validator
+	teams := make(map[string]bool)
+	for _, row := range in.Rows {
+		if _, checked := teams[row.TeamName]; checked {
+			continue
+		}
+		_, err := tx.Teams.GetTeamByName(ctx, row.TeamName)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		teams[row.TeamName] = err == nil
+		if err != nil {
+			v.newID(rowField(row.Line, "team_name"), row.TeamName)
+			plan.newTeams = append(plan.newTeams, row.TeamName)
+			plan.summary.TeamsCreated++
+			plan.changes = append(plan.changes, ImportChange{Line: row.Line, Action: ImportCreateTeam, TeamName: row.TeamName})
+		}
+	}
+
+	// Rows are grouped by user; the first row of a user stands for all.
+	var order []ImportRow
+	listed := make(map[string][]string, len(in.Rows))
+	for _, row := range in.Rows {
+		if _, ok := listed[row.UserID]; !ok {
+			order = append(order, row)
+		}
+		listed[row.UserID] = append(listed[row.UserID], row.TeamName)
+	}
+
+	for _, row := range order {
+		next := models.User{
+			ID:       row.UserID,
+			Username: row.Username,
+			TeamName: row.TeamName,
+			IsActive: row.IsActive,
+			Version:  1,
+		}
+		cur, ok := users[row.UserID]
+		if !ok {
+			v.newID(rowField(row.Line, "user_id"), row.UserID)
+		}
+		if _, imported := teams[cur.TeamName]; ok && cur.TeamName != "" && !imported {
+			next.TeamName = cur.TeamName
+		}
+		// Memberships of teams the import does not mention are kept.
+		after := slices.DeleteFunc(slices.Clone(current[row.UserID]), func(team string) bool {
+			_, imported := teams[team]
+			return imported
+		})
+		after = append(after, listed[row.UserID]...)
+		joined, left := teamsDiff(current[row.UserID], after)
+		for _, team := range joined {
+			plan.join = append(plan.join, models.TeamMembership{
+				TeamName: team, UserID: row.UserID, Role: models.MembershipMember, IsActive: true,
+			})
+			plan.bumpedTeams[team] = struct{}{}
+		}
+		for _, team := range left {
+			plan.leave = append(plan.leave, models.TeamMembership{TeamName: team, UserID: row.UserID})
+			plan.bumpedTeams[team] = struct{}{}
+		}
+
+		if !ok {
+			plan.creates = append(plan.creates, next)
+			plan.summary.UsersCreated++
+			plan.changes = append(plan.changes, ImportChange{
+				Line: row.Line, Action: ImportCreateUser, TeamName: row.TeamName, UserID: row.UserID,
+			})
+			continue
+		}
+
+		fields := userDiff(cur, next)
+		if len(fields) > 0 {
+			plan.updates = append(plan.updates, next)
+			for _, team := range current[row.UserID] {
+				plan.bumpedTeams[team] = struct{}{}
+			}
+		}
+		if len(joined)+len(left) > 0 {
+			fields = append(fields, FieldChange{
+				Field: "teams",
+				From:  strings.Join(current[row.UserID], " "),
+				To:    strings.Join(sortedCopy(after), " "),
+			})
+		}
+		if len(fields) == 0 {
+			plan.summary.Unchanged++
+			continue
+		}
+		plan.summary.UsersUpdated++
+		plan.changes = append(plan.changes, ImportChange{
+			Line: row.Line, Action: ImportUpdateUser, TeamName: row.TeamName, UserID: row.UserID, Fields: fields,
+		})
+	}
+
+	for _, u := range existing {
+		if _, ok := listed[u.ID]; ok {
+			continue
+		}
+		if in.DeactivateMissing && u.IsActive && importedTeamsOnly(current[u.ID], teams) {
+			plan.deactivate = append(plan.deactivate, u.ID)
+			for _, team := range current[u.ID] {
+				plan.bumpedTeams[team] = struct{}{}
+			}
+			plan.summary.UsersDeactivated++
+			plan.changes = append(plan.changes, ImportChange{
+				Action: ImportDeactivateUser, TeamName: u.TeamName, UserID: u.ID,
+				Fields: []FieldChange{{Field: "is_active", From: "true", To: "false"}},
+			})
+		}
+	}
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	// New teams start at version 1.
+	for _, name := range plan.newTeams {
+		delete(plan.bumpedTeams, name)
+	}
+	return plan, nil
+}
+
+// teamsDiff returns the teams of next missing in cur and those of cur
+// missing in next, each sorted.
+func teamsDiff(cur, next []string) (joined, left []string) {
+	for _, team := range sortedCopy(next) {
+		if !slices.Contains(cur, team) {
+			joined = append(joined, team)
+		}
+	}
+	for _, team := range sortedCopy(cur) {
+		if !slices.Contains(next, team) {
+			left = append(left, team)
+		}
+	}
+	return joined, left
+}
+
+func sortedCopy(s []string) []string {
+	out := slices.Clone(s)
+	sort.Strings(out)
+	return out
+}
+
+func userDiff(cur, next models.User) []FieldChange {
+	var fields []FieldChange
+	if cur.Username != next.Username {
+		fields = append(fields, FieldChange{Field: "username", From: cur.Username, To: next.Username})
+	}
+	if cur.TeamName != next.TeamName {
+		fields = append(fields, FieldChange{Field: "team_name", From: cur.TeamName, To: next.TeamName})
+	}
+	if cur.IsActive != next.IsActive {
+		fields = append(fields, FieldChange{
+			Field: "is_active",
+			From:  strconv.FormatBool(cur.IsActive),
+			To:    strconv.FormatBool(next.IsActive),
+		})
+	}
+	return fields
+}
+
+func (p *importPlan) apply(ctx context.Context, tx *repository.Repository) error {
+	for _, name := range p.newTeams {
+		if err := tx.Teams.Create(ctx, &models.Team{Name: name, Version: 1}); err != nil {
+			return err
+		}
+	}
+	for i := range p.creates {
+		if err := tx.Users.UpsertUser(ctx, &p.creates[i]); err != nil {
+			return err
+		}
+	}
+	for _, u := range p.updates {
+		fields := map[string]any{"username": u.Username, "team_name": u.TeamName, "is_active": u.IsActive}
+		if _, err := tx.Users.Update(ctx, u.ID, 0, fields); err != nil {
+			return err
+		}
+	}
+	for i := range p.join {
+		if err := tx.Memberships.Upsert(ctx, &p.join[i]); err != nil {
+			return err
+		}
+	}
+	for _, m := range p.leave {
+		if _, err := tx.Memberships.Delete(ctx, m.TeamName, m.UserID); err != nil {
+			return err
+		}
+	}
+	for _, id := range p.deactivate {
+		if err := tx.Users.SetUserActive(ctx, id, false); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(p.bumpedTeams))
+	for name := range p.bumpedTeams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := tx.Teams.BumpVersion(ctx, name, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
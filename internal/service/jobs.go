@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/scheduler"
+
+	"go.uber.org/zap"
+)
+
+type JobService interface {
+	// ListJobs returns the jobs registered in this process with their last
+	// run on any replica.
+	ListJobs(ctx context.Context) ([]JobStatus, error)
+	ListRuns(ctx context.Context, in ListJobRunsInput) (*JobRunList, error)
+	// TriggerJob starts a run now, outside the schedule, and returns its
+	// record while the job keeps running in the background.
+	TriggerJob(ctx context.Context, name string) (*models.JobRun, error)
+}
+
+type JobStatus struct {
+	scheduler.JobInfo
+	// LastRun is nil when the job never ran.
+	LastRun *models.JobRun
+}
+
+type ListJobRunsInput struct {
+	// JobName limits the history to one job.
+	JobName string
+	Page    Page
+}
+
+type JobRunList struct {
+	Runs  []models.JobRun
+	Total int64
+	Page  Page
+}
+
+type jobService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+	opts options
+}
+
+func NewJobService(repo *repository.Repository, log *zap.Logger, opts ...Option) JobService {
+	return &jobService{repo: repo, log: log, opts: buildOptions(opts)}
+}
+
+func (s *jobService) ListJobs(ctx context.Context) ([]JobStatus, error) {
+	if s.opts.scheduler == nil {
+		return []JobStatus{}, nil
+	}
+
+	infos := s.opts.scheduler.Jobs()
+	out := make([]JobStatus, 0, len(infos))
+	for _, info := range infos {
+		last, err := s.repo.Reader().JobRuns.Last(ctx, info.Name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, JobStatus{JobInfo: info, LastRun: last})
+	}
+	return out, nil
+}
+
+func (s *jobService) ListRuns(ctx context.Context, in ListJobRunsInput) (*JobRunList, error) {
+	var v validator
+	if in.JobName != "" {
+		v.id("job", in.JobName)
+	}
+	v.page(in.Page)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	page := in.Page.withDefaults()
+
+	runs, total, err := s.repo.Reader().JobRuns.List(ctx, in.JobName, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &JobRunList{Runs: runs, Total: total, Page: page}, nil
+}
+
+func (s *jobService) TriggerJob(ctx context.Context, name string) (*models.JobRun, error) {
+	if err := validateID("job", name); err != nil {
+		return nil, err
+	}
+	if s.opts.scheduler == nil {
+		return nil, NewErr(ErrorCodeNotFound, "job not found")
+	}
+
+	run, err := s.opts.scheduler.Trigger(ctx, name)
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		return nil, NewErr(ErrorCodeNotFound, "job not found")
+	case errors.Is(err, scheduler.ErrJobRunning):
+		return nil, NewErr(ErrorCodeResourceInUse, "job is already running")
+	case err != nil:
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("job triggered", zap.String("job", name), zap.Uint64("run_id", run.ID))
+	return run, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"math/rand"
+	"reviewer_pr/internal/digest"
+	"reviewer_pr/internal/notify"
+	"reviewer_pr/internal/scheduler"
+	"time"
+)
+
+type options struct {
+	reviewersPerPR int
+	idempotencyTTL time.Duration
+	notifier       notify.Notifier
+	digestRenderer *digest.Renderer
+	mailer         digest.Mailer
+	scheduler      *scheduler.Scheduler
+	strategy       AssignmentStrategy
+	random         rand.Source
+	clock          func() time.Time
+}
+
+// Option customises the behaviour of the services built by New.
+type Option func(*options)
+
+// WithReviewersPerPR sets how many reviewers are auto-assigned to a new pull request.
+func WithReviewersPerPR(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.reviewersPerPR = n
+		}
+	}
+}
+
+// WithNotifier sends pull request events (assignment, reassignment, merge)
+// to n. By default events are dropped.
+func WithNotifier(n notify.Notifier) Option {
+	return func(o *options) {
+		if n != nil {
+			o.notifier = n
+		}
+	}
+}
+
+// WithDigestRenderer renders review digests with r instead of the built-in
+// templates.
+func WithDigestRenderer(r *digest.Renderer) Option {
+	return func(o *options) {
+		if r != nil {
+			o.digestRenderer = r
+		}
+	}
+}
+
+// WithMailer sends review digests through m. Without a mailer digests can
+// only be previewed.
+func WithMailer(m digest.Mailer) Option {
+	return func(o *options) {
+		o.mailer = m
+	}
+}
+
+// WithScheduler lets the job service list and trigger the jobs of s.
+func WithScheduler(s *scheduler.Scheduler) Option {
+	return func(o *options) {
+		o.scheduler = s
+	}
+}
+
+// WithAssignmentStrategy sets how ties between equally ranked reviewer
+// candidates are broken. The default is StrategyRandom.
+func WithAssignmentStrategy(s AssignmentStrategy) Option {
+	return func(o *options) {
+		if s != "" {
+			o.strategy = s
+		}
+	}
+}
+
+// WithRandomSource draws the seeds of StrategyRandom from src instead of a
+// source seeded from the clock. src does not need to be safe for concurrent
+// use.
+func WithRandomSource(src rand.Source) Option {
+	return func(o *options) {
+		if src != nil {
+			o.random = src
+		}
+	}
+}
+
+// WithClock replaces time.Now for timestamps of merges and reviewer changes
+// and for the expiry of idempotency keys.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		if now != nil {
+			o.clock = now
+		}
+	}
+}
+
+// WithIdempotencyTTL sets how long responses stored for Idempotency-Key are replayed.
+func WithIdempotencyTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.idempotencyTTL = ttl
+		}
+	}
+}
+
+func buildOptions(opts []Option) options {
+	o := options{
+		reviewersPerPR: 2,
+		idempotencyTTL: 24 * time.Hour,
+		notifier:       notify.Nop{},
+		digestRenderer: digest.DefaultRenderer(),
+		strategy:       StrategyRandom,
+		clock:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,28 @@
+package service
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// Page selects part of a list. A zero Limit means DefaultPageLimit.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+func (p Page) withDefaults() Page {
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+	return p
+}
+
+func (v *validator) page(p Page) {
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		v.add("limit", "must be between 1 and %d", MaxPageLimit)
+	}
+	if p.Offset < 0 {
+		v.add("offset", "must not be negative")
+	}
+}
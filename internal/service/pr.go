@@ -3,9 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"hash/fnv"
 	"math/rand"
+	"reviewer_pr/internal/codeowners"
+	"reviewer_pr/internal/logger"
 	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/notify"
 	"reviewer_pr/internal/repository"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -16,46 +24,236 @@ type PRService interface {
 	CreateWithAutoAssign(ctx context.Context, in CreatePRInput) (*CreatePROutput, error)
 	Merge(ctx context.Context, prID string) (*models.PullRequest, error)
 	ReassignReviewer(ctx context.Context, in ReassignInput) (*ReassignOutput, error)
+	// AddReviewer assigns a named reviewer to an open pull request.
+	AddReviewer(ctx context.Context, in ReviewerChangeInput) (*PRDetails, error)
+	// RemoveReviewer unassigns a reviewer without a replacement.
+	RemoveReviewer(ctx context.Context, in ReviewerChangeInput) (*PRDetails, error)
+	// GetAssignmentHistory returns every reviewer change of a pull request,
+	// oldest first.
+	GetAssignmentHistory(ctx context.Context, prID string) ([]models.AssignmentEvent, error)
+	// ListAssignmentEvents returns the reviewer changes of every pull request,
+	// newest first, for auditing.
+	ListAssignmentEvents(ctx context.Context, in ListAssignmentEventsInput) (*AssignmentEventList, error)
+	// Simulate runs automatic assignment for hypothetical pull requests and
+	// writes nothing.
+	Simulate(ctx context.Context, in SimulateInput) (*Simulation, error)
 	GetReviewsByUser(ctx context.Context, reviewerID string) ([]models.PullRequest, error)
 	GetReviewersForPR(ctx context.Context, prID string) ([]models.PRReviewer, error)
+	GetPR(ctx context.Context, prID string) (*PRDetails, error)
+	UpdatePR(ctx context.Context, in UpdatePRInput) (*PRDetails, error)
+	DeletePR(ctx context.Context, prID string, expectedVersion int64) error
+}
+
+type PRDetails struct {
+	PR        *models.PullRequest
+	Reviewers []models.PRReviewer
+}
+
+type ListAssignmentEventsInput struct {
+	PullRequestID string
+	// UserID matches the reviewer, the replaced reviewer and the requester.
+	UserID string
+	Kind   string
+	// Since is inclusive, Until exclusive; zero values do not limit.
+	Since time.Time
+	Until time.Time
+	Page  Page
+}
+
+type AssignmentEventList struct {
+	Events []models.AssignmentEvent
+	Total  int64
+	Page   Page
+}
+
+type UpdatePRInput struct {
+	ID              string
+	Name            *string
+	Status          *models.PullRequestStatus
+	ExpectedVersion int64
 }
 
 type prService struct {
 	repo *repository.Repository
 	log  *zap.Logger
-	rnd  *rand.Rand
+	opts options
+
+	// mu guards rnd: requests rank candidates concurrently.
+	mu  sync.Mutex
+	rnd *rand.Rand
 }
 
-func NewPRService(repo *repository.Repository, log *zap.Logger) PRService {
+func NewPRService(repo *repository.Repository, log *zap.Logger, opts ...Option) PRService {
+	o := buildOptions(opts)
+	src := o.random
+	if src == nil {
+		src = rand.NewSource(time.Now().UnixNano()) //nolint:gosec
+	}
 	return &prService{
 		repo: repo,
 		log:  log,
-		rnd:  rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+		opts: o,
+		rnd:  rand.New(src), //nolint:gosec
 	}
 }
 
+// now is the time recorded for merges and reviewer changes.
+func (s *prService) now() time.Time {
+	return s.opts.clock().UTC()
+}
+
 type CreatePRInput struct {
 	ID       string
 	Name     string
 	AuthorID string
+	// ChangedFiles are the paths the pull request touches; the author team's
+	// code owners of these files are assigned before anyone else.
+	ChangedFiles []string
+	// Labels are matched against reviewer skills, case-insensitively.
+	Labels []string
 }
 
 type CreatePROutput struct {
 	PR        *models.PullRequest
 	Reviewers []models.User
+	// Picks explain the choice of each reviewer, in the order of Reviewers.
+	Picks []ReviewerPick
+	// Skipped are the candidates left out by exclusion rules.
+	Skipped []SkippedCandidate
+}
+
+// PickReason tells why a reviewer was assigned.
+type PickReason string
+
+const (
+	PickCodeOwner PickReason = "code_owner"
+	PickSkills    PickReason = "skills"
+	PickTeam      PickReason = "team"
+	// PickManual: the reviewer was named by the caller.
+	PickManual PickReason = "manual"
+)
+
+// AssignmentStrategy decides how ties between equally ranked candidates are
+// broken. Either way they are shuffled with a seed that is recorded with the
+// assignment, so the decision can be replayed.
+type AssignmentStrategy string
+
+const (
+	// StrategyRandom draws every seed from the service's random source.
+	StrategyRandom AssignmentStrategy = "random"
+	// StrategyDeterministic derives the seed from the pull request ID and
+	// the candidate IDs, see DeterministicSeed.
+	StrategyDeterministic AssignmentStrategy = "deterministic"
+)
+
+type ReviewerPick struct {
+	User   models.User
+	Reason PickReason
+	// Seed shuffled the candidates before ranking; it is zero for PickManual.
+	Seed int64
+	// Pattern is the code owner rule of a PickCodeOwner.
+	Pattern string
+	// Skills are the reviewer's skills matching the pull request labels.
+	Skills []Skill
+	// OpenReviews is the number of open pull requests the reviewer had
+	// before this one.
+	OpenReviews int64
+}
+
+// SkipReason tells why a candidate was not picked automatically.
+type SkipReason string
+
+const (
+	// SkipOptedOut: the user opted out of automatic assignment.
+	SkipOptedOut SkipReason = "opted_out"
+	// SkipExcluded: the user must not review the author's pull requests.
+	SkipExcluded SkipReason = "excluded"
+)
+
+type SkippedCandidate struct {
+	User   models.User
+	Reason SkipReason
+}
+
+// selection is the state of one automatic choice of reviewers.
+type selection struct {
+	repo     *repository.Repository
+	prID     string
+	author   *models.User
+	labels   []string
+	strategy AssignmentStrategy
+	// teams are the author's active teams, loaded by selectReviewers.
+	teams []string
+	// rnd, when set, draws the random strategy's seeds instead of the
+	// service's source.
+	rnd *rand.Rand
+	// load is added to the candidates' open review counts.
+	load map[string]int64
+	// skipped collects the candidates left out by exclusion rules.
+	skipped []SkippedCandidate
+}
+
+func (s *prService) newSelection(repo *repository.Repository, prID string, author *models.User, labels []string) *selection {
+	return &selection{repo: repo, prID: prID, author: author, labels: labels, strategy: s.opts.strategy}
+}
+
+// selectReviewers picks the code owners of files, then fills the remaining
+// of limit reviewers from the author's active teams. It only reads.
+func (s *prService) selectReviewers(ctx context.Context, sel *selection, files []string, limit int) ([]ReviewerPick, error) {
+	teams, err := activeTeams(ctx, sel.repo, sel.author.ID)
+	if err != nil {
+		return nil, err
+	}
+	sel.teams = teams
+
+	picks, err := s.pickCodeOwners(ctx, sel, files)
+	if err != nil {
+		return nil, err
+	}
+	owners := len(picks)
+
+	candidates, err := sel.repo.Users.GetActiveTeamMembersExcept(ctx, sel.teams, sel.author.ID)
+	if err != nil {
+		return nil, err
+	}
+	candidates = slices.DeleteFunc(candidates, func(u models.User) bool {
+		return slices.ContainsFunc(picks, func(p ReviewerPick) bool { return p.User.ID == u.ID })
+	})
+	candidates, err = s.eligible(ctx, sel, candidates)
+	if err != nil {
+		return nil, err
+	}
+	ranked, err := s.rankCandidates(ctx, sel, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return append(picks, ranked[:min(len(ranked), max(limit-owners, 0))]...), nil
 }
 
 func (s *prService) CreateWithAutoAssign(ctx context.Context, in CreatePRInput) (*CreatePROutput, error) {
+	labels := make([]string, len(in.Labels))
+	for i, l := range in.Labels {
+		labels[i] = normalizeTag(l)
+	}
+	in.Labels = labels
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	in.Labels = uniqueLabels(in.Labels)
+
 	var out *CreatePROutput
+	var author *models.User
+	var owners int
 
-	err := s.repo.DB.WithContext(ctx).Transaction(func(_ *gorm.DB) error {
-		if existing, err := s.repo.PRs.GetPullRequestByID(ctx, in.ID); err != nil && existing != nil {
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if existing, err := tx.PRs.GetPullRequestByID(ctx, in.ID); err == nil && existing != nil {
 			return NewErr(ErrorCodePRExists, "pull request already exists")
 		} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return err
 		}
 
-		author, err := s.repo.Users.GetUserByID(ctx, in.AuthorID)
+		var err error
+		author, err = tx.Users.GetUserByID(ctx, in.AuthorID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return NewErr(ErrorCodeNotFound, "author not found")
@@ -63,20 +261,31 @@ func (s *prService) CreateWithAutoAssign(ctx context.Context, in CreatePRInput)
 			return err
 		}
 
-		candidates, err := s.repo.Users.GetActiveTeamMembersExcept(ctx, author.TeamName, author.ID)
+		sel := s.newSelection(tx, in.ID, author, in.Labels)
+		picks, err := s.selectReviewers(ctx, sel, in.ChangedFiles, s.opts.reviewersPerPR)
 		if err != nil {
 			return err
 		}
+		for _, p := range picks {
+			if p.Reason == PickCodeOwner {
+				owners++
+			}
+		}
 
-		reviewers := pickReviewers(s.rnd, candidates, 2)
+		reviewers := make([]models.User, 0, len(picks))
+		for _, p := range picks {
+			reviewers = append(reviewers, p.User)
+		}
 		pr := &models.PullRequest{
 			ID:       in.ID,
 			Name:     in.Name,
 			AuthorID: author.ID,
 			Status:   models.PRStatusOpen,
+			Version:  1,
+			Labels:   strings.Join(in.Labels, " "),
 		}
 
-		if err := s.repo.PRs.Create(ctx, pr); err != nil {
+		if err := tx.PRs.Create(ctx, pr); err != nil {
 			return err
 		}
 
@@ -85,13 +294,31 @@ func (s *prService) CreateWithAutoAssign(ctx context.Context, in CreatePRInput)
 			reviewerIDs = append(reviewerIDs, u.ID)
 		}
 
-		if err := s.repo.PRs.AddReviewers(ctx, pr.ID, reviewerIDs); err != nil {
+		if err := tx.PRs.AddReviewers(ctx, pr.ID, reviewerIDs); err != nil {
+			return err
+		}
+		events := make([]models.AssignmentEvent, 0, len(picks))
+		now := s.now()
+		for _, p := range picks {
+			events = append(events, models.AssignmentEvent{
+				PullRequestID: pr.ID,
+				Kind:          models.AssignmentAssigned,
+				ReviewerID:    p.User.ID,
+				Reason:        string(p.Reason),
+				Strategy:      string(s.opts.strategy),
+				Seed:          p.Seed,
+				CreatedAt:     now,
+			})
+		}
+		if err := tx.Events.Add(ctx, events...); err != nil {
 			return err
 		}
 
 		out = &CreatePROutput{
 			PR:        pr,
 			Reviewers: reviewers,
+			Picks:     picks,
+			Skipped:   sel.skipped,
 		}
 		return nil
 	})
@@ -100,27 +327,241 @@ func (s *prService) CreateWithAutoAssign(ctx context.Context, in CreatePRInput)
 		return nil, err
 	}
 
+	logger.FromContext(ctx, s.log).Info("pull request created",
+		zap.String("pull_request_id", out.PR.ID),
+		zap.String("author_id", out.PR.AuthorID),
+		zap.Int("reviewers", len(out.Reviewers)),
+		zap.Int("code_owners", owners),
+	)
+	if len(out.Reviewers) > 0 {
+		s.opts.notifier.Notify(ctx, notify.Event{
+			Kind:      notify.EventAssigned,
+			PR:        *out.PR,
+			Author:    *author,
+			Reviewers: out.Reviewers,
+		})
+	}
 	return out, nil
 }
 
-func pickReviewers(r *rand.Rand, users []models.User, maxС int) []models.User {
-	if len(users) == 0 || maxС <= 0 {
-		return nil
+// activeTeams returns the teams in which the user's membership is active.
+func activeTeams(ctx context.Context, repo *repository.Repository, userID string) ([]string, error) {
+	memberships, err := repo.Memberships.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]string, 0, len(memberships))
+	for _, m := range memberships {
+		if m.IsActive {
+			teams = append(teams, m.TeamName)
+		}
+	}
+	return teams, nil
+}
+
+// pickCodeOwners picks one active owner, other than the author, for every
+// rule of the author's active teams that owns a changed file, unless an owner
+// picked for an earlier rule also owns it. Owners are ranked like other
+// candidates. Required owners are assigned even beyond the reviewers-per-PR
+// limit. Owners left out by exclusion rules are added to sel.skipped.
+func (s *prService) pickCodeOwners(ctx context.Context, sel *selection, files []string) ([]ReviewerPick, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	// Every team's rules own files independently: the last matching rule
+	// wins within a team, not across teams.
+	var rules []models.CodeOwnerRule
+	var owning []bool
+	for _, team := range sel.teams {
+		teamRules, err := sel.repo.CodeOwners.List(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		patterns := make([]*codeowners.Pattern, len(teamRules))
+		for i, r := range teamRules {
+			if patterns[i], err = codeowners.Compile(r.Pattern); err != nil {
+				return nil, err
+			}
+		}
+		teamOwning := make([]bool, len(teamRules))
+		for _, f := range files {
+			if i := codeowners.LastMatch(patterns, f); i >= 0 {
+				teamOwning[i] = true
+			}
+		}
+		rules = append(rules, teamRules...)
+		owning = append(owning, teamOwning...)
 	}
-	cpy := make([]models.User, len(users))
-	copy(cpy, users)
 
-	r.Shuffle(len(cpy), func(i, j int) {
-		cpy[i], cpy[j] = cpy[j], cpy[i]
+	var picked []ReviewerPick
+	for i, r := range rules {
+		if !owning[i] {
+			continue
+		}
+		owners, err := sel.repo.Users.GetActiveExcept(ctx, strings.Fields(r.Users), strings.Fields(r.Teams), sel.author.ID)
+		if err != nil {
+			return nil, err
+		}
+		owners, err = s.eligible(ctx, sel, owners)
+		if err != nil {
+			return nil, err
+		}
+		if len(owners) == 0 {
+			logger.FromContext(ctx, s.log).Debug("no active code owner", zap.String("pattern", r.Pattern))
+			continue
+		}
+		if slices.ContainsFunc(owners, func(u models.User) bool {
+			return slices.ContainsFunc(picked, func(p ReviewerPick) bool { return p.User.ID == u.ID })
+		}) {
+			continue
+		}
+		ranked, err := s.rankCandidates(ctx, sel, owners)
+		if err != nil {
+			return nil, err
+		}
+		pick := ranked[0]
+		pick.Reason, pick.Pattern = PickCodeOwner, r.Pattern
+		picked = append(picked, pick)
+	}
+	return picked, nil
+}
+
+// eligible drops the users that may not be picked automatically for a pull
+// request of sel.author: those who opted out of automatic assignment and
+// those excluded for the author. Dropped users are added to sel.skipped once.
+func (s *prService) eligible(ctx context.Context, sel *selection, users []models.User) ([]models.User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	excluded, err := sel.repo.Exclusions.Excluded(ctx, sel.author.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(users, func(u models.User) bool {
+		var reason SkipReason
+		switch {
+		case slices.Contains(excluded, u.ID):
+			reason = SkipExcluded
+		case u.AutoAssignOptOut:
+			reason = SkipOptedOut
+		default:
+			return false
+		}
+		if !slices.ContainsFunc(sel.skipped, func(c SkippedCandidate) bool { return c.User.ID == u.ID }) {
+			sel.skipped = append(sel.skipped, SkippedCandidate{User: u, Reason: reason})
+		}
+		return true
+	}), nil
+}
+
+// rankCandidates orders users from the best reviewer to the worst: most
+// skills matching sel.labels, then the highest total proficiency in them,
+// then the fewest open reviews. Ties are broken by shuffling the users, sorted
+// by ID, with a seed chosen by the assignment strategy.
+func (s *prService) rankCandidates(ctx context.Context, sel *selection, users []models.User) ([]ReviewerPick, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	load, err := sel.repo.PRs.OpenReviewCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := sel.repo.Skills.Matching(ctx, ids, sel.labels)
+	if err != nil {
+		return nil, err
+	}
+	skills := make(map[string][]Skill)
+	for _, m := range matched {
+		skills[m.UserID] = append(skills[m.UserID], Skill{Name: m.Skill, Proficiency: m.Proficiency})
+	}
+
+	picks := make([]ReviewerPick, 0, len(users))
+	for _, u := range users {
+		p := ReviewerPick{User: u, Reason: PickTeam, Skills: skills[u.ID], OpenReviews: load[u.ID] + sel.load[u.ID]}
+		if len(p.Skills) > 0 {
+			p.Reason = PickSkills
+		}
+		picks = append(picks, p)
+	}
+	sort.Slice(picks, func(i, j int) bool { return picks[i].User.ID < picks[j].User.ID })
+	seed := s.seed(sel, ids)
+	rand.New(rand.NewSource(seed)).Shuffle(len(picks), func(i, j int) { picks[i], picks[j] = picks[j], picks[i] }) //nolint:gosec
+	for i := range picks {
+		picks[i].Seed = seed
+	}
+	sort.SliceStable(picks, func(i, j int) bool {
+		a, b := picks[i], picks[j]
+		if len(a.Skills) != len(b.Skills) {
+			return len(a.Skills) > len(b.Skills)
+		}
+		if pa, pb := proficiency(a.Skills), proficiency(b.Skills); pa != pb {
+			return pa > pb
+		}
+		return a.OpenReviews < b.OpenReviews
 	})
+	return picks, nil
+}
+
+// seed returns the tie-break seed for ranking candidateIDs for sel.prID.
+func (s *prService) seed(sel *selection, candidateIDs []string) int64 {
+	if sel.strategy == StrategyDeterministic {
+		return DeterministicSeed(sel.prID, candidateIDs)
+	}
+	if sel.rnd != nil {
+		return sel.rnd.Int63()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Int63()
+}
+
+// DeterministicSeed hashes a pull request ID and a candidate set, in any
+// order, into the non-negative seed used by StrategyDeterministic.
+func DeterministicSeed(prID string, candidateIDs []string) int64 {
+	ids := slices.Clone(candidateIDs)
+	slices.Sort(ids)
+	h := fnv.New64a()
+	h.Write([]byte(prID))
+	for _, id := range ids {
+		h.Write([]byte{0})
+		h.Write([]byte(id))
+	}
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+func proficiency(skills []Skill) int {
+	total := 0
+	for _, s := range skills {
+		total += s.Proficiency
+	}
+	return total
+}
 
-	if len(cpy) > maxС {
-		cpy = cpy[:maxС]
+// uniqueLabels drops repeated labels, keeping the first occurrence.
+func uniqueLabels(labels []string) []string {
+	var out []string
+	for _, l := range labels {
+		if !slices.Contains(out, l) {
+			out = append(out, l)
+		}
 	}
-	return cpy
+	return out
 }
 
 func (s *prService) Merge(ctx context.Context, prID string) (*models.PullRequest, error) {
+	if err := validateID("pull_request_id", prID); err != nil {
+		return nil, err
+	}
+
 	pr, err := s.repo.PRs.GetPullRequestByID(ctx, prID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -133,89 +574,173 @@ func (s *prService) Merge(ctx context.Context, prID string) (*models.PullRequest
 		return nil, NewErr(ErrorCodePRMerged, "pull request already merged")
 	}
 
-	_, err = s.repo.PRs.SetPullRequestMerged(ctx, prID, time.Now().UTC())
+	_, err = s.repo.PRs.SetPullRequestMerged(ctx, prID, s.now())
 	if err != nil {
 		return nil, err
 	}
 
-	return s.repo.PRs.GetPullRequestByID(ctx, prID)
+	logger.FromContext(ctx, s.log).Info("pull request merged", zap.String("pull_request_id", prID))
+
+	merged, err := s.repo.PRs.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyMerged(ctx, merged)
+	return merged, nil
+}
+
+// notifyMerged tells the reviewers of pr that it was merged. The merge has
+// already happened, so lookup failures are only logged.
+func (s *prService) notifyMerged(ctx context.Context, pr *models.PullRequest) {
+	author, err := s.repo.Users.GetUserByID(ctx, pr.AuthorID)
+	if err == nil {
+		var reviewers []models.User
+		if reviewers, err = reviewerUsers(ctx, s.repo, pr.ID); err == nil && len(reviewers) > 0 {
+			s.opts.notifier.Notify(ctx, notify.Event{
+				Kind:      notify.EventMerged,
+				PR:        *pr,
+				Author:    *author,
+				Reviewers: reviewers,
+			})
+		}
+	}
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warn("merge notification skipped",
+			zap.String("pull_request_id", pr.ID), zap.Error(err))
+	}
+}
+
+func reviewerUsers(ctx context.Context, repo *repository.Repository, prID string) ([]models.User, error) {
+	assigned, err := repo.PRs.GetReviewersForPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]models.User, 0, len(assigned))
+	for _, r := range assigned {
+		u, err := repo.Users.GetUserByID(ctx, r.ReviewerID)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+	return users, nil
 }
 
 type ReassignInput struct {
 	PRID          string
 	OldReviewerID string
+	// NewReviewerID, when set, names the replacement instead of picking one.
+	// It must be an active member of the author's or the old reviewer's team.
+	NewReviewerID string
+	// RequestedBy is the user asking for the change; it is only recorded.
+	RequestedBy string
+	// ExpectedVersion, when set, must match the current pull request version.
+	ExpectedVersion int64
 }
 
 type ReassignOutput struct {
 	PR           *models.PullRequest
 	ReplacedByID string
+	// Reason explains the choice of the new reviewer.
+	Reason PickReason
+	// Skipped are the candidates left out by exclusion rules.
+	Skipped []SkippedCandidate
 }
 
 func (s *prService) ReassignReviewer(ctx context.Context, in ReassignInput) (*ReassignOutput, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
 	var out *ReassignOutput
+	var event notify.Event
 
-	err := s.repo.DB.WithContext(ctx).Transaction(func(_ *gorm.DB) error {
-		pr, err := s.repo.PRs.GetPullRequestByID(ctx, in.PRID)
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		pr, err := s.openPR(ctx, tx, in.PRID, in.ExpectedVersion)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return NewErr(ErrorCodeNotFound, "pull request not found")
-			}
 			return err
 		}
 
-		if pr.Status == models.PRStatusMerged {
-			return NewErr(ErrorCodePRMerged, "cannot reassing reviewer for merged pull request")
-		}
-
-		reviewers, err := s.repo.PRs.GetReviewersForPR(ctx, in.PRID)
+		reviewers, err := tx.PRs.GetReviewersForPR(ctx, in.PRID)
 		if err != nil {
 			return err
 		}
-
-		assigned := false
-		var otherReviewerID string
-		for _, r := range reviewers {
-			if r.ReviewerID == in.OldReviewerID {
-				assigned = true
-			} else {
-				otherReviewerID = r.ReviewerID
-			}
-		}
-		if !assigned {
+		if !isReviewer(reviewers, in.OldReviewerID) {
 			return NewErr(ErrorCodeNotAssigned, "user is not assigned as reviewer for this PR")
 		}
-		oldUser, err := s.repo.Users.GetUserByID(ctx, in.OldReviewerID)
+		oldUser, err := tx.Users.GetUserByID(ctx, in.OldReviewerID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return NewErr(ErrorCodeNotFound, "old reviewer not found")
 			}
 			return err
 		}
+		author, err := tx.Users.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
 
-		candidates, err := s.repo.Users.GetActiveTeamMembersExcept(ctx, oldUser.TeamName, oldUser.ID)
+		authorTeams, err := activeTeams(ctx, tx, author.ID)
+		if err != nil {
+			return err
+		}
+		oldTeams, err := activeTeams(ctx, tx, oldUser.ID)
 		if err != nil {
 			return err
 		}
 
-		filtered := candidates[:0]
-		for _, c := range candidates {
-			if c.ID != pr.AuthorID && c.ID != otherReviewerID {
-				filtered = append(filtered, c)
+		var pick ReviewerPick
+		sel := s.newSelection(tx, pr.ID, author, strings.Fields(pr.Labels))
+		if in.NewReviewerID != "" {
+			u, err := s.namedReviewer(ctx, tx, pr, reviewers, in.NewReviewerID, append(authorTeams, oldTeams...)...)
+			if err != nil {
+				return err
+			}
+			pick = ReviewerPick{User: *u, Reason: PickManual}
+		} else {
+			candidates, err := tx.Users.GetActiveTeamMembersExcept(ctx, oldTeams, oldUser.ID)
+			if err != nil {
+				return err
+			}
+			candidates = slices.DeleteFunc(candidates, func(c models.User) bool {
+				return c.ID == pr.AuthorID || isReviewer(reviewers, c.ID)
+			})
+			candidates, err = s.eligible(ctx, sel, candidates)
+			if err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return NewErr(ErrorCodeNoCandidate, "no active candidate in reviewer team")
 			}
-		}
-		candidates = filtered
 
-		if len(candidates) == 0 {
-			return NewErr(ErrorCodeNoCandidate, "no active candidate in reviewer team")
+			ranked, err := s.rankCandidates(ctx, sel, candidates)
+			if err != nil {
+				return err
+			}
+			pick = ranked[0]
 		}
+		newReviewer := pick.User
 
-		newReviewer := pickReviewers(s.rnd, candidates, 1)[0]
-
-		if err := s.repo.PRs.ReplaceReviewer(ctx, in.PRID, in.OldReviewerID, newReviewer.ID); err != nil {
+		if err := tx.PRs.ReplaceReviewer(ctx, in.PRID, in.OldReviewerID, newReviewer.ID); err != nil {
+			return err
+		}
+		rec := models.AssignmentEvent{
+			PullRequestID:      pr.ID,
+			Kind:               models.AssignmentReassigned,
+			ReviewerID:         newReviewer.ID,
+			PreviousReviewerID: oldUser.ID,
+			Reason:             string(pick.Reason),
+			RequestedBy:        in.RequestedBy,
+			CreatedAt:          s.now(),
+		}
+		if pick.Reason != PickManual {
+			rec.Strategy, rec.Seed = string(sel.strategy), pick.Seed
+		}
+		if err := tx.Events.Add(ctx, rec); err != nil {
 			return err
 		}
 
-		upd, err := s.repo.PRs.GetPullRequestByID(ctx, in.PRID)
+		upd, err := tx.PRs.GetPullRequestByID(ctx, in.PRID)
 		if err != nil {
 			return err
 		}
@@ -223,8 +748,16 @@ func (s *prService) ReassignReviewer(ctx context.Context, in ReassignInput) (*Re
 		out = &ReassignOutput{
 			PR:           upd,
 			ReplacedByID: newReviewer.ID,
+			Reason:       pick.Reason,
+			Skipped:      sel.skipped,
+		}
+		event = notify.Event{
+			Kind:      notify.EventReassigned,
+			PR:        *upd,
+			Author:    *author,
+			Reviewers: []models.User{newReviewer},
+			Replaced:  oldUser,
 		}
-
 		return nil
 	})
 
@@ -232,11 +765,229 @@ func (s *prService) ReassignReviewer(ctx context.Context, in ReassignInput) (*Re
 		return nil, err
 	}
 
+	logger.FromContext(ctx, s.log).Info("reviewer reassigned",
+		zap.String("pull_request_id", in.PRID),
+		zap.String("old_reviewer_id", in.OldReviewerID),
+		zap.String("new_reviewer_id", out.ReplacedByID),
+	)
+	s.opts.notifier.Notify(ctx, event)
 	return out, nil
 }
 
+type ReviewerChangeInput struct {
+	PRID       string
+	ReviewerID string
+	// RequestedBy is the user asking for the change; it is only recorded.
+	RequestedBy string
+	// ExpectedVersion, when set, must match the current pull request version.
+	ExpectedVersion int64
+}
+
+func (s *prService) AddReviewer(ctx context.Context, in ReviewerChangeInput) (*PRDetails, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	var event notify.Event
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		// Reviewers are counted under the lock, so that two concurrent
+		// additions cannot both pass the limit.
+		pr, err := s.openPR(ctx, tx, in.PRID, in.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+		reviewers, err := tx.PRs.GetReviewersForPR(ctx, pr.ID)
+		if err != nil {
+			return err
+		}
+		author, err := tx.Users.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
+		authorTeams, err := activeTeams(ctx, tx, author.ID)
+		if err != nil {
+			return err
+		}
+		u, err := s.namedReviewer(ctx, tx, pr, reviewers, in.ReviewerID, authorTeams...)
+		if err != nil {
+			return err
+		}
+		if len(reviewers) >= s.opts.reviewersPerPR {
+			return NewErr(ErrorCodeReviewerLimit, "pull request already has the maximum number of reviewers")
+		}
+
+		if err := tx.PRs.AddReviewer(ctx, pr.ID, u.ID); err != nil {
+			return err
+		}
+		if err := tx.Events.Add(ctx, models.AssignmentEvent{
+			PullRequestID: pr.ID,
+			Kind:          models.AssignmentAdded,
+			ReviewerID:    u.ID,
+			Reason:        string(PickManual),
+			RequestedBy:   in.RequestedBy,
+			CreatedAt:     s.now(),
+		}); err != nil {
+			return err
+		}
+		event = notify.Event{Kind: notify.EventAssigned, PR: *pr, Author: *author, Reviewers: []models.User{*u}}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("reviewer added",
+		zap.String("pull_request_id", in.PRID),
+		zap.String("reviewer_id", in.ReviewerID),
+		zap.String("requested_by", in.RequestedBy),
+	)
+	s.opts.notifier.Notify(ctx, event)
+	return s.GetPR(ctx, in.PRID)
+}
+
+func (s *prService) RemoveReviewer(ctx context.Context, in ReviewerChangeInput) (*PRDetails, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		pr, err := s.openPR(ctx, tx, in.PRID, in.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+		ok, err := tx.PRs.RemoveReviewer(ctx, pr.ID, in.ReviewerID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return NewErr(ErrorCodeNotAssigned, "user is not assigned as reviewer for this PR")
+		}
+		return tx.Events.Add(ctx, models.AssignmentEvent{
+			PullRequestID: pr.ID,
+			Kind:          models.AssignmentRemoved,
+			ReviewerID:    in.ReviewerID,
+			Reason:        string(PickManual),
+			RequestedBy:   in.RequestedBy,
+			CreatedAt:     s.now(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("reviewer removed",
+		zap.String("pull_request_id", in.PRID),
+		zap.String("reviewer_id", in.ReviewerID),
+		zap.String("requested_by", in.RequestedBy),
+	)
+	return s.GetPR(ctx, in.PRID)
+}
+
+// openPR locks and loads a pull request whose reviewers are about to change;
+// repo must be bound to a transaction.
+func (s *prService) openPR(ctx context.Context, repo *repository.Repository, prID string, expectedVersion int64) (*models.PullRequest, error) {
+	if err := repo.PRs.Lock(ctx, prID); err != nil {
+		return nil, err
+	}
+	pr, err := repo.PRs.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErr(ErrorCodeNotFound, "pull request not found")
+		}
+		return nil, err
+	}
+	if err := checkVersion(expectedVersion, pr.Version); err != nil {
+		return nil, err
+	}
+	if pr.Status == models.PRStatusMerged {
+		return nil, NewErr(ErrorCodePRMerged, "cannot change reviewers of merged pull request")
+	}
+	return pr, nil
+}
+
+// namedReviewer loads the user a caller wants as a reviewer of pr and checks
+// that they may review it: not the author, not assigned yet, active, an
+// active member of one of teams and not excluded for the author. Opting out of
+// automatic assignment does not prevent being named.
+func (s *prService) namedReviewer(ctx context.Context, repo *repository.Repository, pr *models.PullRequest, assigned []models.PRReviewer, userID string, teams ...string) (*models.User, error) {
+	u, err := repo.Users.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErr(ErrorCodeNotFound, "reviewer not found")
+		}
+		return nil, err
+	}
+
+	switch {
+	case u.ID == pr.AuthorID:
+		return nil, NewErr(ErrorCodeReviewerNotAllowed, "author cannot review own pull request")
+	case isReviewer(assigned, u.ID):
+		return nil, NewErr(ErrorCodeAlreadyAssigned, "user is already assigned as reviewer for this PR")
+	case !u.IsActive:
+		return nil, NewErr(ErrorCodeReviewerNotAllowed, "reviewer is inactive")
+	}
+	memberOf, err := activeTeams(ctx, repo, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(memberOf, func(team string) bool { return slices.Contains(teams, team) }) {
+		return nil, NewErr(ErrorCodeReviewerNotAllowed, "reviewer is not a member of an allowed team")
+	}
+
+	excluded, err := repo.Exclusions.Excluded(ctx, pr.AuthorID, []string{u.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(excluded) > 0 {
+		return nil, NewErr(ErrorCodeReviewerNotAllowed, "reviewer is excluded from reviewing this author")
+	}
+	return u, nil
+}
+
+func isReviewer(assigned []models.PRReviewer, userID string) bool {
+	return slices.ContainsFunc(assigned, func(r models.PRReviewer) bool { return r.ReviewerID == userID })
+}
+
+func (s *prService) GetAssignmentHistory(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+	if err := validateID("pull_request_id", prID); err != nil {
+		return nil, err
+	}
+
+	reader := s.repo.Reader()
+	if _, err := reader.PRs.GetPullRequestByID(ctx, prID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErr(ErrorCodeNotFound, "pull request not found")
+		}
+		return nil, err
+	}
+	return reader.Events.List(ctx, prID)
+}
+
+func (s *prService) ListAssignmentEvents(ctx context.Context, in ListAssignmentEventsInput) (*AssignmentEventList, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	page := in.Page.withDefaults()
+
+	events, total, err := s.repo.Reader().Events.Search(ctx, repository.EventFilter{
+		PullRequestID: in.PullRequestID,
+		UserID:        in.UserID,
+		Kind:          models.AssignmentEventKind(in.Kind),
+		Since:         in.Since,
+		Until:         in.Until,
+	}, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &AssignmentEventList{Events: events, Total: total, Page: page}, nil
+}
+
 func (s *prService) GetReviewsByUser(ctx context.Context, reviewerID string) ([]models.PullRequest, error) {
-	prs, err := s.repo.PRs.GetPullRequestsByReviewer(ctx, reviewerID)
+	if err := validateID("user_id", reviewerID); err != nil {
+		return nil, err
+	}
+
+	prs, err := s.repo.Reader().PRs.GetPullRequestsByReviewer(ctx, reviewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -246,3 +997,89 @@ func (s *prService) GetReviewsByUser(ctx context.Context, reviewerID string) ([]
 func (s *prService) GetReviewersForPR(ctx context.Context, prID string) ([]models.PRReviewer, error) {
 	return s.repo.PRs.GetReviewersForPR(ctx, prID)
 }
+
+func (s *prService) GetPR(ctx context.Context, prID string) (*PRDetails, error) {
+	if err := validateID("pull_request_id", prID); err != nil {
+		return nil, err
+	}
+
+	pr, reviewers, err := s.repo.PRs.GetPullRequestWithReviewers(ctx, prID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewErr(ErrorCodeNotFound, "pull request not found")
+		}
+		return nil, err
+	}
+	return &PRDetails{PR: pr, Reviewers: reviewers}, nil
+}
+
+func (s *prService) UpdatePR(ctx context.Context, in UpdatePRInput) (*PRDetails, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	cur, err := s.GetPR(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(in.ExpectedVersion, cur.PR.Version); err != nil {
+		return nil, err
+	}
+	if cur.PR.Status == models.PRStatusMerged {
+		return nil, NewErr(ErrorCodePRMerged, "pull request already merged")
+	}
+
+	fields := make(map[string]any, 3)
+	if in.Name != nil {
+		fields["pull_request_name"] = *in.Name
+	}
+	if in.Status != nil {
+		fields["status"] = *in.Status
+		fields["merged_at"] = s.now()
+	}
+
+	ok, err := s.repo.PRs.Update(ctx, in.ID, in.ExpectedVersion, fields)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errPreconditionFailed()
+	}
+
+	log := logger.FromContext(ctx, s.log)
+	if in.Status != nil {
+		log.Info("pull request merged", zap.String("pull_request_id", in.ID))
+	} else {
+		log.Info("pull request updated", zap.String("pull_request_id", in.ID))
+	}
+
+	res, err := s.GetPR(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if in.Status != nil {
+		s.notifyMerged(ctx, res.PR)
+	}
+	return res, nil
+}
+
+func (s *prService) DeletePR(ctx context.Context, prID string, expectedVersion int64) error {
+	cur, err := s.GetPR(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(expectedVersion, cur.PR.Version); err != nil {
+		return err
+	}
+
+	ok, err := s.repo.PRs.Delete(ctx, prID, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errPreconditionFailed()
+	}
+
+	logger.FromContext(ctx, s.log).Info("pull request deleted", zap.String("pull_request_id", prID))
+	return nil
+}
@@ -7,21 +7,42 @@ import (
 )
 
 type Services struct {
-	Teams TeamService
-	Users UserService
-	PRs   PRService
-	Stats StatsService
+	Teams  TeamService
+	Users  UserService
+	PRs    PRService
+	Stats  StatsService
+	Health HealthService
+
+	Idempotency IdempotencyService
+	Backup      BackupService
+	Digests     DigestService
+	SLAs        SLAService
+	Jobs        JobService
+	CodeOwners  CodeOwnersService
+	Skills      SkillService
+	Exclusions  ExclusionService
 }
 
-func New(repo *repository.Repository, log *zap.Logger) *Services {
-	return buildServices(repo, log)
+func New(repo *repository.Repository, log *zap.Logger, opts ...Option) *Services {
+	return buildServices(repo, log, opts)
 }
 
-func buildServices(repo *repository.Repository, log *zap.Logger) *Services {
+func buildServices(repo *repository.Repository, log *zap.Logger, opts []Option) *Services {
+	prs := NewPRService(repo, log, opts...)
 	return &Services{
-		Teams: NewTeamService(repo, log),
-		Users: NewUserService(repo, log),
-		PRs:   NewPRService(repo, log),
-		Stats: NewStatsService(repo, log),
+		Teams:  NewTeamService(repo, log),
+		Users:  NewUserService(repo, log),
+		PRs:    prs,
+		Stats:  NewStatsService(repo, log),
+		Health: NewHealthService(repo, log),
+
+		Idempotency: NewIdempotencyService(repo, log, opts...),
+		Backup:      NewBackupService(repo, log),
+		Digests:     NewDigestService(repo, log, opts...),
+		SLAs:        NewSLAService(repo, log, prs, opts...),
+		Jobs:        NewJobService(repo, log, opts...),
+		CodeOwners:  NewCodeOwnersService(repo, log),
+		Skills:      NewSkillService(repo, log),
+		Exclusions:  NewExclusionService(repo, log),
 	}
 }
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SimulatedPRID names the hypothetical pull request when the caller does not.
+const SimulatedPRID = "simulated"
+
+type SimulateInput struct {
+	AuthorID string
+	// PRID names the hypothetical pull request; StrategyDeterministic
+	// derives its seed from it. The pull requests after the first one are
+	// named PRID-2, PRID-3 and so on. Defaults to SimulatedPRID.
+	PRID         string
+	ChangedFiles []string
+	Labels       []string
+	// ReviewersPerPR, when positive, replaces the configured number of
+	// reviewers.
+	ReviewersPerPR int
+	// Strategy, when set, replaces the configured assignment strategy.
+	Strategy AssignmentStrategy
+	// Seed seeds StrategyRandom; when zero a seed is drawn and reported.
+	Seed int64
+	// Count is the number of pull requests to simulate, one by default.
+	// Every pull request sees the reviews picked for the previous ones.
+	Count int
+}
+
+// CandidateStatus tells how the simulation treated a user. Availability,
+// such as being out of office, is not modeled: users carry no absence data,
+// so an absent user is only skipped once marked inactive.
+type CandidateStatus string
+
+const (
+	CandidatePicked CandidateStatus = "picked"
+	// CandidateCapacity: eligible, but ranked below the picked reviewers
+	// after the pull request reached its number of reviewers.
+	CandidateCapacity CandidateStatus = "capacity"
+	CandidateAuthor   CandidateStatus = "author"
+	CandidateInactive CandidateStatus = "inactive"
+	CandidateOptedOut CandidateStatus = CandidateStatus(SkipOptedOut)
+	CandidateExcluded CandidateStatus = CandidateStatus(SkipExcluded)
+)
+
+type SimulatedCandidate struct {
+	User        models.User
+	Status      CandidateStatus
+	OpenReviews int64
+}
+
+// ProjectedLoad is the review load of an eligible candidate after the
+// simulated pull requests.
+type ProjectedLoad struct {
+	User models.User
+	// OpenReviews are the reviews the user has now.
+	OpenReviews int64
+	// Assigned are the simulated pull requests the user was picked for.
+	Assigned int
+}
+
+type Simulation struct {
+	Author   models.User
+	Strategy AssignmentStrategy
+	// Seed is the seed of StrategyRandom; zero for StrategyDeterministic.
+	Seed  int64
+	Count int
+	// Candidates are the author's team and the code owners of the changed
+	// files, as seen by the first pull request, ordered by user ID.
+	Candidates []SimulatedCandidate
+	// Picks are the reviewers of the first pull request.
+	Picks []ReviewerPick
+	// Load covers every eligible candidate, ordered by user ID.
+	Load []ProjectedLoad
+}
+
+func (s *prService) Simulate(ctx context.Context, in SimulateInput) (*Simulation, error) {
+	labels := make([]string, len(in.Labels))
+	for i, l := range in.Labels {
+		labels[i] = normalizeTag(l)
+	}
+	in.Labels = labels
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	in.Labels = uniqueLabels(in.Labels)
+	if in.PRID == "" {
+		in.PRID = SimulatedPRID
+	}
+	if in.ReviewersPerPR == 0 {
+		in.ReviewersPerPR = s.opts.reviewersPerPR
+	}
+	if in.Strategy == "" {
+		in.Strategy = s.opts.strategy
+	}
+	in.Count = max(in.Count, 1)
+
+	out := &Simulation{Strategy: in.Strategy, Count: in.Count}
+	var rnd *rand.Rand
+	if in.Strategy == StrategyRandom {
+		out.Seed = in.Seed
+		if out.Seed == 0 {
+			s.mu.Lock()
+			out.Seed = s.rnd.Int63()
+			s.mu.Unlock()
+		}
+		rnd = rand.New(rand.NewSource(out.Seed)) //nolint:gosec
+	}
+
+	err := s.repo.Reader().Snapshot(ctx, func(tx *repository.Repository) error {
+		author, err := tx.Users.GetUserByID(ctx, in.AuthorID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewErr(ErrorCodeNotFound, "author not found")
+			}
+			return err
+		}
+		out.Author = *author
+
+		assigned := make(map[string]int64)
+		for i := range in.Count {
+			prID := in.PRID
+			if i > 0 {
+				prID = fmt.Sprintf("%s-%d", in.PRID, i+1)
+			}
+			sel := &selection{
+				repo:     tx,
+				prID:     prID,
+				author:   author,
+				labels:   in.Labels,
+				strategy: in.Strategy,
+				rnd:      rnd,
+				load:     assigned,
+			}
+			picks, err := s.selectReviewers(ctx, sel, in.ChangedFiles, in.ReviewersPerPR)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				out.Picks = picks
+				if out.Candidates, err = s.candidates(ctx, sel, picks); err != nil {
+					return err
+				}
+			}
+			for _, p := range picks {
+				assigned[p.User.ID]++
+			}
+		}
+
+		for _, c := range out.Candidates {
+			if c.Status == CandidatePicked || c.Status == CandidateCapacity {
+				out.Load = append(out.Load, ProjectedLoad{
+					User:        c.User,
+					OpenReviews: c.OpenReviews,
+					Assigned:    int(assigned[c.User.ID]),
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Debug("assignment simulated",
+		zap.String("author_id", in.AuthorID),
+		zap.String("strategy", string(in.Strategy)),
+		zap.Int("count", in.Count),
+	)
+	return out, nil
+}
+
+// candidates classifies the members of the author's active teams, and the
+// code owners picked or skipped, after the first simulated selection. A
+// member whose memberships in those teams are all inactive is inactive.
+func (s *prService) candidates(ctx context.Context, sel *selection, picks []ReviewerPick) ([]SimulatedCandidate, error) {
+	var users []models.User
+	inactiveMember := make(map[string]bool)
+	for _, team := range sel.teams {
+		members, err := sel.repo.Memberships.Members(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			inactive, seen := inactiveMember[m.User.ID]
+			if !seen {
+				users = append(users, m.User)
+			}
+			inactiveMember[m.User.ID] = (inactive || !seen) && !m.MembershipActive
+		}
+	}
+	for _, p := range picks {
+		users = append(users, p.User)
+	}
+	for _, c := range sel.skipped {
+		users = append(users, c.User)
+	}
+	slices.SortFunc(users, func(a, b models.User) int { return strings.Compare(a.ID, b.ID) })
+	users = slices.CompactFunc(users, func(a, b models.User) bool { return a.ID == b.ID })
+
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	load, err := sel.repo.PRs.OpenReviewCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]SimulatedCandidate, 0, len(users))
+	for _, u := range users {
+		c := SimulatedCandidate{User: u, Status: CandidateCapacity, OpenReviews: load[u.ID]}
+		if i := slices.IndexFunc(sel.skipped, func(c SkippedCandidate) bool { return c.User.ID == u.ID }); i >= 0 {
+			c.Status = CandidateStatus(sel.skipped[i].Reason)
+		}
+		switch {
+		case u.ID == sel.author.ID:
+			c.Status = CandidateAuthor
+		case !u.IsActive:
+			c.Status = CandidateInactive
+		case inactiveMember[u.ID]:
+			c.Status = CandidateInactive
+		case slices.ContainsFunc(picks, func(p ReviewerPick) bool { return p.User.ID == u.ID }):
+			c.Status = CandidatePicked
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
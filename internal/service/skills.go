@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SkillService interface {
+	GetSkills(ctx context.Context, userID string) (*UserSkills, error)
+	// SetSkill adds a skill to the user or changes its proficiency.
+	SetSkill(ctx context.Context, in SetSkillInput) (*UserSkills, error)
+	RemoveSkill(ctx context.Context, userID, skill string) (*UserSkills, error)
+}
+
+// Skill is a user's area of expertise. Proficiency runs from 1 to 5; 0 means
+// it is not specified.
+type Skill struct {
+	Name        string
+	Proficiency int
+}
+
+type UserSkills struct {
+	UserID string
+	Skills []Skill
+}
+
+type SetSkillInput struct {
+	UserID      string
+	Skill       string
+	Proficiency int
+}
+
+type skillService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+}
+
+func NewSkillService(repo *repository.Repository, log *zap.Logger) SkillService {
+	return &skillService{repo: repo, log: log}
+}
+
+func (s *skillService) GetSkills(ctx context.Context, userID string) (*UserSkills, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.skills(ctx, userID)
+}
+
+func (s *skillService) SetSkill(ctx context.Context, in SetSkillInput) (*UserSkills, error) {
+	in.Skill = normalizeTag(in.Skill)
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, in.UserID); err != nil {
+		return nil, err
+	}
+
+	skills, err := s.repo.Skills.List(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	known := false
+	for _, sk := range skills {
+		known = known || sk.Skill == in.Skill
+	}
+	if !known && len(skills) >= MaxUserSkills {
+		return nil, NewValidationErr(FieldError{Field: "skill", Message: "user already has the maximum number of skills"})
+	}
+
+	if err := s.repo.Skills.Upsert(ctx, &models.UserSkill{UserID: in.UserID, Skill: in.Skill, Proficiency: in.Proficiency}); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("user skill set",
+		zap.String("user_id", in.UserID),
+		zap.String("skill", in.Skill),
+		zap.Int("proficiency", in.Proficiency),
+	)
+	return s.skills(ctx, in.UserID)
+}
+
+func (s *skillService) RemoveSkill(ctx context.Context, userID, skill string) (*UserSkills, error) {
+	skill = normalizeTag(skill)
+	var v validator
+	v.id("user_id", userID)
+	v.tag("skill", skill)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.Skills.Delete(ctx, userID, skill)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, NewErr(ErrorCodeNotFound, "skill not found")
+	}
+
+	logger.FromContext(ctx, s.log).Info("user skill removed", zap.String("user_id", userID), zap.String("skill", skill))
+	return s.skills(ctx, userID)
+}
+
+func (s *skillService) skills(ctx context.Context, userID string) (*UserSkills, error) {
+	rows, err := s.repo.Skills.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := &UserSkills{UserID: userID, Skills: make([]Skill, 0, len(rows))}
+	for _, r := range rows {
+		out.Skills = append(out.Skills, Skill{Name: r.Skill, Proficiency: r.Proficiency})
+	}
+	return out, nil
+}
+
+func (s *skillService) checkUser(ctx context.Context, userID string) error {
+	if err := validateID("user_id", userID); err != nil {
+		return err
+	}
+	if _, err := s.repo.Users.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewErr(ErrorCodeNotFound, "user not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// normalizeTag makes skills and labels case-insensitive.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
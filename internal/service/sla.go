@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reviewer_pr/internal/logger"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/notify"
+	"reviewer_pr/internal/repository"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SLAService interface {
+	// GetTeamSLA returns a zero TeamSLA when the team has no limits.
+	GetTeamSLA(ctx context.Context, teamName string) (*TeamSLA, error)
+	// SetTeamSLA replaces the team limits; all zero removes them.
+	SetTeamSLA(ctx context.Context, in TeamSLA) (*TeamSLA, error)
+	// ListOverdue returns open pull requests breaching their team SLA at
+	// now, the most overdue first.
+	ListOverdue(ctx context.Context, in ListOverdueInput, now time.Time) (*OverdueList, error)
+	// CheckBreaches notifies about breaches not seen before and, when the team
+	// asks for it, reassigns late reviewers. A late reviewer nobody could
+	// replace is retried on the next check.
+	CheckBreaches(ctx context.Context, now time.Time) (*SLACheckResult, error)
+}
+
+// TeamSLA limits how long a team's open pull requests may wait: ReviewWithin
+// from a reviewer's assignment, MergeWithin from creation. Zero is no limit.
+type TeamSLA struct {
+	TeamName     string
+	ReviewWithin time.Duration
+	MergeWithin  time.Duration
+	AutoReassign bool
+}
+
+type ListOverdueInput struct {
+	// TeamName limits the list to pull requests authored by the team.
+	TeamName string
+	Page     Page
+}
+
+type SLABreach struct {
+	Kind models.SLABreachKind
+	// ReviewerID is set for review breaches.
+	ReviewerID string
+	Deadline   time.Time
+}
+
+type OverduePR struct {
+	PR       models.PullRequest
+	TeamName string
+	// Breaches are ordered by deadline.
+	Breaches []SLABreach
+}
+
+type OverdueList struct {
+	PullRequests []OverduePR
+	Total        int64
+	Page         Page
+}
+
+type SLACheckResult struct {
+	// ReviewBreaches and MergeBreaches count breaches found for the first time.
+	ReviewBreaches int
+	MergeBreaches  int
+	Reassigned     int
+}
+
+type slaService struct {
+	repo *repository.Repository
+	log  *zap.Logger
+	prs  PRService
+	opts options
+}
+
+func NewSLAService(repo *repository.Repository, log *zap.Logger, prs PRService, opts ...Option) SLAService {
+	return &slaService{repo: repo, log: log, prs: prs, opts: buildOptions(opts)}
+}
+
+func (s *slaService) GetTeamSLA(ctx context.Context, teamName string) (*TeamSLA, error) {
+	if err := checkTeam(ctx, s.repo, teamName); err != nil {
+		return nil, err
+	}
+	sla, err := s.repo.SLAs.Get(ctx, teamName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &TeamSLA{TeamName: teamName}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toTeamSLA(sla), nil
+}
+
+func (s *slaService) SetTeamSLA(ctx context.Context, in TeamSLA) (*TeamSLA, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkTeam(ctx, s.repo, in.TeamName); err != nil {
+		return nil, err
+	}
+
+	if in.ReviewWithin == 0 && in.MergeWithin == 0 {
+		if _, err := s.repo.SLAs.Delete(ctx, in.TeamName); err != nil {
+			return nil, err
+		}
+		logger.FromContext(ctx, s.log).Info("team SLA removed", zap.String("team_name", in.TeamName))
+		return &TeamSLA{TeamName: in.TeamName}, nil
+	}
+
+	if err := s.repo.SLAs.Upsert(ctx, &models.TeamSLA{
+		TeamName:      in.TeamName,
+		ReviewSeconds: int64(in.ReviewWithin / time.Second),
+		MergeSeconds:  int64(in.MergeWithin / time.Second),
+		AutoReassign:  in.AutoReassign,
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("team SLA set",
+		zap.String("team_name", in.TeamName),
+		zap.Duration("review_within", in.ReviewWithin),
+		zap.Duration("merge_within", in.MergeWithin),
+		zap.Bool("auto_reassign", in.AutoReassign),
+	)
+	return &in, nil
+}
+
+func (s *slaService) ListOverdue(ctx context.Context, in ListOverdueInput, now time.Time) (*OverdueList, error) {
+	var v validator
+	if in.TeamName != "" {
+		v.id("team_name", in.TeamName)
+	}
+	v.page(in.Page)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	page := in.Page.withDefaults()
+
+	overdue, err := s.overdue(ctx, s.repo.Reader(), in.TeamName, now)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &OverdueList{Total: int64(len(overdue)), Page: page}
+	if page.Offset < len(overdue) {
+		end := min(page.Offset+page.Limit, len(overdue))
+		list.PullRequests = overdue[page.Offset:end]
+	}
+	return list, nil
+}
+
+func (s *slaService) CheckBreaches(ctx context.Context, now time.Time) (*SLACheckResult, error) {
+	overdue, err := s.overdue(ctx, s.repo, "", now)
+	if err != nil {
+		return nil, err
+	}
+	slas, err := s.slasByTeam(ctx, s.repo, "")
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SLACheckResult{}
+	var errs []error
+	for _, o := range overdue {
+		for _, b := range o.Breaches {
+			fresh, err := s.repo.SLAs.RecordBreach(ctx, &models.SLABreach{
+				PullRequestID: o.PR.ID,
+				ReviewerID:    b.ReviewerID,
+				Kind:          b.Kind,
+				DetectedAt:    now.UTC(),
+			})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			if fresh {
+				s.log.Warn("SLA breached",
+					zap.String("pull_request_id", o.PR.ID),
+					zap.String("team_name", o.TeamName),
+					zap.String("kind", string(b.Kind)),
+					zap.String("reviewer_id", b.ReviewerID),
+					zap.Time("deadline", b.Deadline),
+				)
+				s.notifyBreach(ctx, o.PR, b, now)
+				if b.Kind == models.SLABreachMerge {
+					res.MergeBreaches++
+				} else {
+					res.ReviewBreaches++
+				}
+			}
+
+			if b.Kind == models.SLABreachReview && slas[o.TeamName].AutoReassign {
+				ok, err := s.reassign(ctx, o.PR.ID, b.ReviewerID)
+				if err != nil {
+					errs = append(errs, err)
+				} else if ok {
+					res.Reassigned++
+				}
+			}
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// reassign replaces a late reviewer through the regular reassignment. It
+// reports false when nobody can take over.
+func (s *slaService) reassign(ctx context.Context, prID, reviewerID string) (bool, error) {
+	out, err := s.prs.ReassignReviewer(ctx, ReassignInput{PRID: prID, OldReviewerID: reviewerID})
+	if err != nil {
+		var serr *Error
+		if errors.As(err, &serr) && (serr.Code == ErrorCodeNoCandidate || serr.Code == ErrorCodeNotAssigned || serr.Code == ErrorCodePRMerged) {
+			s.log.Debug("late reviewer kept",
+				zap.String("pull_request_id", prID),
+				zap.String("reviewer_id", reviewerID),
+				zap.String("reason", string(serr.Code)),
+			)
+			return false, nil
+		}
+		return false, err
+	}
+
+	s.log.Info("late reviewer reassigned",
+		zap.String("pull_request_id", prID),
+		zap.String("old_reviewer_id", reviewerID),
+		zap.String("new_reviewer_id", out.ReplacedByID),
+	)
+	return true, nil
+}
+
+// notifyBreach sends an overdue event; lookup failures are only logged.
+func (s *slaService) notifyBreach(ctx context.Context, pr models.PullRequest, b SLABreach, now time.Time) {
+	e := notify.Event{PR: pr, Overdue: now.Sub(b.Deadline)}
+	author, err := s.repo.Users.GetUserByID(ctx, pr.AuthorID)
+	if err == nil {
+		e.Author = *author
+		if b.Kind == models.SLABreachReview {
+			e.Kind = notify.EventReviewOverdue
+			var reviewer *models.User
+			if reviewer, err = s.repo.Users.GetUserByID(ctx, b.ReviewerID); err == nil {
+				e.Reviewers = []models.User{*reviewer}
+			}
+		} else {
+			e.Kind = notify.EventMergeOverdue
+			e.Reviewers, err = reviewerUsers(ctx, s.repo, pr.ID)
+		}
+	}
+	if err != nil {
+		s.log.Warn("SLA notification skipped", zap.String("pull_request_id", pr.ID), zap.Error(err))
+		return
+	}
+	s.opts.notifier.Notify(ctx, e)
+}
+
+// overdue finds the breaching pull requests of teamName, or of every team
+// when it is empty.
+func (s *slaService) overdue(ctx context.Context, repo *repository.Repository, teamName string, now time.Time) ([]OverduePR, error) {
+	slas, err := s.slasByTeam(ctx, repo, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*OverduePR)
+	add := func(pr models.PullRequest, team string, b SLABreach) {
+		o, ok := byID[pr.ID]
+		if !ok {
+			o = &OverduePR{PR: pr, TeamName: team}
+			byID[pr.ID] = o
+		}
+		o.Breaches = append(o.Breaches, b)
+	}
+
+	for team, sla := range slas {
+		if sla.ReviewWithin > 0 {
+			reviews, err := repo.SLAs.OverdueReviews(ctx, team, now.Add(-sla.ReviewWithin).UTC())
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range reviews {
+				add(r.PullRequest, team, SLABreach{
+					Kind:       models.SLABreachReview,
+					ReviewerID: r.ReviewerID,
+					Deadline:   r.AssignedAt.Add(sla.ReviewWithin),
+				})
+			}
+		}
+		if sla.MergeWithin > 0 {
+			prs, err := repo.SLAs.OverdueMerges(ctx, team, now.Add(-sla.MergeWithin).UTC())
+			if err != nil {
+				return nil, err
+			}
+			for _, pr := range prs {
+				add(pr, team, SLABreach{Kind: models.SLABreachMerge, Deadline: pr.CreatedAt.Add(sla.MergeWithin)})
+			}
+		}
+	}
+
+	out := make([]OverduePR, 0, len(byID))
+	for _, o := range byID {
+		sort.Slice(o.Breaches, func(i, j int) bool {
+			a, b := o.Breaches[i], o.Breaches[j]
+			if !a.Deadline.Equal(b.Deadline) {
+				return a.Deadline.Before(b.Deadline)
+			}
+			if a.Kind != b.Kind {
+				return a.Kind < b.Kind
+			}
+			return a.ReviewerID < b.ReviewerID
+		})
+		out = append(out, *o)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].Breaches[0].Deadline, out[j].Breaches[0].Deadline
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return out[i].PR.ID < out[j].PR.ID
+	})
+	return out, nil
+}
+
+func (s *slaService) slasByTeam(ctx context.Context, repo *repository.Repository, teamName string) (map[string]TeamSLA, error) {
+	var slas []models.TeamSLA
+	if teamName == "" {
+		var err error
+		if slas, err = repo.SLAs.List(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		sla, err := repo.SLAs.Get(ctx, teamName)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if sla != nil {
+			slas = append(slas, *sla)
+		}
+	}
+
+	out := make(map[string]TeamSLA, len(slas))
+	for i := range slas {
+		out[slas[i].TeamName] = *toTeamSLA(&slas[i])
+	}
+	return out, nil
+}
+
+// checkTeam answers NOT_FOUND unless the team exists.
+func checkTeam(ctx context.Context, repo *repository.Repository, teamName string) error {
+	if err := validateID("team_name", teamName); err != nil {
+		return err
+	}
+	if _, err := repo.Teams.GetTeamByName(ctx, teamName); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewErr(ErrorCodeNotFound, "team not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func toTeamSLA(s *models.TeamSLA) *TeamSLA {
+	return &TeamSLA{
+		TeamName:     s.TeamName,
+		ReviewWithin: time.Duration(s.ReviewSeconds) * time.Second,
+		MergeWithin:  time.Duration(s.MergeSeconds) * time.Second,
+		AutoReassign: s.AutoReassign,
+	}
+}
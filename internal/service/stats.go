@@ -38,12 +38,14 @@ func NewStatsService(repo *repository.Repository, log *zap.Logger) StatsService
 }
 
 func (s *statsService) GetStats(ctx context.Context) (*Stats, error) {
-	userStats, err := s.repo.PRs.GetUserReviewStats(ctx)
+	reader := s.repo.Reader()
+
+	userStats, err := reader.PRs.GetUserReviewStats(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	prStats, err := s.repo.PRs.GetPRReviewStats(ctx)
+	prStats, err := reader.PRs.GetPRReviewStats(ctx)
 	if err != nil {
 		return nil, err
 	}
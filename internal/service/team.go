@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reviewer_pr/internal/logger"
 	"reviewer_pr/internal/models"
 	"reviewer_pr/internal/repository"
 
@@ -13,6 +15,18 @@ import (
 type TeamService interface {
 	AddTeam(ctx context.Context, in CreateTeamInput) (*TeamWithMembers, error)
 	GetTeam(ctx context.Context, teamName string) (*TeamWithMembers, error)
+	// UpdateMembers adds or updates members of an existing team.
+	UpdateMembers(ctx context.Context, in UpdateTeamInput) (*TeamWithMembers, error)
+	// SetMembership adds an existing user to a team or changes the role and
+	// activity of their membership.
+	SetMembership(ctx context.Context, in SetMembershipInput) (*TeamWithMembers, error)
+	// RemoveMembership removes a user from a team other than their primary one.
+	RemoveMembership(ctx context.Context, teamName, userID string) (*TeamWithMembers, error)
+	// DeleteTeam removes a team that no longer has members.
+	DeleteTeam(ctx context.Context, teamName string, expectedVersion int64) error
+	// Import creates teams and upserts users from an import file; see ParseImport.
+	Import(ctx context.Context, in ImportInput) (*ImportResult, error)
+	ListTeams(ctx context.Context, page Page) (*TeamList, error)
 }
 
 type teamService struct {
@@ -33,18 +47,61 @@ type CreateTeamMemberInput struct {
 	UserID   string
 	Username string
 	IsActive bool
+	// Role and MembershipActive describe the membership in the team; when
+	// empty, an existing membership keeps them and a new one is an active
+	// member.
+	Role             string
+	MembershipActive *bool
+}
+
+type SetMembershipInput struct {
+	TeamName string
+	UserID   string
+	// Role and IsActive keep their current values when empty.
+	Role     string
+	IsActive *bool
+}
+
+type UpdateTeamInput struct {
+	TeamName        string
+	Members         []CreateTeamMemberInput
+	ExpectedVersion int64
 }
 
 type TeamWithMembers struct {
 	Team    *models.Team
-	Members []models.User
+	Members []TeamMember
+}
+
+// TeamMember is a user listed in a team, with their membership in it.
+type TeamMember struct {
+	models.User
+	Role             string
+	MembershipActive bool
+}
+
+type TeamSummary struct {
+	TeamName          string
+	MemberCount       int64
+	ActiveMemberCount int64
+	OpenPRCount       int64
+}
+
+type TeamList struct {
+	Teams []TeamSummary
+	Total int64
+	Page  Page
 }
 
 func (s *teamService) AddTeam(ctx context.Context, in CreateTeamInput) (*TeamWithMembers, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
 	var result *TeamWithMembers
 
-	err := s.repo.DB.WithContext(ctx).Transaction(func(_ *gorm.DB) error {
-		existing, err := s.repo.Teams.GetTeamByName(ctx, in.TeamName)
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		existing, err := tx.Teams.GetTeamByName(ctx, in.TeamName)
 		if err == nil && existing != nil {
 			return NewErr(ErrorCodeTeamExists, "team already exists")
 		}
@@ -53,25 +110,20 @@ func (s *teamService) AddTeam(ctx context.Context, in CreateTeamInput) (*TeamWit
 		}
 
 		team := &models.Team{
-			Name: in.TeamName,
+			Name:    in.TeamName,
+			Version: 1,
 		}
 
-		if err := s.repo.Teams.Create(ctx, team); err != nil {
+		if err := tx.Teams.Create(ctx, team); err != nil {
 			return err
 		}
 
-		members := make([]models.User, 0, len(in.Members))
-		for _, m := range in.Members {
-			u := &models.User{
-				ID:       m.UserID,
-				Username: m.Username,
-				TeamName: in.TeamName,
-				IsActive: m.IsActive,
-			}
-			if err := s.repo.Users.UpsertUser(ctx, u); err != nil {
-				return err
-			}
-			members = append(members, *u)
+		if err := s.upsertMembers(ctx, tx, in.TeamName, in.Members); err != nil {
+			return err
+		}
+		members, err := s.members(ctx, tx, in.TeamName)
+		if err != nil {
+			return err
 		}
 
 		result = &TeamWithMembers{
@@ -85,11 +137,105 @@ func (s *teamService) AddTeam(ctx context.Context, in CreateTeamInput) (*TeamWit
 	if err != nil {
 		return nil, err
 	}
+
+	logger.FromContext(ctx, s.log).Info("team created",
+		zap.String("team_name", result.Team.Name),
+		zap.Int("members", len(result.Members)),
+	)
 	return result, nil
 }
 
+// upsertMembers creates users and adds them to teamName. A new user gets
+// teamName as primary team; an existing one keeps theirs and only gains or
+// updates the membership. The other teams of a user whose name or activity
+// changed get a new version because their representation changed.
+func (s *teamService) upsertMembers(ctx context.Context, repo *repository.Repository, teamName string, in []CreateTeamMemberInput) error {
+	changedTeams := make(map[string]struct{})
+
+	var v validator
+	for i, m := range in {
+		existing, err := repo.Users.GetUserByID(ctx, m.UserID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing == nil {
+			v.newID(fmt.Sprintf("members[%d].user_id", i), m.UserID)
+		}
+		if existing != nil && (existing.Username != m.Username || existing.IsActive != m.IsActive) {
+			teams, err := repo.Memberships.ListByUser(ctx, m.UserID)
+			if err != nil {
+				return err
+			}
+			for _, t := range teams {
+				changedTeams[t.TeamName] = struct{}{}
+			}
+		}
+
+		u := &models.User{
+			ID:       m.UserID,
+			Username: m.Username,
+			TeamName: teamName,
+			IsActive: m.IsActive,
+			Version:  1,
+		}
+		if err := repo.Users.UpsertUser(ctx, u); err != nil {
+			return err
+		}
+
+		membership := &models.TeamMembership{TeamName: teamName, UserID: m.UserID, Role: models.MembershipMember, IsActive: true}
+		current, err := repo.Memberships.Get(ctx, teamName, m.UserID)
+		switch {
+		case err == nil:
+			membership = current
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		if m.Role != "" {
+			membership.Role = m.Role
+		}
+		if m.MembershipActive != nil {
+			membership.IsActive = *m.MembershipActive
+		}
+		if err := repo.Memberships.Upsert(ctx, membership); err != nil {
+			return err
+		}
+	}
+
+	// The writes so far are rolled back with the caller's transaction.
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	delete(changedTeams, teamName)
+	for name := range changedTeams {
+		if _, err := repo.Teams.BumpVersion(ctx, name, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// members returns the users of teamName with their memberships.
+func (s *teamService) members(ctx context.Context, repo *repository.Repository, teamName string) ([]TeamMember, error) {
+	rows, err := repo.Memberships.Members(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TeamMember, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, TeamMember{User: r.User, Role: r.Role, MembershipActive: r.MembershipActive})
+	}
+	return out, nil
+}
+
 func (s *teamService) GetTeam(ctx context.Context, teamName string) (*TeamWithMembers, error) {
-	team, err := s.repo.Teams.GetTeamByName(ctx, teamName)
+	if err := validateID("team_name", teamName); err != nil {
+		return nil, err
+	}
+
+	reader := s.repo.Reader()
+
+	team, err := reader.Teams.GetTeamByName(ctx, teamName)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, NewErr(ErrorCodeNotFound, "team not found")
@@ -97,13 +243,246 @@ func (s *teamService) GetTeam(ctx context.Context, teamName string) (*TeamWithMe
 		return nil, err
 	}
 
-	users, err := s.repo.Teams.GetTeamMembers(ctx, teamName)
+	members, err := s.members(ctx, reader, teamName)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TeamWithMembers{
 		Team:    team,
-		Members: users,
+		Members: members,
 	}, nil
 }
+
+func (s *teamService) UpdateMembers(ctx context.Context, in UpdateTeamInput) (*TeamWithMembers, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result *TeamWithMembers
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		team, err := tx.Teams.GetTeamByName(ctx, in.TeamName)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewErr(ErrorCodeNotFound, "team not found")
+			}
+			return err
+		}
+		if err := checkVersion(in.ExpectedVersion, team.Version); err != nil {
+			return err
+		}
+
+		// Bumping the version first claims the update: a concurrent writer with
+		// the same If-Match loses here instead of silently overwriting.
+		ok, err := tx.Teams.BumpVersion(ctx, in.TeamName, in.ExpectedVersion)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errPreconditionFailed()
+		}
+
+		if err := s.upsertMembers(ctx, tx, in.TeamName, in.Members); err != nil {
+			return err
+		}
+
+		result, err = s.teamWithMembers(ctx, tx, in.TeamName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("team members updated",
+		zap.String("team_name", in.TeamName),
+		zap.Int("members", len(in.Members)),
+	)
+	return result, nil
+}
+
+func (s *teamService) SetMembership(ctx context.Context, in SetMembershipInput) (*TeamWithMembers, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result *TeamWithMembers
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		if _, err := tx.Teams.GetTeamByName(ctx, in.TeamName); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewErr(ErrorCodeNotFound, "team not found")
+			}
+			return err
+		}
+		if _, err := tx.Users.GetUserByID(ctx, in.UserID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewErr(ErrorCodeNotFound, "user not found")
+			}
+			return err
+		}
+
+		membership := &models.TeamMembership{TeamName: in.TeamName, UserID: in.UserID, Role: models.MembershipMember, IsActive: true}
+		current, err := tx.Memberships.Get(ctx, in.TeamName, in.UserID)
+		switch {
+		case err == nil:
+			membership = current
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		if in.Role != "" {
+			membership.Role = in.Role
+		}
+		if in.IsActive != nil {
+			membership.IsActive = *in.IsActive
+		}
+		if err := tx.Memberships.Upsert(ctx, membership); err != nil {
+			return err
+		}
+		if _, err := tx.Teams.BumpVersion(ctx, in.TeamName, 0); err != nil {
+			return err
+		}
+
+		result, err = s.teamWithMembers(ctx, tx, in.TeamName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("team membership set",
+		zap.String("team_name", in.TeamName),
+		zap.String("user_id", in.UserID),
+	)
+	return result, nil
+}
+
+func (s *teamService) RemoveMembership(ctx context.Context, teamName, userID string) (*TeamWithMembers, error) {
+	var v validator
+	v.id("team_name", teamName)
+	v.id("user_id", userID)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	var result *TeamWithMembers
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		u, err := tx.Users.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewErr(ErrorCodeNotFound, "user not found")
+			}
+			return err
+		}
+		if u.TeamName == teamName {
+			return NewErr(ErrorCodeResourceInUse, "team is the user's primary team")
+		}
+		ok, err := tx.Memberships.Delete(ctx, teamName, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return NewErr(ErrorCodeNotFound, "membership not found")
+		}
+		if _, err := tx.Teams.BumpVersion(ctx, teamName, 0); err != nil {
+			return err
+		}
+
+		result, err = s.teamWithMembers(ctx, tx, teamName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("team membership removed",
+		zap.String("team_name", teamName),
+		zap.String("user_id", userID),
+	)
+	return result, nil
+}
+
+func (s *teamService) teamWithMembers(ctx context.Context, repo *repository.Repository, teamName string) (*TeamWithMembers, error) {
+	team, err := repo.Teams.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.members(ctx, repo, teamName)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamWithMembers{Team: team, Members: members}, nil
+}
+
+func (s *teamService) DeleteTeam(ctx context.Context, teamName string, expectedVersion int64) error {
+	if err := validateID("team_name", teamName); err != nil {
+		return err
+	}
+
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		team, err := tx.Teams.GetTeamByName(ctx, teamName)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewErr(ErrorCodeNotFound, "team not found")
+			}
+			return err
+		}
+		if err := checkVersion(expectedVersion, team.Version); err != nil {
+			return err
+		}
+
+		members, err := tx.Teams.GetTeamMembers(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if len(members) > 0 {
+			return NewErr(ErrorCodeResourceInUse, "team still has members")
+		}
+
+		ok, err := tx.Teams.Delete(ctx, teamName, expectedVersion)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errPreconditionFailed()
+		}
+		if _, err := tx.SLAs.Delete(ctx, teamName); err != nil {
+			return err
+		}
+		return tx.CodeOwners.Delete(ctx, teamName)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, s.log).Info("team deleted", zap.String("team_name", teamName))
+	return nil
+}
+
+func (s *teamService) ListTeams(ctx context.Context, page Page) (*TeamList, error) {
+	var v validator
+	v.page(page)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	page = page.withDefaults()
+
+	reader := s.repo.Reader()
+	total, err := reader.Teams.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := reader.Teams.List(ctx, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TeamList{Teams: make([]TeamSummary, 0, len(rows)), Total: total, Page: page}
+	for _, t := range rows {
+		res.Teams = append(res.Teams, TeamSummary{
+			TeamName:          t.TeamName,
+			MemberCount:       t.MemberCount,
+			ActiveMemberCount: t.ActiveMemberCount,
+			OpenPRCount:       t.OpenPRCount,
+		})
+	}
+	return res, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"reviewer_pr/internal/logger"
 	"reviewer_pr/internal/models"
 	"reviewer_pr/internal/repository"
 
@@ -13,6 +14,34 @@ import (
 type UserService interface {
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error)
 	GetUser(ctx context.Context, userID string) (*models.User, error)
+	UpdateUser(ctx context.Context, in UpdateUserInput) (*models.User, error)
+	// DeleteUser removes a user that is not referenced by any pull request.
+	DeleteUser(ctx context.Context, userID string, expectedVersion int64) error
+	// SetNotifications opts the user in to or out of chat notifications.
+	SetNotifications(ctx context.Context, userID string, enabled bool) (*models.User, error)
+	// SetAutoAssign opts the user in to or out of automatic reviewer picks.
+	SetAutoAssign(ctx context.Context, userID string, enabled bool) (*models.User, error)
+	// SearchUsers finds users by username prefix and/or team.
+	SearchUsers(ctx context.Context, in SearchUsersInput) (*UserList, error)
+}
+
+type UpdateUserInput struct {
+	UserID          string
+	Username        *string
+	IsActive        *bool
+	ExpectedVersion int64
+}
+
+type SearchUsersInput struct {
+	UsernamePrefix string
+	TeamName       string
+	Page           Page
+}
+
+type UserList struct {
+	Users []models.User
+	Total int64
+	Page  Page
 }
 
 type userService struct {
@@ -25,28 +54,208 @@ func NewUserService(repo *repository.Repository, log *zap.Logger) UserService {
 }
 
 func (s *userService) SetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
-	u, err := s.repo.Users.GetUserByID(ctx, userID)
+	var u *models.User
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		var err error
+		if u, err = getUser(ctx, tx, userID); err != nil {
+			return err
+		}
+		if err := tx.Users.SetUserActive(ctx, userID, isActive); err != nil {
+			return err
+		}
+		return bumpTeamsOf(ctx, tx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.IsActive = isActive
+	u.Version++
+
+	logger.FromContext(ctx, s.log).Info("user activity changed",
+		zap.String("user_id", u.ID),
+		zap.Bool("is_active", isActive),
+	)
+	return u, nil
+}
+
+func (s *userService) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	return getUser(ctx, s.repo, userID)
+}
+
+func getUser(ctx context.Context, repo *repository.Repository, userID string) (*models.User, error) {
+	if err := validateID("user_id", userID); err != nil {
+		return nil, err
+	}
+
+	u, err := repo.Users.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, NewErr(ErrorCodeNotFound, "user not found")
 		}
 		return nil, err
 	}
+	return u, nil
+}
 
-	if err := s.repo.Users.SetUserActive(ctx, userID, isActive); err != nil {
+func (s *userService) UpdateUser(ctx context.Context, in UpdateUserInput) (*models.User, error) {
+	if err := in.Validate(); err != nil {
 		return nil, err
 	}
-	u.IsActive = isActive
+
+	fields := make(map[string]any, 2)
+	if in.Username != nil {
+		fields["username"] = *in.Username
+	}
+	if in.IsActive != nil {
+		fields["is_active"] = *in.IsActive
+	}
+
+	var u *models.User
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		cur, err := getUser(ctx, tx, in.UserID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(in.ExpectedVersion, cur.Version); err != nil {
+			return err
+		}
+
+		ok, err := tx.Users.Update(ctx, in.UserID, in.ExpectedVersion, fields)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errPreconditionFailed()
+		}
+		if err := bumpTeamsOf(ctx, tx, in.UserID); err != nil {
+			return err
+		}
+		u, err = tx.Users.GetUserByID(ctx, in.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info("user updated", zap.String("user_id", in.UserID))
 	return u, nil
 }
 
-func (s *userService) GetUser(ctx context.Context, userID string) (*models.User, error) {
-	u, err := s.repo.Users.GetUserByID(ctx, userID)
+func (s *userService) DeleteUser(ctx context.Context, userID string, expectedVersion int64) error {
+	err := s.repo.Transaction(ctx, func(tx *repository.Repository) error {
+		u, err := getUser(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(expectedVersion, u.Version); err != nil {
+			return err
+		}
+
+		refs, err := tx.PRs.CountByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if refs > 0 {
+			return NewErr(ErrorCodeResourceInUse, "user is referenced by pull requests, deactivate it instead")
+		}
+
+		ok, err := tx.Users.Delete(ctx, userID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errPreconditionFailed()
+		}
+		if _, err := tx.Digests.Delete(ctx, userID); err != nil {
+			return err
+		}
+		if err := tx.Skills.DeleteUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := tx.Exclusions.DeleteUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := bumpTeamsOf(ctx, tx, userID); err != nil {
+			return err
+		}
+		return tx.Memberships.DeleteUser(ctx, userID)
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, NewErr(ErrorCodeNotFound, "user not found")
+		return err
+	}
+
+	logger.FromContext(ctx, s.log).Info("user deleted", zap.String("user_id", userID))
+	return nil
+}
+
+// bumpTeamsOf gives every team of the user a new version, since the user is
+// part of their representation.
+func bumpTeamsOf(ctx context.Context, repo *repository.Repository, userID string) error {
+	memberships, err := repo.Memberships.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, m := range memberships {
+		if _, err := repo.Teams.BumpVersion(ctx, m.TeamName, 0); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+func (s *userService) SearchUsers(ctx context.Context, in SearchUsersInput) (*UserList, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	page := in.Page.withDefaults()
+	filter := repository.UserFilter{UsernamePrefix: in.UsernamePrefix, TeamName: in.TeamName}
+
+	reader := s.repo.Reader()
+	total, err := reader.Users.Count(ctx, filter)
+	if err != nil {
 		return nil, err
 	}
+	users, err := reader.Users.Search(ctx, filter, page.Limit, page.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return &UserList{Users: users, Total: total, Page: page}, nil
+}
+
+func (s *userService) SetNotifications(ctx context.Context, userID string, enabled bool) (*models.User, error) {
+	u, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Users.Update(ctx, userID, 0, map[string]any{"notifications_muted": !enabled}); err != nil {
+		return nil, err
+	}
+	u.NotificationsMuted = !enabled
+	u.Version++
+
+	logger.FromContext(ctx, s.log).Info("user notifications changed",
+		zap.String("user_id", u.ID),
+		zap.Bool("enabled", enabled),
+	)
+	return u, nil
+}
+
+func (s *userService) SetAutoAssign(ctx context.Context, userID string, enabled bool) (*models.User, error) {
+	u, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Users.Update(ctx, userID, 0, map[string]any{"auto_assign_opt_out": !enabled}); err != nil {
+		return nil, err
+	}
+	u.AutoAssignOptOut = !enabled
+	u.Version++
+
+	logger.FromContext(ctx, s.log).Info("user auto-assignment changed",
+		zap.String("user_id", u.ID),
+		zap.Bool("enabled", enabled),
+	)
 	return u, nil
 }
@@ -0,0 +1,386 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"reviewer_pr/internal/codeowners"
+	"reviewer_pr/internal/digest"
+	"reviewer_pr/internal/models"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxIDLength       = 64
+	MaxUsernameLength = 128
+	MaxPRNameLength   = 256
+	MaxTeamMembers    = 500
+	MaxSLA            = 365 * 24 * time.Hour
+
+	MaxCodeOwnerRules = 1000
+	MaxRuleOwners     = 50
+	MaxChangedFiles   = 3000
+	MaxFilePathLength = 1024
+
+	MaxUserSkills  = 50
+	MaxPRLabels    = 20
+	MaxProficiency = 5
+
+	MaxExclusionReason = 500
+
+	MaxSimulatedPRs = 1000
+)
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// validator collects field errors so that all problems are reported at once.
+// Field names follow the public API (snake_case, members[1].user_id).
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) bool {
+	if value == "" {
+		v.add(field, "is required")
+		return false
+	}
+	return true
+}
+
+// id checks a reference to a team, user or pull request that may already be
+// stored. Rows created before IDs were restricted can have any ID, so only
+// newID applies the format.
+func (v *validator) id(field, value string) {
+	v.required(field, value)
+}
+
+// newID checks the ID of a team, user or pull request about to be created.
+func (v *validator) newID(field, value string) {
+	if !v.required(field, value) {
+		return
+	}
+	if utf8.RuneCountInString(value) > MaxIDLength {
+		v.add(field, "must be at most %d characters", MaxIDLength)
+		return
+	}
+	if !idPattern.MatchString(value) {
+		v.add(field, "may contain only letters, digits, '.', '_' and '-' and must start with a letter or digit")
+	}
+}
+
+func (v *validator) text(field, value string, maxLen int) {
+	if !v.required(field, value) {
+		return
+	}
+	if utf8.RuneCountInString(value) > maxLen {
+		v.add(field, "must be at most %d characters", maxLen)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return NewValidationErr(v.errs...)
+}
+
+// validateID checks a single identifier passed outside of an input struct.
+func validateID(field, value string) error {
+	var v validator
+	v.id(field, value)
+	return v.err()
+}
+
+func (in CreateTeamInput) Validate() error {
+	var v validator
+	v.newID("team_name", in.TeamName)
+
+	v.members(in.Members)
+	return v.err()
+}
+
+func (in UpdateTeamInput) Validate() error {
+	var v validator
+	v.id("team_name", in.TeamName)
+	if len(in.Members) == 0 {
+		v.add("members", "is required")
+	}
+	v.members(in.Members)
+	return v.err()
+}
+
+func (in SetMembershipInput) Validate() error {
+	var v validator
+	v.id("team_name", in.TeamName)
+	v.id("user_id", in.UserID)
+	v.membershipRole("role", in.Role)
+	return v.err()
+}
+
+// membershipRole accepts an empty role, which keeps the current one.
+func (v *validator) membershipRole(field, role string) {
+	if role != "" && role != models.MembershipMember && role != models.MembershipLead {
+		v.add(field, "must be %q or %q", models.MembershipMember, models.MembershipLead)
+	}
+}
+
+func (v *validator) members(members []CreateTeamMemberInput) {
+	if len(members) > MaxTeamMembers {
+		v.add("members", "must contain at most %d members", MaxTeamMembers)
+	}
+
+	seen := make(map[string]int, len(members))
+	for i, m := range members {
+		prefix := fmt.Sprintf("members[%d]", i)
+		v.id(prefix+".user_id", m.UserID)
+		v.text(prefix+".username", m.Username, MaxUsernameLength)
+		v.membershipRole(prefix+".role", m.Role)
+
+		if m.UserID == "" {
+			continue
+		}
+		if first, dup := seen[m.UserID]; dup {
+			v.add(prefix+".user_id", "duplicates members[%d].user_id %q", first, m.UserID)
+			continue
+		}
+		seen[m.UserID] = i
+	}
+}
+
+func (in UpdateUserInput) Validate() error {
+	var v validator
+	v.id("user_id", in.UserID)
+	if in.Username != nil {
+		v.text("username", *in.Username, MaxUsernameLength)
+	}
+	if in.Username == nil && in.IsActive == nil {
+		v.add("body", "at least one of username, is_active is required")
+	}
+	return v.err()
+}
+
+func (in SearchUsersInput) Validate() error {
+	var v validator
+	if in.UsernamePrefix != "" {
+		v.text("username", in.UsernamePrefix, MaxUsernameLength)
+	}
+	if in.TeamName != "" {
+		v.id("team_name", in.TeamName)
+	}
+	if in.UsernamePrefix == "" && in.TeamName == "" {
+		v.add("query", "at least one of username, team_name is required")
+	}
+	v.page(in.Page)
+	return v.err()
+}
+
+func (in SubscribeDigestInput) Validate() error {
+	var v validator
+	v.id("user_id", in.UserID)
+	if v.required("email", in.Email) {
+		if addr, err := mail.ParseAddress(in.Email); err != nil || addr.Address != in.Email {
+			v.add("email", "must be a plain email address")
+		}
+	}
+	if v.required("send_at", in.SendAt) {
+		if _, err := time.Parse(digest.TimeOfDayLayout, in.SendAt); err != nil {
+			v.add("send_at", "must be a time of day in HH:MM format")
+		}
+	}
+	if v.required("timezone", in.Timezone) {
+		if _, err := time.LoadLocation(in.Timezone); err != nil || in.Timezone == "Local" {
+			v.add("timezone", "must be an IANA time zone name such as Europe/Moscow")
+		}
+	}
+	return v.err()
+}
+
+func (in TeamSLA) Validate() error {
+	var v validator
+	v.id("team_name", in.TeamName)
+	v.sla("review_sla_seconds", in.ReviewWithin)
+	v.sla("merge_sla_seconds", in.MergeWithin)
+	if in.AutoReassign && in.ReviewWithin == 0 {
+		v.add("auto_reassign", "requires review_sla_seconds")
+	}
+	return v.err()
+}
+
+func (v *validator) sla(field string, d time.Duration) {
+	if d < 0 || d > MaxSLA {
+		v.add(field, "must be between 0 and %d", int64(MaxSLA/time.Second))
+	}
+}
+
+func (in CreatePRInput) Validate() error {
+	var v validator
+	v.newID("pull_request_id", in.ID)
+	v.text("pull_request_name", in.Name, MaxPRNameLength)
+	v.id("author_id", in.AuthorID)
+	v.labels(in.Labels)
+	v.changedFiles(in.ChangedFiles)
+	return v.err()
+}
+
+func (v *validator) labels(labels []string) {
+	if len(labels) > MaxPRLabels {
+		v.add("labels", "must contain at most %d labels", MaxPRLabels)
+		return
+	}
+	for i, l := range labels {
+		v.tag(fmt.Sprintf("labels[%d]", i), l)
+	}
+}
+
+func (v *validator) changedFiles(files []string) {
+	if len(files) > MaxChangedFiles {
+		v.add("changed_files", "must contain at most %d paths", MaxChangedFiles)
+		return
+	}
+	for i, path := range files {
+		v.text(fmt.Sprintf("changed_files[%d]", i), codeowners.CleanPath(path), MaxFilePathLength)
+	}
+}
+
+func (in SimulateInput) Validate() error {
+	var v validator
+	v.id("author_id", in.AuthorID)
+	if in.PRID != "" {
+		v.id("pull_request_id", in.PRID)
+	}
+	v.labels(in.Labels)
+	v.changedFiles(in.ChangedFiles)
+	if in.ReviewersPerPR < 0 || in.ReviewersPerPR > MaxTeamMembers {
+		v.add("reviewers_per_pr", "must be between 0 and %d", MaxTeamMembers)
+	}
+	switch in.Strategy {
+	case "", StrategyRandom, StrategyDeterministic:
+	default:
+		v.add("strategy", "must be one of %s, %s", StrategyRandom, StrategyDeterministic)
+	}
+	if in.Count < 0 || in.Count > MaxSimulatedPRs {
+		v.add("count", "must be between 0 and %d", MaxSimulatedPRs)
+	}
+	return v.err()
+}
+
+func (in SetSkillInput) Validate() error {
+	var v validator
+	v.id("user_id", in.UserID)
+	v.tag("skill", in.Skill)
+	if in.Proficiency < 0 || in.Proficiency > MaxProficiency {
+		v.add("proficiency", "must be between 0 and %d", MaxProficiency)
+	}
+	return v.err()
+}
+
+func (in ExclusionInput) Validate() error {
+	var v validator
+	v.id("author_id", in.AuthorID)
+	v.id("reviewer_id", in.ReviewerID)
+	if in.AuthorID != "" && in.AuthorID == in.ReviewerID {
+		v.add("reviewer_id", "must differ from author_id")
+	}
+	if in.Reason != "" {
+		v.text("reason", in.Reason, MaxExclusionReason)
+	}
+	return v.err()
+}
+
+// tag checks a skill or label; callers lowercase it first.
+func (v *validator) tag(field, value string) {
+	v.id(field, value)
+}
+
+func (in TeamCodeOwners) Validate() error {
+	var v validator
+	v.id("team_name", in.TeamName)
+	if len(in.Rules) > MaxCodeOwnerRules {
+		v.add("rules", "must contain at most %d rules", MaxCodeOwnerRules)
+		return v.err()
+	}
+	for i, r := range in.Rules {
+		v.codeOwnerRule(i, r)
+	}
+	return v.err()
+}
+
+// codeOwnerRule checks the i-th rule; owners are not looked up.
+func (v *validator) codeOwnerRule(i int, r CodeOwnerRule) {
+	if _, err := codeowners.Compile(r.Pattern); err != nil {
+		v.add(r.field(i, "pattern"), "%s", err)
+	}
+	if len(r.Users)+len(r.Teams) > MaxRuleOwners {
+		v.add(r.field(i, "owners"), "must contain at most %d owners", MaxRuleOwners)
+		return
+	}
+	for j, id := range r.Users {
+		v.id(r.field(i, fmt.Sprintf("users[%d]", j)), id)
+	}
+	for j, name := range r.Teams {
+		v.id(r.field(i, fmt.Sprintf("teams[%d]", j)), name)
+	}
+}
+
+func (in UpdatePRInput) Validate() error {
+	var v validator
+	v.id("pull_request_id", in.ID)
+	if in.Name != nil {
+		v.text("pull_request_name", *in.Name, MaxPRNameLength)
+	}
+	if in.Status != nil && *in.Status != models.PRStatusMerged {
+		v.add("status", "can only be changed to %s", models.PRStatusMerged)
+	}
+	if in.Name == nil && in.Status == nil {
+		v.add("body", "at least one of pull_request_name, status is required")
+	}
+	return v.err()
+}
+
+func (in ReassignInput) Validate() error {
+	var v validator
+	v.id("pull_request_id", in.PRID)
+	v.id("old_reviewer_id", in.OldReviewerID)
+	if in.NewReviewerID != "" {
+		v.id("new_reviewer_id", in.NewReviewerID)
+	}
+	if in.RequestedBy != "" {
+		v.id("requested_by", in.RequestedBy)
+	}
+	return v.err()
+}
+
+func (in ListAssignmentEventsInput) Validate() error {
+	var v validator
+	if in.PullRequestID != "" {
+		v.id("pull_request_id", in.PullRequestID)
+	}
+	if in.UserID != "" {
+		v.id("user_id", in.UserID)
+	}
+	switch models.AssignmentEventKind(in.Kind) {
+	case "", models.AssignmentAssigned, models.AssignmentAdded, models.AssignmentRemoved, models.AssignmentReassigned:
+	default:
+		v.add("kind", "must be one of assigned, added, removed, reassigned")
+	}
+	if !in.Since.IsZero() && !in.Until.IsZero() && !in.Until.After(in.Since) {
+		v.add("until", "must be after since")
+	}
+	v.page(in.Page)
+	return v.err()
+}
+
+func (in ReviewerChangeInput) Validate() error {
+	var v validator
+	v.id("pull_request_id", in.PRID)
+	v.id("reviewer_id", in.ReviewerID)
+	if in.RequestedBy != "" {
+		v.id("requested_by", in.RequestedBy)
+	}
+	return v.err()
+}
@@ -0,0 +1,14 @@
+package service
+
+// checkVersion implements If-Match semantics: expected == 0 means the caller
+// did not send a precondition.
+func checkVersion(expected, actual int64) error {
+	if expected > 0 && expected != actual {
+		return errPreconditionFailed()
+	}
+	return nil
+}
+
+func errPreconditionFailed() *Error {
+	return NewErr(ErrorCodePreconditionFailed, "resource was modified concurrently, reload it and retry")
+}
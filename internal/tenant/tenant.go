@@ -0,0 +1,25 @@
+// Package tenant carries the organization a request acts for. Teams, users
+// and pull requests belong to one tenant and are invisible to the others.
+package tenant
+
+import "context"
+
+// Default owns the data created before tenants existed and every request
+// made without authentication.
+const Default = "default"
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx acting for tenant id.
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the tenant stored in ctx, or Default when the context
+// carries none.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
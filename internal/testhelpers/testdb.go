@@ -13,8 +13,22 @@ import (
 func SetupTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 
+	return openTestDB(t, "file::memory:?cache=shared")
+}
+
+// SetupNamedTestDB создает отдельную in-memory базу, не пересекающуюся с SetupTestDB
+// (например, для имитации реплики)
+func SetupNamedTestDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+
+	return openTestDB(t, "file:"+name+"?mode=memory&cache=shared")
+}
+
+func openTestDB(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
 	// Создаем in-memory SQLite базу данных
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
 		PrepareStmt: false,
 	})
 	if err != nil {
@@ -41,10 +55,21 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 func CleanDB(t *testing.T, db *gorm.DB) {
 	t.Helper()
 
+	db.Exec("DELETE FROM idempotency_keys")
+	db.Exec("DELETE FROM digest_subscriptions")
+	db.Exec("DELETE FROM sla_breaches")
+	db.Exec("DELETE FROM team_slas")
+	db.Exec("DELETE FROM job_runs")
+	db.Exec("DELETE FROM code_owner_rules")
+	db.Exec("DELETE FROM user_skills")
+	db.Exec("DELETE FROM reviewer_exclusions")
+	db.Exec("DELETE FROM team_memberships")
+	db.Exec("DELETE FROM assignment_events")
 	db.Exec("DELETE FROM pr_reviewers")
 	db.Exec("DELETE FROM pull_requests")
 	db.Exec("DELETE FROM users")
 	db.Exec("DELETE FROM teams")
+	db.Exec("DELETE FROM tenants WHERE tenant_id <> 'default'")
 }
 
 // CreateTestTeam создает тестовую команду с пользователями
@@ -67,6 +92,10 @@ func CreateTestTeam(t *testing.T, db *gorm.DB, teamName string, userCount int) [
 		if err := db.Create(&users[i]).Error; err != nil {
 			t.Fatalf("failed to create test user: %v", err)
 		}
+		membership := &models.TeamMembership{TeamName: teamName, UserID: users[i].ID, IsActive: true}
+		if err := db.Create(membership).Error; err != nil {
+			t.Fatalf("failed to create test membership: %v", err)
+		}
 	}
 
 	return users
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// BackupCounts is the number of exported or restored records of each kind.
+type BackupCounts struct {
+	Teams        int64 `json:"teams"`
+	Users        int64 `json:"users"`
+	Memberships  int64 `json:"memberships"`
+	Digests      int64 `json:"digest_subscriptions"`
+	TeamSLAs     int64 `json:"team_slas"`
+	CodeOwners   int64 `json:"code_owner_rules"`
+	Skills       int64 `json:"user_skills"`
+	Exclusions   int64 `json:"reviewer_exclusions"`
+	PullRequests int64 `json:"pull_requests"`
+	Reviewers    int64 `json:"reviewers"`
+	Events       int64 `json:"assignment_events"`
+}
+
+// ErrTruncatedExport means the export stream ended without its end record,
+// typically because the server failed mid-stream.
+var ErrTruncatedExport = errors.New("reviewer api: export is truncated")
+
+// Export calls GET /admin/export and copies the NDJSON stream to w. It
+// returns the counts from the end record, or ErrTruncatedExport when the
+// stream is incomplete. The WithHTTPClient timeout does not apply; use ctx
+// to bound the call.
+func (c *Client) Export(ctx context.Context, w io.Writer) (*BackupCounts, error) {
+	resp, err := c.stream(ctx, http.MethodGet, "/admin/export", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tail := &lastLine{}
+	if _, err := io.Copy(io.MultiWriter(w, tail), resp.Body); err != nil {
+		return nil, err
+	}
+
+	var end struct {
+		Type   string       `json:"type"`
+		Counts BackupCounts `json:"counts"`
+	}
+	if err := json.Unmarshal(tail.line(), &end); err != nil || end.Type != "end" {
+		return nil, ErrTruncatedExport
+	}
+	return &end.Counts, nil
+}
+
+// Restore calls POST /admin/restore, streaming an export from r into an
+// empty database. It is not retried.
+func (c *Client) Restore(ctx context.Context, r io.Reader) (*BackupCounts, error) {
+	resp, err := c.stream(ctx, http.MethodPost, "/admin/restore", "application/x-ndjson", r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Restored BackupCounts `json:"restored"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &out.Restored, nil
+}
+
+// stream sends a single request with an unbuffered body and returns the open
+// response; the caller closes it.
+func (c *Client) stream(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += path
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	hc := *c.httpClient
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return nil, decodeError(resp, data)
+	}
+	return resp, nil
+}
+
+// lastLine remembers the last non-empty line written to it.
+type lastLine struct {
+	cur  []byte
+	last []byte
+}
+
+func (l *lastLine) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			l.cur = append(l.cur, p...)
+			break
+		}
+		l.cur = append(l.cur, p[:i]...)
+		if len(bytes.TrimSpace(l.cur)) > 0 {
+			l.last = append(l.last[:0], l.cur...)
+		}
+		l.cur = l.cur[:0]
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (l *lastLine) line() []byte {
+	if len(bytes.TrimSpace(l.cur)) > 0 {
+		return l.cur
+	}
+	return l.last
+}
@@ -0,0 +1,235 @@
+// Package client is a Go SDK for the reviewer assignment HTTP API described by
+// api/openapi.yml. It is maintained by hand against the spec; the contract
+// test in test/ keeps the router and the spec in sync.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout    = 10 * time.Second
+	defaultMaxRetries = 2
+	defaultBackoff    = 200 * time.Millisecond
+	maxBackoff        = 5 * time.Second
+)
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	userAgent  string
+
+	maxRetries int
+	backoff    time.Duration
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces the default http.Client (10s timeout).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithToken sends "Authorization: Bearer <token>" with every request.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithRetry sets how many times a failed request is retried and the initial
+// backoff, which doubles on every attempt. maxRetries = 0 disables retries.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		if maxRetries >= 0 {
+			c.maxRetries = maxRetries
+		}
+		if backoff > 0 {
+			c.backoff = backoff
+		}
+	}
+}
+
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// New creates a client for the API served at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base url %q must include scheme and host", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		userAgent:  "reviewer-pr-go-client",
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// request describes one API call. in and out are JSON bodies; a nil out
+// discards the response body. raw is sent as is with contentType instead of in.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	ifMatch     int64
+	in          any
+	raw         []byte
+	contentType string
+	out         any
+}
+
+// do sends the request, retrying transport errors and retryable responses,
+// and returns the response headers. POST requests carry an Idempotency-Key,
+// so retrying them never applies a change twice.
+func (c *Client) do(ctx context.Context, r request) (http.Header, error) {
+	body := r.raw
+	if r.in != nil {
+		var err error
+		if body, err = json.Marshal(r.in); err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		r.contentType = "application/json"
+	}
+
+	var idempotencyKey string
+	if r.method == http.MethodPost {
+		idempotencyKey = newIdempotencyKey()
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		header, err := c.attempt(ctx, r, body, idempotencyKey)
+		if err == nil || attempt >= c.maxRetries || !retryable(err) {
+			return header, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, r request, body []byte, idempotencyKey string) (http.Header, error) {
+	u := *c.baseURL
+	u.Path += r.path
+	u.RawQuery = r.query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	if r.ifMatch > 0 {
+		req.Header.Set("If-Match", formatETag(r.ifMatch))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+
+	if resp.StatusCode >= 300 {
+		return nil, decodeError(resp, data)
+	}
+	if r.out == nil || len(data) == 0 {
+		return resp.Header, nil
+	}
+	if err := json.Unmarshal(data, r.out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return resp.Header, nil
+}
+
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// versionFrom parses the ETag header; the API uses the resource version as
+// the entity tag.
+func versionFrom(h http.Header) int64 {
+	tag := strings.TrimPrefix(h.Get("ETag"), "W/")
+	v, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// transportError marks failures where the request may not have reached the server.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var te *transportError
+	if errors.As(err, &te) {
+		return true
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return apiErr.Code == CodeIdempotencyInProgress
+	}
+	return false
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
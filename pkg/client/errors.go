@@ -0,0 +1,125 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode mirrors ErrorResponse.error.code in the OpenAPI spec.
+type ErrorCode string
+
+const (
+	CodeTeamExists            ErrorCode = "TEAM_EXISTS"
+	CodePRExists              ErrorCode = "PR_EXISTS"
+	CodePRMerged              ErrorCode = "PR_MERGED"
+	CodeNotAssigned           ErrorCode = "NOT_ASSIGNED"
+	CodeNoCandidate           ErrorCode = "NO_CANDIDATE"
+	CodeNotFound              ErrorCode = "NOT_FOUND"
+	CodeValidation            ErrorCode = "VALIDATION_ERROR"
+	CodeInvalidRequest        ErrorCode = "INVALID_REQUEST"
+	CodeInternal              ErrorCode = "INTERNAL"
+	CodeUnauthorized          ErrorCode = "UNAUTHORIZED"
+	CodeForbidden             ErrorCode = "FORBIDDEN"
+	CodePreconditionFailed    ErrorCode = "PRECONDITION_FAILED"
+	CodeResourceInUse         ErrorCode = "RESOURCE_IN_USE"
+	CodeIdempotencyKeyReused  ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyInProgress ErrorCode = "IDEMPOTENCY_IN_PROGRESS"
+	CodeAlreadyAssigned       ErrorCode = "ALREADY_ASSIGNED"
+	CodeReviewerNotAllowed    ErrorCode = "REVIEWER_NOT_ALLOWED"
+	CodeReviewerLimit         ErrorCode = "REVIEWER_LIMIT"
+)
+
+// Sentinel errors for errors.Is; they match any *Error with the same code.
+var (
+	ErrTeamExists   = &Error{Code: CodeTeamExists}
+	ErrPRExists     = &Error{Code: CodePRExists}
+	ErrPRMerged     = &Error{Code: CodePRMerged}
+	ErrNotAssigned  = &Error{Code: CodeNotAssigned}
+	ErrNoCandidate  = &Error{Code: CodeNoCandidate}
+	ErrNotFound     = &Error{Code: CodeNotFound}
+	ErrValidation   = &Error{Code: CodeValidation}
+	ErrUnauthorized = &Error{Code: CodeUnauthorized}
+	ErrForbidden    = &Error{Code: CodeForbidden}
+
+	ErrInvalidRequest = &Error{Code: CodeInvalidRequest}
+
+	ErrPreconditionFailed = &Error{Code: CodePreconditionFailed}
+	ErrResourceInUse      = &Error{Code: CodeResourceInUse}
+
+	ErrAlreadyAssigned    = &Error{Code: CodeAlreadyAssigned}
+	ErrReviewerNotAllowed = &Error{Code: CodeReviewerNotAllowed}
+	ErrReviewerLimit      = &Error{Code: CodeReviewerLimit}
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is returned for every non-2xx response.
+type Error struct {
+	StatusCode int
+	Code       ErrorCode
+	Message    string
+	Details    []FieldError
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("reviewer api: %d %s", e.StatusCode, e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request_id " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, client.ErrNotFound) match by code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    ErrorCode    `json:"code"`
+		Message string       `json:"message"`
+		Details []FieldError `json:"details"`
+	} `json:"error"`
+	RequestID string `json:"request_id"`
+}
+
+func decodeError(resp *http.Response, body []byte) error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	var er errorResponse
+	if err := json.Unmarshal(body, &er); err == nil && er.Error.Code != "" {
+		apiErr.Code = er.Error.Code
+		apiErr.Message = er.Error.Message
+		apiErr.Details = er.Error.Details
+		if er.RequestID != "" {
+			apiErr.RequestID = er.RequestID
+		}
+		return apiErr
+	}
+
+	// Not an API error body (e.g. a proxy page): derive the code from the status.
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		apiErr.Code = CodeUnauthorized
+	case http.StatusForbidden:
+		apiErr.Code = CodeForbidden
+	case http.StatusNotFound:
+		apiErr.Code = CodeNotFound
+	default:
+		apiErr.Code = CodeInternal
+	}
+	apiErr.Message = http.StatusText(resp.StatusCode)
+	return apiErr
+}
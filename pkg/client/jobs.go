@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type JobRun struct {
+	RunID       uint64     `json:"run_id"`
+	Job         string     `json:"job"`
+	Trigger     string     `json:"trigger"`
+	Status      string     `json:"status"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+	Error       string     `json:"error,omitempty"`
+}
+
+type Job struct {
+	Job       string     `json:"job"`
+	Schedule  string     `json:"schedule"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	Running   bool       `json:"running"`
+	LastRun   *JobRun    `json:"last_run,omitempty"`
+}
+
+// JobRunsQuery filters ListJobRuns; an empty Job means every job.
+type JobRunsQuery struct {
+	Job string
+	Page
+}
+
+type JobRunList struct {
+	Runs   []JobRun `json:"runs"`
+	Total  int64    `json:"total"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+}
+
+// ListJobs calls GET /admin/jobs. Requires an admin token when auth is enabled.
+func (c *Client) ListJobs(ctx context.Context) ([]Job, error) {
+	var out struct {
+		Jobs []Job `json:"jobs"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/admin/jobs", out: &out}); err != nil {
+		return nil, err
+	}
+	return out.Jobs, nil
+}
+
+// ListJobRuns calls GET /admin/jobs/runs.
+func (c *Client) ListJobRuns(ctx context.Context, q JobRunsQuery) (*JobRunList, error) {
+	v := url.Values{}
+	if q.Job != "" {
+		v.Set("job", q.Job)
+	}
+
+	var out JobRunList
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/admin/jobs/runs", query: q.query(v), out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TriggerJob calls POST /admin/jobs/trigger and returns the started run; the
+// job keeps running on the server.
+func (c *Client) TriggerJob(ctx context.Context, job string) (*JobRun, error) {
+	in := struct {
+		Job string `json:"job"`
+	}{job}
+
+	var out JobRun
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/admin/jobs/trigger", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,455 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type TeamMember struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+	// Role and MembershipActive describe the membership in the team. When
+	// adding members, empty values keep those of an existing membership.
+	Role             string `json:"role,omitempty"`
+	MembershipActive *bool  `json:"membership_active,omitempty"`
+}
+
+// TeamMembership is the input of SetTeamMember; empty Role and nil IsActive
+// keep the current values.
+type TeamMembership struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+	Role     string `json:"role,omitempty"`
+	IsActive *bool  `json:"is_active,omitempty"`
+}
+
+type Team struct {
+	TeamName string       `json:"team_name"`
+	Members  []TeamMember `json:"members"`
+
+	// Version is the resource ETag; it is only filled in by the V2 methods.
+	Version int64 `json:"-"`
+}
+
+type User struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+	// AutoAssignOptOut is set for users who are never picked automatically.
+	AutoAssignOptOut bool `json:"auto_assign_opt_out,omitempty"`
+
+	// Version is the resource ETag; it is only filled in by the V2 methods.
+	Version int64 `json:"-"`
+}
+
+// Page selects part of a list. Zero values mean the server defaults.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+func (p Page) query(q url.Values) url.Values {
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	return q
+}
+
+type TeamSummary struct {
+	TeamName          string `json:"team_name"`
+	MemberCount       int64  `json:"member_count"`
+	ActiveMemberCount int64  `json:"active_member_count"`
+	OpenPRCount       int64  `json:"open_pr_count"`
+}
+
+type TeamList struct {
+	Teams  []TeamSummary `json:"teams"`
+	Total  int64         `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+// UserSearch filters SearchUsers; at least one of Username (a prefix) and
+// TeamName is required.
+type UserSearch struct {
+	Username string
+	TeamName string
+	Page
+}
+
+type UserList struct {
+	Users  []User `json:"users"`
+	Total  int64  `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+type PullRequestStatus string
+
+const (
+	StatusOpen   PullRequestStatus = "OPEN"
+	StatusMerged PullRequestStatus = "MERGED"
+)
+
+type PullRequest struct {
+	PullRequestID     string            `json:"pull_request_id"`
+	PullRequestName   string            `json:"pull_request_name"`
+	AuthorID          string            `json:"author_id"`
+	Status            PullRequestStatus `json:"status"`
+	AssignedReviewers []string          `json:"assigned_reviewers"`
+	Labels            []string          `json:"labels,omitempty"`
+	CreatedAt         *time.Time        `json:"createdAt,omitempty"`
+	MergedAt          *time.Time        `json:"mergedAt,omitempty"`
+
+	// Version is the resource ETag; it is only filled in by the V2 methods.
+	Version int64 `json:"-"`
+}
+
+type PullRequestShort struct {
+	PullRequestID   string            `json:"pull_request_id"`
+	PullRequestName string            `json:"pull_request_name"`
+	AuthorID        string            `json:"author_id"`
+	Status          PullRequestStatus `json:"status"`
+}
+
+type CreatePullRequest struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	// ChangedFiles are optional; the code owners of these paths are
+	// assigned first.
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// Labels are optional; reviewers whose skills match them are preferred.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// CreatedPullRequest is a new pull request with the reasons each reviewer
+// was picked, in assignment order.
+type CreatedPullRequest struct {
+	PullRequest PullRequest    `json:"pr"`
+	Assignment  []ReviewerPick `json:"assignment"`
+	// Skipped are the candidates left out by exclusion rules.
+	Skipped []SkippedCandidate `json:"skipped"`
+}
+
+type ReviewerPick struct {
+	UserID string `json:"user_id"`
+	// Reason is "code_owner", "skills" or "team".
+	Reason        string  `json:"reason"`
+	Pattern       string  `json:"pattern,omitempty"`
+	MatchedSkills []Skill `json:"matched_skills,omitempty"`
+	OpenReviews   int64   `json:"open_reviews"`
+}
+
+// Skill is an area of expertise; Proficiency runs from 1 to 5 and is 0 when
+// not specified.
+type Skill struct {
+	Skill       string `json:"skill"`
+	Proficiency int    `json:"proficiency,omitempty"`
+}
+
+type UserSkills struct {
+	UserID string  `json:"user_id"`
+	Skills []Skill `json:"skills"`
+}
+
+type SkippedCandidate struct {
+	UserID string `json:"user_id"`
+	// Reason is "opted_out" or "excluded".
+	Reason string `json:"reason"`
+}
+
+// SimulateAssignment describes hypothetical pull requests for
+// SimulateAssignment. Zero fields fall back to the server configuration.
+type SimulateAssignment struct {
+	AuthorID       string   `json:"author_id"`
+	PullRequestID  string   `json:"pull_request_id,omitempty"`
+	ChangedFiles   []string `json:"changed_files,omitempty"`
+	Labels         []string `json:"labels,omitempty"`
+	ReviewersPerPR int      `json:"reviewers_per_pr,omitempty"`
+	// Strategy is "random" or "deterministic".
+	Strategy string `json:"strategy,omitempty"`
+	Seed     int64  `json:"seed,omitempty"`
+	// Count is the number of pull requests to replay, one by default.
+	Count int `json:"count,omitempty"`
+}
+
+// Simulation is a dry run of automatic assignment: the candidates of the
+// first pull request, its reviewers and the load after all of them.
+type Simulation struct {
+	AuthorID   string               `json:"author_id"`
+	Strategy   string               `json:"strategy"`
+	Seed       int64                `json:"seed,omitempty"`
+	Count      int                  `json:"count"`
+	Candidates []SimulatedCandidate `json:"candidates"`
+	Assignment []ReviewerPick       `json:"assignment"`
+	Load       []ProjectedLoad      `json:"load"`
+}
+
+type SimulatedCandidate struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	// Status is "picked", "capacity", "author", "inactive", "opted_out"
+	// or "excluded".
+	Status      string `json:"status"`
+	OpenReviews int64  `json:"open_reviews"`
+}
+
+// ProjectedLoad is a candidate's open reviews now, the simulated pull
+// requests it was picked for, and their sum.
+type ProjectedLoad struct {
+	UserID      string `json:"user_id"`
+	OpenReviews int64  `json:"open_reviews"`
+	Assigned    int    `json:"assigned"`
+	Projected   int64  `json:"projected"`
+}
+
+// Exclusion forbids picking ReviewerID as a reviewer of AuthorID's pull
+// requests.
+type Exclusion struct {
+	AuthorID   string    `json:"author_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+}
+
+// ReviewerReassign replaces OldReviewerID with NewReviewerID or, when it is
+// empty, with a reviewer the service picks.
+type ReviewerReassign struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	// RequestedBy is recorded in the assignment history.
+	RequestedBy string `json:"requested_by,omitempty"`
+}
+
+// ReviewerChange names a reviewer to add to or remove from a pull request.
+type ReviewerChange struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	// RequestedBy is recorded in the assignment history.
+	RequestedBy string `json:"requested_by,omitempty"`
+}
+
+type AssignmentHistory struct {
+	PullRequestID string            `json:"pull_request_id"`
+	Events        []AssignmentEvent `json:"events"`
+}
+
+// AssignmentEvent is one change of a pull request's reviewers. Kind is
+// "assigned", "added", "removed" or "reassigned"; Reason is "code_owner",
+// "skills", "team" or "manual". Strategy ("random" or "deterministic") and
+// Seed are set for automatic picks so that they can be replayed.
+type AssignmentEvent struct {
+	// PullRequestID is only set in the audit log.
+	PullRequestID      string    `json:"pull_request_id,omitempty"`
+	Kind               string    `json:"kind"`
+	ReviewerID         string    `json:"reviewer_id"`
+	PreviousReviewerID string    `json:"previous_reviewer_id,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	RequestedBy        string    `json:"requested_by,omitempty"`
+	Strategy           string    `json:"strategy,omitempty"`
+	Seed               int64     `json:"seed,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// AuditQuery filters ListAssignmentEvents; zero fields match everything.
+type AuditQuery struct {
+	PullRequestID string
+	// UserID matches the reviewer, the replaced reviewer and the requester.
+	UserID string
+	// Kind is "assigned", "added", "removed" or "reassigned".
+	Kind string
+	// Since is inclusive, Until exclusive.
+	Since time.Time
+	Until time.Time
+	Page
+}
+
+type AuditLog struct {
+	Events []AssignmentEvent `json:"events"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+type Reassignment struct {
+	PullRequest PullRequest `json:"pr"`
+	ReplacedBy  string      `json:"replaced_by"`
+	// Reason is "skills", "team" or "manual".
+	Reason  string             `json:"reason"`
+	Skipped []SkippedCandidate `json:"skipped"`
+}
+
+type UserReviews struct {
+	UserID       string             `json:"user_id"`
+	PullRequests []PullRequestShort `json:"pull_requests"`
+}
+
+// DigestSubscription is a user's daily email digest; Enabled is false when the
+// user is not subscribed. Empty SendAt and Timezone mean 09:00 UTC.
+type DigestSubscription struct {
+	UserID     string     `json:"user_id"`
+	Enabled    bool       `json:"enabled"`
+	Email      string     `json:"email,omitempty"`
+	SendAt     string     `json:"send_at,omitempty"`
+	Timezone   string     `json:"timezone,omitempty"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+}
+
+type DigestItem struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	AuthorName      string    `json:"author_name"`
+	CreatedAt       time.Time `json:"created_at"`
+	AgeSeconds      int64     `json:"age_seconds"`
+}
+
+type DigestPreview struct {
+	UserID       string       `json:"user_id"`
+	Timezone     string       `json:"timezone"`
+	Subject      string       `json:"subject"`
+	Text         string       `json:"text"`
+	HTML         string       `json:"html"`
+	PullRequests []DigestItem `json:"pull_requests"`
+}
+
+// TeamSLA limits are in seconds; zero means no limit.
+type TeamSLA struct {
+	TeamName         string `json:"team_name"`
+	ReviewSLASeconds int64  `json:"review_sla_seconds"`
+	MergeSLASeconds  int64  `json:"merge_sla_seconds"`
+	AutoReassign     bool   `json:"auto_reassign"`
+}
+
+// CodeOwners are a team's ownership rules; the last rule matching a file
+// decides its owners.
+type CodeOwners struct {
+	TeamName string          `json:"team_name"`
+	Rules    []CodeOwnerRule `json:"rules"`
+}
+
+type CodeOwnerRule struct {
+	Pattern string   `json:"pattern"`
+	Users   []string `json:"users"`
+	Teams   []string `json:"teams"`
+}
+
+type SLABreach struct {
+	Kind           string    `json:"kind"`
+	ReviewerID     string    `json:"reviewer_id,omitempty"`
+	Deadline       time.Time `json:"deadline"`
+	OverdueSeconds int64     `json:"overdue_seconds"`
+}
+
+type OverduePullRequest struct {
+	PullRequestShort
+	TeamName  string      `json:"team_name"`
+	CreatedAt time.Time   `json:"created_at"`
+	Breaches  []SLABreach `json:"breaches"`
+}
+
+// OverdueQuery filters ListOverdue; an empty TeamName means every team.
+type OverdueQuery struct {
+	TeamName string
+	Page
+}
+
+type OverdueList struct {
+	PullRequests []OverduePullRequest `json:"pull_requests"`
+	Total        int64                `json:"total"`
+	Limit        int                  `json:"limit"`
+	Offset       int                  `json:"offset"`
+}
+
+type UserStats struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	TeamName    string `json:"team_name"`
+	ReviewCount int64  `json:"review_count"`
+}
+
+type PRStats struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerCount int64  `json:"reviewer_count"`
+}
+
+type Stats struct {
+	ByUser []UserStats `json:"by_user"`
+	ByPR   []PRStats   `json:"by_pr"`
+}
+
+// Reviewers is the reviewer list of a pull request in API v2.
+type Reviewers struct {
+	PullRequestID string   `json:"pull_request_id"`
+	Reviewers     []string `json:"reviewers"`
+	ReplacedBy    string   `json:"replaced_by,omitempty"`
+
+	// Version is the ETag of the pull request.
+	Version int64 `json:"-"`
+}
+
+// UpdateUser lists the user fields to change; nil fields are left as is.
+type UpdateUser struct {
+	Username *string `json:"username,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+// UpdatePullRequest lists the pull request fields to change; nil fields are left as is.
+type UpdatePullRequest struct {
+	PullRequestName *string            `json:"pull_request_name,omitempty"`
+	Status          *PullRequestStatus `json:"status,omitempty"`
+}
+
+// ImportFormat is the Content-Type of an import file.
+type ImportFormat string
+
+const (
+	ImportCSV  ImportFormat = "text/csv"
+	ImportYAML ImportFormat = "application/yaml"
+	ImportJSON ImportFormat = "application/json"
+)
+
+type ImportOptions struct {
+	// DryRun only reports the changes without applying them.
+	DryRun bool
+	// KeepMissing leaves users that are not in the file untouched instead of
+	// deactivating them. Without it only users whose teams are all in the
+	// file are deactivated.
+	KeepMissing bool
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type ImportChange struct {
+	Line     int           `json:"line,omitempty"`
+	Action   string        `json:"action"`
+	TeamName string        `json:"team_name"`
+	UserID   string        `json:"user_id,omitempty"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+}
+
+type ImportSummary struct {
+	TeamsCreated     int `json:"teams_created"`
+	UsersCreated     int `json:"users_created"`
+	UsersUpdated     int `json:"users_updated"`
+	UsersDeactivated int `json:"users_deactivated"`
+	Unchanged        int `json:"unchanged"`
+}
+
+type ImportResult struct {
+	DryRun  bool           `json:"dry_run"`
+	Summary ImportSummary  `json:"summary"`
+	Changes []ImportChange `json:"changes"`
+}
@@ -0,0 +1,500 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AddTeam calls POST /team/add. Requires an admin token when auth is enabled.
+func (c *Client) AddTeam(ctx context.Context, team Team) (*Team, error) {
+	var out struct {
+		Team Team `json:"team"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/team/add", in: team, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out.Team, nil
+}
+
+// GetTeam calls GET /team/get.
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*Team, error) {
+	var out Team
+	q := url.Values{"team_name": {teamName}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/team/get", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetTeamMember calls POST /team/setMember and returns the team. Requires an
+// admin token when auth is enabled.
+func (c *Client) SetTeamMember(ctx context.Context, m TeamMembership) (*Team, error) {
+	var out struct {
+		Team Team `json:"team"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/team/setMember", in: m, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out.Team, nil
+}
+
+// RemoveTeamMember calls POST /team/removeMember and returns the team. The
+// user's primary team fails with ErrResourceInUse.
+func (c *Client) RemoveTeamMember(ctx context.Context, teamName, userID string) (*Team, error) {
+	in := struct {
+		TeamName string `json:"team_name"`
+		UserID   string `json:"user_id"`
+	}{teamName, userID}
+
+	var out struct {
+		Team Team `json:"team"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/team/removeMember", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out.Team, nil
+}
+
+// ListTeams calls GET /team/list.
+func (c *Client) ListTeams(ctx context.Context, page Page) (*TeamList, error) {
+	var out TeamList
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/team/list", query: page.query(url.Values{}), out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportTeams calls POST /team/import with the file contents in data.
+// Requires an admin token when auth is enabled.
+func (c *Client) ImportTeams(ctx context.Context, format ImportFormat, data []byte, opts ImportOptions) (*ImportResult, error) {
+	q := url.Values{}
+	if opts.DryRun {
+		q.Set("dry_run", "true")
+	}
+	if opts.KeepMissing {
+		q.Set("deactivate_missing", "false")
+	}
+
+	var out ImportResult
+	if _, err := c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        "/team/import",
+		query:       q,
+		raw:         data,
+		contentType: string(format),
+		out:         &out,
+	}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCodeOwners calls GET /team/codeOwners.
+func (c *Client) GetCodeOwners(ctx context.Context, teamName string) (*CodeOwners, error) {
+	var out CodeOwners
+	q := url.Values{"team_name": {teamName}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/team/codeOwners", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetCodeOwners calls POST /team/setCodeOwners. Requires an admin token when
+// auth is enabled.
+func (c *Client) SetCodeOwners(ctx context.Context, owners CodeOwners) (*CodeOwners, error) {
+	var out CodeOwners
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/team/setCodeOwners", in: owners, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportCodeOwners calls POST /team/importCodeOwners with the contents of a
+// CODEOWNERS file. Requires an admin token when auth is enabled.
+func (c *Client) ImportCodeOwners(ctx context.Context, teamName string, data []byte) (*CodeOwners, error) {
+	var out CodeOwners
+	if _, err := c.do(ctx, request{
+		method:      http.MethodPost,
+		path:        "/team/importCodeOwners",
+		query:       url.Values{"team_name": {teamName}},
+		raw:         data,
+		contentType: "text/plain",
+		out:         &out,
+	}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTeamSLA calls GET /team/sla.
+func (c *Client) GetTeamSLA(ctx context.Context, teamName string) (*TeamSLA, error) {
+	var out TeamSLA
+	q := url.Values{"team_name": {teamName}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/team/sla", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetTeamSLA calls POST /team/setSla. Requires an admin token when auth is enabled.
+func (c *Client) SetTeamSLA(ctx context.Context, sla TeamSLA) (*TeamSLA, error) {
+	var out TeamSLA
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/team/setSla", in: sla, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetIsActive calls POST /users/setIsActive. Requires an admin token when auth is enabled.
+func (c *Client) SetIsActive(ctx context.Context, userID string, isActive bool) (*User, error) {
+	in := struct {
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
+	}{userID, isActive}
+
+	var out struct {
+		User User `json:"user"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/setIsActive", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out.User, nil
+}
+
+// SetNotifications calls POST /users/setNotifications and returns whether
+// notifications are now enabled for the user.
+func (c *Client) SetNotifications(ctx context.Context, userID string, enabled bool) (bool, error) {
+	in := struct {
+		UserID  string `json:"user_id"`
+		Enabled bool   `json:"enabled"`
+	}{userID, enabled}
+
+	var out struct {
+		NotificationsEnabled bool `json:"notifications_enabled"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/setNotifications", in: in, out: &out}); err != nil {
+		return false, err
+	}
+	return out.NotificationsEnabled, nil
+}
+
+// GetDigest calls GET /users/digest.
+func (c *Client) GetDigest(ctx context.Context, userID string) (*DigestSubscription, error) {
+	var out DigestSubscription
+	q := url.Values{"user_id": {userID}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/digest", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetDigest calls POST /users/setDigest. With s.Enabled false it
+// unsubscribes the user and ignores the other fields.
+func (c *Client) SetDigest(ctx context.Context, s DigestSubscription) (*DigestSubscription, error) {
+	in := struct {
+		UserID   string `json:"user_id"`
+		Enabled  bool   `json:"enabled"`
+		Email    string `json:"email,omitempty"`
+		SendAt   string `json:"send_at,omitempty"`
+		Timezone string `json:"timezone,omitempty"`
+	}{s.UserID, s.Enabled, s.Email, s.SendAt, s.Timezone}
+
+	var out DigestSubscription
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/setDigest", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewDigest calls GET /users/digestPreview; nothing is sent.
+func (c *Client) PreviewDigest(ctx context.Context, userID string) (*DigestPreview, error) {
+	var out DigestPreview
+	q := url.Values{"user_id": {userID}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/digestPreview", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUser calls GET /users/get.
+func (c *Client) GetUser(ctx context.Context, userID string) (*User, error) {
+	var out User
+	q := url.Values{"user_id": {userID}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/get", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchUsers calls GET /users/search.
+func (c *Client) SearchUsers(ctx context.Context, s UserSearch) (*UserList, error) {
+	q := url.Values{}
+	if s.Username != "" {
+		q.Set("username", s.Username)
+	}
+	if s.TeamName != "" {
+		q.Set("team_name", s.TeamName)
+	}
+
+	var out UserList
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/search", query: s.query(q), out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReviews calls GET /users/getReview.
+func (c *Client) GetReviews(ctx context.Context, userID string) (*UserReviews, error) {
+	var out UserReviews
+	q := url.Values{"user_id": {userID}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/getReview", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSkills calls GET /users/skills.
+func (c *Client) GetSkills(ctx context.Context, userID string) (*UserSkills, error) {
+	var out UserSkills
+	q := url.Values{"user_id": {userID}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/skills", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetSkill calls POST /users/setSkill; a zero proficiency leaves it
+// unspecified.
+func (c *Client) SetSkill(ctx context.Context, userID string, skill Skill) (*UserSkills, error) {
+	in := struct {
+		UserID string `json:"user_id"`
+		Skill
+	}{userID, skill}
+
+	var out UserSkills
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/setSkill", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveSkill calls POST /users/removeSkill.
+func (c *Client) RemoveSkill(ctx context.Context, userID, skill string) (*UserSkills, error) {
+	in := struct {
+		UserID string `json:"user_id"`
+		Skill  string `json:"skill"`
+	}{userID, skill}
+
+	var out UserSkills
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/removeSkill", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetAutoAssign calls POST /users/setAutoAssign and returns whether the user
+// can now be picked as a reviewer automatically.
+func (c *Client) SetAutoAssign(ctx context.Context, userID string, enabled bool) (bool, error) {
+	in := struct {
+		UserID  string `json:"user_id"`
+		Enabled bool   `json:"enabled"`
+	}{userID, enabled}
+
+	var out struct {
+		AutoAssign bool `json:"auto_assign"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/setAutoAssign", in: in, out: &out}); err != nil {
+		return false, err
+	}
+	return out.AutoAssign, nil
+}
+
+// GetExclusions calls GET /users/exclusions; an empty userID lists every
+// exclusion.
+func (c *Client) GetExclusions(ctx context.Context, userID string) ([]Exclusion, error) {
+	var q url.Values
+	if userID != "" {
+		q = url.Values{"user_id": {userID}}
+	}
+	var out exclusionList
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/users/exclusions", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return out.Exclusions, nil
+}
+
+// AddExclusion calls POST /users/addExclusion and returns the exclusions
+// involving the author.
+func (c *Client) AddExclusion(ctx context.Context, e Exclusion) ([]Exclusion, error) {
+	in := struct {
+		AuthorID   string `json:"author_id"`
+		ReviewerID string `json:"reviewer_id"`
+		Reason     string `json:"reason,omitempty"`
+	}{e.AuthorID, e.ReviewerID, e.Reason}
+
+	var out exclusionList
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/addExclusion", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return out.Exclusions, nil
+}
+
+// RemoveExclusion calls POST /users/removeExclusion and returns the
+// exclusions involving the author.
+func (c *Client) RemoveExclusion(ctx context.Context, authorID, reviewerID string) ([]Exclusion, error) {
+	in := struct {
+		AuthorID   string `json:"author_id"`
+		ReviewerID string `json:"reviewer_id"`
+	}{authorID, reviewerID}
+
+	var out exclusionList
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/users/removeExclusion", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return out.Exclusions, nil
+}
+
+type exclusionList struct {
+	Exclusions []Exclusion `json:"exclusions"`
+}
+
+// CreatePullRequest calls POST /pullRequest/create.
+func (c *Client) CreatePullRequest(ctx context.Context, in CreatePullRequest) (*PullRequest, error) {
+	out, err := c.CreatePullRequestWithReasons(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return &out.PullRequest, nil
+}
+
+// CreatePullRequestWithReasons calls POST /pullRequest/create and also
+// returns why each reviewer was picked.
+func (c *Client) CreatePullRequestWithReasons(ctx context.Context, in CreatePullRequest) (*CreatedPullRequest, error) {
+	var out CreatedPullRequest
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/pullRequest/create", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MergePullRequest calls POST /pullRequest/merge.
+func (c *Client) MergePullRequest(ctx context.Context, pullRequestID string) (*PullRequest, error) {
+	in := struct {
+		PullRequestID string `json:"pull_request_id"`
+	}{pullRequestID}
+
+	var out struct {
+		PR PullRequest `json:"pr"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/pullRequest/merge", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out.PR, nil
+}
+
+// ReassignReviewer calls POST /pullRequest/reassign and lets the service pick
+// the replacement.
+func (c *Client) ReassignReviewer(ctx context.Context, pullRequestID, oldReviewerID string) (*Reassignment, error) {
+	return c.ReassignReviewerTo(ctx, ReviewerReassign{PullRequestID: pullRequestID, OldReviewerID: oldReviewerID})
+}
+
+// ReassignReviewerTo calls POST /pullRequest/reassign.
+func (c *Client) ReassignReviewerTo(ctx context.Context, in ReviewerReassign) (*Reassignment, error) {
+	var out Reassignment
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/pullRequest/reassign", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddReviewer calls POST /pullRequest/addReviewer.
+func (c *Client) AddReviewer(ctx context.Context, in ReviewerChange) (*PullRequest, error) {
+	return c.changeReviewer(ctx, "/pullRequest/addReviewer", in)
+}
+
+// RemoveReviewer calls POST /pullRequest/removeReviewer.
+func (c *Client) RemoveReviewer(ctx context.Context, in ReviewerChange) (*PullRequest, error) {
+	return c.changeReviewer(ctx, "/pullRequest/removeReviewer", in)
+}
+
+func (c *Client) changeReviewer(ctx context.Context, path string, in ReviewerChange) (*PullRequest, error) {
+	var out struct {
+		PR PullRequest `json:"pr"`
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: path, in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out.PR, nil
+}
+
+// GetAssignmentHistory calls GET /pullRequest/history.
+func (c *Client) GetAssignmentHistory(ctx context.Context, pullRequestID string) (*AssignmentHistory, error) {
+	var out AssignmentHistory
+	q := url.Values{"pull_request_id": {pullRequestID}}
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/pullRequest/history", query: q, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAssignmentEvents calls GET /admin/audit and returns the reviewer changes
+// of every pull request, newest first. Requires an admin token when auth is
+// enabled.
+func (c *Client) ListAssignmentEvents(ctx context.Context, q AuditQuery) (*AuditLog, error) {
+	v := url.Values{}
+	if q.PullRequestID != "" {
+		v.Set("pull_request_id", q.PullRequestID)
+	}
+	if q.UserID != "" {
+		v.Set("user_id", q.UserID)
+	}
+	if q.Kind != "" {
+		v.Set("kind", q.Kind)
+	}
+	if !q.Since.IsZero() {
+		v.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		v.Set("until", q.Until.Format(time.RFC3339))
+	}
+
+	var out AuditLog
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/admin/audit", query: q.query(v), out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SimulateAssignment calls POST /pullRequest/simulate; nothing is written.
+func (c *Client) SimulateAssignment(ctx context.Context, in SimulateAssignment) (*Simulation, error) {
+	var out Simulation
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/pullRequest/simulate", in: in, out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListOverdue calls GET /pullRequest/overdue.
+func (c *Client) ListOverdue(ctx context.Context, q OverdueQuery) (*OverdueList, error) {
+	v := url.Values{}
+	if q.TeamName != "" {
+		v.Set("team_name", q.TeamName)
+	}
+
+	var out OverdueList
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/pullRequest/overdue", query: q.query(v), out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStats calls GET /stats.
+func (c *Client) GetStats(ctx context.Context) (*Stats, error) {
+	var out Stats
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: "/stats", out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// The V2 methods call the resource API under /api/v2. Reads return the
+// resource version in the Version field; writes take the version the caller
+// last saw and fail with ErrPreconditionFailed if the resource has changed
+// since. Passing version 0 makes the write unconditional.
+
+const v2Prefix = "/api/v2"
+
+// V2CreateTeam calls POST /api/v2/teams. Requires an admin token when auth is enabled.
+func (c *Client) V2CreateTeam(ctx context.Context, team Team) (*Team, error) {
+	var out Team
+	h, err := c.do(ctx, request{method: http.MethodPost, path: v2Prefix + "/teams", in: team, out: &out})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2GetTeam calls GET /api/v2/teams/{name}.
+func (c *Client) V2GetTeam(ctx context.Context, name string) (*Team, error) {
+	var out Team
+	h, err := c.do(ctx, request{method: http.MethodGet, path: v2Prefix + "/teams/" + url.PathEscape(name), out: &out})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2UpdateTeam calls PATCH /api/v2/teams/{name}, replacing the member list.
+func (c *Client) V2UpdateTeam(ctx context.Context, name string, members []TeamMember, version int64) (*Team, error) {
+	in := struct {
+		Members []TeamMember `json:"members"`
+	}{members}
+
+	var out Team
+	h, err := c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    v2Prefix + "/teams/" + url.PathEscape(name),
+		ifMatch: version,
+		in:      in,
+		out:     &out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2DeleteTeam calls DELETE /api/v2/teams/{name}.
+func (c *Client) V2DeleteTeam(ctx context.Context, name string, version int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: v2Prefix + "/teams/" + url.PathEscape(name), ifMatch: version})
+	return err
+}
+
+// V2GetUser calls GET /api/v2/users/{id}.
+func (c *Client) V2GetUser(ctx context.Context, userID string) (*User, error) {
+	var out User
+	h, err := c.do(ctx, request{method: http.MethodGet, path: v2Prefix + "/users/" + url.PathEscape(userID), out: &out})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2UpdateUser calls PATCH /api/v2/users/{id}.
+func (c *Client) V2UpdateUser(ctx context.Context, userID string, in UpdateUser, version int64) (*User, error) {
+	var out User
+	h, err := c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    v2Prefix + "/users/" + url.PathEscape(userID),
+		ifMatch: version,
+		in:      in,
+		out:     &out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2DeleteUser calls DELETE /api/v2/users/{id}.
+func (c *Client) V2DeleteUser(ctx context.Context, userID string, version int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: v2Prefix + "/users/" + url.PathEscape(userID), ifMatch: version})
+	return err
+}
+
+// V2CreatePullRequest calls POST /api/v2/pull-requests.
+func (c *Client) V2CreatePullRequest(ctx context.Context, in CreatePullRequest) (*PullRequest, error) {
+	var out PullRequest
+	h, err := c.do(ctx, request{method: http.MethodPost, path: v2Prefix + "/pull-requests", in: in, out: &out})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2GetPullRequest calls GET /api/v2/pull-requests/{id}.
+func (c *Client) V2GetPullRequest(ctx context.Context, id string) (*PullRequest, error) {
+	var out PullRequest
+	h, err := c.do(ctx, request{method: http.MethodGet, path: v2Prefix + "/pull-requests/" + url.PathEscape(id), out: &out})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2UpdatePullRequest calls PATCH /api/v2/pull-requests/{id}.
+func (c *Client) V2UpdatePullRequest(ctx context.Context, id string, in UpdatePullRequest, version int64) (*PullRequest, error) {
+	var out PullRequest
+	h, err := c.do(ctx, request{
+		method:  http.MethodPatch,
+		path:    v2Prefix + "/pull-requests/" + url.PathEscape(id),
+		ifMatch: version,
+		in:      in,
+		out:     &out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2DeletePullRequest calls DELETE /api/v2/pull-requests/{id}.
+func (c *Client) V2DeletePullRequest(ctx context.Context, id string, version int64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: v2Prefix + "/pull-requests/" + url.PathEscape(id), ifMatch: version})
+	return err
+}
+
+// V2GetReviewers calls GET /api/v2/pull-requests/{id}/reviewers.
+func (c *Client) V2GetReviewers(ctx context.Context, id string) (*Reviewers, error) {
+	var out Reviewers
+	h, err := c.do(ctx, request{method: http.MethodGet, path: v2Prefix + "/pull-requests/" + url.PathEscape(id) + "/reviewers", out: &out})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2ReassignReviewer calls POST /api/v2/pull-requests/{id}/reviewers.
+func (c *Client) V2ReassignReviewer(ctx context.Context, id, oldReviewerID string, version int64) (*Reviewers, error) {
+	in := struct {
+		OldReviewerID string `json:"old_reviewer_id"`
+	}{oldReviewerID}
+
+	var out Reviewers
+	h, err := c.do(ctx, request{
+		method:  http.MethodPost,
+		path:    v2Prefix + "/pull-requests/" + url.PathEscape(id) + "/reviewers",
+		ifMatch: version,
+		in:      in,
+		out:     &out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2AddReviewer calls PUT /api/v2/pull-requests/{id}/reviewers/{user_id};
+// requestedBy is optional and only recorded in the assignment history.
+func (c *Client) V2AddReviewer(ctx context.Context, id, userID, requestedBy string, version int64) (*Reviewers, error) {
+	return c.v2ChangeReviewer(ctx, http.MethodPut, id, userID, requestedBy, version)
+}
+
+// V2RemoveReviewer calls DELETE /api/v2/pull-requests/{id}/reviewers/{user_id}.
+func (c *Client) V2RemoveReviewer(ctx context.Context, id, userID, requestedBy string, version int64) (*Reviewers, error) {
+	return c.v2ChangeReviewer(ctx, http.MethodDelete, id, userID, requestedBy, version)
+}
+
+func (c *Client) v2ChangeReviewer(ctx context.Context, method, id, userID, requestedBy string, version int64) (*Reviewers, error) {
+	var q url.Values
+	if requestedBy != "" {
+		q = url.Values{"requested_by": {requestedBy}}
+	}
+
+	var out Reviewers
+	h, err := c.do(ctx, request{
+		method:  method,
+		path:    v2Prefix + "/pull-requests/" + url.PathEscape(id) + "/reviewers/" + url.PathEscape(userID),
+		query:   q,
+		ifMatch: version,
+		out:     &out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.Version = versionFrom(h)
+	return &out, nil
+}
+
+// V2GetAssignmentHistory calls GET /api/v2/pull-requests/{id}/events.
+func (c *Client) V2GetAssignmentHistory(ctx context.Context, id string) (*AssignmentHistory, error) {
+	var out AssignmentHistory
+	if _, err := c.do(ctx, request{method: http.MethodGet, path: v2Prefix + "/pull-requests/" + url.PathEscape(id) + "/events", out: &out}); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
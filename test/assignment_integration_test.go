@@ -0,0 +1,202 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// assignmentServices создаёт сервисы на отдельной базе с командой из автора a0 и
+// равноценных кандидатов a1..a5.
+func assignmentServices(t *testing.T, name string, opts ...service.Option) *service.Services {
+	t.Helper()
+	db := testhelpers.SetupNamedTestDB(t, name)
+	svc := service.New(repository.New(db), zap.NewNop(), opts...)
+
+	members := make([]service.CreateTeamMemberInput, 0, 6)
+	for i := range 6 {
+		id := fmt.Sprintf("a%d", i)
+		members = append(members, service.CreateTeamMemberInput{UserID: id, Username: id, IsActive: true})
+	}
+	_, err := svc.Teams.AddTeam(context.Background(), service.CreateTeamInput{TeamName: "assign", Members: members})
+	require.NoError(t, err)
+	return svc
+}
+
+func pickedIDs(picks []service.ReviewerPick) []string {
+	ids := make([]string, 0, len(picks))
+	for _, p := range picks {
+		ids = append(ids, p.User.ID)
+	}
+	return ids
+}
+
+// replay повторяет выбор по сохранённому seed: кандидаты с равным рангом,
+// отсортированные по user_id, перемешиваются этим seed.
+func replay(candidates []string, seed int64, n int) []string {
+	ids := slices.Clone(candidates)
+	slices.Sort(ids)
+	rand.New(rand.NewSource(seed)).Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	return ids[:n]
+}
+
+// TestAssignment_Deterministic - детерминированная стратегия зависит только от PR и кандидатов
+// и воспроизводится по истории назначений
+func TestAssignment_Deterministic(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	opts := []service.Option{
+		service.WithAssignmentStrategy(service.StrategyDeterministic),
+		service.WithClock(func() time.Time { return at }),
+	}
+	first := assignmentServices(t, "assign-det-1", opts...)
+	second := assignmentServices(t, "assign-det-2", opts...)
+	candidates := []string{"a1", "a2", "a3", "a4", "a5"}
+
+	for i := range 5 {
+		in := service.CreatePRInput{ID: fmt.Sprintf("pr-%d", i), Name: "Change", AuthorID: "a0"}
+		a, err := first.PRs.CreateWithAutoAssign(ctx, in)
+		require.NoError(t, err)
+		b, err := second.PRs.CreateWithAutoAssign(ctx, in)
+		require.NoError(t, err)
+		assert.Equal(t, pickedIDs(a.Picks), pickedIDs(b.Picks), in.ID)
+
+		events, err := first.PRs.GetAssignmentHistory(ctx, in.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		seed := service.DeterministicSeed(in.ID, candidates)
+		for _, e := range events {
+			assert.Equal(t, "deterministic", e.Strategy)
+			assert.Equal(t, seed, e.Seed)
+			assert.Equal(t, at, e.CreatedAt.UTC())
+		}
+		assert.Equal(t, replay(candidates, seed, 2), pickedIDs(a.Picks), "the decision replays from the seed")
+
+		// После merge нагрузка кандидатов снова равна, и следующий выбор решает только seed.
+		merged, err := first.PRs.Merge(ctx, in.ID)
+		require.NoError(t, err)
+		require.NotNil(t, merged.MergedAt)
+		assert.Equal(t, at, merged.MergedAt.UTC())
+		_, err = second.PRs.Merge(ctx, in.ID)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, service.DeterministicSeed("pr-0", []string{"a2", "a1"}), service.DeterministicSeed("pr-0", []string{"a1", "a2"}),
+		"the seed does not depend on candidate order")
+	assert.NotEqual(t, service.DeterministicSeed("pr-0", candidates), service.DeterministicSeed("pr-1", candidates))
+}
+
+// TestAssignment_SeededRandom - случайная стратегия с фиксированным источником воспроизводима,
+// а seed каждого выбора сохраняется в истории
+func TestAssignment_SeededRandom(t *testing.T) {
+	ctx := context.Background()
+	first := assignmentServices(t, "assign-rnd-1", service.WithRandomSource(rand.NewSource(42)))
+	second := assignmentServices(t, "assign-rnd-2", service.WithRandomSource(rand.NewSource(42)))
+
+	for i := range 5 {
+		in := service.CreatePRInput{ID: fmt.Sprintf("pr-%d", i), Name: "Change", AuthorID: "a0"}
+		a, err := first.PRs.CreateWithAutoAssign(ctx, in)
+		require.NoError(t, err)
+		b, err := second.PRs.CreateWithAutoAssign(ctx, in)
+		require.NoError(t, err)
+		assert.Equal(t, pickedIDs(a.Picks), pickedIDs(b.Picks), in.ID)
+
+		events, err := first.PRs.GetAssignmentHistory(ctx, in.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "random", events[0].Strategy)
+		assert.Equal(t, a.Picks[0].Seed, events[0].Seed)
+	}
+
+	t.Run("Reassignment records its own seed", func(t *testing.T) {
+		pr, err := first.PRs.GetPR(ctx, "pr-0")
+		require.NoError(t, err)
+		out, err := first.PRs.ReassignReviewer(ctx, service.ReassignInput{PRID: "pr-0", OldReviewerID: pr.Reviewers[0].ReviewerID})
+		require.NoError(t, err)
+
+		events, err := first.PRs.GetAssignmentHistory(ctx, "pr-0")
+		require.NoError(t, err)
+		last := events[len(events)-1]
+		assert.Equal(t, "reassigned", string(last.Kind))
+		assert.Equal(t, out.ReplacedByID, last.ReviewerID)
+		assert.Equal(t, "random", last.Strategy)
+		assert.NotZero(t, last.Seed)
+	})
+}
+
+// TestAssignment_Concurrent - параллельные назначения делят общий источник случайности
+// (проверяется с -race)
+func TestAssignment_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	svc := assignmentServices(t, "assign-concurrent")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.PRs.CreateWithAutoAssign(ctx, service.CreatePRInput{ID: fmt.Sprintf("pr-%d", i), Name: "Change", AuthorID: "a0"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+// TestAssignment_Atomic - создание PR и переназначение пишут PR, ревьюверов и
+// историю в одной транзакции, которой хватает одного соединения с базой
+func TestAssignment_Atomic(t *testing.T) {
+	db := testhelpers.SetupNamedTestDB(t, "assign-atomic")
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	svc := service.New(repository.New(db), zap.NewNop())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = svc.Teams.AddTeam(ctx, service.CreateTeamInput{TeamName: "assign", Members: []service.CreateTeamMemberInput{
+		{UserID: "a0", Username: "a0", IsActive: true},
+		{UserID: "a1", Username: "a1", IsActive: true},
+		{UserID: "a2", Username: "a2", IsActive: true},
+		{UserID: "a3", Username: "a3", IsActive: true},
+	}})
+	require.NoError(t, err)
+	created, err := svc.PRs.CreateWithAutoAssign(ctx, service.CreatePRInput{ID: "pr-1", Name: "Change", AuthorID: "a0"})
+	require.NoError(t, err)
+	old := created.Reviewers[0].ID
+	_, err = svc.PRs.ReassignReviewer(ctx, service.ReassignInput{PRID: "pr-1", OldReviewerID: old})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`CREATE TRIGGER fail_event BEFORE INSERT ON assignment_events
+		BEGIN SELECT RAISE(ABORT, 'boom'); END`).Error)
+
+	_, err = svc.PRs.CreateWithAutoAssign(ctx, service.CreatePRInput{ID: "pr-2", Name: "Change", AuthorID: "a0"})
+	require.Error(t, err)
+	_, err = svc.PRs.GetPR(ctx, "pr-2")
+	var serr *service.Error
+	require.ErrorAs(t, err, &serr, "the pull request is rolled back with its history")
+	assert.Equal(t, service.ErrorCodeNotFound, serr.Code)
+
+	before, err := svc.PRs.GetReviewersForPR(ctx, "pr-1")
+	require.NoError(t, err)
+	_, err = svc.PRs.ReassignReviewer(ctx, service.ReassignInput{PRID: "pr-1", OldReviewerID: before[0].ReviewerID})
+	require.Error(t, err)
+	after, err := svc.PRs.GetReviewersForPR(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "the reviewer is not replaced without history")
+}
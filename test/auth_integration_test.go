@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/config"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestAuth_BearerTokens - проверка токенов и ролей
+func TestAuth_BearerTokens(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	services := service.New(repo, log)
+	handler := httpapi.New(services, log)
+	authenticator := auth.New(config.AuthConfig{Enabled: true, AdminToken: "adm", UserToken: "usr"})
+	r := router.Router(handler, router.WithAuth(authenticator))
+
+	teamBody, _ := json.Marshal(map[string]interface{}{
+		"team_name": "auth-team",
+		"members": []map[string]interface{}{
+			{"user_id": "a1", "username": "A1", "is_active": true},
+		},
+	})
+
+	do := func(method, path, token string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Missing token", func(t *testing.T) {
+		w := do("GET", "/team/get?team_name=auth-team", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var errResp httpapi.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &errResp)
+		assert.Equal(t, "UNAUTHORIZED", errResp.Error.Code)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		w := do("GET", "/team/get?team_name=auth-team", "nope", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("User token cannot manage teams", func(t *testing.T) {
+		w := do("POST", "/team/add", "usr", teamBody)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Admin token can manage teams", func(t *testing.T) {
+		w := do("POST", "/team/add", "adm", teamBody)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("User token can read", func(t *testing.T) {
+		w := do("GET", "/team/get?team_name=auth-team", "usr", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("User token cannot change selection of users", func(t *testing.T) {
+		skill, _ := json.Marshal(map[string]any{"user_id": "a1", "skill": "go"})
+		optOut, _ := json.Marshal(map[string]any{"user_id": "a1", "enabled": false})
+		for path, body := range map[string][]byte{
+			"/users/setSkill":      skill,
+			"/users/removeSkill":   skill,
+			"/users/setAutoAssign": optOut,
+		} {
+			assert.Equal(t, http.StatusForbidden, do("POST", path, "usr", body).Code, path)
+		}
+		assert.Equal(t, http.StatusOK, do("POST", "/users/setSkill", "adm", skill).Code)
+		assert.Equal(t, http.StatusOK, do("POST", "/users/setAutoAssign", "adm", optOut).Code)
+	})
+
+	t.Run("Health endpoints are public", func(t *testing.T) {
+		w := do("GET", "/livez", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,254 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reviewer_pr/internal/testhelpers"
+	"reviewer_pr/pkg/client"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedBackupData создает команду, открытый и смерженный PR, неактивного пользователя
+// с подпиской на дайджест, SLA команды, владельцами кода, навыками, исключением
+// ревьювера и отказом от автоназначения
+func seedBackupData(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "u2"})
+	require.NoError(t, err)
+	_, err = c.MergePullRequest(ctx, "pr-2")
+	require.NoError(t, err)
+	_, err = c.SetIsActive(ctx, "u3", false)
+	require.NoError(t, err)
+	_, err = c.SetDigest(ctx, client.DigestSubscription{UserID: "u1", Enabled: true, Email: "alice@example.com", SendAt: "09:30", Timezone: "Europe/Moscow"})
+	require.NoError(t, err)
+	_, err = c.SetTeamSLA(ctx, client.TeamSLA{TeamName: "backend", ReviewSLASeconds: 86400, MergeSLASeconds: 259200, AutoReassign: true})
+	require.NoError(t, err)
+	_, err = c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{
+		{Pattern: "*.go", Users: []string{"u2"}},
+		{Pattern: "/docs/", Teams: []string{"backend"}},
+	}})
+	require.NoError(t, err)
+	_, err = c.SetSkill(ctx, "u2", client.Skill{Skill: "go", Proficiency: 4})
+	require.NoError(t, err)
+	_, err = c.SetSkill(ctx, "u2", client.Skill{Skill: "sql"})
+	require.NoError(t, err)
+	_, err = c.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "u3", Reason: "same squad"})
+	require.NoError(t, err)
+	_, err = c.SetAutoAssign(ctx, "u2", false)
+	require.NoError(t, err)
+}
+
+// replaceRecord заменяет old на new в первой записи вида kind и возвращает номер ее строки
+func replaceRecord(t *testing.T, lines []string, kind, old, new string) ([]string, int) {
+	t.Helper()
+	out := append([]string(nil), lines...)
+	for i, l := range out {
+		if strings.HasPrefix(l, `{"type":"`+kind+`"`) {
+			require.Contains(t, l, old)
+			out[i] = strings.Replace(l, old, new, 1)
+			return out, i + 1
+		}
+	}
+	t.Fatalf("no %s record in the export", kind)
+	return nil, 0
+}
+
+// withoutHeader отбрасывает первую строку выгрузки (в ней время выгрузки)
+func withoutHeader(export string) string {
+	_, rest, _ := strings.Cut(export, "\n")
+	return rest
+}
+
+// TestBackup_RoundTrip - выгрузка и восстановление в пустую базу сохраняют все данные
+func TestBackup_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := newClient(t, startAPI(t).URL)
+	seedBackupData(t, src)
+
+	var export bytes.Buffer
+	counts, err := src.Export(ctx, &export)
+	require.NoError(t, err)
+	assert.Equal(t, client.BackupCounts{Teams: 1, Users: 3, Memberships: 3, Digests: 1, TeamSLAs: 1, CodeOwners: 2, Skills: 2, Exclusions: 1, PullRequests: 2, Reviewers: 4, Events: 4}, *counts)
+	assert.Contains(t, strings.SplitN(export.String(), "\n", 2)[0], `"format":"reviewer_pr.backup"`)
+
+	dst := newClient(t, startAPI(t, withDB(testhelpers.SetupNamedTestDB(t, "restore_roundtrip"))).URL)
+	restored, err := dst.Restore(ctx, bytes.NewReader(export.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, *counts, *restored)
+
+	t.Run("Data and versions are preserved", func(t *testing.T) {
+		var again bytes.Buffer
+		_, err := dst.Export(ctx, &again)
+		require.NoError(t, err)
+		assert.Equal(t, withoutHeader(export.String()), withoutHeader(again.String()))
+
+		u3, err := dst.V2GetUser(ctx, "u3")
+		require.NoError(t, err)
+		assert.False(t, u3.IsActive)
+		u2, err := dst.V2GetUser(ctx, "u2")
+		require.NoError(t, err)
+		assert.True(t, u2.AutoAssignOptOut)
+
+		pr, err := dst.V2GetPullRequest(ctx, "pr-2")
+		require.NoError(t, err)
+		assert.Equal(t, client.StatusMerged, pr.Status)
+		assert.NotNil(t, pr.MergedAt)
+
+		want, err := src.GetAssignmentHistory(ctx, "pr-1")
+		require.NoError(t, err)
+		got, err := dst.GetAssignmentHistory(ctx, "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		require.NotEmpty(t, got.Events)
+		assert.NotEmpty(t, got.Events[0].Strategy)
+	})
+
+	t.Run("Restore requires an empty database", func(t *testing.T) {
+		_, err := dst.Restore(ctx, bytes.NewReader(export.Bytes()))
+		require.ErrorIs(t, err, client.ErrResourceInUse)
+	})
+}
+
+// TestBackup_ExportIgnoresWriteTimeout - выгрузка не обрывается таймаутом записи сервера
+func TestBackup_ExportIgnoresWriteTimeout(t *testing.T) {
+	ctx := context.Background()
+	src := startAPI(t)
+	seedBackupData(t, newClient(t, src.URL))
+
+	// таймаут истекает раньше, чем обработчик начинает писать ответ
+	slow := startAPI(t, withDB(src.db), withServer(func(s *http.Server) { s.WriteTimeout = time.Nanosecond }))
+	var export bytes.Buffer
+	counts, err := newClient(t, slow.URL).Export(ctx, &export)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, counts.PullRequests)
+	assert.Contains(t, export.String(), `"type":"end"`)
+}
+
+// TestBackup_RestoreErrors - поврежденные и несогласованные выгрузки не меняют базу
+func TestBackup_RestoreErrors(t *testing.T) {
+	ctx := context.Background()
+	src := newClient(t, startAPI(t).URL)
+	seedBackupData(t, src)
+
+	var export bytes.Buffer
+	_, err := src.Export(ctx, &export)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(export.String(), "\n"), "\n")
+
+	dst := newClient(t, startAPI(t, withDB(testhelpers.SetupNamedTestDB(t, "restore_errors"))).URL)
+
+	t.Run("Dangling reference", func(t *testing.T) {
+		broken := append([]string(nil), lines...)
+		// вторая строка - единственная команда; без нее у пользователей нет команды
+		broken = append(broken[:1], broken[2:]...)
+		_, err := dst.Restore(ctx, strings.NewReader(strings.Join(broken, "\n")))
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, client.CodeValidation, apiErr.Code)
+		require.NotEmpty(t, apiErr.Details)
+		assert.Equal(t, "rows[2].team_name", apiErr.Details[0].Field)
+	})
+
+	t.Run("Invalid value", func(t *testing.T) {
+		broken, line := replaceRecord(t, lines, "digest_subscription", `"timezone":"Europe/Moscow"`, `"timezone":"Mars/Olympus"`)
+		_, err := dst.Restore(ctx, strings.NewReader(strings.Join(broken, "\n")))
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 1)
+		assert.Equal(t, fmt.Sprintf("rows[%d].timezone", line), apiErr.Details[0].Field)
+
+		broken, line = replaceRecord(t, lines, "team_sla", `"review_sla_seconds":86400`, `"review_sla_seconds":-1`)
+		_, err = dst.Restore(ctx, strings.NewReader(strings.Join(broken, "\n")))
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 1)
+		assert.Equal(t, fmt.Sprintf("rows[%d].review_sla_seconds", line), apiErr.Details[0].Field)
+
+		broken, line = replaceRecord(t, lines, "code_owner_rule", `"pattern":"*.go"`, `"pattern":"!*.go"`)
+		_, err = dst.Restore(ctx, strings.NewReader(strings.Join(broken, "\n")))
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 1)
+		assert.Equal(t, fmt.Sprintf("rows[%d].pattern", line), apiErr.Details[0].Field)
+
+		broken, line = replaceRecord(t, lines, "user_skill", `"proficiency":4`, `"proficiency":9`)
+		_, err = dst.Restore(ctx, strings.NewReader(strings.Join(broken, "\n")))
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 1)
+		assert.Equal(t, fmt.Sprintf("rows[%d].proficiency", line), apiErr.Details[0].Field)
+
+		broken, line = replaceRecord(t, lines, "assignment_event", `"kind":"assigned"`, `"kind":"promoted"`)
+		_, err = dst.Restore(ctx, strings.NewReader(strings.Join(broken, "\n")))
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 1)
+		assert.Equal(t, fmt.Sprintf("rows[%d].kind", line), apiErr.Details[0].Field)
+
+		broken, line = replaceRecord(t, lines, "reviewer_exclusion", `"reviewer_id":"u3"`, `"reviewer_id":"u1"`)
+		_, err = dst.Restore(ctx, strings.NewReader(strings.Join(broken, "\n")))
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 1)
+		assert.Equal(t, fmt.Sprintf("rows[%d].reviewer_id", line), apiErr.Details[0].Field)
+	})
+
+	t.Run("Truncated stream", func(t *testing.T) {
+		truncated := strings.Join(lines[:len(lines)-1], "\n")
+		_, err := dst.Restore(ctx, strings.NewReader(truncated))
+		require.ErrorIs(t, err, client.ErrInvalidRequest)
+	})
+
+	t.Run("Missing header", func(t *testing.T) {
+		_, err := dst.Restore(ctx, strings.NewReader(strings.Join(lines[1:], "\n")))
+		require.ErrorIs(t, err, client.ErrInvalidRequest)
+	})
+
+	t.Run("Nothing was written", func(t *testing.T) {
+		_, err := dst.Restore(ctx, bytes.NewReader(export.Bytes()))
+		require.NoError(t, err)
+	})
+}
+
+// TestCLI_ExportRestore - reviewerctl export/restore через файл
+func TestCLI_ExportRestore(t *testing.T) {
+	srv := startAPI(t)
+	seedBackupData(t, newClient(t, srv.URL))
+	dst := startAPI(t, withDB(testhelpers.SetupNamedTestDB(t, "restore_cli")))
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yml")
+	file := filepath.Join(dir, "backup.ndjson")
+
+	out, stderr, code := runCLI(t, cfgPath, "--server", srv.URL, "export", "-f", file)
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `1\s+3\s+3\s+1\s+1\s+2\s+2\s+1\s+2\s+4\s+4`, out)
+
+	out, stderr, code = runCLI(t, cfgPath, "--server", dst.URL, "restore", file, "-o", "json")
+	require.Equal(t, 0, code, stderr)
+	assert.JSONEq(t, `{"teams":1,"users":3,"memberships":3,"digest_subscriptions":1,"team_slas":1,"code_owner_rules":2,"user_skills":2,"reviewer_exclusions":1,"pull_requests":2,"reviewers":4,"assignment_events":4}`, out)
+
+	_, stderr, code = runCLI(t, cfgPath, "--server", dst.URL, "restore", file)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "RESOURCE_IN_USE")
+
+	t.Run("Failed export leaves no file", func(t *testing.T) {
+		missing := filepath.Join(dir, "failed.ndjson")
+		_, _, code := runCLI(t, cfgPath, "--server", "http://127.0.0.1:1", "export", "-f", missing)
+		assert.Equal(t, 1, code)
+		_, err := os.Stat(missing)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/cli"
+	"reviewer_pr/internal/config"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// runCLI запускает reviewerctl с отдельным конфигом и возвращает stdout, stderr и код выхода.
+func runCLI(t *testing.T, configPath string, args ...string) (string, string, int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	cmd := cli.NewRootCommand(&out, &errOut)
+	cmd.SetArgs(append([]string{"--config", configPath}, args...))
+	code := cli.Run(cmd)
+	return out.String(), errOut.String(), code
+}
+
+// TestCLI_Commands - команды reviewerctl работают через HTTP API и профиль конфигурации
+func TestCLI_Commands(t *testing.T) {
+	authenticator := auth.New(config.AuthConfig{Enabled: true, AdminToken: "adm", UserToken: "usr"})
+	srv := startAPI(t, withRouter(router.WithAuth(authenticator)))
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+
+	_, _, code := runCLI(t, cfgPath, "config", "set-profile", "local", "--server", srv.URL, "--token", "adm")
+	require.Equal(t, 0, code)
+
+	t.Run("Profile is current and token is masked", func(t *testing.T) {
+		cfg, err := cli.LoadConfig(cfgPath)
+		require.NoError(t, err)
+		assert.Equal(t, "local", cfg.CurrentProfile)
+
+		out, _, code := runCLI(t, cfgPath, "config", "view")
+		require.Equal(t, 0, code)
+		assert.Contains(t, out, srv.URL)
+		assert.NotContains(t, out, "adm\n")
+	})
+
+	out, stderr, code := runCLI(t, cfgPath, "team", "add", "backend",
+		"--member", "u1=Alice", "--member", "u2=Bob", "--member", "u3=Carol")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, out, "Team: backend")
+	assert.Regexp(t, `u3\s+Carol\s+yes`, out)
+
+	t.Run("JSON output", func(t *testing.T) {
+		out, _, code := runCLI(t, cfgPath, "team", "get", "backend", "-o", "json")
+		require.Equal(t, 0, code)
+		var team client.Team
+		require.NoError(t, json.Unmarshal([]byte(out), &team))
+		assert.Len(t, team.Members, 3)
+	})
+
+	out, stderr, code = runCLI(t, cfgPath, "pr", "create", "pr-1", "--name", "Add search", "--author", "u1", "-o", "yaml")
+	require.Equal(t, 0, code, stderr)
+	var pr map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(out), &pr))
+	assert.Equal(t, "pr-1", pr["pull_request_id"])
+	assert.ElementsMatch(t, []any{"u2", "u3"}, pr["assigned_reviewers"])
+
+	t.Run("User deactivate and activate", func(t *testing.T) {
+		out, _, code := runCLI(t, cfgPath, "user", "deactivate", "u2")
+		require.Equal(t, 0, code)
+		assert.Regexp(t, `u2\s+Bob\s+backend\s+no`, out)
+
+		_, _, code = runCLI(t, cfgPath, "user", "activate", "u2", "u3")
+		require.Equal(t, 0, code)
+	})
+
+	t.Run("User mute and unmute", func(t *testing.T) {
+		out, _, code := runCLI(t, cfgPath, "user", "mute", "u2", "u3")
+		require.Equal(t, 0, code)
+		assert.Regexp(t, `u2\s+no`, out)
+		assert.Regexp(t, `u3\s+no`, out)
+
+		out, _, code = runCLI(t, cfgPath, "user", "unmute", "u2", "-o", "json")
+		require.Equal(t, 0, code)
+		assert.JSONEq(t, `[{"user_id":"u2","notifications_enabled":true}]`, out)
+	})
+
+	t.Run("Merge, get and stats", func(t *testing.T) {
+		_, _, code := runCLI(t, cfgPath, "pr", "merge", "pr-1")
+		require.Equal(t, 0, code)
+
+		out, _, code := runCLI(t, cfgPath, "pr", "get", "pr-1")
+		require.Equal(t, 0, code)
+		assert.Contains(t, out, "MERGED")
+
+		out, _, code = runCLI(t, cfgPath, "stats")
+		require.Equal(t, 0, code)
+		assert.Contains(t, out, "pr-1")
+	})
+
+	t.Run("API errors are reported with code", func(t *testing.T) {
+		_, stderr, code := runCLI(t, cfgPath, "pr", "reassign", "pr-1", "--old", "u2", "-o", "json")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "PR_MERGED")
+	})
+
+	t.Run("Flag token overrides profile", func(t *testing.T) {
+		_, stderr, code := runCLI(t, cfgPath, "--token", "usr", "user", "deactivate", "u1")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "FORBIDDEN")
+	})
+
+	t.Run("Unknown output format", func(t *testing.T) {
+		_, stderr, code := runCLI(t, cfgPath, "stats", "-o", "xml")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "unknown output format")
+	})
+}
+
+// TestCLI_Completion - генерация скриптов автодополнения
+func TestCLI_Completion(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		out, _, code := runCLI(t, cfgPath, "completion", shell)
+		require.Equal(t, 0, code, shell)
+		assert.Contains(t, out, "reviewerctl", shell)
+	}
+}
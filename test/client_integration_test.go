@@ -0,0 +1,290 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/config"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"reviewer_pr/pkg/client"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// testAPI - тестовый HTTP-сервер вместе с сервисами и базой, на которых он поднят
+type testAPI struct {
+	*httptest.Server
+	svc *service.Services
+	db  *gorm.DB
+}
+
+// apiConfig - настройки тестового сервера startAPI
+type apiConfig struct {
+	db      *gorm.DB
+	service []service.Option
+	router  []router.Option
+	server  func(*http.Server)
+}
+
+type apiOption func(*apiConfig)
+
+// withDB поднимает сервер на заданной базе вместо новой пустой
+func withDB(db *gorm.DB) apiOption {
+	return func(c *apiConfig) { c.db = db }
+}
+
+// withService передает опции сервисному слою
+func withService(opts ...service.Option) apiOption {
+	return func(c *apiConfig) { c.service = append(c.service, opts...) }
+}
+
+// withRouter передает опции роутеру
+func withRouter(opts ...router.Option) apiOption {
+	return func(c *apiConfig) { c.router = append(c.router, opts...) }
+}
+
+// withServer настраивает http.Server до запуска (например, таймауты)
+func withServer(fn func(*http.Server)) apiOption {
+	return func(c *apiConfig) { c.server = fn }
+}
+
+// startAPI поднимает API на тестовой базе; сервер закрывается по завершении теста
+func startAPI(t *testing.T, opts ...apiOption) *testAPI {
+	t.Helper()
+	var cfg apiConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.db == nil {
+		cfg.db = testhelpers.SetupTestDB(t)
+	}
+
+	log := zap.NewNop()
+	svc := service.New(repository.New(cfg.db), log, cfg.service...)
+	srv := httptest.NewUnstartedServer(router.Router(httpapi.New(svc, log), cfg.router...))
+	if cfg.server != nil {
+		cfg.server(srv.Config)
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return &testAPI{Server: srv, svc: svc, db: cfg.db}
+}
+
+func newClient(t *testing.T, baseURL string, opts ...client.Option) *client.Client {
+	t.Helper()
+	c, err := client.New(baseURL, opts...)
+	require.NoError(t, err)
+	return c
+}
+
+func clientTeam() client.Team {
+	return client.Team{
+		TeamName: "backend",
+		Members: []client.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+		},
+	}
+}
+
+// TestClient_V1Flow - полный сценарий v1 через SDK
+func TestClient_V1Flow(t *testing.T) {
+	srv := startAPI(t)
+	c := newClient(t, srv.URL)
+	ctx := context.Background()
+
+	team, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 3)
+
+	got, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", got.TeamName)
+
+	pr, err := c.CreatePullRequest(ctx, client.CreatePullRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusOpen, pr.Status)
+	require.Len(t, pr.AssignedReviewers, 2)
+	assert.NotContains(t, pr.AssignedReviewers, "u1")
+
+	reviews, err := c.GetReviews(ctx, pr.AssignedReviewers[0])
+	require.NoError(t, err)
+	require.Len(t, reviews.PullRequests, 1)
+	assert.Equal(t, "pr-1", reviews.PullRequests[0].PullRequestID)
+
+	user, err := c.SetIsActive(ctx, "u1", false)
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+
+	merged, err := c.MergePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusMerged, merged.Status)
+	assert.NotNil(t, merged.MergedAt)
+
+	_, err = c.ReassignReviewer(ctx, "pr-1", pr.AssignedReviewers[0])
+	assert.ErrorIs(t, err, client.ErrPRMerged)
+
+	stats, err := c.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stats.ByPR, 1)
+}
+
+// TestClient_TypedErrors - коды ErrorResponse превращаются в *client.Error
+func TestClient_TypedErrors(t *testing.T) {
+	srv := startAPI(t)
+	c := newClient(t, srv.URL)
+	ctx := context.Background()
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := c.GetTeam(ctx, "missing")
+		require.ErrorIs(t, err, client.ErrNotFound)
+
+		var apiErr *client.Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.NotEmpty(t, apiErr.RequestID)
+	})
+
+	t.Run("Validation details", func(t *testing.T) {
+		_, err := c.AddTeam(ctx, client.Team{TeamName: ""})
+		require.ErrorIs(t, err, client.ErrValidation)
+
+		var apiErr *client.Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.NotEmpty(t, apiErr.Details)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		_, err := c.AddTeam(ctx, clientTeam())
+		require.NoError(t, err)
+		_, err = c.AddTeam(ctx, clientTeam())
+		assert.ErrorIs(t, err, client.ErrTeamExists)
+		assert.NotErrorIs(t, err, client.ErrNotFound)
+	})
+}
+
+// TestClient_Auth - WithToken передаёт bearer-токен, роли проверяются сервером
+func TestClient_Auth(t *testing.T) {
+	authenticator := auth.New(config.AuthConfig{Enabled: true, AdminToken: "adm", UserToken: "usr"})
+	srv := startAPI(t, withRouter(router.WithAuth(authenticator)))
+	ctx := context.Background()
+
+	_, err := newClient(t, srv.URL).GetTeam(ctx, "backend")
+	assert.ErrorIs(t, err, client.ErrUnauthorized)
+
+	_, err = newClient(t, srv.URL, client.WithToken("usr")).AddTeam(ctx, clientTeam())
+	assert.ErrorIs(t, err, client.ErrForbidden)
+
+	_, err = newClient(t, srv.URL, client.WithToken("adm")).AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+
+	_, err = newClient(t, srv.URL, client.WithToken("usr")).GetTeam(ctx, "backend")
+	assert.NoError(t, err)
+}
+
+// TestClient_RetryIsIdempotent - повтор POST после потерянного ответа не создаёт PR дважды
+func TestClient_RetryIsIdempotent(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	log := zap.NewNop()
+	api := router.Router(httpapi.New(service.New(repository.New(db), log), log))
+
+	// Первый запрос на создание PR выполняется, но клиент получает 503.
+	var creates atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pullRequest/create" && creates.Add(1) == 1 {
+			api.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newClient(t, srv.URL, client.WithRetry(2, time.Millisecond))
+	ctx := context.Background()
+
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+
+	pr, err := c.CreatePullRequest(ctx, client.CreatePullRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pr-1", pr.PullRequestID)
+	assert.Equal(t, int32(2), creates.Load())
+
+	t.Run("Retries disabled", func(t *testing.T) {
+		creates.Store(0)
+		c := newClient(t, srv.URL, client.WithRetry(0, 0))
+		_, err := c.CreatePullRequest(ctx, client.CreatePullRequest{
+			PullRequestID:   "pr-2",
+			PullRequestName: "Add filters",
+			AuthorID:        "u1",
+		})
+		var apiErr *client.Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Equal(t, int32(1), creates.Load())
+	})
+}
+
+// TestClient_V2Versions - методы v2 возвращают версию и передают её в If-Match
+func TestClient_V2Versions(t *testing.T) {
+	srv := startAPI(t)
+	c := newClient(t, srv.URL)
+	ctx := context.Background()
+
+	team, err := c.V2CreateTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	require.Positive(t, team.Version)
+
+	pr, err := c.V2CreatePullRequest(ctx, client.CreatePullRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "u1",
+	})
+	require.NoError(t, err)
+
+	name := "Add full-text search"
+	updated, err := c.V2UpdatePullRequest(ctx, "pr-1", client.UpdatePullRequest{PullRequestName: &name}, pr.Version)
+	require.NoError(t, err)
+	assert.Equal(t, name, updated.PullRequestName)
+	assert.Greater(t, updated.Version, pr.Version)
+
+	_, err = c.V2UpdatePullRequest(ctx, "pr-1", client.UpdatePullRequest{PullRequestName: &name}, pr.Version)
+	assert.ErrorIs(t, err, client.ErrPreconditionFailed)
+
+	reviewers, err := c.V2GetReviewers(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, updated.Version, reviewers.Version)
+
+	user, err := c.V2GetUser(ctx, "u1")
+	require.NoError(t, err)
+	active := false
+	user, err = c.V2UpdateUser(ctx, "u1", client.UpdateUser{IsActive: &active}, user.Version)
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+
+	require.NoError(t, c.V2DeletePullRequest(ctx, "pr-1", 0))
+	_, err = c.V2GetPullRequest(ctx, "pr-1")
+	assert.ErrorIs(t, err, client.ErrNotFound)
+}
@@ -0,0 +1,247 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reviewer_pr/internal/codeowners"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCodeOwners_Patterns - шаблоны CODEOWNERS: якоря, каталоги и подстановки
+func TestCodeOwners_Patterns(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "README.md", true},
+		{"*", "a/b/c.go", true},
+		{"*.go", "main.go", true},
+		{"*.go", "internal/service/pr.go", true},
+		{"*.go", "go.mod", false},
+		{"/docs/", "docs/index.md", true},
+		{"/docs/", "docs/api/v1.md", true},
+		{"/docs/", "web/docs/index.md", false},
+		{"docs/", "web/docs/index.md", true},
+		{"docs", "web/docs/index.md", true},
+		{"/docs", "docs", true},
+		{"docs/*", "docs/index.md", true},
+		{"docs/*", "docs/api/v1.md", false},
+		{"/internal/billing", "internal/billing/pay.go", true},
+		{"/internal/billing", "internal/billing_v2/pay.go", false},
+		{"internal/billing/", "internal/billing/pay.go", true},
+		{"internal/billing/", "pkg/internal/billing/pay.go", false},
+		{"api/**/*.yml", "api/openapi.yml", true},
+		{"api/**/*.yml", "api/v2/spec/openapi.yml", true},
+		{"**/migrations", "db/migrations/001.sql", true},
+		{"/cmd/?pp/", "cmd/app/main.go", true},
+		{"/", "anything/at/all.txt", true},
+		{"*.go", "./main.go", true},
+	}
+	for _, tc := range cases {
+		p, err := codeowners.Compile(tc.pattern)
+		require.NoError(t, err, tc.pattern)
+		assert.Equal(t, tc.want, p.Match(tc.path), "%s ~ %s", tc.pattern, tc.path)
+	}
+
+	for _, bad := range []string{"", "!*.go", "[ab].go", `\#file`} {
+		_, err := codeowners.Compile(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func ownersTeams(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.AddTeam(ctx, client.Team{TeamName: "dba", Members: []client.TeamMember{
+		{UserID: "d1", Username: "Dan", IsActive: true},
+	}})
+	require.NoError(t, err)
+}
+
+// TestCodeOwners_Rules - сохранение, импорт CODEOWNERS и валидация правил
+func TestCodeOwners_Rules(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	ownersTeams(t, c)
+
+	owners, err := c.GetCodeOwners(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{}}, *owners)
+
+	want := client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{
+		{Pattern: "*", Users: []string{}, Teams: []string{"backend"}},
+		{Pattern: "/internal/billing/", Users: []string{"u3"}, Teams: []string{}},
+		{Pattern: "*.sql", Users: []string{"u2"}, Teams: []string{"dba"}},
+	}}
+	owners, err = c.SetCodeOwners(ctx, want)
+	require.NoError(t, err)
+	assert.Equal(t, want, *owners)
+	owners, err = c.GetCodeOwners(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, want, *owners)
+
+	t.Run("Import replaces rules", func(t *testing.T) {
+		file := "# Owners\n\n*.md    @acme/backend   # docs\n/internal/ @u2 @u3\n"
+		owners, err := c.ImportCodeOwners(ctx, "backend", []byte(file))
+		require.NoError(t, err)
+		assert.Equal(t, []client.CodeOwnerRule{
+			{Pattern: "*.md", Users: []string{}, Teams: []string{"backend"}},
+			{Pattern: "/internal/", Users: []string{"u2", "u3"}, Teams: []string{}},
+		}, owners.Rules)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{
+			{Pattern: "!*.go", Users: []string{"u1"}},
+			{Pattern: "*.go", Users: []string{"ghost"}, Teams: []string{"nope"}},
+		}})
+		require.ErrorIs(t, err, client.ErrValidation)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		fields := map[string]bool{}
+		for _, d := range apiErr.Details {
+			fields[d.Field] = true
+		}
+		assert.Equal(t, map[string]bool{"rules[0].pattern": true}, fields, "syntax is checked before owners")
+
+		_, err = c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{
+			{Pattern: "*.go", Users: []string{"ghost"}, Teams: []string{"nope"}},
+		}})
+		require.ErrorAs(t, err, &apiErr)
+		assert.ElementsMatch(t, []client.FieldError{
+			{Field: "rules[0].users[0]", Message: "user not found"},
+			{Field: "rules[0].teams[0]", Message: "team not found"},
+		}, apiErr.Details)
+
+		_, err = c.ImportCodeOwners(ctx, "backend", []byte("*.go @u1\n\n*.sql dba@example.com\n/docs/ @ghost\n"))
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 1)
+		assert.Equal(t, "rows[3].owners", apiErr.Details[0].Field)
+
+		_, err = c.ImportCodeOwners(ctx, "backend", []byte("*.go @u1\n/docs/ @ghost\n"))
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, []client.FieldError{{Field: "rows[2].users[0]", Message: "user not found"}}, apiErr.Details)
+
+		_, err = c.GetCodeOwners(ctx, "missing")
+		require.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "missing"})
+		require.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("Empty list removes rules", func(t *testing.T) {
+		_, err := c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "backend"})
+		require.NoError(t, err)
+		owners, err := c.GetCodeOwners(ctx, "backend")
+		require.NoError(t, err)
+		assert.Empty(t, owners.Rules)
+	})
+}
+
+// TestCodeOwners_Assignment - владельцы изменённых файлов назначаются первыми, остальные места заполняются из команды
+func TestCodeOwners_Assignment(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	ownersTeams(t, c)
+	_, err := c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{
+		{Pattern: "*", Teams: []string{"backend"}},
+		{Pattern: "/internal/billing/", Users: []string{"u3"}},
+		{Pattern: "*.sql", Teams: []string{"dba"}},
+		{Pattern: "/docs/", Users: []string{"u2"}},
+		{Pattern: "/vendor/"},
+	}})
+	require.NoError(t, err)
+
+	create := func(id string, files ...string) []string {
+		t.Helper()
+		pr, err := c.CreatePullRequest(ctx, client.CreatePullRequest{
+			PullRequestID: id, PullRequestName: id, AuthorID: "u1", ChangedFiles: files,
+		})
+		require.NoError(t, err)
+		return pr.AssignedReviewers
+	}
+
+	t.Run("Required owners of every rule", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"u3", "d1"}, create("pr-1", "internal/billing/pay.go", "db/001.sql"))
+	})
+
+	t.Run("Owners beyond the reviewer limit", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"u3", "d1", "u2"}, create("pr-2", "internal/billing/pay.go", "db/001.sql", "docs/billing.md"))
+	})
+
+	t.Run("Team fills remaining places", func(t *testing.T) {
+		reviewers := create("pr-3", "db/002.sql")
+		require.Len(t, reviewers, 2)
+		assert.Contains(t, reviewers, "d1")
+	})
+
+	t.Run("Owner already covered by a picked reviewer", func(t *testing.T) {
+		// u3 владеет и billing, и "*" (команда backend): одного назначения хватает на оба правила.
+		assert.ElementsMatch(t, []string{"u3", "u2"}, create("pr-4", "internal/billing/pay.go", "go.mod"))
+	})
+
+	t.Run("Unowned files and author as owner", func(t *testing.T) {
+		reviewers := create("pr-5", "vendor/lib/x.go")
+		assert.ElementsMatch(t, []string{"u2", "u3"}, reviewers)
+
+		_, err := c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{
+			{Pattern: "*.go", Users: []string{"u1"}},
+		}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, create("pr-6", "main.go"))
+	})
+
+	t.Run("Inactive owner is skipped", func(t *testing.T) {
+		_, err := c.SetIsActive(ctx, "d1", false)
+		require.NoError(t, err)
+		_, err = c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "backend", Rules: []client.CodeOwnerRule{
+			{Pattern: "*.sql", Teams: []string{"dba"}},
+		}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, create("pr-7", "db/003.sql"))
+	})
+
+	t.Run("Invalid paths", func(t *testing.T) {
+		_, err := c.CreatePullRequest(ctx, client.CreatePullRequest{
+			PullRequestID: "pr-8", PullRequestName: "x", AuthorID: "u1", ChangedFiles: []string{"ok.go", " "},
+		})
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "changed_files[1]", apiErr.Details[0].Field)
+	})
+}
+
+// TestCLI_CodeOwners - reviewerctl team owners и pr create --file
+func TestCLI_CodeOwners(t *testing.T) {
+	srv := startAPI(t)
+	ownersTeams(t, newClient(t, srv.URL))
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	_, _, code := runCLI(t, cfgPath, "config", "set-profile", "local", "--server", srv.URL)
+	require.Equal(t, 0, code)
+
+	file := filepath.Join(t.TempDir(), "CODEOWNERS")
+	require.NoError(t, os.WriteFile(file, []byte("*.sql @acme/dba\n/internal/billing/ @u3\n"), 0o600))
+	out, errOut, code := runCLI(t, cfgPath, "team", "owners", "import", "backend", file)
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "team:dba")
+	assert.Contains(t, out, "/internal/billing/")
+
+	out, _, code = runCLI(t, cfgPath, "pr", "create", "pr-1", "--name", "Billing", "--author", "u1",
+		"--file", "internal/billing/pay.go", "--file", "db/001.sql", "-o", "json")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, `"u3"`)
+	assert.Contains(t, out, `"d1"`)
+
+	_, _, code = runCLI(t, cfgPath, "team", "owners", "clear", "backend")
+	require.Equal(t, 0, code)
+	out, _, code = runCLI(t, cfgPath, "team", "owners", "get", "backend", "-o", "json")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, `"rules": []`)
+}
@@ -0,0 +1,228 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"reviewer_pr/internal/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// TestConfig_FileAndEnvOverrides - значения из файла переопределяются переменными окружения
+func TestConfig_FileAndEnvOverrides(t *testing.T) {
+	path := writeConfigFile(t, `
+env: development
+server:
+  port: "9090"
+  write_timeout: 30s
+db:
+  max_open_conns: 50
+  max_idle_conns: 5
+log:
+  level: debug
+assignment:
+  reviewers_per_pr: 3
+`)
+	t.Setenv("ENV", "development")
+	t.Setenv("APP_PORT", "9191")
+	t.Setenv("DB_MAX_IDLE_CONNS", "7")
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "9191", cfg.Server.Port, "env should override file")
+	assert.Equal(t, 30*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.ReadTimeout, "default should be kept")
+	assert.Equal(t, 50, cfg.DB.MaxOpenConns)
+	assert.Equal(t, 7, cfg.DB.MaxIdleConns)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, 3, cfg.Assignment.ReviewersPerPR)
+	assert.True(t, cfg.DevelopmentLogging())
+}
+
+// TestConfig_Validation - понятные ошибки валидации
+func TestConfig_Validation(t *testing.T) {
+	t.Run("Unknown field in file", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		path := writeConfigFile(t, "server:\n  prot: \"8080\"\n")
+
+		_, err := config.Load(path)
+		assert.ErrorContains(t, err, "prot")
+	})
+
+	t.Run("Malformed env value", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		t.Setenv("HTTP_READ_TIMEOUT", "ten seconds")
+
+		_, err := config.Load("")
+		assert.ErrorContains(t, err, "HTTP_READ_TIMEOUT")
+	})
+
+	t.Run("Invalid values are all reported", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		t.Setenv("APP_PORT", "0")
+		t.Setenv("DB_MAX_OPEN_CONNS", "5")
+		t.Setenv("DB_MAX_IDLE_CONNS", "10")
+		t.Setenv("LOG_LEVEL", "verbose")
+		t.Setenv("ASSIGNMENT_STRATEGY", "fair")
+
+		_, err := config.Load("")
+		assert.ErrorContains(t, err, "server.port")
+		assert.ErrorContains(t, err, "db.max_idle_conns")
+		assert.ErrorContains(t, err, "log.level")
+		assert.ErrorContains(t, err, "assignment.strategy")
+	})
+
+	t.Run("gRPC port must differ from HTTP port", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		t.Setenv("APP_PORT", "9090")
+		t.Setenv("GRPC_PORT", "9090")
+
+		_, err := config.Load("")
+		assert.ErrorContains(t, err, "grpc.port")
+
+		t.Setenv("GRPC_ENABLED", "false")
+		_, err = config.Load("")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Notifications need a webhook and valid templates", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		path := writeConfigFile(t, "notifications:\n  enabled: true\n  templates:\n    merged: \"{{.PR.Name\"\n")
+
+		_, err := config.Load(path)
+		assert.ErrorContains(t, err, "notifications.webhook_url")
+		assert.ErrorContains(t, err, "notifications.templates.merged")
+
+		t.Setenv("NOTIFY_WEBHOOK_URL", "https://hooks.slack.com/services/T0/B0/x")
+		_, err = config.Load(path)
+		assert.NotContains(t, err.Error(), "notifications.webhook_url")
+	})
+
+	t.Run("Digest needs an SMTP server", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		t.Setenv("DIGEST_ENABLED", "true")
+		t.Setenv("SMTP_PORT", "0")
+		path := writeConfigFile(t, "digest:\n  templates:\n    html_file: /nonexistent/digest.html\n")
+
+		_, err := config.Load(path)
+		assert.ErrorContains(t, err, "digest.smtp.host")
+		assert.ErrorContains(t, err, "digest.smtp.port")
+		assert.ErrorContains(t, err, "digest.smtp.from")
+		assert.ErrorContains(t, err, "digest.templates.html_file")
+
+		t.Setenv("SMTP_HOST", "smtp.example.com")
+		t.Setenv("SMTP_PORT", "587")
+		t.Setenv("SMTP_FROM", "Reviewer <reviewer@example.com>")
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, cfg.Digest.Interval)
+	})
+
+	t.Run("SLA check interval", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		t.Setenv("SLA_ENABLED", "true")
+		t.Setenv("SLA_CHECK_INTERVAL", "0s")
+
+		_, err := config.Load("")
+		assert.ErrorContains(t, err, "sla.interval")
+
+		t.Setenv("SLA_CHECK_INTERVAL", "5m")
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		assert.True(t, cfg.SLA.Enabled)
+		assert.Equal(t, 5*time.Minute, cfg.SLA.Interval)
+	})
+
+	t.Run("Job schedules", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+
+		path := writeConfigFile(t, "scheduler:\n  schedules:\n    sla: \"61 * * * *\"\n    backup: \"@daily\"\n")
+		_, err := config.Load(path)
+		assert.ErrorContains(t, err, "scheduler.schedules.sla")
+		assert.ErrorContains(t, err, "scheduler.schedules.backup")
+
+		path = writeConfigFile(t, "scheduler:\n  schedules:\n    digest: \"0 8 * * 1-5\"\n")
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "0 8 * * 1-5", cfg.Scheduler.Schedules["digest"])
+	})
+
+	t.Run("Tenants", func(t *testing.T) {
+		t.Setenv("ENV", "development")
+		path := writeConfigFile(t, `auth:
+  tenants:
+    - {id: acme, admin_token: acme-adm, user_token: acme-usr}
+    - {id: acme, admin_token: acme-adm-2, user_token: admin-token}
+    - {id: default, admin_token: d-adm, user_token: d-usr}
+    - {id: "Bad Name", user_token: x-usr}
+`)
+		_, err := config.Load(path)
+		assert.ErrorContains(t, err, `auth.tenants[1].id: duplicate tenant "acme"`)
+		assert.ErrorContains(t, err, "auth.tenants[1].user_token: must differ from auth.admin_token")
+		assert.ErrorContains(t, err, "auth.tenants[2].id")
+		assert.ErrorContains(t, err, "auth.tenants[3].id")
+		assert.ErrorContains(t, err, "auth.tenants[3].admin_token: is required")
+
+		path = writeConfigFile(t, "auth:\n  tenants:\n    - {id: acme, admin_token: acme-adm, user_token: acme-usr}\n")
+		cfg, err := config.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, []config.TenantAuth{{ID: "acme", AdminToken: "acme-adm", UserToken: "acme-usr"}}, cfg.Auth.Tenants)
+	})
+
+	t.Run("Production refuses insecure defaults", func(t *testing.T) {
+		t.Setenv("ENV", "production")
+
+		_, err := config.Load("")
+		assert.ErrorContains(t, err, "db.password")
+		assert.ErrorContains(t, err, "auth.enabled")
+		assert.ErrorContains(t, err, "auth.admin_token")
+	})
+
+	t.Run("Production with explicit secrets", func(t *testing.T) {
+		t.Setenv("ENV", "production")
+		t.Setenv("DB_PASSWORD", "s3cret")
+		t.Setenv("AUTH_ENABLED", "true")
+		t.Setenv("ADMIN_TOKEN", "adm-0123456789")
+		t.Setenv("USER_TOKEN", "usr-0123456789")
+
+		cfg, err := config.Load("")
+		require.NoError(t, err)
+		assert.True(t, cfg.IsProduction())
+	})
+}
+
+// TestConfig_Redacted - секреты скрываются при выводе
+func TestConfig_Redacted(t *testing.T) {
+	t.Setenv("ENV", "development")
+	t.Setenv("DB_PASSWORD", "s3cret")
+	t.Setenv("ADMIN_TOKEN", "adm-0123456789")
+	t.Setenv("NOTIFY_WEBHOOK_URL", "https://hooks.slack.com/services/T0/B0/secret-path")
+	t.Setenv("SMTP_PASSWORD", "smtp-pass")
+
+	cfg, err := config.Load(writeConfigFile(t, "auth:\n  tenants:\n    - {id: acme, admin_token: acme-secret-adm, user_token: acme-secret-usr}\n"))
+	require.NoError(t, err)
+
+	out, err := cfg.Redacted().YAML()
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(out), "s3cret")
+	assert.NotContains(t, string(out), "adm-0123456789")
+	assert.NotContains(t, string(out), "secret-path")
+	assert.NotContains(t, string(out), "smtp-pass")
+	assert.NotContains(t, string(out), "acme-secret")
+	assert.Contains(t, string(out), "acme")
+	assert.Equal(t, "s3cret", cfg.DB.Password, "original config must stay intact")
+	assert.Equal(t, "acme-secret-adm", cfg.Auth.Tenants[0].AdminToken, "original config must stay intact")
+}
@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"regexp"
+	"reviewer_pr/api"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Служебные маршруты не описываются в спецификации API.
+var infraRoutes = map[string]bool{
+	"GET /health": true,
+	"GET /livez":  true,
+	"GET /readyz": true,
+}
+
+var specParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// specRoutes возвращает "METHOD /path" для всех операций спецификации
+// с учётом servers[0].url; параметры пути приводятся к виду gin (:id).
+func specRoutes(t *testing.T, spec []byte) []string {
+	t.Helper()
+
+	var doc struct {
+		Servers []struct {
+			URL string `yaml:"url"`
+		} `yaml:"servers"`
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(spec, &doc))
+
+	prefix := ""
+	if len(doc.Servers) > 0 && strings.HasPrefix(doc.Servers[0].URL, "/") {
+		prefix = strings.TrimRight(doc.Servers[0].URL, "/")
+	}
+
+	var routes []string
+	for path, ops := range doc.Paths {
+		for method := range ops {
+			switch method {
+			case "get", "post", "put", "patch", "delete":
+				routes = append(routes, strings.ToUpper(method)+" "+prefix+specParam.ReplaceAllString(path, ":$1"))
+			}
+		}
+	}
+	return routes
+}
+
+// TestContract_RouterMatchesSpec - каждый маршрут роутера описан в OpenAPI, и наоборот
+func TestContract_RouterMatchesSpec(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	log := zap.NewNop()
+	r := router.Router(httpapi.New(service.New(repository.New(db), log), log), router.WithSwagger(false))
+
+	var served []string
+	for _, ri := range r.Routes() {
+		route := ri.Method + " " + ri.Path
+		if !infraRoutes[route] {
+			served = append(served, route)
+		}
+	}
+
+	documented := append(specRoutes(t, api.OpenAPISpec), specRoutes(t, api.OpenAPIV2Spec)...)
+
+	sort.Strings(served)
+	sort.Strings(documented)
+	assert.Equal(t, documented, served, "router and api/openapi*.yml are out of sync")
+}
+
+// TestContract_SpecErrorCodes - все коды ошибок сервиса перечислены в ErrorResponse спецификации
+func TestContract_SpecErrorCodes(t *testing.T) {
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Properties map[string]struct {
+						Enum []string `yaml:"enum"`
+					} `yaml:"properties"`
+				} `yaml:"properties"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	require.NoError(t, yaml.Unmarshal(api.OpenAPISpec, &doc))
+
+	schema, ok := doc.Components.Schemas["ErrorResponse"]
+	require.True(t, ok, "ErrorResponse schema is missing")
+	codes := schema.Properties["error"].Properties["code"].Enum
+	require.NotEmpty(t, codes)
+
+	for _, code := range []service.ErrorCode{
+		service.ErrorCodeTeamExists,
+		service.ErrorCodePRExists,
+		service.ErrorCodePRMerged,
+		service.ErrorCodeNotAssigned,
+		service.ErrorCodeNoCandidate,
+		service.ErrorCodeNotFound,
+		service.ErrorCodeValidation,
+		service.ErrorCodeInvalidRequest,
+		service.ErrorCodeInternal,
+		service.ErrorCodeUnauthorized,
+		service.ErrorCodeForbidden,
+		service.ErrorCodeIdempotencyKeyReused,
+		service.ErrorCodeIdempotencyInProgress,
+	} {
+		assert.Contains(t, codes, string(code))
+	}
+}
@@ -0,0 +1,113 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reviewer_pr/internal/config"
+	"reviewer_pr/internal/database"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestDatabase_ConnectRetries - при недоступной БД подключение повторяется и завершается ошибкой
+func TestDatabase_ConnectRetries(t *testing.T) {
+	cfg := config.Default().DB
+	cfg.Host = "127.0.0.1"
+	cfg.Port = "1"
+	cfg.ConnectRetries = 2
+	cfg.ConnectBackoff = 10 * time.Millisecond
+	cfg.ConnectMaxBackoff = 20 * time.Millisecond
+
+	start := time.Now()
+	db, err := database.ConnectDB(context.Background(), &cfg, zap.NewNop())
+
+	assert.Nil(t, db)
+	assert.ErrorContains(t, err, "after 3 attempts")
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "backoff should be applied")
+
+	t.Run("Cancelled context stops retries", func(t *testing.T) {
+		cfg.ConnectRetries = 100
+		cfg.ConnectBackoff = time.Second
+		cfg.ConnectMaxBackoff = time.Second
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := database.ConnectDB(ctx, &cfg, zap.NewNop())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("No replica configured", func(t *testing.T) {
+		replica, err := database.ConnectReplica(context.Background(), &cfg, zap.NewNop())
+		assert.NoError(t, err)
+		assert.Nil(t, replica)
+	})
+}
+
+// TestDatabase_ReadReplicaRouting - чтения /team/get, /users/getReview и /stats идут в реплику,
+// запись - в основную БД
+func TestDatabase_ReadReplicaRouting(t *testing.T) {
+	primary := testhelpers.SetupNamedTestDB(t, "primary")
+	replica := testhelpers.SetupNamedTestDB(t, "replica")
+	repo := repository.NewWithReplica(primary, replica)
+	log := zap.NewNop()
+	services := service.New(repo, log)
+	ctx := context.Background()
+
+	_, err := services.Teams.AddTeam(ctx, service.CreateTeamInput{
+		TeamName: "primary-team",
+		Members: []service.CreateTeamMemberInput{
+			{UserID: "p1", Username: "P1", IsActive: true},
+			{UserID: "p2", Username: "P2", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	// Запись попала только в основную БД - реплика ещё "не догнала"
+	_, err = services.Teams.GetTeam(ctx, "primary-team")
+	assert.Error(t, err, "team must be read from the replica")
+
+	testhelpers.CreateTestTeam(t, replica, "replica-team", 2)
+
+	res, err := services.Teams.GetTeam(ctx, "replica-team")
+	require.NoError(t, err)
+	assert.Len(t, res.Members, 2)
+
+	// Запись PR идёт в основную БД, где есть автор
+	_, err = services.PRs.CreateWithAutoAssign(ctx, service.CreatePRInput{ID: "PR-R1", Name: "Replica", AuthorID: "p1"})
+	require.NoError(t, err)
+
+	stats, err := services.Stats.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stats.ByPR, "stats must be read from the replica")
+
+	prs, err := services.PRs.GetReviewsByUser(ctx, "p2")
+	require.NoError(t, err)
+	assert.Empty(t, prs, "reviews must be read from the replica")
+
+	t.Run("Readiness reports replica", func(t *testing.T) {
+		handler := httpapi.New(services, log)
+		r := router.Router(handler)
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp httpapi.ReadinessDTO
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "up", resp.Checks["replica"].Status)
+	})
+}
@@ -0,0 +1,399 @@
+package service_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/mail"
+	"path/filepath"
+	"reviewer_pr/internal/digest"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/pkg/client"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStub - минимальный SMTP сервер: принимает письма и сохраняет их; rejectRcpt отклоняет получателей
+type smtpStub struct {
+	ln net.Listener
+
+	mu         sync.Mutex
+	rejectRcpt bool
+	messages   []smtpMessage
+}
+
+type smtpMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStub{ln: ln}
+	go s.serve()
+	t.Cleanup(func() { _ = ln.Close() })
+	return s
+}
+
+func (s *smtpStub) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) received() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+func (s *smtpStub) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStub) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	var msg smtpMessage
+	reply("220 localhost ESMTP stub")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg = smtpMessage{From: strings.Trim(line[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			reject := s.rejectRcpt
+			s.mu.Unlock()
+			if reject {
+				reply("550 mailbox unavailable")
+				continue
+			}
+			msg.To = append(msg.To, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			msg.Data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// parsedMail - письмо, разобранное на заголовки и текстовую/HTML части
+type parsedMail struct {
+	Subject string
+	To      string
+	Text    string
+	HTML    string
+}
+
+func parseMail(t *testing.T, data string) parsedMail {
+	t.Helper()
+	m, err := mail.ReadMessage(strings.NewReader(data))
+	require.NoError(t, err)
+
+	var dec mime.WordDecoder
+	subject, err := dec.DecodeHeader(m.Header.Get("Subject"))
+	require.NoError(t, err)
+	out := parsedMail{Subject: subject, To: m.Header.Get("To")}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(p)
+		require.NoError(t, err)
+		switch {
+		case strings.HasPrefix(p.Header.Get("Content-Type"), "text/plain"):
+			out.Text = string(body)
+		case strings.HasPrefix(p.Header.Get("Content-Type"), "text/html"):
+			out.HTML = string(body)
+		}
+	}
+	return out
+}
+
+// startDigestAPI поднимает API, сервисы которого отправляют дайджесты через stub
+func startDigestAPI(t *testing.T, stub *smtpStub) *testAPI {
+	t.Helper()
+	mailer, err := digest.NewSMTP(digest.SMTPConfig{Host: "127.0.0.1", Port: stub.port(), From: "Reviewer <reviewer@example.com>"})
+	require.NoError(t, err)
+
+	return startAPI(t, withService(service.WithMailer(mailer)))
+}
+
+// seedDigest создает команду backend и PR автора u1: pr-1 и pr-2 открыты, pr-3 смержен;
+// ревьюверы во всех PR - u2 и u3
+func seedDigest(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	for _, pr := range []client.CreatePullRequest{
+		{PullRequestID: "pr-1", PullRequestName: "Add <search>", AuthorID: "u1"},
+		{PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "u1"},
+		{PullRequestID: "pr-3", PullRequestName: "Old change", AuthorID: "u1"},
+	} {
+		_, err := c.CreatePullRequest(ctx, pr)
+		require.NoError(t, err)
+	}
+	_, err = c.MergePullRequest(ctx, "pr-3")
+	require.NoError(t, err)
+}
+
+// TestDigest_Subscription - подписка на дайджест, значения по умолчанию, валидация и отписка
+func TestDigest_Subscription(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+
+	sub, err := c.GetDigest(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, &client.DigestSubscription{UserID: "u2"}, sub)
+
+	sub, err = c.SetDigest(ctx, client.DigestSubscription{UserID: "u2", Enabled: true, Email: "bob@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, &client.DigestSubscription{UserID: "u2", Enabled: true, Email: "bob@example.com", SendAt: "09:00", Timezone: "UTC"}, sub)
+
+	sub, err = c.SetDigest(ctx, client.DigestSubscription{UserID: "u2", Enabled: true, Email: "bob@example.com", SendAt: "18:30", Timezone: "Asia/Tokyo"})
+	require.NoError(t, err)
+	got, err := c.GetDigest(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, sub, got)
+	assert.Equal(t, "18:30", got.SendAt)
+	assert.Equal(t, "Asia/Tokyo", got.Timezone)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := c.SetDigest(ctx, client.DigestSubscription{UserID: "u3", Enabled: true, Email: "Carol <carol@", SendAt: "25:00", Timezone: "Mars/Olympus"})
+		require.ErrorIs(t, err, client.ErrValidation)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		fields := make([]string, 0, len(apiErr.Details))
+		for _, d := range apiErr.Details {
+			fields = append(fields, d.Field)
+		}
+		assert.ElementsMatch(t, []string{"email", "send_at", "timezone"}, fields)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		_, err := c.SetDigest(ctx, client.DigestSubscription{UserID: "nobody", Enabled: true, Email: "x@example.com"})
+		require.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.GetDigest(ctx, "nobody")
+		require.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		sub, err := c.SetDigest(ctx, client.DigestSubscription{UserID: "u2"})
+		require.NoError(t, err)
+		assert.False(t, sub.Enabled)
+
+		got, err := c.GetDigest(ctx, "u2")
+		require.NoError(t, err)
+		assert.False(t, got.Enabled)
+	})
+}
+
+// TestDigest_Preview - предпросмотр содержит только открытые PR с автором и возрастом, HTML экранируется
+func TestDigest_Preview(t *testing.T) {
+	ctx := context.Background()
+	srv := startAPI(t)
+	c := newClient(t, srv.URL)
+	seedDigest(t, c)
+
+	_, err := c.SetDigest(ctx, client.DigestSubscription{UserID: "u2", Enabled: true, Email: "bob@example.com", Timezone: "Europe/Moscow"})
+	require.NoError(t, err)
+
+	p, err := c.PreviewDigest(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Moscow", p.Timezone)
+	assert.Equal(t, "2 pull requests waiting for your review", p.Subject)
+	require.Len(t, p.PullRequests, 2)
+	assert.Equal(t, "pr-1", p.PullRequests[0].PullRequestID)
+	assert.Equal(t, "pr-2", p.PullRequests[1].PullRequestID)
+	assert.Equal(t, "Alice", p.PullRequests[0].AuthorName)
+	assert.GreaterOrEqual(t, p.PullRequests[0].AgeSeconds, int64(0))
+
+	assert.Contains(t, p.Text, "Hi Bob,")
+	assert.Contains(t, p.Text, "- Add <search> (pr-1) by Alice, open for 0m")
+	assert.NotContains(t, p.Text, "pr-3")
+	assert.Contains(t, p.HTML, "Add &lt;search&gt; (pr-1)")
+
+	t.Run("Raw formats", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/users/digestPreview?user_id=u2&format=html")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Equal(t, p.HTML, string(body))
+
+		resp, err = http.Get(srv.URL + "/users/digestPreview?user_id=u2&format=pdf")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Not subscribed user", func(t *testing.T) {
+		p, err := c.PreviewDigest(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, "UTC", p.Timezone)
+		assert.Empty(t, p.PullRequests)
+	})
+}
+
+// TestDigest_SendDue - дайджест отправляется раз в день в заданное время по часовому поясу пользователя
+func TestDigest_SendDue(t *testing.T) {
+	ctx := context.Background()
+	stub := newSMTPStub(t)
+	srv := startDigestAPI(t, stub)
+	svc := srv.svc
+	c := newClient(t, srv.URL)
+	seedDigest(t, c)
+
+	_, err := c.SetDigest(ctx, client.DigestSubscription{UserID: "u2", Enabled: true, Email: "bob@example.com", SendAt: "09:00", Timezone: "Europe/Moscow"})
+	require.NoError(t, err)
+	// у u1 нет PR на ревью - письмо не отправляется
+	_, err = c.SetDigest(ctx, client.DigestSubscription{UserID: "u1", Enabled: true, Email: "alice@example.com", SendAt: "09:00", Timezone: "Europe/Moscow"})
+	require.NoError(t, err)
+
+	// 09:00 по Москве через два дня - 06:00 UTC
+	day := time.Now().UTC().Add(48 * time.Hour).Truncate(24 * time.Hour)
+	at := day.Add(6 * time.Hour)
+
+	sent, err := svc.Digests.SendDue(ctx, at.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	sent, err = svc.Digests.SendDue(ctx, at)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	msgs := stub.received()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "reviewer@example.com", msgs[0].From)
+	assert.Equal(t, []string{"bob@example.com"}, msgs[0].To)
+
+	m := parseMail(t, msgs[0].Data)
+	assert.Equal(t, "<bob@example.com>", m.To)
+	assert.Equal(t, "2 pull requests waiting for your review", m.Subject)
+	assert.Regexp(t, `- Add <search> \(pr-1\) by Alice, open for [12]d \d+h`, m.Text)
+	assert.Contains(t, m.Text, "MSK")
+	assert.Contains(t, m.HTML, "<td>Add &lt;search&gt; (pr-1)</td><td>Alice</td>")
+
+	sub, err := c.GetDigest(ctx, "u2")
+	require.NoError(t, err)
+	require.NotNil(t, sub.LastSentAt)
+	assert.True(t, sub.LastSentAt.Equal(at))
+
+	t.Run("Once a day", func(t *testing.T) {
+		sent, err := svc.Digests.SendDue(ctx, at.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+
+		sent, err = svc.Digests.SendDue(ctx, at.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Len(t, stub.received(), 2)
+	})
+
+	t.Run("Failed delivery is retried", func(t *testing.T) {
+		stub.mu.Lock()
+		stub.rejectRcpt = true
+		stub.mu.Unlock()
+
+		next := at.Add(48 * time.Hour)
+		sent, err := svc.Digests.SendDue(ctx, next)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "user u2")
+		assert.Equal(t, 0, sent)
+
+		stub.mu.Lock()
+		stub.rejectRcpt = false
+		stub.mu.Unlock()
+
+		sent, err = svc.Digests.SendDue(ctx, next.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 1, sent)
+	})
+}
+
+// TestCLI_Digest - подписка и предпросмотр дайджеста через reviewerctl
+func TestCLI_Digest(t *testing.T) {
+	srv := startAPI(t)
+	seedDigest(t, newClient(t, srv.URL))
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	run := func(args ...string) (string, string, int) {
+		return runCLI(t, cfgPath, append([]string{"--server", srv.URL}, args...)...)
+	}
+
+	out, stderr, code := run("user", "digest", "set", "u2", "--email", "bob@example.com", "--at", "08:15", "--tz", "Europe/Berlin")
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `u2\s+yes\s+bob@example.com\s+08:15\s+Europe/Berlin\s+-`, out)
+
+	out, _, code = run("user", "digest", "preview", "u2")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Subject: 2 pull requests waiting for your review")
+	assert.Contains(t, out, "Fix login (pr-2) by Alice")
+
+	out, _, code = run("user", "digest", "off", "u2", "-o", "json")
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, `{"user_id":"u2","enabled":false}`, out)
+
+	_, stderr, code = run("user", "digest", "set", "u2")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `"email" not set`)
+}
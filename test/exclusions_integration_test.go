@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exclusionsSetup создаёт команду backend с u1–u4, запрещает назначать u2 на
+// PR автора u1 и отключает автоматическое назначение u3.
+func exclusionsSetup(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.V2UpdateTeam(ctx, "backend", []client.TeamMember{{UserID: "u4", Username: "Dave", IsActive: true}}, 0)
+	require.NoError(t, err)
+
+	_, err = c.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "u2", Reason: "pair programming"})
+	require.NoError(t, err)
+	on, err := c.SetAutoAssign(ctx, "u3", false)
+	require.NoError(t, err)
+	require.False(t, on)
+}
+
+// TestExclusions_CRUD - правила исключения и отказ от автоматического назначения
+func TestExclusions_CRUD(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	exclusionsSetup(t, c)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := c.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "u1"})
+		assert.ErrorIs(t, err, client.ErrValidation)
+		_, err = c.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "ghost"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.RemoveExclusion(ctx, "u2", "u1")
+		assert.ErrorIs(t, err, client.ErrNotFound, "exclusions are one-way")
+		_, err = c.GetExclusions(ctx, "ghost")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		rows, err := c.GetExclusions(ctx, "u2")
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "u1", rows[0].AuthorID)
+		assert.Equal(t, "pair programming", rows[0].Reason)
+		assert.False(t, rows[0].CreatedAt.IsZero())
+
+		rows, err = c.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "u2", Reason: "mentor"})
+		require.NoError(t, err)
+		require.Len(t, rows, 1, "adding again updates the reason")
+		assert.Equal(t, "mentor", rows[0].Reason)
+
+		rows, err = c.GetExclusions(ctx, "u4")
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+
+	t.Run("Opt-out is visible on the user", func(t *testing.T) {
+		u, err := c.GetUser(ctx, "u3")
+		require.NoError(t, err)
+		assert.True(t, u.AutoAssignOptOut)
+		u, err = c.GetUser(ctx, "u4")
+		require.NoError(t, err)
+		assert.False(t, u.AutoAssignOptOut)
+	})
+}
+
+// TestExclusions_Assignment - исключённые и отказавшиеся не выбираются автоматически,
+// но отказавшегося можно назначить явно
+func TestExclusions_Assignment(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	exclusionsSetup(t, c)
+
+	res, err := c.CreatePullRequestWithReasons(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u4"}, res.PullRequest.AssignedReviewers)
+	assert.ElementsMatch(t, []client.SkippedCandidate{
+		{UserID: "u2", Reason: "excluded"},
+		{UserID: "u3", Reason: "opted_out"},
+	}, res.Skipped)
+
+	change := client.ReviewerChange{PullRequestID: "pr-1", ReviewerID: "u2"}
+	_, err = c.AddReviewer(ctx, change)
+	assert.ErrorIs(t, err, client.ErrReviewerNotAllowed)
+
+	change.ReviewerID = "u3"
+	pr, err := c.AddReviewer(ctx, change)
+	require.NoError(t, err, "opted-out users can still be requested by name")
+	assert.ElementsMatch(t, []string{"u3", "u4"}, pr.AssignedReviewers)
+
+	in := client.ReviewerReassign{PullRequestID: "pr-1", OldReviewerID: "u4"}
+	_, err = c.ReassignReviewerTo(ctx, in)
+	assert.ErrorIs(t, err, client.ErrNoCandidate)
+
+	in.NewReviewerID = "u2"
+	_, err = c.ReassignReviewerTo(ctx, in)
+	assert.ErrorIs(t, err, client.ErrReviewerNotAllowed)
+
+	_, err = c.RemoveExclusion(ctx, "u1", "u2")
+	require.NoError(t, err)
+	in.NewReviewerID = ""
+	moved, err := c.ReassignReviewerTo(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, "u2", moved.ReplacedBy)
+	assert.Equal(t, "team", moved.Reason)
+	assert.Empty(t, moved.Skipped)
+
+	// Правило действует в одну сторону: u1 можно назначить на PR автора u2.
+	_, err = c.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "u2"})
+	require.NoError(t, err)
+	res, err = c.CreatePullRequestWithReasons(ctx, client.CreatePullRequest{PullRequestID: "pr-2", PullRequestName: "Fix", AuthorID: "u2"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u1", "u4"}, res.PullRequest.AssignedReviewers)
+	assert.Equal(t, []client.SkippedCandidate{{UserID: "u3", Reason: "opted_out"}}, res.Skipped)
+}
+
+// TestCLI_Exclusions - reviewerctl user exclusions и user opt-out/opt-in
+func TestCLI_Exclusions(t *testing.T) {
+	srv := startAPI(t)
+	c := newClient(t, srv.URL)
+	_, err := c.AddTeam(context.Background(), clientTeam())
+	require.NoError(t, err)
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	_, _, code := runCLI(t, cfgPath, "config", "set-profile", "local", "--server", srv.URL)
+	require.Equal(t, 0, code)
+
+	out, errOut, code := runCLI(t, cfgPath, "user", "exclusions", "add", "u1", "u2", "--reason", "mentor")
+	require.Equal(t, 0, code, errOut)
+	assert.Regexp(t, `u1\s+u2\s+mentor`, out)
+
+	out, errOut, code = runCLI(t, cfgPath, "user", "opt-out", "u3")
+	require.Equal(t, 0, code, errOut)
+	assert.Regexp(t, `u3\s+no`, out)
+
+	out, errOut, code = runCLI(t, cfgPath, "pr", "create", "pr-1", "--name", "Add search", "--author", "u1")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "Skipped: ")
+	assert.Contains(t, out, "u2 (excluded)")
+	assert.Contains(t, out, "u3 (opted_out)")
+
+	out, errOut, code = runCLI(t, cfgPath, "user", "exclusions", "list")
+	require.Equal(t, 0, code, errOut)
+	assert.Regexp(t, `u1\s+u2\s+mentor`, out)
+
+	_, errOut, code = runCLI(t, cfgPath, "user", "exclusions", "rm", "u1", "u2")
+	require.Equal(t, 0, code, errOut)
+	_, errOut, code = runCLI(t, cfgPath, "user", "opt-in", "u3")
+	require.Equal(t, 0, code, errOut)
+
+	out, errOut, code = runCLI(t, cfgPath, "user", "exclusions", "list", "-o", "json")
+	require.Equal(t, 0, code, errOut)
+	assert.JSONEq(t, `[]`, out)
+}
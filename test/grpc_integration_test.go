@@ -0,0 +1,256 @@
+package service_test
+
+import (
+	"context"
+	"net"
+	reviewerv1 "reviewer_pr/api/gen/reviewer/v1"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/config"
+	"reviewer_pr/internal/grpcapi"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startGRPC поднимает gRPC-сервер поверх bufconn и возвращает клиентское соединение
+func startGRPC(t *testing.T, log *zap.Logger, opts ...grpcapi.Option) *grpc.ClientConn {
+	t.Helper()
+
+	db := testhelpers.SetupTestDB(t)
+	return serveGRPC(t, service.New(repository.New(db), log), log, opts...)
+}
+
+// serveGRPC поднимает gRPC-сервер с готовыми сервисами
+func serveGRPC(t *testing.T, services *service.Services, log *zap.Logger, opts ...grpcapi.Option) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcapi.New(services, log, opts...)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func errorReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+// TestGRPC_Workflow - полный сценарий через gRPC: команда, PR, переназначение, merge, статистика
+func TestGRPC_Workflow(t *testing.T) {
+	conn := startGRPC(t, zap.NewNop())
+	ctx := context.Background()
+
+	teams := reviewerv1.NewTeamServiceClient(conn)
+	users := reviewerv1.NewUserServiceClient(conn)
+	prs := reviewerv1.NewPullRequestServiceClient(conn)
+	stats := reviewerv1.NewStatsServiceClient(conn)
+
+	_, err := teams.CreateTeam(ctx, &reviewerv1.CreateTeamRequest{Team: &reviewerv1.Team{
+		TeamName: "backend",
+		Members: []*reviewerv1.TeamMember{
+			{UserId: "alice", Username: "Alice", IsActive: true},
+			{UserId: "bob", Username: "Bob", IsActive: true},
+			{UserId: "charlie", Username: "Charlie", IsActive: true},
+			{UserId: "dave", Username: "Dave", IsActive: true},
+		},
+	}})
+	require.NoError(t, err)
+
+	team, err := teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "backend"})
+	require.NoError(t, err)
+	assert.Len(t, team.GetTeam().GetMembers(), 4)
+
+	created, err := prs.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{
+		PullRequestId:   "PR-GRPC",
+		PullRequestName: "gRPC API",
+		AuthorId:        "alice",
+	})
+	require.NoError(t, err)
+	pr := created.GetPullRequest()
+	assert.Equal(t, reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, pr.GetStatus())
+	require.Len(t, pr.GetAssignedReviewers(), 2)
+	assert.NotContains(t, pr.GetAssignedReviewers(), "alice")
+
+	reassigned, err := prs.ReassignReviewer(ctx, &reviewerv1.ReassignReviewerRequest{
+		PullRequestId: "PR-GRPC",
+		OldReviewerId: pr.GetAssignedReviewers()[0],
+	})
+	require.NoError(t, err)
+	assert.Contains(t, reassigned.GetPullRequest().GetAssignedReviewers(), reassigned.GetReplacedBy())
+
+	reviews, err := users.GetReviews(ctx, &reviewerv1.GetReviewsRequest{UserId: reassigned.GetReplacedBy()})
+	require.NoError(t, err)
+	require.Len(t, reviews.GetPullRequests(), 1)
+	assert.Equal(t, "PR-GRPC", reviews.GetPullRequests()[0].GetPullRequestId())
+
+	merged, err := prs.MergePullRequest(ctx, &reviewerv1.MergePullRequestRequest{PullRequestId: "PR-GRPC"})
+	require.NoError(t, err)
+	assert.Equal(t, reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED, merged.GetPullRequest().GetStatus())
+	assert.NotNil(t, merged.GetPullRequest().GetMergedAt())
+
+	st, err := stats.GetStats(ctx, &reviewerv1.GetStatsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, st.GetByPr(), 1)
+
+	u, err := users.SetIsActive(ctx, &reviewerv1.SetIsActiveRequest{UserId: "dave", IsActive: false})
+	require.NoError(t, err)
+	assert.False(t, u.GetUser().GetIsActive())
+}
+
+// TestGRPC_Errors - маппинг кодов ошибок сервиса в статусы gRPC
+func TestGRPC_Errors(t *testing.T) {
+	conn := startGRPC(t, zap.NewNop())
+	ctx := context.Background()
+
+	teams := reviewerv1.NewTeamServiceClient(conn)
+	prs := reviewerv1.NewPullRequestServiceClient(conn)
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "missing"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, "NOT_FOUND", errorReason(err))
+	})
+
+	t.Run("Validation details", func(t *testing.T) {
+		_, err := teams.CreateTeam(ctx, &reviewerv1.CreateTeamRequest{Team: &reviewerv1.Team{TeamName: "bad name"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, "VALIDATION_ERROR", errorReason(err))
+
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, d := range status.Convert(err).Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok {
+				violations = br.GetFieldViolations()
+			}
+		}
+		require.NotEmpty(t, violations)
+		assert.Equal(t, "team_name", violations[0].GetField())
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		req := &reviewerv1.CreateTeamRequest{Team: &reviewerv1.Team{
+			TeamName: "dup",
+			Members:  []*reviewerv1.TeamMember{{UserId: "x1", Username: "X", IsActive: true}},
+		}}
+		_, err := teams.CreateTeam(ctx, req)
+		require.NoError(t, err)
+
+		_, err = teams.CreateTeam(ctx, req)
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		assert.Equal(t, "TEAM_EXISTS", errorReason(err))
+	})
+
+	t.Run("FailedPrecondition on merged PR", func(t *testing.T) {
+		_, err := prs.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{
+			PullRequestId: "PR-E", PullRequestName: "E", AuthorId: "x1",
+		})
+		require.NoError(t, err)
+		_, err = prs.MergePullRequest(ctx, &reviewerv1.MergePullRequestRequest{PullRequestId: "PR-E"})
+		require.NoError(t, err)
+
+		_, err = prs.ReassignReviewer(ctx, &reviewerv1.ReassignReviewerRequest{PullRequestId: "PR-E", OldReviewerId: "x1"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Equal(t, "PR_MERGED", errorReason(err))
+	})
+}
+
+// TestGRPC_Auth - общие с HTTP токены и роли
+func TestGRPC_Auth(t *testing.T) {
+	authenticator := auth.New(config.AuthConfig{Enabled: true, AdminToken: "admin-secret", UserToken: "user-secret"})
+	core, logs := observer.New(zap.InfoLevel)
+	conn := startGRPC(t, zap.New(core), grpcapi.WithAuth(authenticator))
+	teams := reviewerv1.NewTeamServiceClient(conn)
+
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+	req := &reviewerv1.CreateTeamRequest{Team: &reviewerv1.Team{
+		TeamName: "secured",
+		Members:  []*reviewerv1.TeamMember{{UserId: "s1", Username: "S", IsActive: true}},
+	}}
+
+	_, err := teams.GetTeam(context.Background(), &reviewerv1.GetTeamRequest{TeamName: "secured"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = teams.CreateTeam(withToken("user-secret"), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = teams.CreateTeam(withToken("admin-secret"), req)
+	require.NoError(t, err)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(withToken("user-secret"), grpcapi.MetadataRequestID, "grpc-req-1")
+	_, err = teams.GetTeam(ctx, &reviewerv1.GetTeamRequest{TeamName: "secured"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grpc-req-1"}, header.Get(grpcapi.MetadataRequestID))
+
+	entries := logs.FilterMessage("grpc request").All()
+	require.Len(t, entries, 4)
+	assert.NotContains(t, entries[0].ContextMap(), "actor", "an unauthenticated call has no actor")
+	for i, actor := range []string{"user", "admin", "user"} {
+		fields := entries[i+1].ContextMap()
+		assert.Equal(t, actor, fields["actor"])
+		assert.Equal(t, "default", fields["tenant"])
+	}
+	assert.Equal(t, "grpc-req-1", entries[3].ContextMap()["request_id"])
+}
+
+// TestGRPC_Health - gRPC health check повторяет readiness: NOT_SERVING при
+// недоступной БД и после начала остановки
+func TestGRPC_Health(t *testing.T) {
+	ctx := context.Background()
+
+	check := func(t *testing.T, conn *grpc.ClientConn) healthpb.HealthCheckResponse_ServingStatus {
+		t.Helper()
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		return resp.GetStatus()
+	}
+
+	t.Run("Shutting down", func(t *testing.T) {
+		services := service.New(repository.New(testhelpers.SetupNamedTestDB(t, "grpc-health")), zap.NewNop())
+		conn := serveGRPC(t, services, zap.NewNop())
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, conn))
+
+		services.Health.SetShuttingDown()
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, conn))
+	})
+
+	t.Run("Database down", func(t *testing.T) {
+		db := testhelpers.SetupNamedTestDB(t, "grpc-health-db")
+		conn := serveGRPC(t, service.New(repository.New(db), zap.NewNop()), zap.NewNop())
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, conn))
+
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, conn))
+	})
+}
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reviewer_pr/internal/config"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/server"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestHealth_LivezReadyz - проверка liveness/readiness эндпоинтов
+func TestHealth_LivezReadyz(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	services := service.New(repo, log)
+	handler := httpapi.New(services, log)
+	r := router.Router(handler)
+
+	t.Run("Liveness", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/livez", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Ready when database is up and migrated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp httpapi.ReadinessDTO
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "ready", resp.Status)
+		assert.Equal(t, "up", resp.Checks["database"].Status)
+		assert.Equal(t, "up", resp.Checks["migrations"].Status)
+		assert.Empty(t, resp.PendingMigrations)
+	})
+
+	t.Run("Not ready with pending migrations", func(t *testing.T) {
+		assert.NoError(t, db.Migrator().DropTable("pr_reviewers"))
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var resp httpapi.ReadinessDTO
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "not_ready", resp.Status)
+		assert.Equal(t, []string{"pr_reviewers"}, resp.PendingMigrations)
+	})
+
+	t.Run("Not ready while shutting down", func(t *testing.T) {
+		services.Health.SetShuttingDown()
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		// liveness не зависит от readiness
+		req = httptest.NewRequest("GET", "/livez", nil)
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Not ready when database is down", func(t *testing.T) {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		sqlDB.Close()
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var resp httpapi.ReadinessDTO
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.Equal(t, "down", resp.Checks["database"].Status)
+		assert.NotContains(t, w.Body.String(), "closed", "driver errors are only logged")
+
+		req = httptest.NewRequest("GET", "/health", nil)
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+// TestServer_DrainDelay - после сигнала остановки сервер ещё drain_delay
+// принимает запросы и отвечает 503 на /readyz, а затем останавливается
+func TestServer_DrainDelay(t *testing.T) {
+	services := service.New(repository.New(testhelpers.SetupNamedTestDB(t, "drain-delay")), zap.NewNop())
+	r := router.Router(httpapi.New(services, zap.NewNop()))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	srv := server.New(addr, config.Default().Server, r)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Run(ctx, srv, 300*time.Millisecond, time.Second, services.Health.SetShuttingDown, zap.NewNop())
+	}()
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/readyz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/readyz")
+		require.NoError(t, err, "the server keeps serving while draining")
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusServiceUnavailable
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not stop after the drain delay")
+	}
+}
@@ -0,0 +1,215 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func postIdempotent(r *gin.Engine, path, key string, payload any) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(httpapi.HeaderIdempotencyKey, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func idempotencyTeam() map[string]any {
+	return map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "alice", "username": "Alice", "is_active": true},
+			{"user_id": "bob", "username": "Bob", "is_active": true},
+			{"user_id": "charlie", "username": "Charlie", "is_active": true},
+			{"user_id": "dave", "username": "Dave", "is_active": true},
+			{"user_id": "eve", "username": "Eve", "is_active": true},
+		},
+	}
+}
+
+// TestIdempotency_Replay - повтор запроса с тем же ключом возвращает сохраненный ответ
+func TestIdempotency_Replay(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	services := service.New(repo, log)
+	r := router.Router(httpapi.New(services, log))
+
+	t.Run("Retried create returns original 201", func(t *testing.T) {
+		first := postIdempotent(r, "/team/add", "team-key-1", idempotencyTeam())
+		require.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get(httpapi.HeaderIdempotentReplayed))
+
+		retry := postIdempotent(r, "/team/add", "team-key-1", idempotencyTeam())
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get(httpapi.HeaderIdempotentReplayed))
+		assert.JSONEq(t, first.Body.String(), retry.Body.String())
+
+		// Без ключа повтор выполняется заново
+		noKey := postIdempotent(r, "/team/add", "", idempotencyTeam())
+		assert.Equal(t, http.StatusBadRequest, noKey.Code)
+	})
+
+	t.Run("Retried reassign returns same reviewer", func(t *testing.T) {
+		pr := map[string]any{
+			"pull_request_id":   "PR-IDEM-1",
+			"pull_request_name": "Idempotent",
+			"author_id":         "alice",
+		}
+		created := postIdempotent(r, "/pullRequest/create", "pr-key-1", pr)
+		require.Equal(t, http.StatusCreated, created.Code)
+
+		var createResp struct {
+			PR struct {
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+		}
+		require.NoError(t, json.Unmarshal(created.Body.Bytes(), &createResp))
+		require.NotEmpty(t, createResp.PR.AssignedReviewers)
+
+		reassign := map[string]any{
+			"pull_request_id": "PR-IDEM-1",
+			"old_reviewer_id": createResp.PR.AssignedReviewers[0],
+		}
+		first := postIdempotent(r, "/pullRequest/reassign", "reassign-key-1", reassign)
+		require.Equal(t, http.StatusOK, first.Code)
+
+		retry := postIdempotent(r, "/pullRequest/reassign", "reassign-key-1", reassign)
+		assert.Equal(t, http.StatusOK, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get(httpapi.HeaderIdempotentReplayed))
+		assert.JSONEq(t, first.Body.String(), retry.Body.String())
+	})
+
+	t.Run("Same key with different body is rejected", func(t *testing.T) {
+		other := idempotencyTeam()
+		other["team_name"] = "frontend"
+
+		w := postIdempotent(r, "/team/add", "team-key-1", other)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var errResp httpapi.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &errResp)
+		assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", errResp.Error.Code)
+	})
+
+	t.Run("Replay restores Location, ETag and request ID", func(t *testing.T) {
+		team := idempotencyTeam()
+		team["team_name"] = "platform"
+		for i, m := range team["members"].([]map[string]any) {
+			m["user_id"] = fmt.Sprintf("p%d", i)
+		}
+		first := postIdempotent(r, "/api/v2/teams", "v2-team-key", team)
+		require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+		require.NotEmpty(t, first.Header().Get("Location"))
+
+		retry := postIdempotent(r, "/api/v2/teams", "v2-team-key", team)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		for _, name := range []string{"Location", httpapi.HeaderETag, httpapi.HeaderRequestID} {
+			assert.Equal(t, first.Header().Get(name), retry.Header().Get(name), name)
+		}
+
+		failed := postIdempotent(r, "/api/v2/teams", "v2-team-key-2", team)
+		require.Equal(t, http.StatusBadRequest, failed.Code)
+		retry = postIdempotent(r, "/api/v2/teams", "v2-team-key-2", team)
+		var errResp httpapi.ErrorResponse
+		require.NoError(t, json.Unmarshal(retry.Body.Bytes(), &errResp))
+		assert.Equal(t, retry.Header().Get(httpapi.HeaderRequestID), errResp.RequestID,
+			"the replayed body and header name the same request")
+	})
+
+	t.Run("Invalid key is rejected", func(t *testing.T) {
+		w := postIdempotent(r, "/team/add", "bad\tkey", idempotencyTeam())
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestIdempotency_Expired - по истечении TTL запрос с тем же ключом выполняется заново
+func TestIdempotency_Expired(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	services := service.New(repo, log,
+		service.WithIdempotencyTTL(time.Hour),
+		service.WithClock(func() time.Time { return now }),
+	)
+	r := router.Router(httpapi.New(services, log))
+
+	first := postIdempotent(r, "/team/add", "expiring-key", idempotencyTeam())
+	require.Equal(t, http.StatusCreated, first.Code)
+
+	now = now.Add(59 * time.Minute)
+	replay := postIdempotent(r, "/team/add", "expiring-key", idempotencyTeam())
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, "true", replay.Header().Get(httpapi.HeaderIdempotentReplayed))
+
+	now = now.Add(time.Minute)
+	retry := postIdempotent(r, "/team/add", "expiring-key", idempotencyTeam())
+	assert.Equal(t, http.StatusBadRequest, retry.Code, "Expected request to be executed again")
+	assert.Empty(t, retry.Header().Get(httpapi.HeaderIdempotentReplayed))
+
+	// Повторный запрос сохранил новую запись, которая истекает через час
+	purged, err := services.Idempotency.PurgeExpired(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	now = now.Add(time.Hour)
+	purged, err = services.Idempotency.PurgeExpired(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+// TestIdempotency_AbandonedRace - брошенный ключ, который другой повтор уже
+// удалил и занял заново, не удаляется второй раз: запрос считается выполняющимся
+func TestIdempotency_AbandonedRace(t *testing.T) {
+	db := testhelpers.SetupNamedTestDB(t, "idempotency-race")
+	repo := repository.New(db)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	services := service.New(repo, zap.NewNop(), service.WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	in := service.BeginIdempotentInput{Key: "race-key", RequestHash: "hash", Method: "POST", Path: "/team/add"}
+	_, err := repo.Idempotency.Reserve(ctx, &models.IdempotencyKey{
+		Key: in.Key, RequestHash: in.RequestHash, Method: in.Method, Path: in.Path,
+		Status:    models.IdempotencyInProgress,
+		CreatedAt: now.Add(-2 * time.Minute),
+		ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	// Другой повтор успевает удалить брошенную запись и занять ключ.
+	raced := false
+	require.NoError(t, db.Callback().Delete().Before("gorm:delete").Register("test:race", func(tx *gorm.DB) {
+		if !raced && tx.Statement.Table == "idempotency_keys" {
+			raced = true
+			require.NoError(t, db.Exec("UPDATE idempotency_keys SET created_at = ? WHERE idempotency_key = ?", now, in.Key).Error)
+		}
+	}))
+
+	_, err = services.Idempotency.Begin(ctx, in)
+	var serr *service.Error
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, service.ErrorCodeIdempotencyInProgress, serr.Code)
+	assert.True(t, raced)
+}
@@ -0,0 +1,255 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const orgCSV = `team_name,user_id,username,is_active
+backend,u1,Alice,true
+backend,u2,Bob,false
+payments,u3,Carol,
+`
+
+func postImport(r *gin.Engine, query, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/team/import"+query, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestParseImport - разбор CSV и YAML с номерами строк
+func TestParseImport(t *testing.T) {
+	t.Run("CSV", func(t *testing.T) {
+		rows, err := service.ParseImport(service.ImportFormatCSV, strings.NewReader(orgCSV))
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, service.ImportRow{Line: 3, TeamName: "backend", UserID: "u2", Username: "Bob", IsActive: false}, rows[1])
+		assert.True(t, rows[2].IsActive, "empty is_active means active")
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		rows, err := service.ParseImport(service.ImportFormatYAML, strings.NewReader(`teams:
+  - team_name: backend
+    members:
+      - user_id: u1
+        username: Alice
+      - {user_id: u2, username: Bob, is_active: false}
+`))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, 4, rows[0].Line)
+		assert.True(t, rows[0].IsActive)
+		assert.Equal(t, 6, rows[1].Line)
+		assert.False(t, rows[1].IsActive)
+	})
+
+	t.Run("CSV header and values", func(t *testing.T) {
+		_, err := service.ParseImport(service.ImportFormatCSV, strings.NewReader("team,user_id,username\n"))
+		var serr *service.Error
+		require.ErrorAs(t, err, &serr)
+		assert.Equal(t, service.ErrorCodeValidation, serr.Code)
+
+		_, err = service.ParseImport(service.ImportFormatCSV, strings.NewReader("team_name,user_id,username,is_active\nbackend,u1,Alice,maybe\n"))
+		require.ErrorAs(t, err, &serr)
+		assert.Equal(t, "rows[2].is_active", serr.Details[0].Field)
+	})
+}
+
+// TestTeamImport - импорт через HTTP: dry-run, применение, деактивация отсутствующих
+func TestTeamImport(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	r := router.Router(httpapi.New(service.New(repo, log), log))
+	ctx := context.Background()
+
+	// Существующая команда: u1 будет переименован, u9 отсутствует в файле.
+	w := doV2(r, "POST", "/team/add", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "u1", "username": "Alice Old", "is_active": true},
+			{"user_id": "u9", "username": "Zed", "is_active": true},
+		},
+	}, nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	// Команды mobile нет в файле: её участники не деактивируются, даже если m1
+	// состоит и в backend.
+	w = doV2(r, "POST", "/team/add", map[string]any{
+		"team_name": "mobile",
+		"members": []map[string]any{
+			{"user_id": "m1", "username": "Mia", "is_active": true},
+			{"user_id": "m2", "username": "Max", "is_active": true},
+		},
+	}, nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = doV2(r, "POST", "/team/setMember", map[string]any{"team_name": "backend", "user_id": "m1"}, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	before, err := repo.Teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	t.Run("Dry run reports changes without writing", func(t *testing.T) {
+		w := postImport(r, "?dry_run=true", "text/csv", orgCSV)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var res httpapi.ImportResultDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.True(t, res.DryRun)
+		assert.Equal(t, httpapi.ImportSummaryDTO{
+			TeamsCreated: 1, UsersCreated: 2, UsersUpdated: 1, UsersDeactivated: 1,
+		}, res.Summary)
+
+		actions := map[string]string{}
+		for _, ch := range res.Changes {
+			actions[ch.Action+":"+ch.TeamName+":"+ch.UserID] = ch.Action
+			if ch.UserID == "u1" {
+				assert.Equal(t, 2, ch.Line)
+				assert.Equal(t, []httpapi.FieldChangeDTO{{Field: "username", From: "Alice Old", To: "Alice"}}, ch.Fields)
+			}
+		}
+		assert.Contains(t, actions, "create_team:payments:")
+		assert.Contains(t, actions, "deactivate_user:backend:u9")
+
+		_, err := repo.Teams.GetTeamByName(ctx, "payments")
+		assert.Error(t, err, "dry run must not create teams")
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		w := postImport(r, "", "text/csv", orgCSV)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		u1, err := repo.Users.GetUserByID(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", u1.Username)
+
+		u2, err := repo.Users.GetUserByID(ctx, "u2")
+		require.NoError(t, err)
+		assert.False(t, u2.IsActive, "is_active=false must be stored on insert")
+
+		u3, err := repo.Users.GetUserByID(ctx, "u3")
+		require.NoError(t, err)
+		assert.Equal(t, "payments", u3.TeamName)
+
+		u9, err := repo.Users.GetUserByID(ctx, "u9")
+		require.NoError(t, err)
+		assert.False(t, u9.IsActive)
+
+		for _, id := range []string{"m1", "m2"} {
+			u, err := repo.Users.GetUserByID(ctx, id)
+			require.NoError(t, err)
+			assert.True(t, u.IsActive, "%s belongs to a team outside the import", id)
+		}
+
+		after, err := repo.Teams.GetTeamByName(ctx, "backend")
+		require.NoError(t, err)
+		assert.Greater(t, after.Version, before.Version)
+	})
+
+	t.Run("Repeated import changes nothing", func(t *testing.T) {
+		w := postImport(r, "", "text/csv", orgCSV)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var res httpapi.ImportResultDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Empty(t, res.Changes)
+		assert.Equal(t, 3, res.Summary.Unchanged)
+	})
+
+	t.Run("Keep missing users", func(t *testing.T) {
+		w := postImport(r, "?deactivate_missing=false", "application/json",
+			`{"teams":[{"team_name":"backend","members":[{"user_id":"u1","username":"Alice"}]}]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var res httpapi.ImportResultDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Zero(t, res.Summary.UsersDeactivated)
+
+		u3, err := repo.Users.GetUserByID(ctx, "u3")
+		require.NoError(t, err)
+		assert.True(t, u3.IsActive)
+	})
+}
+
+// TestTeamImport_Errors - ошибки строк и откат всей операции
+func TestTeamImport_Errors(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	r := router.Router(httpapi.New(service.New(repo, log), log))
+
+	t.Run("Per-row errors", func(t *testing.T) {
+		w := postImport(r, "", "text/csv", "team_name,user_id,username\nbackend,u1,Alice\nbackend,,Bob\nbackend,u1,Again\n")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var errResp httpapi.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal(t, "VALIDATION_ERROR", errResp.Error.Code)
+
+		fields := map[string]string{}
+		for _, d := range errResp.Error.Details {
+			fields[d.Field] = d.Message
+		}
+		assert.Contains(t, fields, "rows[3].user_id")
+		assert.Contains(t, fields["rows[4].user_id"], "line 2")
+	})
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		w := postImport(r, "", "text/plain", orgCSV)
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Failure rolls back the whole import", func(t *testing.T) {
+		require.NoError(t, db.Exec(`CREATE TRIGGER fail_import BEFORE INSERT ON users
+			WHEN NEW.user_id = 'u3' BEGIN SELECT RAISE(ABORT, 'boom'); END`).Error)
+		t.Cleanup(func() { db.Exec("DROP TRIGGER fail_import") })
+
+		w := postImport(r, "", "text/csv", orgCSV)
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var count int64
+		require.NoError(t, db.Model(&models.User{}).Count(&count).Error)
+		assert.Zero(t, count)
+		require.NoError(t, db.Model(&models.Team{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
+// TestCLI_TeamImport - reviewerctl team import с dry-run
+func TestCLI_TeamImport(t *testing.T) {
+	srv := startAPI(t)
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yml")
+	file := filepath.Join(dir, "org.csv")
+	require.NoError(t, os.WriteFile(file, []byte(orgCSV), 0o600))
+
+	out, stderr, code := runCLI(t, cfgPath, "--server", srv.URL, "team", "import", file, "--dry-run")
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `3\s+create_user\s+backend\s+u2`, out)
+	assert.Contains(t, out, "Dry run, nothing applied: 2 teams created, 3 users created")
+
+	_, stderr, code = runCLI(t, cfgPath, "--server", srv.URL, "team", "import", file)
+	require.Equal(t, 0, code, stderr)
+
+	out, _, code = runCLI(t, cfgPath, "--server", srv.URL, "team", "get", "backend")
+	require.Equal(t, 0, code)
+	assert.Regexp(t, `u2\s+Bob\s+no`, out)
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedListing создает две команды: backend (3 участника, 1 неактивный, 1 открытый PR) и payments
+func seedListing(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.AddTeam(ctx, client.Team{TeamName: "payments", Members: []client.TeamMember{
+		{UserID: "p1", Username: "alan", IsActive: true},
+	}})
+	require.NoError(t, err)
+	_, err = c.SetIsActive(ctx, "u3", false)
+	require.NoError(t, err)
+
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "u2"})
+	require.NoError(t, err)
+	_, err = c.MergePullRequest(ctx, "pr-2")
+	require.NoError(t, err)
+}
+
+// TestTeamList - список команд со счетчиками и пагинацией
+func TestTeamList(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	seedListing(t, c)
+
+	list, err := c.ListTeams(ctx, client.Page{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), list.Total)
+	assert.Equal(t, 50, list.Limit)
+	assert.Equal(t, []client.TeamSummary{
+		{TeamName: "backend", MemberCount: 3, ActiveMemberCount: 2, OpenPRCount: 1},
+		{TeamName: "payments", MemberCount: 1, ActiveMemberCount: 1, OpenPRCount: 0},
+	}, list.Teams)
+
+	t.Run("Pagination", func(t *testing.T) {
+		page, err := c.ListTeams(ctx, client.Page{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), page.Total)
+		require.Len(t, page.Teams, 1)
+		assert.Equal(t, "payments", page.Teams[0].TeamName)
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		_, err := c.ListTeams(ctx, client.Page{Limit: 1000})
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, client.CodeValidation, apiErr.Code)
+		assert.Equal(t, "limit", apiErr.Details[0].Field)
+	})
+}
+
+// TestUserSearch - поиск пользователей по префиксу имени и команде, получение пользователя
+func TestUserSearch(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	seedListing(t, c)
+
+	t.Run("Prefix is case-insensitive and spans teams", func(t *testing.T) {
+		res, err := c.SearchUsers(ctx, client.UserSearch{Username: "AL"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), res.Total)
+		require.Len(t, res.Users, 2)
+		assert.Equal(t, "alan", res.Users[0].Username)
+		assert.Equal(t, "Alice", res.Users[1].Username)
+	})
+
+	t.Run("Prefix and team", func(t *testing.T) {
+		res, err := c.SearchUsers(ctx, client.UserSearch{Username: "al", TeamName: "payments"})
+		require.NoError(t, err)
+		require.Len(t, res.Users, 1)
+		assert.Equal(t, "p1", res.Users[0].UserID)
+	})
+
+	t.Run("Team with pagination", func(t *testing.T) {
+		res, err := c.SearchUsers(ctx, client.UserSearch{TeamName: "backend", Page: client.Page{Limit: 2}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), res.Total)
+		assert.Len(t, res.Users, 2)
+	})
+
+	t.Run("LIKE wildcards are literal", func(t *testing.T) {
+		res, err := c.SearchUsers(ctx, client.UserSearch{Username: "%"})
+		require.NoError(t, err)
+		assert.Empty(t, res.Users)
+	})
+
+	t.Run("Filter is required", func(t *testing.T) {
+		_, err := c.SearchUsers(ctx, client.UserSearch{})
+		require.ErrorIs(t, err, client.ErrValidation)
+	})
+
+	t.Run("Get user", func(t *testing.T) {
+		u, err := c.GetUser(ctx, "u3")
+		require.NoError(t, err)
+		assert.Equal(t, client.User{UserID: "u3", Username: "Carol", TeamName: "backend", IsActive: false}, *u)
+
+		_, err = c.GetUser(ctx, "nobody")
+		require.ErrorIs(t, err, client.ErrNotFound)
+	})
+}
+
+// TestCLI_ListAndSearch - reviewerctl team list, user search и user get
+func TestCLI_ListAndSearch(t *testing.T) {
+	srv := startAPI(t)
+	seedListing(t, newClient(t, srv.URL))
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+
+	out, stderr, code := runCLI(t, cfgPath, "--server", srv.URL, "team", "list", "--limit", "1")
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `backend\s+3\s+2\s+1`, out)
+	assert.Contains(t, out, "1 of 2 shown, next page: --offset 1")
+
+	out, stderr, code = runCLI(t, cfgPath, "--server", srv.URL, "user", "search", "--team", "backend")
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `u3\s+Carol\s+backend\s+no`, out)
+	assert.NotContains(t, out, "next page")
+
+	out, stderr, code = runCLI(t, cfgPath, "--server", srv.URL, "user", "get", "p1", "-o", "json")
+	require.Equal(t, 0, code, stderr)
+	assert.JSONEq(t, `{"user_id":"p1","username":"alan","team_name":"payments","is_active":true}`, out)
+}
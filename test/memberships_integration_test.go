@@ -0,0 +1,226 @@
+package service_test
+
+import (
+	"context"
+	"reviewer_pr/internal/database"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// membershipsSetup создаёт команды backend (u1–u3) и payments (u4, u5)
+// и добавляет u3 в payments, не меняя его основную команду.
+func membershipsSetup(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.AddTeam(ctx, client.Team{TeamName: "payments", Members: []client.TeamMember{
+		{UserID: "u4", Username: "Dave", IsActive: true},
+		{UserID: "u5", Username: "Eve", IsActive: true},
+	}})
+	require.NoError(t, err)
+	_, err = c.SetTeamMember(ctx, client.TeamMembership{TeamName: "payments", UserID: "u3", Role: "lead"})
+	require.NoError(t, err)
+}
+
+func memberIDs(team *client.Team) []string {
+	ids := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		ids = append(ids, m.UserID)
+	}
+	return ids
+}
+
+// TestMemberships_Teams - пользователь состоит в нескольких командах, а основная
+// команда не меняется, когда другая команда перечисляет его среди участников
+func TestMemberships_Teams(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	membershipsSetup(t, c)
+
+	payments, err := c.GetTeam(ctx, "payments")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u4", "u5"}, memberIDs(payments))
+	assert.Equal(t, "lead", payments.Members[0].Role)
+	require.NotNil(t, payments.Members[0].MembershipActive)
+	assert.True(t, *payments.Members[0].MembershipActive)
+	assert.Equal(t, "member", payments.Members[1].Role)
+
+	u3, err := c.GetUser(ctx, "u3")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", u3.TeamName)
+
+	team, err := c.AddTeam(ctx, client.Team{TeamName: "platform", Members: []client.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, memberIDs(team))
+	u1, err := c.GetUser(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", u1.TeamName, "another team listing a user does not move them")
+	backend, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2", "u3"}, memberIDs(backend))
+
+	users, err := c.SearchUsers(ctx, client.UserSearch{TeamName: "payments"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), users.Total)
+
+	teams, err := c.ListTeams(ctx, client.Page{})
+	require.NoError(t, err)
+	require.Len(t, teams.Teams, 3)
+	assert.Equal(t, "payments", teams.Teams[1].TeamName)
+	assert.Equal(t, int64(3), teams.Teams[1].MemberCount)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := c.SetTeamMember(ctx, client.TeamMembership{TeamName: "payments", UserID: "u1", Role: "owner"})
+		assert.ErrorIs(t, err, client.ErrValidation)
+		_, err = c.SetTeamMember(ctx, client.TeamMembership{TeamName: "payments", UserID: "ghost"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.SetTeamMember(ctx, client.TeamMembership{TeamName: "ghost", UserID: "u1"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		_, err := c.RemoveTeamMember(ctx, "backend", "u3")
+		assert.ErrorIs(t, err, client.ErrResourceInUse, "the primary team cannot be left")
+
+		team, err := c.RemoveTeamMember(ctx, "platform", "u1")
+		require.NoError(t, err)
+		assert.Empty(t, team.Members)
+		_, err = c.RemoveTeamMember(ctx, "platform", "u1")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		require.NoError(t, c.V2DeleteTeam(ctx, "platform", 0), "a team without memberships can be deleted")
+	})
+}
+
+// TestMemberships_Assignment - ревьюверы выбираются среди активных участников
+// всех команд автора
+func TestMemberships_Assignment(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	membershipsSetup(t, c)
+
+	pr, err := c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Refund", AuthorID: "u4"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u3", "u5"}, pr.AssignedReviewers)
+
+	_, err = c.SetIsActive(ctx, "u2", false)
+	require.NoError(t, err)
+	_, err = c.SetIsActive(ctx, "u1", false)
+	require.NoError(t, err)
+	pr, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-2", PullRequestName: "Ledger", AuthorID: "u3"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u4", "u5"}, pr.AssignedReviewers, "candidates come from both teams of the author")
+
+	inactive := false
+	payments, err := c.SetTeamMember(ctx, client.TeamMembership{TeamName: "payments", UserID: "u3", IsActive: &inactive})
+	require.NoError(t, err)
+	assert.Equal(t, "lead", payments.Members[0].Role, "the role is kept")
+	pr, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-3", PullRequestName: "Payout", AuthorID: "u4"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u5"}, pr.AssignedReviewers, "an inactive membership is not a candidate")
+
+	_, err = c.AddReviewer(ctx, client.ReviewerChange{PullRequestID: "pr-3", ReviewerID: "u3"})
+	assert.ErrorIs(t, err, client.ErrReviewerNotAllowed)
+}
+
+// TestMemberships_Import - пользователь указан в нескольких командах импорта
+func TestMemberships_Import(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	membershipsSetup(t, c)
+
+	res, err := c.ImportTeams(ctx, client.ImportCSV, []byte("team_name,user_id,username\n"+
+		"payments,u3,Carol\nbackend,u3,Carol\nbackend,u1,Alice\nbackend,u2,Bob\npayments,u4,Dave\npayments,u5,Eve\n"),
+		client.ImportOptions{})
+	require.NoError(t, err)
+	require.Len(t, res.Changes, 1)
+	assert.Equal(t, []client.FieldChange{{Field: "team_name", From: "backend", To: "payments"}}, res.Changes[0].Fields)
+
+	u3, err := c.GetUser(ctx, "u3")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", u3.TeamName, "the first row sets the primary team")
+	backend, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2", "u3"}, memberIDs(backend))
+
+	res, err = c.ImportTeams(ctx, client.ImportCSV, []byte("team_name,user_id,username\n"+
+		"payments,u3,Carol\nbackend,u1,Alice\nbackend,u2,Bob\npayments,u4,Dave\npayments,u5,Eve\n"),
+		client.ImportOptions{})
+	require.NoError(t, err)
+	require.Len(t, res.Changes, 1)
+	assert.Equal(t, []client.FieldChange{{Field: "teams", From: "backend payments", To: "payments"}}, res.Changes[0].Fields)
+	backend, err = c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, memberIDs(backend))
+
+	_, err = c.ImportTeams(ctx, client.ImportCSV, []byte("team_name,user_id,username\npayments,u3,Carol\nbackend,u3,Caroline\n"),
+		client.ImportOptions{})
+	assert.ErrorIs(t, err, client.ErrValidation)
+}
+
+// TestMemberships_PartialImport - импорт одной команды не исключает пользователя
+// из других команд и не меняет его основную команду вне файла
+func TestMemberships_PartialImport(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	membershipsSetup(t, c)
+
+	res, err := c.ImportTeams(ctx, client.ImportCSV, []byte("team_name,user_id,username\n"+
+		"payments,u3,Carol\npayments,u4,Dave\npayments,u5,Eve\n"), client.ImportOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, res.Changes)
+	assert.Equal(t, 3, res.Summary.Unchanged)
+
+	u3, err := c.GetUser(ctx, "u3")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", u3.TeamName, "the primary team is outside the import")
+	backend, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2", "u3"}, memberIDs(backend))
+	payments, err := c.GetTeam(ctx, "payments")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u4", "u5"}, memberIDs(payments))
+}
+
+// TestMemberships_LegacyData - пользователи существующей базы становятся
+// участниками своей команды
+func TestMemberships_LegacyData(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:memberships-legacy?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	require.NoError(t, db.AutoMigrate(&models.Tenant{}, &models.Team{}, &models.User{}))
+	require.NoError(t, db.Create(&models.Team{Name: "legacy", Version: 1}).Error)
+	require.NoError(t, db.Create(&models.User{ID: "l1", Username: "Old", TeamName: "legacy", IsActive: true, Version: 1}).Error)
+	require.NoError(t, db.Create(&models.User{ID: "l2", Username: "Older", TeamName: "legacy", IsActive: true, Version: 1}).Error)
+
+	require.NoError(t, database.AutoMigrate(db, zap.NewNop()))
+	require.NoError(t, database.AutoMigrate(db, zap.NewNop()), "the backfill runs once")
+
+	var memberships []models.TeamMembership
+	require.NoError(t, db.Order("user_id").Find(&memberships).Error)
+	require.Len(t, memberships, 2)
+	assert.Equal(t, "legacy", memberships[0].TeamName)
+	assert.Equal(t, models.MembershipMember, memberships[0].Role)
+	assert.True(t, memberships[0].IsActive)
+
+	svc := service.New(repository.New(db), zap.NewNop())
+	pr, err := svc.PRs.CreateWithAutoAssign(context.Background(), service.CreatePRInput{ID: "pr-1", Name: "Fix", AuthorID: "l1"})
+	require.NoError(t, err)
+	require.Len(t, pr.Reviewers, 1)
+	assert.Equal(t, "l2", pr.Reviewers[0].ID)
+}
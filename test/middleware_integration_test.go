@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestMiddleware_RequestID - проверка генерации и проброса X-Request-ID
+func TestMiddleware_RequestID(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	services := service.New(repo, log)
+	handler := httpapi.New(services, log)
+	r := router.Router(handler)
+
+	t.Run("Propagates incoming request id", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/team/get?team_name=nonexistent", nil)
+		req.Header.Set(httpapi.HeaderRequestID, "req-123")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "req-123", w.Header().Get(httpapi.HeaderRequestID))
+
+		var errResp httpapi.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &errResp)
+		assert.Equal(t, "req-123", errResp.RequestID)
+		assert.Equal(t, "NOT_FOUND", errResp.Error.Code)
+	})
+
+	t.Run("Generates request id when missing", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/health", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, w.Header().Get(httpapi.HeaderRequestID), 32)
+	})
+
+	t.Run("Replaces malformed request id", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.Header.Set(httpapi.HeaderRequestID, "bad id with spaces")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.NotEqual(t, "bad id with spaces", w.Header().Get(httpapi.HeaderRequestID))
+	})
+}
+
+// TestMiddleware_AccessLog - проверка структурированного access-лога
+func TestMiddleware_AccessLog(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core)
+	services := service.New(repo, log)
+	handler := httpapi.New(services, log)
+	r := router.Router(handler)
+
+	req := httptest.NewRequest("GET", "/team/get?team_name=nonexistent", nil)
+	req.Header.Set(httpapi.HeaderRequestID, "req-log")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	entries := logs.FilterMessage("http request").All()
+	if assert.Len(t, entries, 1) {
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "req-log", fields["request_id"])
+		assert.Equal(t, "/team/get", fields["route"])
+		assert.Equal(t, int64(http.StatusNotFound), fields["status"])
+		assert.Equal(t, "NOT_FOUND", fields["error_code"])
+		assert.Contains(t, fields, "latency")
+	}
+}
@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/notify"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/pkg/client"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// webhookStub - заглушка incoming webhook: сохраняет сообщения, первые fail запросов отвечает status
+type webhookStub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	fail     int
+	status   int
+	attempts int
+	messages []map[string]string
+}
+
+func newWebhookStub(t *testing.T) *webhookStub {
+	t.Helper()
+	s := &webhookStub{status: http.StatusInternalServerError}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.attempts++
+		if s.fail > 0 {
+			s.fail--
+			w.WriteHeader(s.status)
+			return
+		}
+		var payload map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		s.messages = append(s.messages, payload)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookStub) texts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, 0, len(s.messages))
+	for _, m := range s.messages {
+		out = append(out, m["text"])
+	}
+	return out
+}
+
+func (s *webhookStub) attemptCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+// startNotifyingAPI поднимает API, отправляющий уведомления в stub; возвращает клиент и функцию,
+// дожидающуюся доставки всех сообщений
+func startNotifyingAPI(t *testing.T, stub *webhookStub, cfg notify.Config) (*client.Client, func()) {
+	t.Helper()
+	log := zap.NewNop()
+	d, err := notify.NewDispatcher(notify.NewWebhook(stub.URL, notify.WithChannel("#review")), cfg, log)
+	require.NoError(t, err)
+
+	srv := startAPI(t, withService(service.WithNotifier(d)))
+
+	flush := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, d.Close(ctx))
+	}
+	return newClient(t, srv.URL), flush
+}
+
+// TestNotifications_Events - сообщения о назначении, переназначении и merge
+func TestNotifications_Events(t *testing.T) {
+	ctx := context.Background()
+	stub := newWebhookStub(t)
+	c, flush := startNotifyingAPI(t, stub, notify.Config{Handles: map[string]string{"u1": "<@U1>"}})
+
+	_, err := c.AddTeam(ctx, client.Team{TeamName: "backend", Members: []client.TeamMember{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: true},
+		{UserID: "u4", Username: "dave", IsActive: true},
+	}})
+	require.NoError(t, err)
+
+	pr, err := c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, pr.AssignedReviewers, 2)
+
+	re, err := c.ReassignReviewer(ctx, "pr-1", pr.AssignedReviewers[0])
+	require.NoError(t, err)
+
+	_, err = c.MergePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	flush()
+
+	names := map[string]string{"u2": "@bob", "u3": "@carol", "u4": "@dave"}
+	texts := stub.texts()
+	require.Len(t, texts, 3)
+	assert.Equal(t, names[pr.AssignedReviewers[0]]+", "+names[pr.AssignedReviewers[1]]+
+		": please review *Add search* (pr-1) by <@U1>", texts[0])
+	assert.Equal(t, names[re.ReplacedBy]+": please review *Add search* (pr-1) by <@U1> instead of "+
+		names[pr.AssignedReviewers[0]], texts[1])
+	assert.Contains(t, texts[2], "*Add search* (pr-1) by <@U1> was merged. Thanks, ")
+	assert.Contains(t, texts[2], names[re.ReplacedBy])
+
+	stub.mu.Lock()
+	assert.Equal(t, "#review", stub.messages[0]["channel"])
+	stub.mu.Unlock()
+}
+
+// TestNotifications_OptOut - пользователь с отключенными уведомлениями не получает сообщений и не упоминается
+func TestNotifications_OptOut(t *testing.T) {
+	ctx := context.Background()
+	stub := newWebhookStub(t)
+	c, flush := startNotifyingAPI(t, stub, notify.Config{})
+
+	_, err := c.AddTeam(ctx, client.Team{TeamName: "backend", Members: []client.TeamMember{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: false},
+	}})
+	require.NoError(t, err)
+
+	enabled, err := c.SetNotifications(ctx, "u2", false)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	// единственный ревьювер u2 отключил уведомления - сообщений нет
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Quiet", AuthorID: "u1"})
+	require.NoError(t, err)
+	_, err = c.MergePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+
+	// u3 становится ревьювером вместо u2 и упоминает его без @
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-2", PullRequestName: "Loud", AuthorID: "u1"})
+	require.NoError(t, err)
+	_, err = c.SetIsActive(ctx, "u3", true)
+	require.NoError(t, err)
+	_, err = c.ReassignReviewer(ctx, "pr-2", "u2")
+	require.NoError(t, err)
+	flush()
+
+	assert.Equal(t, []string{"@carol: please review *Loud* (pr-2) by @alice instead of bob"}, stub.texts())
+
+	_, err = c.SetNotifications(ctx, "nobody", true)
+	require.ErrorIs(t, err, client.ErrNotFound)
+}
+
+// TestNotifications_Retry - временные ошибки webhook повторяются, постоянные - нет
+func TestNotifications_Retry(t *testing.T) {
+	ctx := context.Background()
+	event := notify.Event{
+		Kind:      notify.EventAssigned,
+		Author:    testUser("u1", "alice"),
+		Reviewers: []models.User{testUser("u2", "bob")},
+	}
+	event.PR.ID, event.PR.Name = "pr-1", "Add search"
+
+	newDispatcher := func(t *testing.T, stub *webhookStub, cfg notify.Config) *notify.Dispatcher {
+		d, err := notify.NewDispatcher(notify.NewWebhook(stub.URL), cfg, zap.NewNop())
+		require.NoError(t, err)
+		return d
+	}
+
+	t.Run("Transient errors are retried", func(t *testing.T) {
+		stub := newWebhookStub(t)
+		stub.fail = 2
+		d := newDispatcher(t, stub, notify.Config{MaxAttempts: 3, RetryBackoff: time.Millisecond})
+
+		d.Notify(ctx, event)
+		require.NoError(t, d.Close(ctx))
+		assert.Equal(t, 3, stub.attemptCount())
+		assert.Equal(t, []string{"@bob: please review *Add search* (pr-1) by @alice"}, stub.texts())
+	})
+
+	t.Run("Client errors are not retried", func(t *testing.T) {
+		stub := newWebhookStub(t)
+		stub.fail, stub.status = 1, http.StatusBadRequest
+		d := newDispatcher(t, stub, notify.Config{MaxAttempts: 3, RetryBackoff: time.Millisecond})
+
+		d.Notify(ctx, event)
+		require.NoError(t, d.Close(ctx))
+		assert.Equal(t, 1, stub.attemptCount())
+		assert.Empty(t, stub.texts())
+	})
+
+	t.Run("Custom template", func(t *testing.T) {
+		stub := newWebhookStub(t)
+		d := newDispatcher(t, stub, notify.Config{Templates: notify.Templates{Assigned: "review {{.PR.ID}} → {{.Reviewers}}"}})
+
+		d.Notify(ctx, event)
+		require.NoError(t, d.Close(ctx))
+		assert.Equal(t, []string{"review pr-1 → @bob"}, stub.texts())
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		_, err := notify.NewDispatcher(notify.NewWebhook("http://127.0.0.1:1"), notify.Config{Templates: notify.Templates{Merged: "{{"}}, zap.NewNop())
+		assert.Error(t, err)
+	})
+}
+
+func testUser(id, username string) models.User {
+	return models.User{ID: id, Username: username, TeamName: "backend", IsActive: true}
+}
@@ -0,0 +1,275 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"reviewer_pr/pkg/client"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// reviewersSetup создаёт команду backend с PR pr-1 (ревьюверы u2 и u3), после
+// чего добавляет в неё u4 и заводит команду other с o1.
+func reviewersSetup(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	pr, err := c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+	_, err = c.V2UpdateTeam(ctx, "backend", []client.TeamMember{{UserID: "u4", Username: "Dave", IsActive: true}}, 0)
+	require.NoError(t, err)
+	_, err = c.AddTeam(ctx, client.Team{TeamName: "other", Members: []client.TeamMember{
+		{UserID: "o1", Username: "Olga", IsActive: true},
+	}})
+	require.NoError(t, err)
+}
+
+// TestReviewers_ManualChanges - добавление, снятие и замена ревьювера на указанного пользователя
+func TestReviewers_ManualChanges(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	reviewersSetup(t, c)
+
+	change := func(id string) client.ReviewerChange {
+		return client.ReviewerChange{PullRequestID: "pr-1", ReviewerID: id, RequestedBy: "u1"}
+	}
+
+	t.Run("Reviewer limit", func(t *testing.T) {
+		_, err := c.AddReviewer(ctx, change("u4"))
+		assert.ErrorIs(t, err, client.ErrReviewerLimit)
+	})
+
+	t.Run("Remove without replacement", func(t *testing.T) {
+		pr, err := c.RemoveReviewer(ctx, change("u2"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3"}, pr.AssignedReviewers)
+
+		_, err = c.RemoveReviewer(ctx, change("u2"))
+		assert.ErrorIs(t, err, client.ErrNotAssigned)
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		for id, want := range map[string]error{
+			"u1":    client.ErrReviewerNotAllowed,
+			"u3":    client.ErrAlreadyAssigned,
+			"o1":    client.ErrReviewerNotAllowed,
+			"ghost": client.ErrNotFound,
+		} {
+			_, err := c.AddReviewer(ctx, change(id))
+			assert.ErrorIs(t, err, want, id)
+		}
+
+		_, err := c.SetIsActive(ctx, "u4", false)
+		require.NoError(t, err)
+		_, err = c.AddReviewer(ctx, change("u4"))
+		assert.ErrorIs(t, err, client.ErrReviewerNotAllowed)
+		_, err = c.SetIsActive(ctx, "u4", true)
+		require.NoError(t, err)
+	})
+
+	t.Run("Add named reviewer", func(t *testing.T) {
+		pr, err := c.AddReviewer(ctx, change("u4"))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u3", "u4"}, pr.AssignedReviewers)
+	})
+
+	t.Run("Reassign to named reviewer", func(t *testing.T) {
+		in := client.ReviewerReassign{PullRequestID: "pr-1", OldReviewerID: "u3", NewReviewerID: "o1", RequestedBy: "u1"}
+		_, err := c.ReassignReviewerTo(ctx, in)
+		assert.ErrorIs(t, err, client.ErrReviewerNotAllowed)
+
+		in.NewReviewerID = "u4"
+		_, err = c.ReassignReviewerTo(ctx, in)
+		assert.ErrorIs(t, err, client.ErrAlreadyAssigned)
+
+		in.NewReviewerID = "u2"
+		res, err := c.ReassignReviewerTo(ctx, in)
+		require.NoError(t, err)
+		assert.Equal(t, "u2", res.ReplacedBy)
+		assert.ElementsMatch(t, []string{"u2", "u4"}, res.PullRequest.AssignedReviewers)
+	})
+
+	t.Run("History", func(t *testing.T) {
+		h, err := c.GetAssignmentHistory(ctx, "pr-1")
+		require.NoError(t, err)
+		require.Len(t, h.Events, 5)
+		for _, e := range h.Events {
+			assert.False(t, e.CreatedAt.IsZero())
+		}
+		strip := func(e client.AssignmentEvent) client.AssignmentEvent {
+			e.CreatedAt = time.Time{}
+			return e
+		}
+		seed := h.Events[0].Seed
+		assert.NotZero(t, seed)
+		assert.ElementsMatch(t, []client.AssignmentEvent{
+			{Kind: "assigned", ReviewerID: "u2", Reason: "team", Strategy: "random", Seed: seed},
+			{Kind: "assigned", ReviewerID: "u3", Reason: "team", Strategy: "random", Seed: seed},
+		}, []client.AssignmentEvent{strip(h.Events[0]), strip(h.Events[1])})
+		assert.Equal(t, []client.AssignmentEvent{
+			{Kind: "removed", ReviewerID: "u2", Reason: "manual", RequestedBy: "u1"},
+			{Kind: "added", ReviewerID: "u4", Reason: "manual", RequestedBy: "u1"},
+			{Kind: "reassigned", ReviewerID: "u2", PreviousReviewerID: "u3", Reason: "manual", RequestedBy: "u1"},
+		}, []client.AssignmentEvent{strip(h.Events[2]), strip(h.Events[3]), strip(h.Events[4])})
+
+		_, err = c.GetAssignmentHistory(ctx, "nope")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		log, err := c.ListAssignmentEvents(ctx, client.AuditQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), log.Total)
+		assert.Equal(t, "reassigned", log.Events[0].Kind, "newest first")
+		assert.Equal(t, "pr-1", log.Events[0].PullRequestID)
+
+		log, err = c.ListAssignmentEvents(ctx, client.AuditQuery{UserID: "u4"})
+		require.NoError(t, err)
+		require.Len(t, log.Events, 1)
+		assert.Equal(t, "added", log.Events[0].Kind)
+
+		log, err = c.ListAssignmentEvents(ctx, client.AuditQuery{UserID: "u1", Kind: "removed", Page: client.Page{Limit: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), log.Total)
+		assert.Equal(t, "u2", log.Events[0].ReviewerID)
+
+		log, err = c.ListAssignmentEvents(ctx, client.AuditQuery{Since: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		assert.Empty(t, log.Events)
+		log, err = c.ListAssignmentEvents(ctx, client.AuditQuery{Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), log.Total)
+
+		_, err = c.ListAssignmentEvents(ctx, client.AuditQuery{Kind: "merged"})
+		assert.ErrorIs(t, err, client.ErrValidation)
+	})
+
+	t.Run("Merged pull request", func(t *testing.T) {
+		_, err := c.MergePullRequest(ctx, "pr-1")
+		require.NoError(t, err)
+		_, err = c.RemoveReviewer(ctx, change("u2"))
+		assert.ErrorIs(t, err, client.ErrPRMerged)
+		_, err = c.ReassignReviewerTo(ctx, client.ReviewerReassign{PullRequestID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u3"})
+		assert.ErrorIs(t, err, client.ErrPRMerged)
+	})
+}
+
+// TestReviewers_ConcurrentAdd - параллельные добавления не превышают лимит ревьюверов
+func TestReviewers_ConcurrentAdd(t *testing.T) {
+	ctx := context.Background()
+	srv := startAPI(t)
+	c := newClient(t, srv.URL)
+	reviewersSetup(t, c)
+	_, err := c.V2UpdateTeam(ctx, "backend", []client.TeamMember{
+		{UserID: "u5", Username: "Eve", IsActive: true},
+		{UserID: "u6", Username: "Frank", IsActive: true},
+	}, 0)
+	require.NoError(t, err)
+	_, err = c.RemoveReviewer(ctx, client.ReviewerChange{PullRequestID: "pr-1", ReviewerID: "u2"})
+	require.NoError(t, err)
+
+	// Чтение ревьюверов замедлено, чтобы добавления гарантированно пересеклись.
+	require.NoError(t, srv.db.Callback().Query().After("gorm:query").Register("test:slow_reviewers", func(db *gorm.DB) {
+		if db.Statement.Table == "pr_reviewers" {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	var wg sync.WaitGroup
+	for _, id := range []string{"u2", "u4", "u5", "u6"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = c.AddReviewer(ctx, client.ReviewerChange{PullRequestID: "pr-1", ReviewerID: id})
+		}()
+	}
+	wg.Wait()
+
+	pr, err := c.V2GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(pr.AssignedReviewers), 2)
+}
+
+// TestReviewers_V2 - ручные изменения ревьюверов в v2 с ETag и история событий
+func TestReviewers_V2(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	reviewersSetup(t, c)
+
+	cur, err := c.V2GetReviewers(ctx, "pr-1")
+	require.NoError(t, err)
+
+	res, err := c.V2RemoveReviewer(ctx, "pr-1", "u3", "u1", cur.Version)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, res.Reviewers)
+	assert.Greater(t, res.Version, cur.Version)
+
+	_, err = c.V2AddReviewer(ctx, "pr-1", "u4", "", cur.Version)
+	assert.ErrorIs(t, err, client.ErrPreconditionFailed, "stale version")
+
+	res, err = c.V2AddReviewer(ctx, "pr-1", "u4", "", res.Version)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u2", "u4"}, res.Reviewers)
+
+	_, err = c.V2ReassignReviewer(ctx, "pr-1", "u4", 0)
+	require.NoError(t, err)
+
+	h, err := c.V2GetAssignmentHistory(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Len(t, h.Events, 5)
+	assert.Equal(t, "removed", h.Events[2].Kind)
+	assert.Equal(t, "u1", h.Events[2].RequestedBy)
+	assert.Equal(t, "added", h.Events[3].Kind)
+	assert.Empty(t, h.Events[3].RequestedBy)
+	last := h.Events[4]
+	assert.Equal(t, "reassigned", last.Kind)
+	assert.Equal(t, "u4", last.PreviousReviewerID)
+	assert.Equal(t, "u3", last.ReviewerID, "the only free member of the team")
+	assert.Equal(t, "team", last.Reason)
+}
+
+// TestCLI_Reviewers - reviewerctl pr reviewers, pr reassign --to, pr history и audit
+func TestCLI_Reviewers(t *testing.T) {
+	srv := startAPI(t)
+	reviewersSetup(t, newClient(t, srv.URL))
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	_, _, code := runCLI(t, cfgPath, "config", "set-profile", "local", "--server", srv.URL)
+	require.Equal(t, 0, code)
+
+	out, errOut, code := runCLI(t, cfgPath, "pr", "reviewers", "rm", "pr-1", "u2", "--by", "u1")
+	require.Equal(t, 0, code, errOut)
+	assert.Regexp(t, `pr-1\s+Add search\s+u1\s+OPEN\s+u3\s`, out)
+
+	_, errOut, code = runCLI(t, cfgPath, "pr", "reviewers", "add", "pr-1", "u4")
+	require.Equal(t, 0, code, errOut)
+
+	out, errOut, code = runCLI(t, cfgPath, "pr", "reassign", "pr-1", "--old", "u3", "--to", "u2", "--by", "u1")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "Replaced u3 with u2")
+
+	_, errOut, code = runCLI(t, cfgPath, "pr", "reviewers", "add", "pr-1", "u1")
+	assert.NotEqual(t, 0, code)
+	assert.Contains(t, errOut, "REVIEWER_NOT_ALLOWED")
+
+	out, errOut, code = runCLI(t, cfgPath, "pr", "history", "pr-1")
+	require.Equal(t, 0, code, errOut)
+	assert.Regexp(t, `removed\s+u2\s+-\s+manual\s+u1`, out)
+	assert.Regexp(t, `reassigned\s+u2\s+u3\s+manual\s+u1`, out)
+
+	out, errOut, code = runCLI(t, cfgPath, "audit", "--user", "u1", "--since", "1h")
+	require.Equal(t, 0, code, errOut)
+	assert.Regexp(t, `pr-1\s+reassigned\s+u2\s+u3\s+manual\s+u1`, out)
+	assert.Regexp(t, `pr-1\s+removed\s+u2\s+-\s+manual\s+u1`, out)
+	assert.NotContains(t, out, "added")
+
+	_, errOut, code = runCLI(t, cfgPath, "audit", "--since", "yesterday")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "--since")
+}
@@ -0,0 +1,321 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/scheduler"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"reviewer_pr/pkg/client"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newScheduler(t *testing.T, db *gorm.DB, locker scheduler.Locker, jobs ...scheduler.Job) *scheduler.Scheduler {
+	t.Helper()
+	s := scheduler.New(repository.New(db).JobRuns, locker, zap.NewNop())
+	for _, j := range jobs {
+		require.NoError(t, s.Add(j))
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = s.Stop(ctx)
+	})
+	return s
+}
+
+func jobRuns(t *testing.T, db *gorm.DB, job string) []models.JobRun {
+	t.Helper()
+	runs, _, err := repository.New(db).JobRuns.List(context.Background(), job, 100, 0)
+	require.NoError(t, err)
+	return runs
+}
+
+// TestScheduler_Parse - разбор расписаний и вычисление следующего запуска
+func TestScheduler_Parse(t *testing.T) {
+	at := time.Date(2025, 3, 14, 10, 17, 30, 0, time.UTC) // пятница
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"@every 1m", time.Date(2025, 3, 14, 10, 18, 0, 0, time.UTC)},
+		{"@every 15m", time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2025, 3, 14, 10, 20, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)},
+		{"30 8,18 * * *", time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Оба дня ограничены: достаточно совпадения любого из них.
+		{"0 0 1 * 6", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		s, err := scheduler.Parse(tc.spec)
+		require.NoError(t, err, tc.spec)
+		assert.Equal(t, tc.want, s.Next(at), tc.spec)
+		if !strings.HasPrefix(tc.spec, "@every") {
+			assert.Equal(t, tc.spec, s.String())
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "0 0 31 2 *", "@every 10ms", "@every soon", "@yearly"} {
+		_, err := scheduler.Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// TestScheduler_RunsJobs - задачи запускаются по расписанию, запуски записываются, Stop останавливает задачи
+func TestScheduler_RunsJobs(t *testing.T) {
+	// Задачи запускаются в одну и ту же секунду; у каждой своя база, чтобы
+	// одновременные записи не упирались в блокировку таблицы SQLite.
+	db := testhelpers.SetupTestDB(t)
+	failingDB := testhelpers.SetupNamedTestDB(t, "scheduler_failing")
+	var ok, failing atomic.Int32
+	s := newScheduler(t, db, scheduler.NewLocalLocker(),
+		scheduler.Job{Name: "ok", Schedule: scheduler.Every(time.Second), Run: func(context.Context, time.Time) error {
+			ok.Add(1)
+			return nil
+		}},
+	)
+	sf := newScheduler(t, failingDB, scheduler.NewLocalLocker(),
+		scheduler.Job{Name: "failing", Schedule: scheduler.Every(time.Second), Run: func(context.Context, time.Time) error {
+			failing.Add(1)
+			return errors.New("boom")
+		}},
+	)
+	s.Start(context.Background())
+	sf.Start(context.Background())
+
+	require.Eventually(t, func() bool {
+		return ok.Load() >= 2 && failing.Load() >= 2
+	}, 5*time.Second, 20*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, s.Stop(ctx))
+	require.NoError(t, sf.Stop(ctx))
+	stopped := ok.Load()
+
+	for _, r := range jobRuns(t, db, "ok") {
+		assert.Equal(t, models.JobRunSucceeded, r.Status)
+		assert.Equal(t, models.JobTriggerSchedule, r.Trigger)
+		require.NotNil(t, r.ScheduledAt)
+		require.NotNil(t, r.FinishedAt)
+	}
+	for _, r := range jobRuns(t, failingDB, "failing") {
+		assert.Equal(t, models.JobRunFailed, r.Status)
+		assert.Equal(t, "boom", r.Error)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	assert.Equal(t, stopped, ok.Load(), "jobs must stop with the scheduler")
+}
+
+// flakyStore отказывает в записи результата первые failures раз
+type flakyStore struct {
+	scheduler.Store
+	failures atomic.Int32
+}
+
+func (s *flakyStore) Finish(ctx context.Context, run *models.JobRun) error {
+	if s.failures.Add(-1) >= 0 {
+		return errors.New("database table is locked")
+	}
+	return s.Store.Finish(ctx, run)
+}
+
+// TestScheduler_RetriesFinish - неудачная запись результата повторяется, запуск не остается в статусе running
+func TestScheduler_RetriesFinish(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	store := &flakyStore{Store: repository.New(db).JobRuns}
+	store.failures.Store(2)
+	s := scheduler.New(store, scheduler.NewLocalLocker(), zap.NewNop())
+	require.NoError(t, s.Add(scheduler.Job{Name: "ok", Schedule: scheduler.Every(time.Hour), Run: func(context.Context, time.Time) error {
+		return nil
+	}}))
+	s.Start(context.Background())
+
+	_, err := s.Trigger(context.Background(), "ok")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, s.Stop(ctx))
+
+	runs := jobRuns(t, db, "ok")
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.JobRunSucceeded, runs[0].Status)
+	assert.NotNil(t, runs[0].FinishedAt)
+	assert.Less(t, store.failures.Load(), int32(0))
+}
+
+// TestScheduler_SingleRunPerSlot - два экземпляра с общей блокировкой запускают каждый слот один раз
+func TestScheduler_SingleRunPerSlot(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	locker := scheduler.NewLocalLocker()
+
+	var runs atomic.Int32
+	job := scheduler.Job{Name: "shared", Schedule: scheduler.Every(time.Second), Run: func(context.Context, time.Time) error {
+		runs.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}}
+	a := newScheduler(t, db, locker, job)
+	b := newScheduler(t, db, locker, job)
+	a.Start(context.Background())
+	b.Start(context.Background())
+
+	time.Sleep(3500 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, a.Stop(ctx))
+	require.NoError(t, b.Stop(ctx))
+
+	recorded := jobRuns(t, db, "shared")
+	assert.Len(t, recorded, int(runs.Load()))
+	slots := map[time.Time]bool{}
+	for _, r := range recorded {
+		require.NotNil(t, r.ScheduledAt)
+		assert.False(t, slots[*r.ScheduledAt], "slot %s ran twice", r.ScheduledAt)
+		slots[*r.ScheduledAt] = true
+	}
+	assert.GreaterOrEqual(t, len(slots), 2)
+}
+
+func startJobsAPI(t *testing.T, jobs ...scheduler.Job) (*testAPI, *scheduler.Scheduler) {
+	t.Helper()
+	db := testhelpers.SetupTestDB(t)
+	s := newScheduler(t, db, scheduler.NewLocalLocker(), jobs...)
+	s.Start(context.Background())
+	return startAPI(t, withDB(db), withService(service.WithScheduler(s))), s
+}
+
+// TestJobs_AdminAPI - список задач, ручной запуск и история запусков через /admin/jobs
+func TestJobs_AdminAPI(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	srv, _ := startJobsAPI(t,
+		scheduler.Job{Name: "digest", Schedule: scheduler.Every(time.Hour), Run: func(ctx context.Context, _ time.Time) error {
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return nil
+		}},
+		scheduler.Job{Name: "sla", Schedule: mustParse(t, "0 9 * * 1-5"), Run: func(context.Context, time.Time) error {
+			return errors.New("smtp down")
+		}},
+	)
+	c := newClient(t, srv.URL)
+
+	jobs, err := c.ListJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "digest", jobs[0].Job)
+	assert.Equal(t, "@every 1h0m0s", jobs[0].Schedule)
+	assert.Equal(t, "0 9 * * 1-5", jobs[1].Schedule)
+	assert.Eventually(t, func() bool {
+		jobs, err := c.ListJobs(ctx)
+		return err == nil && jobs[0].NextRunAt != nil
+	}, time.Second, 10*time.Millisecond)
+
+	run, err := c.TriggerJob(ctx, "digest")
+	require.NoError(t, err)
+	assert.Equal(t, "digest", run.Job)
+	assert.Equal(t, "manual", run.Trigger)
+	assert.Equal(t, "running", run.Status)
+	assert.Nil(t, run.ScheduledAt)
+
+	_, err = c.TriggerJob(ctx, "digest")
+	require.ErrorIs(t, err, client.ErrResourceInUse)
+	_, err = c.TriggerJob(ctx, "nope")
+	require.ErrorIs(t, err, client.ErrNotFound)
+
+	jobs, err = c.ListJobs(ctx)
+	require.NoError(t, err)
+	assert.True(t, jobs[0].Running)
+	require.NotNil(t, jobs[0].LastRun)
+	assert.Equal(t, run.RunID, jobs[0].LastRun.RunID)
+	close(release)
+
+	_, err = c.TriggerJob(ctx, "sla")
+	require.NoError(t, err)
+	var runs *client.JobRunList
+	require.Eventually(t, func() bool {
+		runs, err = c.ListJobRuns(ctx, client.JobRunsQuery{})
+		return err == nil && runs.Total == 2 && runs.Runs[0].Status != "running" && runs.Runs[1].Status != "running"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "sla", runs.Runs[0].Job)
+	assert.Equal(t, "failed", runs.Runs[0].Status)
+	assert.Equal(t, "smtp down", runs.Runs[0].Error)
+	assert.Equal(t, "succeeded", runs.Runs[1].Status)
+	require.NotNil(t, runs.Runs[1].FinishedAt)
+
+	runs, err = c.ListJobRuns(ctx, client.JobRunsQuery{Job: "digest", Page: client.Page{Limit: 1}})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, runs.Total)
+	require.Len(t, runs.Runs, 1)
+	assert.Equal(t, run.RunID, runs.Runs[0].RunID)
+}
+
+// TestJobs_WithoutScheduler - без планировщика список задач пуст, запуск возвращает 404
+func TestJobs_WithoutScheduler(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+
+	jobs, err := c.ListJobs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	_, err = c.TriggerJob(ctx, "digest")
+	require.ErrorIs(t, err, client.ErrNotFound)
+}
+
+// TestCLI_Jobs - reviewerctl jobs list/run/runs
+func TestCLI_Jobs(t *testing.T) {
+	srv, _ := startJobsAPI(t, scheduler.Job{Name: "sla", Schedule: scheduler.Every(time.Hour), Run: func(context.Context, time.Time) error {
+		return nil
+	}})
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	_, _, code := runCLI(t, cfgPath, "config", "set-profile", "local", "--server", srv.URL)
+	require.Equal(t, 0, code)
+
+	out, _, code := runCLI(t, cfgPath, "jobs", "list")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "SCHEDULE")
+	assert.Contains(t, out, "@every 1h0m0s")
+
+	out, _, code = runCLI(t, cfgPath, "jobs", "run", "sla")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "manual")
+
+	assert.Eventually(t, func() bool {
+		out, _, code := runCLI(t, cfgPath, "jobs", "runs", "sla")
+		return code == 0 && strings.Contains(out, "succeeded")
+	}, time.Second, 20*time.Millisecond)
+
+	_, errOut, code := runCLI(t, cfgPath, "jobs", "run", "nope")
+	assert.NotEqual(t, 0, code)
+	assert.Contains(t, errOut, "job not found")
+}
+
+func mustParse(t *testing.T, spec string) scheduler.Schedule {
+	t.Helper()
+	s, err := scheduler.Parse(spec)
+	require.NoError(t, err)
+	return s
+}
@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimulate_Candidates - пробное назначение объясняет статус каждого кандидата
+// и ничего не записывает
+func TestSimulate_Candidates(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.V2UpdateTeam(ctx, "backend", []client.TeamMember{
+		{UserID: "u4", Username: "Dave", IsActive: true},
+		{UserID: "u5", Username: "Eve", IsActive: false},
+		{UserID: "u6", Username: "Frank", IsActive: true},
+	}, 0)
+	require.NoError(t, err)
+	_, err = c.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "u2"})
+	require.NoError(t, err)
+	_, err = c.SetAutoAssign(ctx, "u6", false)
+	require.NoError(t, err)
+
+	res, err := c.SimulateAssignment(ctx, client.SimulateAssignment{AuthorID: "u1", ReviewersPerPR: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "random", res.Strategy)
+	assert.NotZero(t, res.Seed)
+	assert.Equal(t, 1, res.Count)
+	require.Len(t, res.Assignment, 1)
+	picked := res.Assignment[0].UserID
+	assert.Contains(t, []string{"u3", "u4"}, picked)
+
+	statuses := make(map[string]string)
+	for _, cand := range res.Candidates {
+		assert.Equal(t, "backend", cand.TeamName)
+		statuses[cand.UserID] = cand.Status
+	}
+	other := map[string]string{"u3": "u4", "u4": "u3"}[picked]
+	assert.Equal(t, map[string]string{
+		"u1":   "author",
+		"u2":   "excluded",
+		picked: "picked",
+		other:  "capacity",
+		"u5":   "inactive",
+		"u6":   "opted_out",
+	}, statuses)
+	require.Len(t, res.Load, 2)
+	assert.ElementsMatch(t, []string{"u3", "u4"}, []string{res.Load[0].UserID, res.Load[1].UserID})
+
+	again, err := c.SimulateAssignment(ctx, client.SimulateAssignment{AuthorID: "u1", ReviewersPerPR: 1, Seed: res.Seed})
+	require.NoError(t, err)
+	assert.Equal(t, res.Assignment, again.Assignment, "the reported seed replays the simulation")
+
+	_, err = c.V2GetPullRequest(ctx, service.SimulatedPRID)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	reviews, err := c.GetReviews(ctx, picked)
+	require.NoError(t, err)
+	assert.Empty(t, reviews.PullRequests)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := c.SimulateAssignment(ctx, client.SimulateAssignment{AuthorID: "ghost"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.SimulateAssignment(ctx, client.SimulateAssignment{AuthorID: "u1", Strategy: "round_robin"})
+		assert.ErrorIs(t, err, client.ErrValidation)
+		_, err = c.SimulateAssignment(ctx, client.SimulateAssignment{AuthorID: "u1", Count: service.MaxSimulatedPRs + 1})
+		assert.ErrorIs(t, err, client.ErrValidation)
+	})
+}
+
+// TestSimulate_Batch - серия PR показывает распределение нагрузки и
+// воспроизводится детерминированной стратегией
+func TestSimulate_Batch(t *testing.T) {
+	ctx := context.Background()
+	svc := assignmentServices(t, "simulate-batch")
+	_, err := svc.PRs.CreateWithAutoAssign(ctx, service.CreatePRInput{ID: "pr-open", Name: "Change", AuthorID: "a0"})
+	require.NoError(t, err)
+
+	in := service.SimulateInput{AuthorID: "a0", Strategy: service.StrategyDeterministic, Count: 9}
+	sim, err := svc.PRs.Simulate(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, service.StrategyDeterministic, sim.Strategy)
+	assert.Zero(t, sim.Seed)
+	require.Len(t, sim.Load, 5)
+
+	var open int64
+	assigned := 0
+	for _, l := range sim.Load {
+		open += l.OpenReviews
+		assigned += l.Assigned
+		assert.Equal(t, int64(4), l.OpenReviews+int64(l.Assigned), "least loaded candidates are picked, %s", l.User.ID)
+	}
+	assert.Equal(t, int64(2), open, "the existing pull request counts")
+	assert.Equal(t, 18, assigned)
+
+	again, err := svc.PRs.Simulate(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, pickedIDs(sim.Picks), pickedIDs(again.Picks))
+	assert.Equal(t, sim.Load, again.Load)
+
+	for _, id := range []string{service.SimulatedPRID, service.SimulatedPRID + "-9"} {
+		_, err = svc.PRs.GetPR(ctx, id)
+		var serr *service.Error
+		require.ErrorAs(t, err, &serr, "the simulation writes nothing")
+		assert.Equal(t, service.ErrorCodeNotFound, serr.Code)
+	}
+}
+
+// TestCLI_Simulate - reviewerctl pr simulate
+func TestCLI_Simulate(t *testing.T) {
+	srv := startAPI(t)
+	c := newClient(t, srv.URL)
+	_, err := c.AddTeam(context.Background(), clientTeam())
+	require.NoError(t, err)
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	_, _, code := runCLI(t, cfgPath, "config", "set-profile", "local", "--server", srv.URL)
+	require.Equal(t, 0, code)
+
+	out, errOut, code := runCLI(t, cfgPath, "pr", "simulate", "--author", "u1", "--strategy", "deterministic", "--count", "4")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "Strategy: deterministic, pull requests: 4")
+	assert.Regexp(t, `u1\s+backend\s+author`, out)
+	assert.Regexp(t, `u2\s+0\s+4\s+4`, out)
+	assert.Regexp(t, `u3\s+0\s+4\s+4`, out)
+
+	out, errOut, code = runCLI(t, cfgPath, "pr", "simulate", "--author", "u1", "-o", "json")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, `"candidates"`)
+}
@@ -0,0 +1,195 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// skillsTeam создаёт команду search: автор a1 и кандидаты s1..s4 с навыками
+// s1 go+postgres, s2 go:5, s3 go:1, у s4 навыков нет.
+func skillsTeam(t *testing.T, c *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := c.AddTeam(ctx, client.Team{TeamName: "search", Members: []client.TeamMember{
+		{UserID: "a1", Username: "Author", IsActive: true},
+		{UserID: "s1", Username: "Sam", IsActive: true},
+		{UserID: "s2", Username: "Sue", IsActive: true},
+		{UserID: "s3", Username: "Sid", IsActive: true},
+		{UserID: "s4", Username: "Sol", IsActive: true},
+	}})
+	require.NoError(t, err)
+
+	for _, s := range []struct {
+		user  string
+		skill client.Skill
+	}{
+		{"s1", client.Skill{Skill: "go", Proficiency: 2}},
+		{"s1", client.Skill{Skill: "postgres"}},
+		{"s2", client.Skill{Skill: "go", Proficiency: 5}},
+		{"s3", client.Skill{Skill: "go", Proficiency: 1}},
+	} {
+		_, err := c.SetSkill(ctx, s.user, s.skill)
+		require.NoError(t, err)
+	}
+}
+
+// TestSkills_CRUD - навыки пользователя: добавление, изменение, удаление и валидация
+func TestSkills_CRUD(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+
+	skills, err := c.GetSkills(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, client.UserSkills{UserID: "u2", Skills: []client.Skill{}}, *skills)
+
+	_, err = c.SetSkill(ctx, "u2", client.Skill{Skill: " Go ", Proficiency: 3})
+	require.NoError(t, err)
+	skills, err = c.SetSkill(ctx, "u2", client.Skill{Skill: "postgres"})
+	require.NoError(t, err)
+	assert.Equal(t, []client.Skill{{Skill: "go", Proficiency: 3}, {Skill: "postgres"}}, skills.Skills)
+
+	skills, err = c.SetSkill(ctx, "u2", client.Skill{Skill: "go", Proficiency: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []client.Skill{{Skill: "go", Proficiency: 5}, {Skill: "postgres"}}, skills.Skills)
+
+	skills, err = c.RemoveSkill(ctx, "u2", "POSTGRES")
+	require.NoError(t, err)
+	assert.Equal(t, []client.Skill{{Skill: "go", Proficiency: 5}}, skills.Skills)
+
+	t.Run("Validation", func(t *testing.T) {
+		var apiErr *client.Error
+		_, err := c.SetSkill(ctx, "u2", client.Skill{Skill: "go", Proficiency: 6})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "proficiency", apiErr.Details[0].Field)
+
+		_, err = c.SetSkill(ctx, "u2", client.Skill{Skill: "  "})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "skill", apiErr.Details[0].Field)
+
+		_, err = c.SetSkill(ctx, "ghost", client.Skill{Skill: "go"})
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.RemoveSkill(ctx, "u2", "rust")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+		_, err = c.GetSkills(ctx, "ghost")
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("Labels are validated", func(t *testing.T) {
+		_, err := c.CreatePullRequest(ctx, client.CreatePullRequest{
+			PullRequestID: "pr-1", PullRequestName: "x", AuthorID: "u1", Labels: []string{"go", ""},
+		})
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "labels[1]", apiErr.Details[0].Field)
+	})
+}
+
+// TestSkills_Assignment - выбор ревьюверов по меткам PR, нагрузке и активности
+func TestSkills_Assignment(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	skillsTeam(t, c)
+
+	create := func(id string, labels ...string) *client.CreatedPullRequest {
+		t.Helper()
+		res, err := c.CreatePullRequestWithReasons(ctx, client.CreatePullRequest{
+			PullRequestID: id, PullRequestName: id, AuthorID: "a1", Labels: labels,
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("Most matching skills first", func(t *testing.T) {
+		res := create("pr-1", "Go", "postgres")
+		assert.Equal(t, []string{"s1", "s2"}, res.PullRequest.AssignedReviewers)
+		assert.Equal(t, []string{"go", "postgres"}, res.PullRequest.Labels)
+		assert.Equal(t, []client.ReviewerPick{
+			{UserID: "s1", Reason: "skills", MatchedSkills: []client.Skill{{Skill: "go", Proficiency: 2}, {Skill: "postgres"}}},
+			{UserID: "s2", Reason: "skills", MatchedSkills: []client.Skill{{Skill: "go", Proficiency: 5}}},
+		}, res.Assignment)
+	})
+
+	t.Run("Skills beat load", func(t *testing.T) {
+		res := create("pr-2", "go", "postgres")
+		assert.Equal(t, []string{"s1", "s2"}, res.PullRequest.AssignedReviewers)
+		assert.Equal(t, int64(1), res.Assignment[0].OpenReviews)
+	})
+
+	t.Run("Least loaded without labels", func(t *testing.T) {
+		res := create("pr-3")
+		assert.ElementsMatch(t, []string{"s3", "s4"}, res.PullRequest.AssignedReviewers)
+		for _, p := range res.Assignment {
+			assert.Equal(t, "team", p.Reason)
+			assert.Zero(t, p.OpenReviews)
+			assert.Empty(t, p.MatchedSkills)
+		}
+	})
+
+	t.Run("Reassign prefers matching skills", func(t *testing.T) {
+		// У s3 и s4 по одному открытому ревью; s3 знает go.
+		res, err := c.ReassignReviewer(ctx, "pr-2", "s1")
+		require.NoError(t, err)
+		assert.Equal(t, "s3", res.ReplacedBy)
+	})
+
+	t.Run("Inactive users are skipped", func(t *testing.T) {
+		_, err := c.SetIsActive(ctx, "s1", false)
+		require.NoError(t, err)
+		res := create("pr-4", "postgres")
+		assert.NotContains(t, res.PullRequest.AssignedReviewers, "s1")
+		assert.Len(t, res.PullRequest.AssignedReviewers, 2)
+	})
+
+	t.Run("Code owner reason", func(t *testing.T) {
+		_, err := c.SetCodeOwners(ctx, client.CodeOwners{TeamName: "search", Rules: []client.CodeOwnerRule{
+			{Pattern: "*.sql", Users: []string{"s4"}},
+		}})
+		require.NoError(t, err)
+		res, err := c.CreatePullRequestWithReasons(ctx, client.CreatePullRequest{
+			PullRequestID: "pr-5", PullRequestName: "pr-5", AuthorID: "a1",
+			ChangedFiles: []string{"db/001.sql"}, Labels: []string{"go"},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Assignment, 2)
+		assert.Equal(t, client.ReviewerPick{UserID: "s4", Reason: "code_owner", Pattern: "*.sql", OpenReviews: 2}, res.Assignment[0])
+		assert.Equal(t, "s2", res.Assignment[1].UserID)
+	})
+
+	t.Run("Labels in v2", func(t *testing.T) {
+		pr, err := c.V2GetPullRequest(ctx, "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "postgres"}, pr.Labels)
+	})
+}
+
+// TestCLI_Skills - reviewerctl user skills и pr create --label
+func TestCLI_Skills(t *testing.T) {
+	srv := startAPI(t)
+	_, err := newClient(t, srv.URL).AddTeam(context.Background(), clientTeam())
+	require.NoError(t, err)
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	_, _, code := runCLI(t, cfgPath, "config", "set-profile", "local", "--server", srv.URL)
+	require.Equal(t, 0, code)
+
+	out, errOut, code := runCLI(t, cfgPath, "user", "skills", "set", "u3", "frontend", "--proficiency", "4")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "frontend")
+
+	out, errOut, code = runCLI(t, cfgPath, "pr", "create", "pr-1", "--name", "UI", "--author", "u1", "--label", "frontend")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "REASON")
+	assert.Contains(t, out, "frontend:4")
+
+	_, _, code = runCLI(t, cfgPath, "user", "skills", "rm", "u3", "frontend")
+	require.Equal(t, 0, code)
+	out, _, code = runCLI(t, cfgPath, "user", "skills", "get", "u3", "-o", "json")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, `"skills": []`)
+}
@@ -0,0 +1,212 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"reviewer_pr/internal/notify"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/pkg/client"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// backdate сдвигает создание PR и назначение его ревьюверов на age назад
+func backdate(t *testing.T, db *gorm.DB, prID string, age time.Duration) {
+	t.Helper()
+	at := time.Now().Add(-age).UTC()
+	require.NoError(t, db.Exec("UPDATE pull_requests SET created_at = ? WHERE pull_request_id = ?", at, prID).Error)
+	require.NoError(t, db.Exec("UPDATE pr_reviewers SET assigned_at = ? WHERE pull_request_id = ?", at, prID).Error)
+}
+
+// TestSLA_Settings - SLA команды: значения по умолчанию, сохранение, валидация и отключение
+func TestSLA_Settings(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, startAPI(t).URL)
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+
+	sla, err := c.GetTeamSLA(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, client.TeamSLA{TeamName: "backend"}, *sla)
+
+	want := client.TeamSLA{TeamName: "backend", ReviewSLASeconds: 86400, MergeSLASeconds: 259200, AutoReassign: true}
+	sla, err = c.SetTeamSLA(ctx, want)
+	require.NoError(t, err)
+	assert.Equal(t, want, *sla)
+
+	sla, err = c.GetTeamSLA(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, want, *sla)
+
+	_, err = c.SetTeamSLA(ctx, client.TeamSLA{TeamName: "backend", ReviewSLASeconds: -1})
+	require.ErrorIs(t, err, client.ErrValidation)
+	_, err = c.SetTeamSLA(ctx, client.TeamSLA{TeamName: "backend", MergeSLASeconds: 3600, AutoReassign: true})
+	require.ErrorIs(t, err, client.ErrValidation)
+	_, err = c.SetTeamSLA(ctx, client.TeamSLA{TeamName: "nobody", ReviewSLASeconds: 3600})
+	require.ErrorIs(t, err, client.ErrNotFound)
+	_, err = c.GetTeamSLA(ctx, "nobody")
+	require.ErrorIs(t, err, client.ErrNotFound)
+
+	sla, err = c.SetTeamSLA(ctx, client.TeamSLA{TeamName: "backend"})
+	require.NoError(t, err)
+	assert.Equal(t, client.TeamSLA{TeamName: "backend"}, *sla)
+	sla, err = c.GetTeamSLA(ctx, "backend")
+	require.NoError(t, err)
+	assert.Zero(t, sla.ReviewSLASeconds)
+}
+
+// TestSLA_Overdue - список просроченных PR: сортировка, фильтр по команде, пагинация
+func TestSLA_Overdue(t *testing.T) {
+	ctx := context.Background()
+	srv := startAPI(t)
+	db := srv.db
+	c := newClient(t, srv.URL)
+
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.AddTeam(ctx, client.Team{TeamName: "payments", Members: []client.TeamMember{
+		{UserID: "p1", Username: "Paul", IsActive: true},
+		{UserID: "p2", Username: "Pam", IsActive: true},
+	}})
+	require.NoError(t, err)
+	for _, pr := range []client.CreatePullRequest{
+		{PullRequestID: "pr-1", PullRequestName: "Late review", AuthorID: "u1"},
+		{PullRequestID: "pr-2", PullRequestName: "Late merge", AuthorID: "u1"},
+		{PullRequestID: "pr-3", PullRequestName: "Fresh", AuthorID: "u1"},
+		{PullRequestID: "pr-4", PullRequestName: "Merged", AuthorID: "u1"},
+		{PullRequestID: "pay-1", PullRequestName: "No SLA", AuthorID: "p1"},
+	} {
+		_, err := c.CreatePullRequest(ctx, pr)
+		require.NoError(t, err)
+	}
+	_, err = c.MergePullRequest(ctx, "pr-4")
+	require.NoError(t, err)
+	backdate(t, db, "pr-1", 30*time.Hour)
+	backdate(t, db, "pr-2", 50*time.Hour)
+	backdate(t, db, "pr-4", 50*time.Hour)
+	backdate(t, db, "pay-1", 50*time.Hour)
+
+	_, err = c.SetTeamSLA(ctx, client.TeamSLA{TeamName: "backend", ReviewSLASeconds: 24 * 3600, MergeSLASeconds: 48 * 3600})
+	require.NoError(t, err)
+
+	list, err := c.ListOverdue(ctx, client.OverdueQuery{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, list.Total)
+	require.Len(t, list.PullRequests, 2)
+
+	// pr-2 просрочен сильнее: ревью на 26ч, мерж на 2ч
+	late := list.PullRequests[0]
+	assert.Equal(t, "pr-2", late.PullRequestID)
+	assert.Equal(t, "backend", late.TeamName)
+	require.Len(t, late.Breaches, 3)
+	assert.Equal(t, []string{"review", "review", "merge"}, []string{late.Breaches[0].Kind, late.Breaches[1].Kind, late.Breaches[2].Kind})
+	assert.ElementsMatch(t, []string{"u2", "u3"}, []string{late.Breaches[0].ReviewerID, late.Breaches[1].ReviewerID})
+	assert.Empty(t, late.Breaches[2].ReviewerID)
+	assert.InDelta(t, 26*3600, late.Breaches[0].OverdueSeconds, 60)
+	assert.InDelta(t, 2*3600, late.Breaches[2].OverdueSeconds, 60)
+
+	assert.Equal(t, "pr-1", list.PullRequests[1].PullRequestID)
+	assert.Len(t, list.PullRequests[1].Breaches, 2)
+
+	page, err := c.ListOverdue(ctx, client.OverdueQuery{TeamName: "backend", Page: client.Page{Limit: 1, Offset: 1}})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, page.Total)
+	require.Len(t, page.PullRequests, 1)
+	assert.Equal(t, "pr-1", page.PullRequests[0].PullRequestID)
+
+	other, err := c.ListOverdue(ctx, client.OverdueQuery{TeamName: "payments"})
+	require.NoError(t, err)
+	assert.Zero(t, other.Total)
+	assert.Empty(t, other.PullRequests)
+}
+
+// TestSLA_CheckBreaches - фоновая проверка уведомляет о новых нарушениях один раз
+// и переназначает просрочивших ревьюверов, если есть замена
+func TestSLA_CheckBreaches(t *testing.T) {
+	ctx := context.Background()
+	stub := newWebhookStub(t)
+	d, err := notify.NewDispatcher(notify.NewWebhook(stub.URL), notify.Config{}, zap.NewNop())
+	require.NoError(t, err)
+	srv := startAPI(t, withService(service.WithNotifier(d)))
+	svc := srv.svc
+	c := newClient(t, srv.URL)
+
+	_, err = c.AddTeam(ctx, client.Team{TeamName: "backend", Members: []client.TeamMember{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "carol", IsActive: false},
+	}})
+	require.NoError(t, err)
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	_, err = c.SetTeamSLA(ctx, client.TeamSLA{TeamName: "backend", ReviewSLASeconds: 3600, MergeSLASeconds: 7200, AutoReassign: true})
+	require.NoError(t, err)
+
+	// через 90 минут просрочено только ревью; заменить bob некем - он остается ревьювером
+	res, err := svc.SLAs.CheckBreaches(ctx, time.Now().Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, service.SLACheckResult{ReviewBreaches: 1}, *res)
+
+	res, err = svc.SLAs.CheckBreaches(ctx, time.Now().Add(100*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, service.SLACheckResult{}, *res, "breaches are reported once")
+
+	// через 3 часа просрочен и мерж; появившаяся carol заменяет bob
+	_, err = c.SetIsActive(ctx, "u3", true)
+	require.NoError(t, err)
+	res, err = svc.SLAs.CheckBreaches(ctx, time.Now().Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, service.SLACheckResult{MergeBreaches: 1, Reassigned: 1}, *res)
+
+	pr, err := c.GetReviews(ctx, "u3")
+	require.NoError(t, err)
+	require.Len(t, pr.PullRequests, 1)
+	assert.Equal(t, "pr-1", pr.PullRequests[0].PullRequestID)
+
+	require.NoError(t, d.Close(ctx))
+	assert.Equal(t, []string{
+		"@bob: please review *Add search* (pr-1) by @alice",
+		"@bob: review of *Add search* (pr-1) by @alice is 30m overdue",
+		"@carol: please review *Add search* (pr-1) by @alice instead of @bob",
+		"*Add search* (pr-1) by @alice is 1h past its merge deadline. Reviewers: @carol",
+	}, stub.texts())
+}
+
+// TestCLI_SLA - команды team sla и pr overdue
+func TestCLI_SLA(t *testing.T) {
+	ctx := context.Background()
+	srv := startAPI(t)
+	db := srv.db
+	c := newClient(t, srv.URL)
+	_, err := c.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	backdate(t, db, "pr-1", 30*time.Hour)
+
+	cfgPath := filepath.Join(t.TempDir(), "config.yml")
+	run := func(args ...string) (string, string, int) {
+		return runCLI(t, cfgPath, append([]string{"--server", srv.URL}, args...)...)
+	}
+
+	out, stderr, code := run("team", "sla", "set", "backend", "--review", "24h", "--auto-reassign")
+	require.Equal(t, 0, code, stderr)
+	assert.Regexp(t, `backend\s+24h0m0s\s+-\s+yes`, out)
+
+	out, _, code = run("team", "sla", "get", "backend", "-o", "json")
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, `{"team_name":"backend","review_sla_seconds":86400,"merge_sla_seconds":0,"auto_reassign":true}`, out)
+
+	out, _, code = run("pr", "overdue", "--team", "backend")
+	require.Equal(t, 0, code)
+	assert.Regexp(t, `pr-1\s+Add search\s+u1\s+backend\s+review by u[23] \(6h0m\d+s late\); review by u[23]`, out)
+
+	_, stderr, code = run("team", "sla", "set", "backend", "--merge", "1h", "--auto-reassign")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "auto_reassign")
+}
@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"reviewer_pr/internal/auth"
+	"reviewer_pr/internal/config"
+	"reviewer_pr/internal/database"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/tenant"
+	"reviewer_pr/internal/testhelpers"
+	"reviewer_pr/pkg/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestTenants_Isolation - команды, пользователи и PR разных тенантов не видны друг другу,
+// а их имена и идентификаторы могут совпадать
+func TestTenants_Isolation(t *testing.T) {
+	ctx := context.Background()
+	authenticator := auth.New(config.AuthConfig{
+		Enabled:    true,
+		AdminToken: "adm",
+		UserToken:  "usr",
+		Tenants:    []config.TenantAuth{{ID: "acme", AdminToken: "acme-adm", UserToken: "acme-usr"}},
+	})
+	srv := startAPI(t, withRouter(router.WithAuth(authenticator)))
+	def := newClient(t, srv.URL, client.WithToken("adm"))
+	acme := newClient(t, srv.URL, client.WithToken("acme-adm"))
+
+	_, err := def.AddTeam(ctx, clientTeam())
+	require.NoError(t, err)
+	_, err = acme.AddTeam(ctx, clientTeam())
+	require.NoError(t, err, "team names are unique per tenant")
+	_, err = acme.AddTeam(ctx, clientTeam())
+	assert.ErrorIs(t, err, client.ErrTeamExists)
+
+	_, err = acme.V2UpdateTeam(ctx, "backend", []client.TeamMember{{UserID: "u4", Username: "Dave", IsActive: true}}, 0)
+	require.NoError(t, err)
+	team, err := def.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 3)
+	_, err = def.GetUser(ctx, "u4")
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	_, err = acme.AddExclusion(ctx, client.Exclusion{AuthorID: "u1", ReviewerID: "u2"})
+	require.NoError(t, err)
+	exclusions, err := def.GetExclusions(ctx, "u2")
+	require.NoError(t, err)
+	assert.Empty(t, exclusions)
+
+	pr, err := acme.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u3", "u4"}, pr.AssignedReviewers, "u2 is excluded in acme only")
+	_, err = def.V2GetPullRequest(ctx, "pr-1")
+	assert.ErrorIs(t, err, client.ErrNotFound)
+
+	pr, err = def.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Fix", AuthorID: "u1"})
+	require.NoError(t, err, "pull request IDs are unique per tenant")
+	assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+	_, err = acme.MergePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	pr, err = def.V2GetPullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, client.PullRequestStatus("OPEN"), pr.Status)
+
+	reviews, err := newClient(t, srv.URL, client.WithToken("usr")).GetReviews(ctx, "u3")
+	require.NoError(t, err)
+	require.Len(t, reviews.PullRequests, 1)
+	assert.Equal(t, "Fix", reviews.PullRequests[0].PullRequestName)
+
+	teams, err := newClient(t, srv.URL, client.WithToken("acme-usr")).ListTeams(ctx, client.Page{})
+	require.NoError(t, err)
+	require.Len(t, teams.Teams, 1)
+	assert.Equal(t, int64(1), teams.Total)
+
+	t.Run("Roles apply within the tenant", func(t *testing.T) {
+		_, err := newClient(t, srv.URL, client.WithToken("acme-usr")).AddTeam(ctx, client.Team{TeamName: "frontend"})
+		assert.ErrorIs(t, err, client.ErrForbidden)
+	})
+}
+
+// TestTenants_LegacyData - данные, созданные до появления тенантов, переносятся
+// в тенант по умолчанию
+func TestTenants_LegacyData(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:tenants-legacy?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	for _, stmt := range []string{
+		`CREATE TABLE teams (team_name text PRIMARY KEY, version integer NOT NULL DEFAULT 1, created_at datetime, updated_at datetime)`,
+		`CREATE TABLE users (user_id text PRIMARY KEY, username text NOT NULL, team_name text NOT NULL, is_active numeric NOT NULL DEFAULT true,
+			version integer NOT NULL DEFAULT 1, created_at datetime, updated_at datetime)`,
+		`INSERT INTO teams (team_name) VALUES ('legacy')`,
+		`INSERT INTO users (user_id, username, team_name) VALUES ('l1', 'Old', 'legacy')`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	require.NoError(t, database.AutoMigrate(db, zap.NewNop()))
+
+	var tenants []models.Tenant
+	require.NoError(t, db.Find(&tenants).Error)
+	require.Len(t, tenants, 1)
+	assert.Equal(t, tenant.Default, tenants[0].ID)
+
+	repos := repository.New(db)
+	svc := service.New(repos, zap.NewNop())
+	team, err := svc.Teams.GetTeam(context.Background(), "legacy")
+	require.NoError(t, err)
+	require.Len(t, team.Members, 1)
+	assert.Equal(t, "l1", team.Members[0].ID)
+
+	acme := tenant.WithContext(context.Background(), "acme")
+	_, err = svc.Teams.GetTeam(acme, "legacy")
+	var serr *service.Error
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, service.ErrorCodeNotFound, serr.Code)
+	_, err = svc.Teams.AddTeam(acme, service.CreateTeamInput{
+		TeamName: "legacy",
+		Members:  []service.CreateTeamMemberInput{{UserID: "l1", Username: "New", IsActive: true}},
+	})
+	require.NoError(t, err, "the migrated keys include the tenant")
+
+	require.NoError(t, repos.Tenants.Ensure(acme, "acme", tenant.Default))
+	list, err := repos.Tenants.List(acme)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	testhelpers.CleanDB(t, db)
+	require.NoError(t, db.Find(&tenants).Error)
+	assert.Len(t, tenants, 1, "the default tenant survives cleanup")
+}
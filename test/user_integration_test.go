@@ -7,6 +7,8 @@ import (
 	"reviewer_pr/internal/testhelpers"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -89,3 +91,34 @@ func TestUserService_GetUser(t *testing.T) {
 		}
 	}
 }
+
+// TestUserService_DeleteUserAtomic - ошибка на любом шаге удаления оставляет
+// пользователя, его навыки и версии команд нетронутыми
+func TestUserService_DeleteUserAtomic(t *testing.T) {
+	db := testhelpers.SetupNamedTestDB(t, "user-delete-atomic")
+	repo := repository.New(db)
+	svc := service.New(repo, zap.NewNop())
+	ctx := context.Background()
+
+	users := testhelpers.CreateTestTeam(t, db, "backend", 2)
+	_, err := svc.Skills.SetSkill(ctx, service.SetSkillInput{UserID: users[0].ID, Skill: "go", Proficiency: 3})
+	require.NoError(t, err)
+	before, err := repo.Teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`CREATE TRIGGER fail_membership BEFORE DELETE ON team_memberships
+		BEGIN SELECT RAISE(ABORT, 'boom'); END`).Error)
+	require.Error(t, svc.Users.DeleteUser(ctx, users[0].ID, 0))
+
+	_, err = svc.Users.GetUser(ctx, users[0].ID)
+	require.NoError(t, err, "the user is not deleted")
+	skills, err := repo.Skills.List(ctx, users[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, skills, 1)
+	after, err := repo.Teams.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, before.Version, after.Version)
+
+	require.NoError(t, db.Exec("DROP TRIGGER fail_membership").Error)
+	require.NoError(t, svc.Users.DeleteUser(ctx, users[0].ID, 0))
+}
@@ -0,0 +1,259 @@
+package service_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func doV2(r *gin.Engine, method, path string, payload any, headers map[string]string) *httptest.ResponseRecorder {
+	var body *bytes.Buffer
+	if payload != nil {
+		b, _ := json.Marshal(payload)
+		body = bytes.NewBuffer(b)
+	} else {
+		body = &bytes.Buffer{}
+	}
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var errResp httpapi.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	return errResp.Error.Code
+}
+
+// TestV2_Teams - ресурсные маршруты команд с ETag/If-Match
+func TestV2_Teams(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	r := router.Router(httpapi.New(service.New(repo, log), log))
+
+	created := doV2(r, "POST", "/api/v2/teams", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "alice", "username": "Alice", "is_active": true},
+		},
+	}, nil)
+	require.Equal(t, http.StatusCreated, created.Code)
+	assert.Equal(t, "/api/v2/teams/backend", created.Header().Get("Location"))
+	etag := created.Header().Get(httpapi.HeaderETag)
+	require.NotEmpty(t, etag)
+
+	var team httpapi.TeamDTO
+	require.NoError(t, json.Unmarshal(created.Body.Bytes(), &team))
+	assert.Equal(t, "backend", team.TeamName)
+	assert.Len(t, team.Members, 1)
+
+	t.Run("Conditional GET returns 304", func(t *testing.T) {
+		w := doV2(r, "GET", "/api/v2/teams/backend", nil, map[string]string{httpapi.HeaderIfNoneMatch: etag})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("PATCH with current ETag adds members", func(t *testing.T) {
+		w := doV2(r, "PATCH", "/api/v2/teams/backend", map[string]any{
+			"members": []map[string]any{
+				{"user_id": "bob", "username": "Bob", "is_active": true},
+			},
+		}, map[string]string{httpapi.HeaderIfMatch: etag})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get(httpapi.HeaderETag))
+
+		var upd httpapi.TeamDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &upd))
+		assert.Len(t, upd.Members, 2)
+	})
+
+	t.Run("PATCH with stale ETag is rejected", func(t *testing.T) {
+		w := doV2(r, "PATCH", "/api/v2/teams/backend", map[string]any{
+			"members": []map[string]any{
+				{"user_id": "carol", "username": "Carol", "is_active": true},
+			},
+		}, map[string]string{httpapi.HeaderIfMatch: etag})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Equal(t, "PRECONDITION_FAILED", errorCode(t, w))
+	})
+
+	t.Run("Change through v1 invalidates ETag", func(t *testing.T) {
+		before := doV2(r, "GET", "/api/v2/teams/backend", nil, nil).Header().Get(httpapi.HeaderETag)
+
+		w := doV2(r, "POST", "/users/setIsActive", map[string]any{"user_id": "bob", "is_active": false}, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		after := doV2(r, "GET", "/api/v2/teams/backend", nil, map[string]string{httpapi.HeaderIfNoneMatch: before})
+		assert.Equal(t, http.StatusOK, after.Code)
+		assert.NotEqual(t, before, after.Header().Get(httpapi.HeaderETag))
+	})
+
+	t.Run("Failed PATCH keeps version and members", func(t *testing.T) {
+		require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail_membership", func(tx *gorm.DB) {
+			if row, ok := tx.Statement.Dest.(map[string]any); ok && tx.Statement.Table == "team_memberships" && row["user_id"] == "dave" {
+				_ = tx.AddError(errors.New("membership write failed"))
+			}
+		}))
+		defer func() { _ = db.Callback().Create().Remove("test:fail_membership") }()
+
+		before := doV2(r, "GET", "/api/v2/teams/backend", nil, nil)
+		tag := before.Header().Get(httpapi.HeaderETag)
+		w := doV2(r, "PATCH", "/api/v2/teams/backend", map[string]any{
+			"members": []map[string]any{
+				{"user_id": "carol", "username": "Carol", "is_active": true},
+				{"user_id": "dave", "username": "Dave", "is_active": true},
+			},
+		}, map[string]string{httpapi.HeaderIfMatch: tag})
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		after := doV2(r, "GET", "/api/v2/teams/backend", nil, nil)
+		assert.Equal(t, tag, after.Header().Get(httpapi.HeaderETag))
+		assert.JSONEq(t, before.Body.String(), after.Body.String())
+		assert.Equal(t, http.StatusNotFound, doV2(r, "GET", "/api/v2/users/carol", nil, nil).Code)
+	})
+
+	t.Run("Non-empty team cannot be deleted", func(t *testing.T) {
+		w := doV2(r, "DELETE", "/api/v2/teams/backend", nil, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "RESOURCE_IN_USE", errorCode(t, w))
+	})
+
+	t.Run("Malformed If-Match is rejected", func(t *testing.T) {
+		w := doV2(r, "DELETE", "/api/v2/teams/backend", nil, map[string]string{httpapi.HeaderIfMatch: "abc"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// TestV2_UsersAndPullRequests - пользователи, PR и ревьюверы через v2
+func TestV2_UsersAndPullRequests(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	r := router.Router(httpapi.New(service.New(repo, log), log))
+
+	w := doV2(r, "POST", "/api/v2/teams", map[string]any{
+		"team_name": "backend",
+		"members": []map[string]any{
+			{"user_id": "alice", "username": "Alice", "is_active": true},
+			{"user_id": "bob", "username": "Bob", "is_active": true},
+			{"user_id": "charlie", "username": "Charlie", "is_active": true},
+			{"user_id": "dave", "username": "Dave", "is_active": true},
+			{"user_id": "erin", "username": "Erin", "is_active": true},
+		},
+	}, nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("User PATCH and DELETE", func(t *testing.T) {
+		get := doV2(r, "GET", "/api/v2/users/erin", nil, nil)
+		require.Equal(t, http.StatusOK, get.Code)
+		etag := get.Header().Get(httpapi.HeaderETag)
+
+		upd := doV2(r, "PATCH", "/api/v2/users/erin", map[string]any{"username": "Erin B."},
+			map[string]string{httpapi.HeaderIfMatch: etag})
+		require.Equal(t, http.StatusOK, upd.Code)
+		var user httpapi.UserDTO
+		require.NoError(t, json.Unmarshal(upd.Body.Bytes(), &user))
+		assert.Equal(t, "Erin B.", user.Username)
+		assert.True(t, user.IsActive)
+
+		stale := doV2(r, "DELETE", "/api/v2/users/erin", nil, map[string]string{httpapi.HeaderIfMatch: etag})
+		assert.Equal(t, http.StatusPreconditionFailed, stale.Code)
+
+		del := doV2(r, "DELETE", "/api/v2/users/erin", nil,
+			map[string]string{httpapi.HeaderIfMatch: upd.Header().Get(httpapi.HeaderETag)})
+		assert.Equal(t, http.StatusNoContent, del.Code)
+
+		missing := doV2(r, "GET", "/api/v2/users/erin", nil, nil)
+		assert.Equal(t, http.StatusNotFound, missing.Code)
+	})
+
+	var pr httpapi.PullRequestDTO
+	created := doV2(r, "POST", "/api/v2/pull-requests", map[string]any{
+		"pull_request_id":   "PR-V2",
+		"pull_request_name": "Resource API",
+		"author_id":         "alice",
+	}, nil)
+	require.Equal(t, http.StatusCreated, created.Code)
+	require.NoError(t, json.Unmarshal(created.Body.Bytes(), &pr))
+	require.Len(t, pr.AssignedReviewers, 2)
+	etag := created.Header().Get(httpapi.HeaderETag)
+
+	t.Run("Referenced user cannot be deleted", func(t *testing.T) {
+		w := doV2(r, "DELETE", "/api/v2/users/alice", nil, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "RESOURCE_IN_USE", errorCode(t, w))
+	})
+
+	t.Run("Reassign reviewer with If-Match", func(t *testing.T) {
+		w := doV2(r, "POST", "/api/v2/pull-requests/PR-V2/reviewers",
+			map[string]any{"old_reviewer_id": pr.AssignedReviewers[0]},
+			map[string]string{httpapi.HeaderIfMatch: etag})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var rev httpapi.ReviewersDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rev))
+		assert.NotEmpty(t, rev.ReplacedBy)
+		assert.Contains(t, rev.Reviewers, rev.ReplacedBy)
+		assert.NotContains(t, rev.Reviewers, pr.AssignedReviewers[0])
+
+		stale := doV2(r, "POST", "/api/v2/pull-requests/PR-V2/reviewers",
+			map[string]any{"old_reviewer_id": rev.ReplacedBy},
+			map[string]string{httpapi.HeaderIfMatch: etag})
+		assert.Equal(t, http.StatusPreconditionFailed, stale.Code)
+
+		etag = w.Header().Get(httpapi.HeaderETag)
+	})
+
+	t.Run("PATCH merges pull request", func(t *testing.T) {
+		w := doV2(r, "PATCH", "/api/v2/pull-requests/PR-V2", map[string]any{"status": "MERGED"},
+			map[string]string{httpapi.HeaderIfMatch: etag})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var merged httpapi.PullRequestDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &merged))
+		assert.Equal(t, "MERGED", merged.Status)
+		assert.NotNil(t, merged.MergedAt)
+
+		again := doV2(r, "PATCH", "/api/v2/pull-requests/PR-V2", map[string]any{"pull_request_name": "Renamed"}, nil)
+		assert.Equal(t, http.StatusConflict, again.Code)
+		assert.Equal(t, "PR_MERGED", errorCode(t, again))
+	})
+
+	t.Run("Invalid status transition", func(t *testing.T) {
+		w := doV2(r, "PATCH", "/api/v2/pull-requests/PR-V2", map[string]any{"status": "OPEN"}, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "VALIDATION_ERROR", errorCode(t, w))
+	})
+
+	t.Run("DELETE removes pull request and assignments", func(t *testing.T) {
+		w := doV2(r, "DELETE", "/api/v2/pull-requests/PR-V2", nil, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		get := doV2(r, "GET", "/api/v2/pull-requests/PR-V2/reviewers", nil, nil)
+		assert.Equal(t, http.StatusNotFound, get.Code)
+
+		reviews := doV2(r, "GET", "/users/getReview?user_id=bob", nil, nil)
+		assert.NotContains(t, reviews.Body.String(), "PR-V2")
+	})
+}
@@ -0,0 +1,252 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	httpapi "reviewer_pr/internal/http"
+	"reviewer_pr/internal/models"
+	"reviewer_pr/internal/repository"
+	"reviewer_pr/internal/router"
+	"reviewer_pr/internal/service"
+	"reviewer_pr/internal/testhelpers"
+	"reviewer_pr/pkg/client"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func fieldsOf(details []httpapi.FieldErrorDTO) []string {
+	fields := make([]string, 0, len(details))
+	for _, d := range details {
+		fields = append(fields, d.Field)
+	}
+	return fields
+}
+
+// TestValidation_ErrorCodes - единообразные коды ошибок для некорректного ввода
+func TestValidation_ErrorCodes(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	log := zap.NewNop()
+	services := service.New(repo, log)
+	handler := httpapi.New(services, log)
+	r := router.Router(handler)
+
+	do := func(method, path string, payload interface{}) (int, httpapi.ErrorResponse) {
+		var body []byte
+		switch p := payload.(type) {
+		case nil:
+		case string:
+			body = []byte(p)
+		default:
+			body, _ = json.Marshal(p)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp httpapi.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		payload interface{}
+		code    string
+		fields  []string
+	}{
+		{
+			name:    "Malformed JSON",
+			method:  "POST",
+			path:    "/pullRequest/create",
+			payload: "{not json",
+			code:    "INVALID_REQUEST",
+		},
+		{
+			name:    "PR create missing fields",
+			method:  "POST",
+			path:    "/pullRequest/create",
+			payload: map[string]interface{}{"pull_request_id": "PR-1"},
+			code:    "VALIDATION_ERROR",
+			fields:  []string{"pull_request_name", "author_id"},
+		},
+		{
+			name:   "PR create bad ID format",
+			method: "POST",
+			path:   "/pullRequest/create",
+			payload: map[string]interface{}{
+				"pull_request_id":   "PR 1",
+				"pull_request_name": "Name",
+				"author_id":         "u1",
+			},
+			code:   "VALIDATION_ERROR",
+			fields: []string{"pull_request_id"},
+		},
+		{
+			name:   "PR name too long",
+			method: "POST",
+			path:   "/pullRequest/create",
+			payload: map[string]interface{}{
+				"pull_request_id":   "PR-1",
+				"pull_request_name": strings.Repeat("x", service.MaxPRNameLength+1),
+				"author_id":         "u1",
+			},
+			code:   "VALIDATION_ERROR",
+			fields: []string{"pull_request_name"},
+		},
+		{
+			name:   "Team with duplicate members",
+			method: "POST",
+			path:   "/team/add",
+			payload: map[string]interface{}{
+				"team_name": "dups",
+				"members": []map[string]interface{}{
+					{"user_id": "u1", "username": "U1", "is_active": true},
+					{"user_id": "u1", "username": "U1 again", "is_active": true},
+					{"user_id": "", "username": "", "is_active": true},
+				},
+			},
+			code:   "VALIDATION_ERROR",
+			fields: []string{"members[1].user_id", "members[2].user_id", "members[2].username"},
+		},
+		{
+			name:    "Team get without name",
+			method:  "GET",
+			path:    "/team/get",
+			code:    "VALIDATION_ERROR",
+			fields:  []string{"team_name"},
+			payload: nil,
+		},
+		{
+			name:    "Set is_active without flag",
+			method:  "POST",
+			path:    "/users/setIsActive",
+			payload: map[string]interface{}{"user_id": "u1"},
+			code:    "VALIDATION_ERROR",
+			fields:  []string{"is_active"},
+		},
+		{
+			name:    "Merge without PR ID",
+			method:  "POST",
+			path:    "/pullRequest/merge",
+			payload: map[string]interface{}{},
+			code:    "VALIDATION_ERROR",
+			fields:  []string{"pull_request_id"},
+		},
+		{
+			name:    "Reassign without reviewer",
+			method:  "POST",
+			path:    "/pullRequest/reassign",
+			payload: map[string]interface{}{"pull_request_id": "PR-1"},
+			code:    "VALIDATION_ERROR",
+			fields:  []string{"old_reviewer_id"},
+		},
+		{
+			name:   "Team with too long name",
+			method: "POST",
+			path:   "/team/add",
+			payload: map[string]interface{}{
+				"team_name": strings.Repeat("a", service.MaxIDLength+1),
+				"members":   []map[string]interface{}{},
+			},
+			code:   "VALIDATION_ERROR",
+			fields: []string{"team_name"},
+		},
+		{
+			name:   "Team with malformed member ID",
+			method: "POST",
+			path:   "/team/add",
+			payload: map[string]interface{}{
+				"team_name": "mail",
+				"members": []map[string]interface{}{
+					{"user_id": "bob@example.com", "username": "Bob", "is_active": true},
+				},
+			},
+			code:   "VALIDATION_ERROR",
+			fields: []string{"members[0].user_id"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, resp := do(tc.method, tc.path, tc.payload)
+
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, tc.code, resp.Error.Code)
+			assert.Equal(t, tc.fields, nilIfEmpty(fieldsOf(resp.Error.Details)))
+		})
+	}
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+// TestValidation_DuplicatePRReturnsConflict - повторное создание PR возвращает PR_EXISTS
+func TestValidation_DuplicatePRReturnsConflict(t *testing.T) {
+	db := testhelpers.SetupTestDB(t)
+	repo := repository.New(db)
+	prService := service.NewPRService(repo, zap.NewNop())
+	ctx := context.Background()
+
+	testhelpers.CreateTestTeam(t, db, "dup-team", 2)
+
+	in := service.CreatePRInput{ID: "PR-DUP", Name: "Dup", AuthorID: "dup-team-user-A"}
+	_, err := prService.CreateWithAutoAssign(ctx, in)
+	require.NoError(t, err)
+
+	_, err = prService.CreateWithAutoAssign(ctx, in)
+	var serr *service.Error
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, service.ErrorCodePRExists, serr.Code)
+}
+
+// TestValidation_LegacyIDs - строки с ID в старом формате по-прежнему доступны,
+// а формат проверяется только у создаваемых записей
+func TestValidation_LegacyIDs(t *testing.T) {
+	api := startAPI(t)
+	c := newClient(t, api.URL)
+	ctx := context.Background()
+
+	require.NoError(t, api.db.Create(&models.Team{Name: "legacy.team"}).Error)
+	for _, id := range []string{"alice@example.com", "bob@example.com", "carol@example.com", "erin@example.com"} {
+		require.NoError(t, api.db.Create(&models.User{ID: id, Username: id, TeamName: "legacy.team", IsActive: true}).Error)
+		require.NoError(t, api.db.Create(&models.TeamMembership{TeamName: "legacy.team", UserID: id, IsActive: true}).Error)
+	}
+
+	team, err := c.GetTeam(ctx, "legacy.team")
+	require.NoError(t, err)
+	assert.Len(t, team.Members, 4)
+
+	_, err = c.SetIsActive(ctx, "alice@example.com", true)
+	require.NoError(t, err)
+
+	pr, err := c.CreatePullRequest(ctx, client.CreatePullRequest{PullRequestID: "pr-1", PullRequestName: "Legacy", AuthorID: "alice@example.com"})
+	require.NoError(t, err)
+	require.NotEmpty(t, pr.AssignedReviewers)
+
+	_, err = c.ReassignReviewer(ctx, "pr-1", pr.AssignedReviewers[0])
+	require.NoError(t, err)
+
+	team, err = c.V2GetTeam(ctx, "legacy.team")
+	require.NoError(t, err)
+	members := append(team.Members, client.TeamMember{UserID: "dave@example.com", Username: "Dave", IsActive: true})
+	_, err = c.V2UpdateTeam(ctx, "legacy.team", members, team.Version)
+	assert.ErrorIs(t, err, client.ErrValidation)
+
+	_, err = c.V2UpdateTeam(ctx, "legacy.team", team.Members[:3], team.Version)
+	require.NoError(t, err)
+}